@@ -27,7 +27,11 @@ func (gc *goClient) SubmitAggregateSelectionProof(slot phase0.Slot, committeeInd
 	}
 
 	attDataReqStart := time.Now()
-	data, err := gc.client.AttestationData(gc.ctx, slot, committeeIndex)
+	var data *phase0.AttestationData
+	err = gc.withBestNode(func(client Client) (err error) {
+		data, err = client.AttestationData(gc.ctx, slot, committeeIndex)
+		return err
+	})
 	if err != nil {
 		return nil, DataVersionNil, errors.Wrap(err, "failed to get attestation data")
 	}
@@ -44,7 +48,11 @@ func (gc *goClient) SubmitAggregateSelectionProof(slot phase0.Slot, committeeInd
 	}
 
 	aggDataReqStart := time.Now()
-	aggregateData, err := gc.client.AggregateAttestation(gc.ctx, slot, root)
+	var aggregateData *phase0.Attestation
+	err = gc.withBestNode(func(client Client) (err error) {
+		aggregateData, err = client.AggregateAttestation(gc.ctx, slot, root)
+		return err
+	})
 	if err != nil {
 		return nil, DataVersionNil, errors.Wrap(err, "failed to get aggregate attestation")
 	}
@@ -66,7 +74,9 @@ func (gc *goClient) SubmitAggregateSelectionProof(slot phase0.Slot, committeeInd
 
 // SubmitSignedAggregateSelectionProof broadcasts a signed aggregator msg
 func (gc *goClient) SubmitSignedAggregateSelectionProof(msg *phase0.SignedAggregateAndProof) error {
-	return gc.client.SubmitAggregateAttestations(gc.ctx, []*phase0.SignedAggregateAndProof{msg})
+	return gc.submitToAll(func(client Client) error {
+		return client.SubmitAggregateAttestations(gc.ctx, []*phase0.SignedAggregateAndProof{msg})
+	})
 }
 
 // IsAggregator returns true if the signature is from the input validator. The committee
@@ -14,7 +14,11 @@ func (gc *goClient) GetAttestationData(slot phase0.Slot, committeeIndex phase0.C
 	gc.waitOneThirdOrValidBlock(slot)
 
 	startTime := time.Now()
-	attestationData, err := gc.client.AttestationData(gc.ctx, slot, committeeIndex)
+	var attestationData *phase0.AttestationData
+	err := gc.withBestNode(func(client Client) (err error) {
+		attestationData, err = client.AttestationData(gc.ctx, slot, committeeIndex)
+		return err
+	})
 	if err != nil {
 		return nil, DataVersionNil, err
 	}
@@ -35,7 +39,9 @@ func (gc *goClient) SubmitAttestation(attestation *phase0.Attestation) error {
 		return errors.Wrap(err, "failed attestation slashing protection check")
 	}
 
-	return gc.client.SubmitAttestations(gc.ctx, []*phase0.Attestation{attestation})
+	return gc.submitToAll(func(client Client) error {
+		return client.SubmitAttestations(gc.ctx, []*phase0.Attestation{attestation})
+	})
 }
 
 // getSigningRoot returns signing root
@@ -49,7 +49,11 @@ func (gc *goClient) GetDuties(logger *zap.Logger, epoch phase0.Epoch, validatorI
 // AttesterDuties applies attester + aggregator duties
 func (gc *goClient) AttesterDuties(epoch phase0.Epoch, validatorIndices []phase0.ValidatorIndex) ([]*spectypes.Duty, error) {
 	var duties []*spectypes.Duty
-	attesterDuties, err := gc.client.AttesterDuties(gc.ctx, epoch, validatorIndices)
+	var attesterDuties []*eth2apiv1.AttesterDuty
+	err := gc.withBestNode(func(client Client) (err error) {
+		attesterDuties, err = client.AttesterDuties(gc.ctx, epoch, validatorIndices)
+		return err
+	})
 	if err != nil {
 		return duties, err
 	}
@@ -76,7 +80,11 @@ func (gc *goClient) AttesterDuties(epoch phase0.Epoch, validatorIndices []phase0
 // ProposerDuties applies proposer duties
 func (gc *goClient) ProposerDuties(epoch phase0.Epoch, validatorIndices []phase0.ValidatorIndex) ([]*spectypes.Duty, error) {
 	var duties []*spectypes.Duty
-	proposerDuties, err := gc.client.ProposerDuties(gc.ctx, epoch, validatorIndices)
+	var proposerDuties []*eth2apiv1.ProposerDuty
+	err := gc.withBestNode(func(client Client) (err error) {
+		proposerDuties, err = client.ProposerDuties(gc.ctx, epoch, validatorIndices)
+		return err
+	})
 	if err != nil {
 		return duties, err
 	}
@@ -93,5 +101,10 @@ func (gc *goClient) ProposerDuties(epoch phase0.Epoch, validatorIndices []phase0
 
 // SyncCommitteeDuties applies sync committee + sync committee contributor duties
 func (gc *goClient) SyncCommitteeDuties(epoch phase0.Epoch, validatorIndices []phase0.ValidatorIndex) ([]*eth2apiv1.SyncCommitteeDuty, error) {
-	return gc.client.SyncCommitteeDuties(gc.ctx, epoch, validatorIndices)
+	var duties []*eth2apiv1.SyncCommitteeDuty
+	err := gc.withBestNode(func(client Client) (err error) {
+		duties, err = client.SyncCommitteeDuties(gc.ctx, epoch, validatorIndices)
+		return err
+	})
+	return duties, err
 }
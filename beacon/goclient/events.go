@@ -0,0 +1,89 @@
+package goclient
+
+import (
+	"context"
+
+	eth2client "github.com/attestantio/go-eth2-client"
+	"go.uber.org/zap"
+)
+
+// eventsSubscription is an events subscription that follows the best node,
+// go-eth2-client keeps reconnecting to the node it was subscribed with even if that node is down.
+type eventsSubscription struct {
+	ctx     context.Context
+	topics  []string
+	handler eth2client.EventHandlerFunc
+
+	// node is the node the subscription is currently on, nil if the latest subscription attempt failed
+	node   *beaconNode
+	cancel context.CancelFunc
+}
+
+// Events subscribes to events of the best available node,
+// the subscription moves to another node once its node isn't healthy anymore.
+func (gc *goClient) Events(ctx context.Context, topics []string, handler eth2client.EventHandlerFunc) error {
+	sub := &eventsSubscription{
+		ctx:     ctx,
+		topics:  topics,
+		handler: handler,
+	}
+
+	gc.eventsMu.Lock()
+	defer gc.eventsMu.Unlock()
+
+	if err := gc.subscribe(sub); err != nil {
+		return err
+	}
+	gc.eventsSubs = append(gc.eventsSubs, sub)
+	return nil
+}
+
+// subscribe subscribes to the best node that accepts the subscription, the caller must hold eventsMu
+func (gc *goClient) subscribe(sub *eventsSubscription) error {
+	err := errNotConnected
+	for _, node := range gc.rankedNodes() {
+		client := node.getClient()
+		if client == nil {
+			continue
+		}
+		ctx, cancel := context.WithCancel(sub.ctx)
+		if err = client.Events(ctx, sub.topics, sub.handler); err != nil {
+			cancel()
+			node.recordFailure(err)
+			gc.log.Debug("beacon node events subscription failed", zap.Int("node", node.index), zap.Error(err))
+			continue
+		}
+		sub.node = node
+		sub.cancel = cancel
+		return nil
+	}
+	return err
+}
+
+// resubscribeEvents moves subscriptions of nodes that aren't healthy anymore to the best healthy node
+func (gc *goClient) resubscribeEvents() {
+	gc.eventsMu.Lock()
+	defer gc.eventsMu.Unlock()
+
+	best := gc.rankedNodes()[0]
+	active := gc.eventsSubs[:0]
+	for _, sub := range gc.eventsSubs {
+		if sub.ctx.Err() != nil {
+			continue
+		}
+		active = append(active, sub)
+		if sub.node != nil && (sub.node.healthy() || sub.node == best || !best.healthy()) {
+			continue
+		}
+		if sub.cancel != nil {
+			sub.cancel()
+		}
+		sub.node, sub.cancel = nil, nil
+		if err := gc.subscribe(sub); err != nil {
+			gc.log.Warn("could not resubscribe to beacon node events", zap.Strings("topics", sub.topics), zap.Error(err))
+			continue
+		}
+		gc.log.Info("resubscribed to beacon node events", zap.Strings("topics", sub.topics), zap.Int("node", sub.node.index))
+	}
+	gc.eventsSubs = active
+}
@@ -0,0 +1,56 @@
+package goclient
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// ForkInfo returns the fork that is active in the given epoch and the genesis validators root,
+// the fork schedule and genesis are requested once and then served from memory.
+func (gc *goClient) ForkInfo(epoch phase0.Epoch) (*phase0.Fork, phase0.Root, error) {
+	gc.forkInfoMu.Lock()
+	defer gc.forkInfoMu.Unlock()
+
+	if len(gc.forkSchedule) == 0 {
+		if err := gc.loadForkInfo(); err != nil {
+			return nil, phase0.Root{}, err
+		}
+	}
+
+	var fork *phase0.Fork
+	for _, f := range gc.forkSchedule {
+		if f.Epoch <= epoch && (fork == nil || f.Epoch >= fork.Epoch) {
+			fork = f
+		}
+	}
+	if fork == nil {
+		return nil, phase0.Root{}, errors.Errorf("no fork is scheduled for epoch %d", epoch)
+	}
+	return fork, gc.genesisRoot, nil
+}
+
+// loadForkInfo requests the fork schedule and genesis, forkInfoMu must be held
+func (gc *goClient) loadForkInfo() error {
+	var schedule []*phase0.Fork
+	var genesisRoot phase0.Root
+	err := gc.withBestNode(func(client Client) error {
+		forks, err := client.ForkSchedule(gc.ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get fork schedule")
+		}
+		genesis, err := client.Genesis(gc.ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get genesis")
+		}
+		schedule, genesisRoot = forks, genesis.GenesisValidatorsRoot
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(schedule) == 0 {
+		return errors.New("fork schedule is empty")
+	}
+	gc.forkSchedule, gc.genesisRoot = schedule, genesisRoot
+	return nil
+}
@@ -2,7 +2,6 @@ package goclient
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"math"
 	"sync"
@@ -10,15 +9,12 @@ import (
 
 	eth2client "github.com/attestantio/go-eth2-client"
 	"github.com/attestantio/go-eth2-client/api"
-	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
-	"github.com/bloxapp/eth2-key-manager/core"
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"github.com/rs/zerolog"
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv/logging/fields"
@@ -104,6 +100,9 @@ type Client interface {
 	eth2client.BlindedBeaconBlockProposalProvider
 	eth2client.BlindedBeaconBlockSubmitter
 	eth2client.ValidatorRegistrationsSubmitter
+	eth2client.VoluntaryExitSubmitter
+	eth2client.ForkScheduleProvider
+	eth2client.GenesisProvider
 }
 
 // goClient implementing Beacon struct
@@ -111,13 +110,18 @@ type goClient struct {
 	log                  *zap.Logger
 	ctx                  context.Context
 	network              beaconprotocol.Network
-	client               Client
+	nodes                []*beaconNode
 	graffiti             []byte
 	gasLimit             uint64
 	operatorID           spectypes.OperatorID
 	registrationMu       sync.Mutex
 	registrationLastSlot phase0.Slot
 	registrationCache    map[phase0.BLSPubKey]*api.VersionedSignedValidatorRegistration
+	eventsMu             sync.Mutex
+	eventsSubs           []*eventsSubscription
+	forkInfoMu           sync.Mutex
+	forkSchedule         []*phase0.Fork
+	genesisRoot          phase0.Root
 }
 
 // verifies that the client implements HealthCheckAgent
@@ -125,66 +129,75 @@ var _ metrics.HealthCheckAgent = &goClient{}
 
 // New init new client and go-client instance
 func New(logger *zap.Logger, opt beaconprotocol.Options, operatorID spectypes.OperatorID, slotTicker slot_ticker.Ticker) (beaconprotocol.Beacon, error) {
-	logger.Info("consensus client: connecting", fields.Address(opt.BeaconNodeAddr), fields.Network(opt.Network))
-
-	httpClient, err := http.New(opt.Context,
-		// WithAddress supplies the address of the beacon node, in host:port format.
-		http.WithAddress(opt.BeaconNodeAddr),
-		// LogLevel supplies the level of logging to carry out.
-		http.WithLogLevel(zerolog.DebugLevel),
-		http.WithTimeout(time.Second*5),
-	)
+	client, err := newClient(logger, opt, operatorID, slotTicker)
 	if err != nil {
-		return nil, errors.WithMessage(err, "failed to create http client")
+		return nil, err
 	}
+	return client, nil
+}
 
-	logger.Info("consensus client: connected", fields.Name(httpClient.Name()), fields.Address(httpClient.Address()))
+func newClient(logger *zap.Logger, opt beaconprotocol.Options, operatorID spectypes.OperatorID, slotTicker slot_ticker.Ticker) (*goClient, error) {
+	addrs := opt.BeaconNodeAddresses()
+	if len(addrs) == 0 {
+		return nil, errors.New("no beacon node address was provided")
+	}
 
-	network := beaconprotocol.NewNetwork(core.NetworkFromString(opt.Network), opt.MinGenesisTime)
+	logger.Info("consensus client: connecting", fields.Count(len(addrs)), fields.Network(opt.Network))
 
-	tickerChan := make(chan phase0.Slot, 32)
-	slotTicker.Subscribe(tickerChan)
+	network, err := beaconprotocol.NetworkFromOptions(opt)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to resolve beacon network")
+	}
+
+	nodes := make([]*beaconNode, 0, len(addrs))
+	for i, addr := range addrs {
+		nodes = append(nodes, newBeaconNode(i, addr))
+	}
 
 	client := &goClient{
 		log:               logger,
 		ctx:               opt.Context,
 		network:           network,
-		client:            httpClient.(*http.Service),
+		nodes:             nodes,
 		graffiti:          opt.Graffiti,
 		gasLimit:          opt.GasLimit,
 		operatorID:        operatorID,
 		registrationCache: map[phase0.BLSPubKey]*api.VersionedSignedValidatorRegistration{},
 	}
 
-	go client.registrationSubmitter(tickerChan)
+	client.checkNodes()
+	if client.rankedNodes()[0].getClient() == nil {
+		return nil, errors.New("failed to connect to any of the beacon nodes")
+	}
+	go client.monitorNodes()
+
+	if slotTicker != nil {
+		tickerChan := make(chan phase0.Slot, 32)
+		slotTicker.Subscribe(tickerChan)
+		go client.registrationSubmitter(tickerChan)
+	}
 
 	return client, nil
 }
 
-// HealthCheck provides health status of beacon node
+// HealthCheck provides health status of the beacon nodes.
+// Issues of every node are reported, even if other nodes are healthy,
+// so that a degraded node is noticed before the node that replaces it fails too.
 func (gc *goClient) HealthCheck() []string {
-	if gc.client == nil {
-		return []string{"not connected to beacon node"}
-	}
-	ctx, cancel := context.WithTimeout(gc.ctx, healthCheckTimeout)
-	defer cancel()
-	syncState, err := gc.client.NodeSyncing(ctx)
-	if err != nil {
-		metricsBeaconNodeStatus.Set(float64(statusUnknown))
-		return []string{"could not get beacon node sync state"}
-	}
-	if syncState != nil && syncState.IsSyncing {
-		metricsBeaconNodeStatus.Set(float64(statusSyncing))
-		return []string{fmt.Sprintf("beacon node is currently syncing: head=%d, distance=%d",
-			syncState.HeadSlot, syncState.SyncDistance)}
+	gc.checkNodes()
+
+	issues := []string{}
+	for _, node := range gc.nodes {
+		if issue := node.issue(); issue != "" {
+			issues = append(issues, issue)
+		}
 	}
-	metricsBeaconNodeStatus.Set(float64(statusOK))
-	return []string{}
+	return issues
 }
 
 // GetBeaconNetwork returns the beacon network the node is on
 func (gc *goClient) GetBeaconNetwork() spectypes.BeaconNetwork {
-	return spectypes.BeaconNetwork(gc.network.Network)
+	return gc.network.BeaconNetwork()
 }
 
 // SlotStartTime returns the start time in terms of its unix epoch
@@ -194,7 +207,3 @@ func (gc *goClient) slotStartTime(slot phase0.Slot) time.Time {
 	startTime := time.Unix(int64(gc.network.MinGenesisTime()), 0).Add(duration)
 	return startTime
 }
-
-func (gc *goClient) Events(ctx context.Context, topics []string, handler eth2client.EventHandlerFunc) error {
-	return gc.client.Events(ctx, topics, handler)
-}
@@ -0,0 +1,304 @@
+package goclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+)
+
+// beaconStandIn is a minimal beacon node HTTP API, serving just enough endpoints for go-eth2-client
+type beaconStandIn struct {
+	*httptest.Server
+
+	syncing          atomic.Bool
+	failData         atomic.Bool
+	dataRequests     atomic.Int32
+	aggregateSubmits atomic.Int32
+	eventsStreams    atomic.Int32
+}
+
+func newBeaconStandIn(t *testing.T) *beaconStandIn {
+	bn := &beaconStandIn{}
+	root := "0x" + strings.Repeat("00", 32)
+	responses := map[string]string{
+		"/eth/v1/beacon/genesis":          `{"data":{"genesis_time":"1616508000","genesis_validators_root":"` + root + `","genesis_fork_version":"0x00001020"}}`,
+		"/eth/v1/config/spec":             `{"data":{"SECONDS_PER_SLOT":"12","SLOTS_PER_EPOCH":"32"}}`,
+		"/eth/v1/config/deposit_contract": `{"data":{"chain_id":"5","address":"0xff50ed3d0ec03ac01d4c79aad74928bff48a7b2b"}}`,
+		"/eth/v1/config/fork_schedule":    `{"data":[{"previous_version":"0x00001020","current_version":"0x00001020","epoch":"0"},{"previous_version":"0x00001020","current_version":"0x01001020","epoch":"36660"}]}`,
+		"/eth/v1/node/version":            `{"data":{"version":"stand-in/v1.0.0"}}`,
+	}
+	bn.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if resp, ok := responses[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(resp))
+			return
+		}
+		switch r.URL.Path {
+		case "/eth/v1/node/syncing":
+			_, _ = fmt.Fprintf(w, `{"data":{"head_slot":"100","sync_distance":"%d","is_syncing":%t}}`, boolToInt(bn.syncing.Load())*50, bn.syncing.Load())
+		case "/eth/v1/validator/attestation_data":
+			bn.dataRequests.Add(1)
+			if bn.failData.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			checkpoint := `{"epoch":"0","root":"` + root + `"}`
+			_, _ = w.Write([]byte(`{"data":{"slot":"1","index":"0","beacon_block_root":"` + root + `","source":` + checkpoint + `,"target":` + checkpoint + `}}`))
+		case "/eth/v1/events":
+			// a single head event, then the stream is kept open until the client disconnects
+			bn.eventsStreams.Add(1)
+			defer bn.eventsStreams.Add(-1)
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "event: head\ndata: {\"slot\":\"100\",\"block\":\"%s\",\"state\":\"%s\"}\n\n", root, root)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "/eth/v1/validator/aggregate_and_proofs":
+			_, _ = io.Copy(io.Discard, r.Body)
+			bn.aggregateSubmits.Add(1)
+			w.WriteHeader(http.StatusOK)
+		case "/eth/v1/validator/liveness/5":
+			// validators with an odd index are live
+			var indices []string
+			if err := json.NewDecoder(r.Body).Decode(&indices); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data := make([]string, len(indices))
+			for i, index := range indices {
+				n, _ := strconv.Atoi(index)
+				data[i] = fmt.Sprintf(`{"index":"%s","is_live":%t}`, index, n%2 == 1)
+			}
+			_, _ = fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(bn.Close)
+	return bn
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func newTestClient(t *testing.T, addrs ...string) *goClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	gc, err := newClient(logging.TestLogger(t), beaconprotocol.Options{
+		Context:         ctx,
+		Network:         "prater",
+		BeaconNodeAddrs: addrs,
+	}, 1, nil)
+	require.NoError(t, err)
+	return gc
+}
+
+func TestGoClient_Failover(t *testing.T) {
+	primary := newBeaconStandIn(t)
+	secondary := newBeaconStandIn(t)
+	gc := newTestClient(t, primary.URL, secondary.URL)
+
+	t.Run("requests go to the preferred node", func(t *testing.T) {
+		_, _, err := gc.GetAttestationData(1, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, primary.dataRequests.Load())
+		require.EqualValues(t, 0, secondary.dataRequests.Load())
+	})
+
+	t.Run("failing node is skipped", func(t *testing.T) {
+		primary.failData.Store(true)
+		_, _, err := gc.GetAttestationData(1, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, primary.dataRequests.Load())
+		require.EqualValues(t, 1, secondary.dataRequests.Load())
+
+		// primary is now ranked lower until it recovers
+		_, _, err = gc.GetAttestationData(1, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, primary.dataRequests.Load())
+		require.EqualValues(t, 2, secondary.dataRequests.Load())
+	})
+
+	t.Run("recovered node is preferred again", func(t *testing.T) {
+		primary.failData.Store(false)
+		gc.checkNodes()
+		_, _, err := gc.GetAttestationData(1, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, primary.dataRequests.Load())
+		require.EqualValues(t, 2, secondary.dataRequests.Load())
+	})
+
+	t.Run("syncing node is not preferred", func(t *testing.T) {
+		primary.syncing.Store(true)
+		gc.checkNodes()
+		_, _, err := gc.GetAttestationData(1, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, primary.dataRequests.Load())
+		require.EqualValues(t, 3, secondary.dataRequests.Load())
+		primary.syncing.Store(false)
+	})
+}
+
+func TestGoClient_SubmitToAll(t *testing.T) {
+	nodes := []*beaconStandIn{newBeaconStandIn(t), newBeaconStandIn(t), newBeaconStandIn(t)}
+	gc := newTestClient(t, nodes[0].URL, nodes[1].URL, nodes[2].URL)
+
+	msg := &phase0.SignedAggregateAndProof{
+		Message: &phase0.AggregateAndProof{
+			Aggregate: &phase0.Attestation{
+				Data: &phase0.AttestationData{
+					Source: &phase0.Checkpoint{},
+					Target: &phase0.Checkpoint{},
+				},
+			},
+		},
+	}
+	require.NoError(t, gc.SubmitSignedAggregateSelectionProof(msg))
+	for _, node := range nodes {
+		require.EqualValues(t, 1, node.aggregateSubmits.Load())
+	}
+
+	// submission succeeds as long as one of the nodes accepts it
+	nodes[0].Close()
+	nodes[1].Close()
+	require.NoError(t, gc.SubmitSignedAggregateSelectionProof(msg))
+	require.EqualValues(t, 2, nodes[2].aggregateSubmits.Load())
+
+	nodes[2].Close()
+	require.Error(t, gc.SubmitSignedAggregateSelectionProof(msg))
+}
+
+func TestGoClient_ValidatorLiveness(t *testing.T) {
+	bn := newBeaconStandIn(t)
+	gc := newTestClient(t, bn.URL)
+
+	liveness, err := gc.ValidatorLiveness(5, []phase0.ValidatorIndex{1, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, map[phase0.ValidatorIndex]bool{1: true, 2: false, 3: true}, liveness)
+
+	liveness, err = gc.ValidatorLiveness(5, nil)
+	require.NoError(t, err)
+	require.Empty(t, liveness)
+
+	_, err = gc.ValidatorLiveness(6, []phase0.ValidatorIndex{1})
+	require.Error(t, err)
+}
+
+func TestGoClient_ForkInfo(t *testing.T) {
+	bn := newBeaconStandIn(t)
+	gc := newTestClient(t, bn.URL)
+
+	fork, genesisRoot, err := gc.ForkInfo(100)
+	require.NoError(t, err)
+	require.Equal(t, phase0.Version{0x00, 0x00, 0x10, 0x20}, fork.CurrentVersion)
+	require.Equal(t, phase0.Epoch(0), fork.Epoch)
+	require.Equal(t, phase0.Root{}, genesisRoot)
+
+	fork, _, err = gc.ForkInfo(40000)
+	require.NoError(t, err)
+	require.Equal(t, phase0.Version{0x00, 0x00, 0x10, 0x20}, fork.PreviousVersion)
+	require.Equal(t, phase0.Version{0x01, 0x00, 0x10, 0x20}, fork.CurrentVersion)
+	require.Equal(t, phase0.Epoch(36660), fork.Epoch)
+}
+
+func TestGoClient_HealthCheck(t *testing.T) {
+	primary := newBeaconStandIn(t)
+	secondary := newBeaconStandIn(t)
+	gc := newTestClient(t, primary.URL, secondary.URL)
+
+	require.Empty(t, gc.HealthCheck())
+
+	// a degraded node is reported even though the other node is healthy
+	primary.syncing.Store(true)
+	issues := gc.HealthCheck()
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0], "beacon node 0")
+	require.Contains(t, issues[0], "currently syncing")
+
+	secondary.Close()
+	issues = gc.HealthCheck()
+	require.Len(t, issues, 2)
+	require.Contains(t, issues[0], "beacon node 0")
+	require.Contains(t, issues[0], "currently syncing")
+	require.Contains(t, issues[1], "beacon node 1")
+	require.Contains(t, issues[1], "could not get sync state")
+}
+
+func TestGoClient_EventsFailover(t *testing.T) {
+	primary := newBeaconStandIn(t)
+	secondary := newBeaconStandIn(t)
+	gc := newTestClient(t, primary.URL, secondary.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan *eth2apiv1.Event, 8)
+	require.NoError(t, gc.Events(ctx, []string{"head"}, func(event *eth2apiv1.Event) {
+		events <- event
+	}))
+
+	receiveHead := func() {
+		select {
+		case event := <-events:
+			require.Equal(t, phase0.Slot(100), event.Data.(*eth2apiv1.HeadEvent).Slot)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no head event received")
+		}
+	}
+	receiveHead()
+	require.EqualValues(t, 1, primary.eventsStreams.Load())
+	require.EqualValues(t, 0, secondary.eventsStreams.Load())
+
+	// a healthy node keeps its subscription
+	gc.checkNodes()
+	gc.resubscribeEvents()
+	require.EqualValues(t, 0, secondary.eventsStreams.Load())
+
+	// the subscription moves to the secondary once the primary isn't healthy
+	primary.syncing.Store(true)
+	gc.checkNodes()
+	gc.resubscribeEvents()
+	receiveHead()
+	require.EqualValues(t, 1, secondary.eventsStreams.Load())
+	require.Eventually(t, func() bool {
+		return primary.eventsStreams.Load() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// cancelled subscriptions are dropped
+	cancel()
+	require.Eventually(t, func() bool {
+		return secondary.eventsStreams.Load() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	gc.resubscribeEvents()
+	require.Empty(t, gc.eventsSubs)
+}
+
+func TestGoClient_NoNodeAvailable(t *testing.T) {
+	bn := newBeaconStandIn(t)
+	addr := bn.URL
+	bn.Close()
+
+	_, err := newClient(logging.TestLogger(t), beaconprotocol.Options{
+		Context:        context.Background(),
+		Network:        "prater",
+		BeaconNodeAddr: addr,
+	}, 1, nil)
+	require.Error(t, err)
+}
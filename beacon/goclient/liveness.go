@@ -0,0 +1,91 @@
+package goclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+)
+
+// livenessResponse is the response of the beacon API liveness endpoint
+type livenessResponse struct {
+	Data []struct {
+		Index  string `json:"index"`
+		IsLive bool   `json:"is_live"`
+	} `json:"data"`
+}
+
+// ValidatorLiveness returns whether each of the given validators was seen performing duties in the given epoch,
+// go-eth2-client doesn't support the liveness endpoint so it is called directly.
+func (gc *goClient) ValidatorLiveness(epoch phase0.Epoch, indices []phase0.ValidatorIndex) (map[phase0.ValidatorIndex]bool, error) {
+	if len(indices) == 0 {
+		return map[phase0.ValidatorIndex]bool{}, nil
+	}
+	body := make([]string, len(indices))
+	for i, index := range indices {
+		body[i] = strconv.FormatUint(uint64(index), 10)
+	}
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal liveness request")
+	}
+
+	var liveness map[phase0.ValidatorIndex]bool
+	err = gc.withBestNode(func(client Client) (err error) {
+		liveness, err = gc.requestLiveness(client.Address(), epoch, reqBody)
+		return err
+	})
+	return liveness, err
+}
+
+func (gc *goClient) requestLiveness(addr string, epoch phase0.Epoch, reqBody []byte) (map[phase0.ValidatorIndex]bool, error) {
+	if !strings.HasPrefix(addr, "http") {
+		addr = fmt.Sprintf("http://%s", addr)
+	}
+	url := fmt.Sprintf("%s/eth/v1/validator/liveness/%d", strings.TrimSuffix(addr, "/"), epoch)
+
+	ctx, cancel := context.WithTimeout(gc.ctx, healthCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create liveness request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not request liveness")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read liveness response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("liveness request failed with status %d: %s", resp.StatusCode, string(raw))
+	}
+
+	var res livenessResponse
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal liveness response")
+	}
+	liveness := make(map[phase0.ValidatorIndex]bool, len(res.Data))
+	for _, v := range res.Data {
+		index, err := strconv.ParseUint(v.Index, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid validator index %q", v.Index)
+		}
+		liveness[phase0.ValidatorIndex(index)] = v.IsLive
+	}
+	return liveness, nil
+}
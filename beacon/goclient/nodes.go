@@ -0,0 +1,344 @@
+package goclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+)
+
+const (
+	// nodeHealthCheckInterval is the interval between two health checks of the beacon nodes
+	nodeHealthCheckInterval = 12 * time.Second
+	// latencyWeight is the weight of the latest request in the moving average of a node's latency
+	latencyWeight = 0.2
+	// latencyTolerance is the latency difference under which nodes are considered equally fast
+	latencyTolerance = 100 * time.Millisecond
+)
+
+var (
+	metricsBeaconNodesStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv_beacon_node_status",
+		Help: "Status of each of the configured beacon nodes",
+	}, []string{"node"})
+	metricsBeaconNodesLatency = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv_beacon_node_latency_seconds",
+		Help: "Moving average of data request duration of each of the configured beacon nodes (seconds)",
+	}, []string{"node"})
+)
+
+var errNotConnected = errors.New("not connected to beacon node")
+
+// connectHTTP connects to a beacon node using go-eth2-client's HTTP implementation
+func connectHTTP(ctx context.Context, addr string) (Client, error) {
+	httpClient, err := http.New(ctx,
+		// WithAddress supplies the address of the beacon node, in host:port format.
+		http.WithAddress(addr),
+		// LogLevel supplies the level of logging to carry out.
+		http.WithLogLevel(zerolog.DebugLevel),
+		http.WithTimeout(time.Second*5),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return httpClient.(*http.Service), nil
+}
+
+// beaconNode is a single consensus client endpoint, along with the state used to score it.
+type beaconNode struct {
+	index int
+	addr  string
+
+	mu           sync.RWMutex
+	client       Client
+	status       beaconNodeStatus
+	headSlot     phase0.Slot
+	syncDistance phase0.Slot
+	latency      time.Duration
+	failures     int
+	lastErr      error
+}
+
+func newBeaconNode(index int, addr string) *beaconNode {
+	return &beaconNode{
+		index:  index,
+		addr:   addr,
+		status: statusUnknown,
+	}
+}
+
+// label is used to identify the node in metrics, addresses are not used as they might contain credentials
+func (n *beaconNode) label() string {
+	return strconv.Itoa(n.index)
+}
+
+func (n *beaconNode) getClient() Client {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.client
+}
+
+func (n *beaconNode) setClient(client Client) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.client = client
+}
+
+// recordSuccess updates the moving average latency of the node
+func (n *beaconNode) recordSuccess(duration time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.latency == 0 {
+		n.latency = duration
+	} else {
+		n.latency = time.Duration(latencyWeight*float64(duration) + (1-latencyWeight)*float64(n.latency))
+	}
+	n.failures = 0
+	n.lastErr = nil
+	metricsBeaconNodesLatency.WithLabelValues(n.label()).Set(n.latency.Seconds())
+}
+
+// recordFailure lowers the score of the node until its next successful request or health check
+func (n *beaconNode) recordFailure(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.failures++
+	n.lastErr = err
+}
+
+// setStatus updates the sync status of the node
+func (n *beaconNode) setStatus(status beaconNodeStatus, headSlot, syncDistance phase0.Slot, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.status = status
+	n.headSlot = headSlot
+	n.syncDistance = syncDistance
+	if err != nil {
+		n.lastErr = err
+	} else if status == statusOK {
+		n.failures = 0
+		n.lastErr = nil
+	}
+	metricsBeaconNodesStatus.WithLabelValues(n.label()).Set(float64(status))
+}
+
+// healthy returns true if the node is synced and its latest request didn't fail
+func (n *beaconNode) healthy() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.client != nil && n.status == statusOK && n.failures == 0
+}
+
+// issue returns a description of the node's problem, or an empty string if the node is healthy
+func (n *beaconNode) issue() string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	prefix := fmt.Sprintf("beacon node %d (%s)", n.index, n.addr)
+	switch {
+	case n.client == nil:
+		return fmt.Sprintf("%s: %s", prefix, errNotConnected)
+	case n.status == statusSyncing:
+		return fmt.Sprintf("%s: currently syncing: head=%d, distance=%d", prefix, n.headSlot, n.syncDistance)
+	case n.status != statusOK:
+		return fmt.Sprintf("%s: could not get sync state: %v", prefix, n.lastErr)
+	case n.failures > 0:
+		return fmt.Sprintf("%s: %d failed requests, last error: %v", prefix, n.failures, n.lastErr)
+	default:
+		return ""
+	}
+}
+
+// less returns true if n should be preferred over other.
+// Nodes are ranked by sync status, then by recent failures and then by latency.
+func (n *beaconNode) less(other *beaconNode) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+
+	if (n.client != nil) != (other.client != nil) {
+		return n.client != nil
+	}
+	if n.status != other.status {
+		return n.status > other.status
+	}
+	if n.failures != other.failures {
+		return n.failures < other.failures
+	}
+	return n.latency/latencyTolerance < other.latency/latencyTolerance
+}
+
+// rankedNodes returns the nodes sorted from the best to the worst.
+// Nodes with an equal score keep the configured order.
+func (gc *goClient) rankedNodes() []*beaconNode {
+	nodes := make([]*beaconNode, len(gc.nodes))
+	copy(nodes, gc.nodes)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].less(nodes[j])
+	})
+	return nodes
+}
+
+// withBestNode calls f with the best available node, failing over to the next node in case of an error.
+func (gc *goClient) withBestNode(f func(client Client) error) error {
+	err := errNotConnected
+	for _, node := range gc.rankedNodes() {
+		client := node.getClient()
+		if client == nil {
+			continue
+		}
+		start := time.Now()
+		if err = f(client); err != nil {
+			node.recordFailure(err)
+			gc.log.Debug("beacon node request failed", zap.Int("node", node.index), zap.Error(err))
+			continue
+		}
+		node.recordSuccess(time.Since(start))
+		return nil
+	}
+	return err
+}
+
+// submitToAll calls f with all connected nodes in parallel,
+// it succeeds if at least one of the nodes accepted the submission.
+func (gc *goClient) submitToAll(f func(client Client) error) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(gc.nodes))
+	for i, node := range gc.nodes {
+		client := node.getClient()
+		if client == nil {
+			errs[i] = errNotConnected
+			continue
+		}
+		wg.Add(1)
+		go func(i int, node *beaconNode, client Client) {
+			defer wg.Done()
+			if err := f(client); err != nil {
+				node.recordFailure(err)
+				errs[i] = err
+			}
+		}(i, node, client)
+	}
+	wg.Wait()
+
+	var lastErr error
+	submitted := 0
+	for i, err := range errs {
+		if err != nil {
+			gc.log.Debug("beacon node submission failed", zap.Int("node", gc.nodes[i].index), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		submitted++
+	}
+	if submitted == 0 {
+		return lastErr
+	}
+	return nil
+}
+
+// connectNodes tries to connect to all the nodes that aren't connected yet
+func (gc *goClient) connectNodes() {
+	var wg sync.WaitGroup
+	for _, node := range gc.nodes {
+		if node.getClient() != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(node *beaconNode) {
+			defer wg.Done()
+			client, err := connectHTTP(gc.ctx, node.addr)
+			if err != nil {
+				node.setStatus(statusUnknown, 0, 0, err)
+				gc.log.Warn("consensus client: could not connect", zap.Int("node", node.index), zap.Error(err))
+				return
+			}
+			node.setClient(client)
+			gc.log.Info("consensus client: connected", zap.Int("node", node.index), fields.Address(node.addr))
+		}(node)
+	}
+	wg.Wait()
+}
+
+// checkNodes updates the sync status of all the nodes
+func (gc *goClient) checkNodes() {
+	gc.connectNodes()
+
+	var wg sync.WaitGroup
+	for _, node := range gc.nodes {
+		client := node.getClient()
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(node *beaconNode, client Client) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(gc.ctx, healthCheckTimeout)
+			defer cancel()
+			syncState, err := client.NodeSyncing(ctx)
+			switch {
+			case err != nil:
+				node.setStatus(statusUnknown, 0, 0, err)
+			case syncState != nil && syncState.IsSyncing:
+				node.setStatus(statusSyncing, syncState.HeadSlot, syncState.SyncDistance, nil)
+			default:
+				var headSlot phase0.Slot
+				if syncState != nil {
+					headSlot = syncState.HeadSlot
+				}
+				node.setStatus(statusOK, headSlot, 0, nil)
+			}
+		}(node, client)
+	}
+	wg.Wait()
+
+	metricsBeaconNodeStatus.Set(float64(gc.bestStatus()))
+}
+
+// bestStatus returns the status of the best node
+func (gc *goClient) bestStatus() beaconNodeStatus {
+	best := statusUnknown
+	for _, node := range gc.nodes {
+		node.mu.RLock()
+		if node.client != nil && node.status > best {
+			best = node.status
+		}
+		node.mu.RUnlock()
+	}
+	return best
+}
+
+// monitorNodes periodically checks the health of the nodes, until the context is done
+func (gc *goClient) monitorNodes() {
+	ticker := time.NewTicker(nodeHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-gc.ctx.Done():
+			return
+		case <-ticker.C:
+			gc.checkNodes()
+			gc.resubscribeEvents()
+		}
+	}
+}
@@ -30,7 +30,11 @@ func (gc *goClient) GetBeaconBlock(slot phase0.Slot, graffiti, randao []byte) (s
 	copy(sig[:], randao[:])
 
 	reqStart := time.Now()
-	beaconBlock, err := gc.client.BeaconBlockProposal(gc.ctx, slot, sig, graffiti)
+	var beaconBlock *spec.VersionedBeaconBlock
+	err := gc.withBestNode(func(client Client) (err error) {
+		beaconBlock, err = client.BeaconBlockProposal(gc.ctx, slot, sig, graffiti)
+		return err
+	})
 	if err != nil {
 		return nil, DataVersionNil, err
 	}
@@ -80,7 +84,11 @@ func (gc *goClient) GetBlindedBeaconBlock(slot phase0.Slot, graffiti, randao []b
 	copy(sig[:], randao[:])
 
 	reqStart := time.Now()
-	beaconBlock, err := gc.client.BlindedBeaconBlockProposal(gc.ctx, slot, sig, graffiti)
+	var beaconBlock *api.VersionedBlindedBeaconBlock
+	err := gc.withBestNode(func(client Client) (err error) {
+		beaconBlock, err = client.BlindedBeaconBlockProposal(gc.ctx, slot, sig, graffiti)
+		return err
+	})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -153,7 +161,9 @@ func (gc *goClient) SubmitBlindedBeaconBlock(block *api.VersionedBlindedBeaconBl
 		return errors.New("unknown block version")
 	}
 
-	return gc.client.SubmitBlindedBeaconBlock(gc.ctx, signedBlock)
+	return gc.submitToAll(func(client Client) error {
+		return client.SubmitBlindedBeaconBlock(gc.ctx, signedBlock)
+	})
 }
 
 // SubmitBeaconBlock submit the block to the node
@@ -206,7 +216,9 @@ func (gc *goClient) SubmitBeaconBlock(block *spec.VersionedBeaconBlock, sig phas
 		return errors.New("unknown block version")
 	}
 
-	return gc.client.SubmitBeaconBlock(gc.ctx, signedBlock)
+	return gc.submitToAll(func(client Client) error {
+		return client.SubmitBeaconBlock(gc.ctx, signedBlock)
+	})
 }
 
 func (gc *goClient) SubmitValidatorRegistration(pubkey []byte, feeRecipient bellatrix.ExecutionAddress, sig phase0.BLSSignature) error {
@@ -221,7 +233,9 @@ func (gc *goClient) SubmitProposalPreparation(feeRecipients map[phase0.Validator
 			FeeRecipient:   recipient,
 		})
 	}
-	return gc.client.SubmitProposalPreparations(gc.ctx, preparations)
+	return gc.submitToAll(func(client Client) error {
+		return client.SubmitProposalPreparations(gc.ctx, preparations)
+	})
 }
 
 func (gc *goClient) updateBatchRegistrationCache(registration *api.VersionedSignedValidatorRegistration) error {
@@ -319,7 +333,10 @@ func (gc *goClient) submitBatchedRegistrations(slot phase0.Slot, registrations [
 			bs = len(registrations)
 		}
 
-		if err := gc.client.SubmitValidatorRegistrations(gc.ctx, registrations[0:bs]); err != nil {
+		batch := registrations[0:bs]
+		if err := gc.submitToAll(func(client Client) error {
+			return client.SubmitValidatorRegistrations(gc.ctx, batch)
+		}); err != nil {
 			return err
 		}
 
@@ -15,7 +15,7 @@ func (gc *goClient) DomainData(epoch phase0.Epoch, domain phase0.DomainType) (ph
 	if domain == spectypes.DomainApplicationBuilder { // no domain for DomainApplicationBuilder. need to create.  https://github.com/bloxapp/ethereum2-validator/blob/v2-main/signing/keyvault/signer.go#L62
 		var appDomain phase0.Domain
 		forkData := phase0.ForkData{
-			CurrentVersion:        GenesisForkVersion,
+			CurrentVersion:        gc.network.GenesisForkVersion(),
 			GenesisValidatorsRoot: phase0.Root{},
 		}
 		root, err := forkData.HashTreeRoot()
@@ -27,7 +27,11 @@ func (gc *goClient) DomainData(epoch phase0.Epoch, domain phase0.DomainType) (ph
 		return appDomain, nil
 	}
 
-	data, err := gc.client.Domain(gc.ctx, domain, epoch)
+	var data phase0.Domain
+	err := gc.withBestNode(func(client Client) (err error) {
+		data, err = client.Domain(gc.ctx, domain, epoch)
+		return err
+	})
 	if err != nil {
 		return phase0.Domain{}, err
 	}
@@ -15,7 +15,11 @@ func (gc *goClient) GetSyncMessageBlockRoot(slot phase0.Slot) (phase0.Root, spec
 	gc.waitOneThirdOrValidBlock(slot)
 
 	reqStart := time.Now()
-	root, err := gc.client.BeaconBlockRoot(gc.ctx, "head")
+	var root *phase0.Root
+	err := gc.withBestNode(func(client Client) (err error) {
+		root, err = client.BeaconBlockRoot(gc.ctx, "head")
+		return err
+	})
 	if err != nil {
 		return phase0.Root{}, DataVersionNil, err
 	}
@@ -30,8 +34,7 @@ func (gc *goClient) GetSyncMessageBlockRoot(slot phase0.Slot) (phase0.Root, spec
 
 // SubmitSyncMessage submits a signed sync committee msg
 func (gc *goClient) SubmitSyncMessage(msg *altair.SyncCommitteeMessage) error {
-	if err := gc.client.SubmitSyncCommitteeMessages(gc.ctx, []*altair.SyncCommitteeMessage{msg}); err != nil {
-		return err
-	}
-	return nil
+	return gc.submitToAll(func(client Client) error {
+		return client.SubmitSyncCommitteeMessages(gc.ctx, []*altair.SyncCommitteeMessage{msg})
+	})
 }
@@ -43,7 +43,11 @@ func (gc *goClient) GetSyncCommitteeContribution(slot phase0.Slot, selectionProo
 	gc.waitOneThirdOrValidBlock(slot)
 
 	scDataReqStart := time.Now()
-	blockRoot, err := gc.client.BeaconBlockRoot(gc.ctx, fmt.Sprint(slot))
+	var blockRoot *phase0.Root
+	err := gc.withBestNode(func(client Client) (err error) {
+		blockRoot, err = client.BeaconBlockRoot(gc.ctx, fmt.Sprint(slot))
+		return err
+	})
 	if err != nil {
 		return nil, DataVersionNil, err
 	}
@@ -64,7 +68,11 @@ func (gc *goClient) GetSyncCommitteeContribution(slot phase0.Slot, selectionProo
 	for i := range subnetIDs {
 		index := i
 		g.Go(func() error {
-			contribution, err := gc.client.SyncCommitteeContribution(gc.ctx, slot, subnetIDs[index], *blockRoot)
+			var contribution *altair.SyncCommitteeContribution
+			err := gc.withBestNode(func(client Client) (err error) {
+				contribution, err = client.SyncCommitteeContribution(gc.ctx, slot, subnetIDs[index], *blockRoot)
+				return err
+			})
 			if err != nil {
 				return err
 			}
@@ -89,5 +97,7 @@ func (gc *goClient) GetSyncCommitteeContribution(slot phase0.Slot, selectionProo
 
 // SubmitSignedContributionAndProof broadcasts to the network
 func (gc *goClient) SubmitSignedContributionAndProof(contribution *altair.SignedContributionAndProof) error {
-	return gc.client.SubmitSyncCommitteeContributions(gc.ctx, []*altair.SignedContributionAndProof{contribution})
+	return gc.submitToAll(func(client Client) error {
+		return client.SubmitSyncCommitteeContributions(gc.ctx, []*altair.SignedContributionAndProof{contribution})
+	})
 }
@@ -7,5 +7,10 @@ import (
 
 // GetValidatorData returns metadata (balance, index, status, more) for each pubkey from the node
 func (gc *goClient) GetValidatorData(validatorPubKeys []phase0.BLSPubKey) (map[phase0.ValidatorIndex]*eth2apiv1.Validator, error) {
-	return gc.client.ValidatorsByPubKey(gc.ctx, "head", validatorPubKeys) // TODO maybe need to get the chainId (head) as var
+	var validators map[phase0.ValidatorIndex]*eth2apiv1.Validator
+	err := gc.withBestNode(func(client Client) (err error) {
+		validators, err = client.ValidatorsByPubKey(gc.ctx, "head", validatorPubKeys) // TODO maybe need to get the chainId (head) as var
+		return err
+	})
+	return validators, err
 }
@@ -0,0 +1,12 @@
+package goclient
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// SubmitVoluntaryExit submits a signed voluntary exit to all beacon nodes
+func (gc *goClient) SubmitVoluntaryExit(voluntaryExit *phase0.SignedVoluntaryExit) error {
+	return gc.submitToAll(func(client Client) error {
+		return client.SubmitVoluntaryExit(gc.ctx, voluntaryExit)
+	})
+}
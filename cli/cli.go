@@ -32,4 +32,6 @@ func Execute(appName, version string) {
 func init() {
 	RootCmd.AddCommand(bootnode.StartBootNodeCmd)
 	RootCmd.AddCommand(operator.StartNodeCmd)
+	RootCmd.AddCommand(operator.ExportSlashingProtectionCmd)
+	RootCmd.AddCommand(operator.ImportSlashingProtectionCmd)
 }
@@ -0,0 +1,33 @@
+package flags
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bloxapp/ssv/utils/cliflag"
+)
+
+// Flag names.
+const (
+	keystoreFileFlag = "keystore-file"
+	passwordFileFlag = "password-file"
+)
+
+// AddKeystoreFileFlag adds the operator keystore file flag to the command
+func AddKeystoreFileFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, keystoreFileFlag, "encrypted_private_key.json", "Path of the operator private key keystore file", false)
+}
+
+// GetKeystoreFileFlagValue gets the operator keystore file flag from the command
+func GetKeystoreFileFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(keystoreFileFlag)
+}
+
+// AddPasswordFileFlag adds the operator keystore password file flag to the command
+func AddPasswordFileFlag(c *cobra.Command) {
+	cliflag.AddPersistentStringFlag(c, passwordFileFlag, "", "Path to a file of the password of the operator private key keystore", true)
+}
+
+// GetPasswordFileFlagValue gets the operator keystore password file flag from the command
+func GetPasswordFileFlagValue(c *cobra.Command) (string, error) {
+	return c.Flags().GetString(passwordFileFlag)
+}
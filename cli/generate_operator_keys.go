@@ -4,13 +4,15 @@ import (
 	"encoding/base64"
 	"log"
 
+	"github.com/bloxapp/ssv/cli/flags"
 	"github.com/bloxapp/ssv/logging"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
-// generateOperatorKeysCmd is the command to generate operator private/public keys
+// generateOperatorKeysCmd is the command to generate operator private/public keys,
+// the private key is only written to a keystore that is encrypted with the given password
 var generateOperatorKeysCmd = &cobra.Command{
 	Use:   "generate-operator-keys",
 	Short: "generates ssv operator keys",
@@ -20,15 +22,41 @@ var generateOperatorKeysCmd = &cobra.Command{
 		}
 		logger := zap.L().Named(RootCmd.Short)
 
+		passwordFile, err := flags.GetPasswordFileFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get password file flag value", zap.Error(err))
+		}
+		if len(passwordFile) == 0 {
+			logger.Fatal("password file is required to encrypt the private key keystore")
+		}
+
 		pk, sk, err := rsaencryption.GenerateKeys()
 		if err != nil {
 			logger.Fatal("Failed to generate operator keys", zap.Error(err))
 		}
 		logger.Info("generated public key (base64)", zap.String("pk", base64.StdEncoding.EncodeToString(pk)))
-		logger.Info("generated private key (base64)", zap.String("sk", base64.StdEncoding.EncodeToString(sk)))
+
+		keystoreFile, err := flags.GetKeystoreFileFlagValue(cmd)
+		if err != nil {
+			logger.Fatal("failed to get keystore file flag value", zap.Error(err))
+		}
+		password, err := rsaencryption.ReadPasswordFile(passwordFile)
+		if err != nil {
+			logger.Fatal("failed to read password file", zap.Error(err))
+		}
+		ks, err := rsaencryption.EncryptKeystore(sk, password)
+		if err != nil {
+			logger.Fatal("failed to encrypt private key", zap.Error(err))
+		}
+		if err := rsaencryption.WriteKeystore(keystoreFile, ks); err != nil {
+			logger.Fatal("failed to write keystore", zap.Error(err))
+		}
+		logger.Info("private key keystore saved", zap.String("file", keystoreFile))
 	},
 }
 
 func init() {
+	flags.AddPasswordFileFlag(generateOperatorKeysCmd)
+	flags.AddKeystoreFileFlag(generateOperatorKeysCmd)
 	RootCmd.AddCommand(generateOperatorKeysCmd)
 }
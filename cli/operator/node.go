@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
-	"github.com/bloxapp/eth2-key-manager/core"
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/pkg/errors"
@@ -26,12 +25,16 @@ import (
 	"github.com/bloxapp/ssv/exporter/api"
 	"github.com/bloxapp/ssv/exporter/api/decided"
 	ssv_identity "github.com/bloxapp/ssv/identity"
+	"github.com/bloxapp/ssv/message/validation"
 	"github.com/bloxapp/ssv/migrations"
 	"github.com/bloxapp/ssv/monitoring/metrics"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/forks"
 	forksfactory "github.com/bloxapp/ssv/network/forks/factory"
 	p2pv1 "github.com/bloxapp/ssv/network/p2p"
+	"github.com/bloxapp/ssv/network/peers"
 	"github.com/bloxapp/ssv/network/records"
+	"github.com/bloxapp/ssv/network/topics"
 	"github.com/bloxapp/ssv/operator"
 	"github.com/bloxapp/ssv/operator/slot_ticker"
 	operatorstorage "github.com/bloxapp/ssv/operator/storage"
@@ -44,6 +47,7 @@ import (
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/utils/commons"
 	"github.com/bloxapp/ssv/utils/format"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
 )
 
 type config struct {
@@ -53,16 +57,24 @@ type config struct {
 	ETH1Options                eth1.Options           `yaml:"eth1"`
 	ETH2Options                beaconprotocol.Options `yaml:"eth2"`
 	P2pNetworkConfig           p2pv1.Config           `yaml:"p2p"`
+	KeyManagerOptions          ekm.Options            `yaml:"KeyManager"`
 
 	OperatorPrivateKey         string `yaml:"OperatorPrivateKey" env:"OPERATOR_KEY" env-description:"Operator private key, used to decrypt contract events"`
 	GenerateOperatorPrivateKey bool   `yaml:"GenerateOperatorPrivateKey" env:"GENERATE_OPERATOR_KEY" env-description:"Whether to generate operator key if none is passed by config"`
-	MetricsAPIPort             int    `yaml:"MetricsAPIPort" env:"METRICS_API_PORT" env-description:"port of metrics api"`
-	EnableProfile              bool   `yaml:"EnableProfile" env:"ENABLE_PROFILE" env-description:"flag that indicates whether go profiling tools are enabled"`
-	NetworkPrivateKey          string `yaml:"NetworkPrivateKey" env:"NETWORK_PRIVATE_KEY" env-description:"private key for network identity"`
+	KeyStore                   struct {
+		PrivateKeyFile string `yaml:"PrivateKeyFile" env:"PRIVATE_KEY_FILE" env-description:"Operator private key keystore file"`
+		PasswordFile   string `yaml:"PasswordFile" env:"PASSWORD_FILE" env-description:"File of the password of the operator private key keystore"`
+	} `yaml:"KeyStore"`
+	MetricsAPIPort    int    `yaml:"MetricsAPIPort" env:"METRICS_API_PORT" env-description:"port of metrics api"`
+	EnableProfile     bool   `yaml:"EnableProfile" env:"ENABLE_PROFILE" env-description:"flag that indicates whether go profiling tools are enabled"`
+	NetworkPrivateKey string `yaml:"NetworkPrivateKey" env:"NETWORK_PRIVATE_KEY" env-description:"private key for network identity"`
 
 	WsAPIPort int  `yaml:"WebSocketAPIPort" env:"WS_API_PORT" env-description:"port of WS API"`
 	WithPing  bool `yaml:"WithPing" env:"WITH_PING" env-description:"Whether to send websocket ping messages'"`
 
+	SSVAPIPort      int    `yaml:"SSVAPIPort" env:"SSV_API_PORT" env-description:"port of the node REST API"`
+	SSVAPIExitToken string `yaml:"SSVAPIExitToken" env:"SSV_API_EXIT_TOKEN" env-description:"Bearer token that authorizes voluntary exits through the node REST API, exits can't be requested if empty"`
+
 	LocalEventsPath string `yaml:"LocalEventsPath" env:"EVENTS_PATH" env-description:"path to local events"`
 }
 
@@ -92,11 +104,6 @@ var StartNodeCmd = &cobra.Command{
 		}
 		nodeStorage, operatorData := setupOperatorStorage(logger, db)
 
-		keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, eth2Network, types.GetDefaultDomain(), cfg.SSVOptions.ValidatorOptions.BuilderProposals)
-		if err != nil {
-			logger.Fatal("could not create new eth-key-manager signer", zap.Error(err))
-		}
-
 		cfg.P2pNetworkConfig.Ctx = cmd.Context()
 
 		permissioned := func() bool {
@@ -107,13 +114,15 @@ var StartNodeCmd = &cobra.Command{
 		cfg.P2pNetworkConfig.Permissioned = permissioned
 		cfg.P2pNetworkConfig.WhitelistedOperatorKeys = append(cfg.P2pNetworkConfig.WhitelistedOperatorKeys, p2pv1.StageExporterPubkeys...) // TODO: get whitelisted from network config
 
-		p2pNetwork := setupP2P(forkVersion, operatorData, db, logger)
+		p2pNetwork := setupP2P(forkVersion, operatorData, nodeStorage, db, logger, eth2Network)
 
 		ctx := cmd.Context()
 		slotTicker := slot_ticker.NewTicker(ctx, eth2Network, phase0.Epoch(cfg.SSVOptions.GenesisEpoch))
 
 		cfg.ETH2Options.Context = cmd.Context()
-		el, cl := setupNodes(logger, operatorData.ID, slotTicker)
+		el, cl := setupNodes(logger, operatorData.ID, slotTicker, nodeStorage)
+
+		keyManager := setupKeyManager(logger, db, eth2Network, el)
 
 		cfg.SSVOptions.ForkVersion = forkVersion
 		cfg.SSVOptions.Context = ctx
@@ -133,7 +142,12 @@ var StartNodeCmd = &cobra.Command{
 		cfg.SSVOptions.ValidatorOptions.ShareEncryptionKeyProvider = nodeStorage.GetPrivateKey
 		cfg.SSVOptions.ValidatorOptions.OperatorData = operatorData
 		cfg.SSVOptions.ValidatorOptions.RegistryStorage = nodeStorage
+		if cfg.P2pNetworkConfig.ValidatorsFilter != nil {
+			// the shares of non-committee messages were already cached by the filter of pubsub messages
+			cfg.SSVOptions.ValidatorOptions.SharesCache = cfg.P2pNetworkConfig.ValidatorsFilter
+		}
 		cfg.SSVOptions.ValidatorOptions.GasLimit = cfg.ETH2Options.GasLimit
+		cfg.SSVOptions.ValidatorOptions.SlotTicker = slotTicker
 
 		cfg.SSVOptions.Eth1Client = cl
 
@@ -144,6 +158,12 @@ var StartNodeCmd = &cobra.Command{
 			cfg.SSVOptions.ValidatorOptions.NewDecidedHandler = decided.NewStreamPublisher(logger, ws)
 		}
 
+		cfg.SSVOptions.APIPort = cfg.SSVAPIPort
+		cfg.SSVOptions.APIExitToken = cfg.SSVAPIExitToken
+		if cfg.SSVOptions.ValidatorOptions.VoluntaryExits && (cfg.SSVAPIPort == 0 || len(cfg.SSVAPIExitToken) == 0) {
+			logger.Warn("voluntary exits are enabled, but can't be requested without the node API and its exit token")
+		}
+
 		cfg.SSVOptions.ValidatorOptions.DutyRoles = []spectypes.BeaconRole{spectypes.BNRoleAttester} // TODO could be better to set in other place
 		validatorCtrl := validator.NewController(logger, cfg.SSVOptions.ValidatorOptions)
 		cfg.SSVOptions.ValidatorController = validatorCtrl
@@ -227,9 +247,11 @@ func setupDb(logger *zap.Logger, eth2Network beaconprotocol.Network) (basedb.IDb
 	}
 
 	migrationOpts := migrations.Options{
-		Db:      db,
-		DbPath:  cfg.DBOptions.Path,
-		Network: eth2Network,
+		Db:                   db,
+		DbPath:               cfg.DBOptions.Path,
+		Network:              eth2Network,
+		OperatorKeystoreFile: cfg.KeyStore.PrivateKeyFile,
+		OperatorPasswordFile: cfg.KeyStore.PasswordFile,
 	}
 	applied, err := migrations.Run(cfg.DBOptions.Ctx, logger, migrationOpts)
 	if err != nil {
@@ -268,9 +290,25 @@ func setupDb(logger *zap.Logger, eth2Network beaconprotocol.Network) (basedb.IDb
 
 func setupOperatorStorage(logger *zap.Logger, db basedb.IDb) (operatorstorage.Storage, *registrystorage.OperatorData) {
 	nodeStorage := operatorstorage.NewNodeStorage(db)
-	operatorPubKey, err := nodeStorage.SetupPrivateKey(logger, cfg.OperatorPrivateKey, cfg.GenerateOperatorPrivateKey)
-	if err != nil {
-		logger.Fatal("could not setup operator private key", zap.Error(err))
+	var operatorPubKey []byte
+	var err error
+	if len(cfg.KeyStore.PrivateKeyFile) > 0 {
+		if len(cfg.OperatorPrivateKey) > 0 {
+			logger.Fatal("operator private key and keystore can't be configured together")
+		}
+		sk, err := rsaencryption.LoadKeystore(cfg.KeyStore.PrivateKeyFile, cfg.KeyStore.PasswordFile)
+		if err != nil {
+			logger.Fatal("could not load operator private key keystore", zap.Error(err))
+		}
+		operatorPubKey, err = nodeStorage.SetupKeystorePrivateKey(logger, sk)
+		if err != nil {
+			logger.Fatal("could not setup operator private key", zap.Error(err))
+		}
+	} else {
+		operatorPubKey, err = nodeStorage.SetupPrivateKey(logger, cfg.OperatorPrivateKey, cfg.GenerateOperatorPrivateKey)
+		if err != nil {
+			logger.Fatal("could not setup operator private key", zap.Error(err))
+		}
 	}
 
 	_, found, err := nodeStorage.GetPrivateKey()
@@ -302,10 +340,13 @@ func setupSSVNetwork(logger *zap.Logger) (beaconprotocol.Network, forksprotocol.
 		}
 		types.SetDefaultDomain(spectypes.DomainType(domainType))
 	}
-	eth2Network := beaconprotocol.NewNetwork(core.NetworkFromString(cfg.ETH2Options.Network), cfg.ETH2Options.MinGenesisTime)
+	eth2Network, err := beaconprotocol.NetworkFromOptions(cfg.ETH2Options)
+	if err != nil {
+		logger.Fatal("failed to resolve beacon network", zap.Error(err))
+	}
 
 	currentEpoch := eth2Network.EstimatedCurrentEpoch()
-	forkVersion := forksprotocol.GetCurrentForkVersion(currentEpoch)
+	forkVersion := cfg.SSVOptions.ForkEpochs().GetCurrentForkVersion(currentEpoch)
 
 	logger.Info("setting ssv network", fields.Domain(types.GetDefaultDomain()),
 		fields.NetworkID(cfg.P2pNetworkConfig.NetworkID),
@@ -313,14 +354,30 @@ func setupSSVNetwork(logger *zap.Logger) (beaconprotocol.Network, forksprotocol.
 	return eth2Network, forkVersion
 }
 
-func setupP2P(forkVersion forksprotocol.ForkVersion, operatorData *registrystorage.OperatorData, db basedb.IDb, logger *zap.Logger) network.P2PNetwork {
+func setupKeyManager(logger *zap.Logger, db basedb.IDb, network beaconprotocol.Network, forkInfo ekm.ForkInfoProvider) spectypes.KeyManager {
+	if len(cfg.KeyManagerOptions.RemoteSignerURL) > 0 {
+		keyManager, err := ekm.NewRemoteKeyManager(logger, db, network, forkInfo, types.GetDefaultDomain(), cfg.SSVOptions.ValidatorOptions.BuilderProposals, cfg.KeyManagerOptions)
+		if err != nil {
+			logger.Fatal("could not create remote key manager", zap.Error(err))
+		}
+		return keyManager
+	}
+	keyManager, err := ekm.NewETHKeyManagerSigner(logger, db, network, types.GetDefaultDomain(), cfg.SSVOptions.ValidatorOptions.BuilderProposals)
+	if err != nil {
+		logger.Fatal("could not create new eth-key-manager signer", zap.Error(err))
+	}
+	return keyManager
+}
+
+func setupP2P(forkVersion forksprotocol.ForkVersion, operatorData *registrystorage.OperatorData, nodeStorage operatorstorage.Storage, db basedb.IDb, logger *zap.Logger, eth2Network beaconprotocol.Network) network.P2PNetwork {
 	istore := ssv_identity.NewIdentityStore(db)
 	netPrivKey, err := istore.SetupNetworkKey(logger, cfg.NetworkPrivateKey)
 	if err != nil {
 		logger.Fatal("failed to setup network private key", zap.Error(err))
 	}
 
-	cfg.P2pNetworkConfig.NodeStorage = operatorstorage.NewNodeStorage(db)
+	// the operator key may be held in memory only, so p2p must use the same storage instance
+	cfg.P2pNetworkConfig.NodeStorage = nodeStorage
 	if len(cfg.P2pNetworkConfig.Subnets) == 0 {
 		subnets := getNodeSubnets(logger, cfg.P2pNetworkConfig.NodeStorage.GetFilteredShares, forkVersion, operatorData.ID)
 		cfg.P2pNetworkConfig.Subnets = subnets.String()
@@ -330,18 +387,42 @@ func setupP2P(forkVersion forksprotocol.ForkVersion, operatorData *registrystora
 	cfg.P2pNetworkConfig.ForkVersion = forkVersion
 	cfg.P2pNetworkConfig.OperatorID = format.OperatorID(operatorData.PublicKey)
 	cfg.P2pNetworkConfig.FullNode = cfg.SSVOptions.ValidatorOptions.FullNode
+	var shares validation.SharesStorage = cfg.P2pNetworkConfig.NodeStorage
+	if cfg.P2pNetworkConfig.ValidatorsFilterCacheSize > 0 {
+		validatorsFilter, err := topics.NewValidatorsFilter(cfg.P2pNetworkConfig.NodeStorage, cfg.P2pNetworkConfig.ValidatorsFilterCacheSize)
+		if err != nil {
+			logger.Fatal("failed to create validators filter", zap.Error(err))
+		}
+		cfg.P2pNetworkConfig.ValidatorsFilter = validatorsFilter
+		// the message validator shares the cache of the filter
+		shares = validatorsFilter
+	}
+	if cfg.P2pNetworkConfig.ReputationTTL > 0 {
+		cfg.P2pNetworkConfig.ReputationStore = peers.NewReputationStore(db, cfg.P2pNetworkConfig.ReputationTTL)
+	}
+	validationMode, err := validation.ParseMode(cfg.P2pNetworkConfig.MessageValidation)
+	if err != nil {
+		logger.Fatal("invalid message validation mode", zap.Error(err))
+	}
+	if validationMode != validation.ModeDisabled {
+		cfg.P2pNetworkConfig.MessageValidator = validation.NewMessageValidator(validation.Options{
+			Network: eth2Network,
+			Shares:  shares,
+			Mode:    validationMode,
+		})
+	}
 
 	return p2pv1.New(logger, &cfg.P2pNetworkConfig)
 }
 
-func setupNodes(logger *zap.Logger, operatorID spectypes.OperatorID, slotTicker slot_ticker.Ticker) (beaconprotocol.Beacon, eth1.Client) {
+func setupNodes(logger *zap.Logger, operatorID spectypes.OperatorID, slotTicker slot_ticker.Ticker, recentLogsStorage eth1.RecentLogsStorage) (beaconprotocol.Beacon, eth1.Client) {
 	// consensus client
 	cfg.ETH2Options.Graffiti = []byte("SSV.Network")
 	cfg.ETH2Options.GasLimit = spectypes.DefaultGasLimit
 	cl, err := goclient.New(logger, cfg.ETH2Options, operatorID, slotTicker)
 	if err != nil {
 		logger.Fatal("failed to create beacon go-client", zap.Error(err),
-			fields.Address(cfg.ETH2Options.BeaconNodeAddr))
+			zap.Strings("addresses", cfg.ETH2Options.BeaconNodeAddresses()))
 	}
 
 	// execution client
@@ -354,8 +435,12 @@ func setupNodes(logger *zap.Logger, operatorID spectypes.OperatorID, slotTicker
 	}
 	el, err := goeth.NewEth1Client(logger, goeth.ClientOptions{
 		Ctx:                  cfg.ETH2Options.Context,
-		NodeAddr:             cfg.ETH1Options.ETH1Addr,
+		NodeAddrs:            cfg.ETH1Options.Addresses(),
 		ConnectionTimeout:    cfg.ETH1Options.ETH1ConnectionTimeout,
+		PollingInterval:      cfg.ETH1Options.ETH1PollingInterval,
+		FollowDistance:       cfg.ETH1Options.ETH1FollowDistance,
+		FollowFinalized:      cfg.ETH1Options.ETH1FollowFinalized,
+		RecentLogsStorage:    recentLogsStorage,
 		ContractABI:          eth1.ContractABI(cfg.ETH1Options.AbiVersion),
 		RegistryContractAddr: cfg.ETH1Options.RegistryContractAddr,
 		AbiVersion:           cfg.ETH1Options.AbiVersion,
@@ -378,7 +463,7 @@ func startMetricsHandler(ctx context.Context, logger *zap.Logger, db basedb.IDb,
 	}
 }
 
-// getNodeSubnets reads all shares and calculates the subnets for this node
+// getNodeSubnets reads all shares and calculates the subnets for this node, according to the given fork
 // note that we'll trigger another update once finished processing registry events
 func getNodeSubnets(
 	logger *zap.Logger,
@@ -393,6 +478,20 @@ func getNodeSubnets(
 		logger.Warn("could not read validators to bootstrap subnets")
 		return nil
 	}
+	if committeeAware, ok := f.(forks.CommitteeAware); ok {
+		committees := make(map[string][]spectypes.OperatorID, len(shares))
+		for _, share := range shares {
+			committee := make([]spectypes.OperatorID, 0, len(share.Committee))
+			for _, operator := range share.Committee {
+				committee = append(committee, operator.OperatorID)
+			}
+			committees[hex.EncodeToString(share.ValidatorPubKey)] = committee
+		}
+		committeeAware.SetCommitteeResolver(func(pk []byte) ([]spectypes.OperatorID, bool) {
+			committee, ok := committees[hex.EncodeToString(pk)]
+			return committee, ok
+		})
+	}
 	for _, share := range shares {
 		subnet := f.ValidatorSubnet(hex.EncodeToString(share.ValidatorPubKey))
 		if subnet < 0 {
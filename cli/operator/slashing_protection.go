@@ -0,0 +1,128 @@
+package operator
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	global_config "github.com/bloxapp/ssv/cli/config"
+	"github.com/bloxapp/ssv/cli/flags"
+	"github.com/bloxapp/ssv/ekm"
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// ExportSlashingProtectionCmd is the command to export the slashing protection of all shares
+// to an EIP-3076 interchange file
+var ExportSlashingProtectionCmd = &cobra.Command{
+	Use:   "export-slashing-protection",
+	Short: "Exports the slashing protection of all shares to an EIP-3076 interchange file",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal(cmd)
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+		logger = logger.Named(logging.NameSlashingProtection)
+
+		path, gvr := slashingProtectionFlags(logger, cmd)
+		eth2Network, _ := setupSSVNetwork(logger)
+		cfg.DBOptions.Ctx = cmd.Context()
+		db, err := openSlashingProtectionDb(logger)
+		if err != nil {
+			logger.Fatal("could not open db", zap.Error(err))
+		}
+		defer func() {
+			_ = db.Close(logger)
+		}()
+
+		interchange, err := ekm.ExportSlashingProtection(ekm.NewSignerStorage(db, eth2Network, logger), gvr)
+		if err != nil {
+			logger.Fatal("could not export slashing protection", zap.Error(err))
+		}
+		raw, err := json.MarshalIndent(interchange, "", "  ")
+		if err != nil {
+			logger.Fatal("could not marshal interchange", zap.Error(err))
+		}
+		if err := os.WriteFile(filepath.Clean(path), raw, 0600); err != nil {
+			logger.Fatal("could not write interchange file", zap.Error(err))
+		}
+		logger.Info("exported slashing protection", zap.String("file", path), zap.Int("shares", len(interchange.Data)))
+	},
+}
+
+// ImportSlashingProtectionCmd is the command to import the slashing protection of shares
+// from an EIP-3076 interchange file, merged with the existing records
+var ImportSlashingProtectionCmd = &cobra.Command{
+	Use:   "import-slashing-protection",
+	Short: "Imports the slashing protection of shares from an EIP-3076 interchange file",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger, err := setupGlobal(cmd)
+		if err != nil {
+			log.Fatal("could not create logger", err)
+		}
+		logger = logger.Named(logging.NameSlashingProtection)
+
+		path, gvr := slashingProtectionFlags(logger, cmd)
+		raw, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			logger.Fatal("could not read interchange file", zap.Error(err))
+		}
+		interchange := &ekm.Interchange{}
+		if err := json.Unmarshal(raw, interchange); err != nil {
+			logger.Fatal("could not unmarshal interchange", zap.Error(err))
+		}
+
+		eth2Network, _ := setupSSVNetwork(logger)
+		cfg.DBOptions.Ctx = cmd.Context()
+		db, err := openSlashingProtectionDb(logger)
+		if err != nil {
+			logger.Fatal("could not open db", zap.Error(err))
+		}
+		defer func() {
+			_ = db.Close(logger)
+		}()
+
+		imported, err := ekm.ImportSlashingProtection(logger, ekm.NewSignerStorage(db, eth2Network, logger), interchange, gvr)
+		if err != nil {
+			logger.Fatal("could not import slashing protection", zap.Error(err))
+		}
+		logger.Info("imported slashing protection", zap.String("file", path), zap.Int("public_keys", imported))
+	},
+}
+
+// openSlashingProtectionDb opens the node db without running the pending migrations,
+// so that an offline export or import doesn't change unrelated db state.
+// no migration changes the slashing protection records.
+func openSlashingProtectionDb(logger *zap.Logger) (basedb.IDb, error) {
+	return storage.GetStorageFactory(logger, cfg.DBOptions)
+}
+
+// slashingProtectionFlags returns the interchange file path and the genesis validators root from the command flags
+func slashingProtectionFlags(logger *zap.Logger, cmd *cobra.Command) (string, [32]byte) {
+	path, err := flags.GetInterchangeFileFlagValue(cmd)
+	if err != nil {
+		logger.Fatal("failed to get interchange file flag value", zap.Error(err))
+	}
+	gvrStr, err := flags.GetGenesisValidatorsRootFlagValue(cmd)
+	if err != nil {
+		logger.Fatal("failed to get genesis validators root flag value", zap.Error(err))
+	}
+	gvr, err := ekm.ParseGenesisValidatorsRoot(gvrStr)
+	if err != nil {
+		logger.Fatal("invalid genesis validators root", zap.Error(err))
+	}
+	return path, gvr
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{ExportSlashingProtectionCmd, ImportSlashingProtectionCmd} {
+		global_config.ProcessArgs(&cfg, &globalArgs, cmd)
+		flags.AddInterchangeFileFlag(cmd)
+		flags.AddGenesisValidatorsRootFlag(cmd)
+	}
+}
@@ -45,6 +45,10 @@ type ethKeyManagerSigner struct {
 
 // NewETHKeyManagerSigner returns a new instance of ethKeyManagerSigner
 func NewETHKeyManagerSigner(logger *zap.Logger, db basedb.IDb, network beaconprotocol.Network, domain spectypes.DomainType, builderProposals bool) (spectypes.KeyManager, error) {
+	return newETHKeyManagerSigner(logger, db, network, domain, builderProposals)
+}
+
+func newETHKeyManagerSigner(logger *zap.Logger, db basedb.IDb, network beaconprotocol.Network, domain spectypes.DomainType, builderProposals bool) (*ethKeyManagerSigner, error) {
 	signerStore := NewSignerStorage(db, network, logger)
 	options := &eth2keymanager.KeyVaultOptions{}
 	options.SetStorage(signerStore)
@@ -66,7 +70,7 @@ func NewETHKeyManagerSigner(logger *zap.Logger, db basedb.IDb, network beaconpro
 	}
 
 	slashingProtector := slashingprotection.NewNormalProtection(signerStore)
-	beaconSigner := signer.NewSimpleSigner(wallet, slashingProtector, network.Network)
+	beaconSigner := signer.NewSimpleSigner(wallet, slashingProtector, network.SignerNetwork())
 
 	return &ethKeyManagerSigner{
 		wallet:            wallet,
@@ -99,6 +103,9 @@ func (km *ethKeyManagerSigner) signBeaconObject(obj ssz.HashRoot, domain phase0.
 		if !ok {
 			return nil, nil, errors.New("could not cast obj to AttestationData")
 		}
+		if err := km.checkFarFutureAttestation(data); err != nil {
+			return nil, nil, err
+		}
 		return km.signer.SignBeaconAttestation(data, domain, pk)
 	case spectypes.DomainProposer:
 		if km.builderProposals {
@@ -109,12 +116,18 @@ func (km *ethKeyManagerSigner) signBeaconObject(obj ssz.HashRoot, domain phase0.
 					Version:   spec.DataVersionBellatrix,
 					Bellatrix: v,
 				}
+				if err := km.checkFarFutureSlot(v.Slot); err != nil {
+					return nil, nil, err
+				}
 				return km.signer.SignBlindedBeaconBlock(vBlindedBlock, domain, pk)
 			case *apiv1capella.BlindedBeaconBlock:
 				vBlindedBlock = &api.VersionedBlindedBeaconBlock{
 					Version: spec.DataVersionCapella,
 					Capella: v,
 				}
+				if err := km.checkFarFutureSlot(v.Slot); err != nil {
+					return nil, nil, err
+				}
 				return km.signer.SignBlindedBeaconBlock(vBlindedBlock, domain, pk)
 			}
 		}
@@ -145,6 +158,13 @@ func (km *ethKeyManagerSigner) signBeaconObject(obj ssz.HashRoot, domain phase0.
 			return nil, nil, fmt.Errorf("obj type is unknown: %T", obj)
 		}
 
+		slot, err := vBlock.Slot()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get block slot")
+		}
+		if err := km.checkFarFutureSlot(slot); err != nil {
+			return nil, nil, err
+		}
 		return km.signer.SignBeaconBlock(vBlock, domain, pk)
 	case spectypes.DomainAggregateAndProof:
 		data, ok := obj.(*phase0.AggregateAndProof)
@@ -196,6 +216,12 @@ func (km *ethKeyManagerSigner) signBeaconObject(obj ssz.HashRoot, domain phase0.
 			return nil, nil, fmt.Errorf("obj type is unknown: %T", obj)
 		}
 		return km.signer.SignRegistration(data, domain, pk)
+	case spectypes.DomainVoluntaryExit:
+		data, ok := obj.(*phase0.VoluntaryExit)
+		if !ok {
+			return nil, nil, errors.New("could not cast obj to VoluntaryExit")
+		}
+		return km.signer.SignVoluntaryExit(data, domain, pk)
 	default:
 		return nil, nil, errors.New("domain unknown")
 	}
@@ -254,7 +280,7 @@ func (km *ethKeyManagerSigner) AddShare(shareKey *bls.SecretKey) error {
 		return errors.Wrap(err, "could not check share existence")
 	}
 	if acc == nil {
-		if err := km.saveMinimalSlashingProtection(shareKey.GetPublicKey().Serialize()); err != nil {
+		if err := saveMinimalSlashingProtection(km.storage, shareKey.GetPublicKey().Serialize()); err != nil {
 			return errors.Wrap(err, "could not save minimal slashing protection")
 		}
 		if err := km.saveShare(shareKey); err != nil {
@@ -265,19 +291,39 @@ func (km *ethKeyManagerSigner) AddShare(shareKey *bls.SecretKey) error {
 	return nil
 }
 
-func (km *ethKeyManagerSigner) saveMinimalSlashingProtection(pk []byte) error {
-	currentSlot := km.storage.Network().EstimatedCurrentSlot()
-	currentEpoch := km.storage.Network().EstimatedEpochAtSlot(currentSlot)
+// checkFarFutureAttestation checks the attestation epochs with the genesis of the network,
+// as the signer only knows the genesis of known networks
+func (km *ethKeyManagerSigner) checkFarFutureAttestation(data *phase0.AttestationData) error {
+	if !km.storage.BeaconNetwork().IsValidFarFutureEpoch(data.Target.Epoch) {
+		return errors.New("target epoch too far into the future")
+	}
+	if !km.storage.BeaconNetwork().IsValidFarFutureEpoch(data.Source.Epoch) {
+		return errors.New("source epoch too far into the future")
+	}
+	return nil
+}
+
+// checkFarFutureSlot checks the proposal slot with the genesis of the network
+func (km *ethKeyManagerSigner) checkFarFutureSlot(slot phase0.Slot) error {
+	if !km.storage.BeaconNetwork().IsValidFarFutureSlot(slot) {
+		return errors.New("proposed block slot too far into the future")
+	}
+	return nil
+}
+
+// saveMinimalSlashingProtection saves the current epoch and slot as the highest attestation and proposal of a new share,
+// higher records that already exist, e.g. imported before the share was added, are kept
+func saveMinimalSlashingProtection(storage Storage, pk []byte) error {
+	currentSlot := storage.BeaconNetwork().EstimatedCurrentSlot()
+	currentEpoch := storage.BeaconNetwork().EstimatedEpochAtSlot(currentSlot)
 	highestTarget := currentEpoch + minimalAttSlashingProtectionEpochDistance
 	highestSource := highestTarget - 1
 	highestProposal := currentSlot + minimalBlockSlashingProtectionSlotDistance
 
-	minAttData := minimalAttProtectionData(highestSource, highestTarget)
-
-	if err := km.storage.SaveHighestAttestation(pk, minAttData); err != nil {
+	if err := mergeHighestAttestation(storage, pk, highestSource, highestTarget); err != nil {
 		return errors.Wrapf(err, "could not save minimal highest attestation for %s", string(pk))
 	}
-	if err := km.storage.SaveHighestProposal(pk, highestProposal); err != nil {
+	if err := mergeHighestProposal(storage, pk, highestProposal); err != nil {
 		return errors.Wrapf(err, "could not save minimal highest proposal for %s", string(pk))
 	}
 	return nil
@@ -309,6 +355,36 @@ func (km *ethKeyManagerSigner) RemoveShare(pubKey string) error {
 	return nil
 }
 
+// saveShareKey saves the given share to the wallet if it doesn't exist, without touching its slashing protection
+func (km *ethKeyManagerSigner) saveShareKey(shareKey *bls.SecretKey) error {
+	km.walletLock.Lock()
+	defer km.walletLock.Unlock()
+
+	acc, err := km.wallet.AccountByPublicKey(shareKey.GetPublicKey().SerializeToHexStr())
+	if err != nil && err.Error() != "account not found" {
+		return errors.Wrap(err, "could not check share existence")
+	}
+	if acc != nil {
+		return nil
+	}
+	return km.saveShare(shareKey)
+}
+
+// deleteShareKey deletes the given share from the wallet if it exists, without touching its slashing protection
+func (km *ethKeyManagerSigner) deleteShareKey(pubKey string) error {
+	km.walletLock.Lock()
+	defer km.walletLock.Unlock()
+
+	acc, err := km.wallet.AccountByPublicKey(pubKey)
+	if err != nil && err.Error() != "account not found" {
+		return errors.Wrap(err, "could not check share existence")
+	}
+	if acc == nil {
+		return nil
+	}
+	return km.wallet.DeleteAccountByPublicKey(pubKey)
+}
+
 func (km *ethKeyManagerSigner) saveShare(shareKey *bls.SecretKey) error {
 	key, err := core.NewHDKeyFromPrivateKey(shareKey.Serialize(), "")
 	if err != nil {
@@ -0,0 +1,431 @@
+package ekm
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	apiv1bellatrix "github.com/attestantio/go-eth2-client/api/v1/bellatrix"
+	apiv1capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/encryptor/keystorev4"
+	slashingprotection "github.com/bloxapp/eth2-key-manager/slashing_protection"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/google/uuid"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// Options defines the options of the key manager
+type Options struct {
+	RemoteSignerURL          string        `yaml:"RemoteSignerURL" env:"REMOTE_SIGNER_URL" env-description:"URL of a remote signer that implements the Web3Signer API, share keys are kept in the node if empty"`
+	RemoteSignerTimeout      time.Duration `yaml:"RemoteSignerTimeout" env:"REMOTE_SIGNER_TIMEOUT" env-default:"10s" env-description:"Timeout of requests to the remote signer"`
+	RemoteSignerLocalSSVKeys bool          `yaml:"RemoteSignerLocalSSVKeys" env:"REMOTE_SIGNER_LOCAL_SSV_KEYS" env-description:"Keep a copy of the share keys in the node to sign SSV message roots, for remote signers that don't support the non-standard SSV sign type such as Web3Signer. Share keys are then no longer held only by the remote signer"`
+}
+
+// ForkInfoProvider provides the fork info that the remote signer requires to sign beacon objects
+type ForkInfoProvider interface {
+	// ForkInfo returns the fork that is active in the given epoch and the genesis validators root
+	ForkInfo(epoch phase0.Epoch) (*phase0.Fork, phase0.Root, error)
+}
+
+// remoteKeyManager is a key manager that holds share keys in a remote signer over the Web3Signer API,
+// while slashing protection is enforced locally before anything is sent for signing.
+// SSV message roots are sent with a non-standard SSV sign type, which Web3Signer doesn't have,
+// unless RemoteSignerLocalSSVKeys is set and they are signed in the node with a copy of the share keys.
+type remoteKeyManager struct {
+	client            *web3SignerClient
+	storage           Storage
+	forkInfo          ForkInfoProvider
+	domain            spectypes.DomainType
+	slashingProtector core.SlashingProtector
+	builderProposals  bool
+	// ssvSigner signs SSV message roots with the local copy of the share keys, nil unless RemoteSignerLocalSSVKeys is set
+	ssvSigner *ethKeyManagerSigner
+	// protectionLock makes the slashing check and update of a signing atomic
+	protectionLock *sync.Mutex
+	// sharesLock protects adding and removing shares
+	sharesLock *sync.Mutex
+}
+
+// NewRemoteKeyManager returns a new instance of remoteKeyManager
+func NewRemoteKeyManager(logger *zap.Logger, db basedb.IDb, network beaconprotocol.Network, forkInfo ForkInfoProvider, domain spectypes.DomainType, builderProposals bool, opts Options) (spectypes.KeyManager, error) {
+	if len(opts.RemoteSignerURL) == 0 {
+		return nil, errors.New("remote signer url was not provided")
+	}
+	var ssvSigner *ethKeyManagerSigner
+	if opts.RemoteSignerLocalSSVKeys {
+		logger.Warn("share keys are kept in the node to sign ssv message roots, they are not held only by the remote signer")
+		var err error
+		ssvSigner, err = newETHKeyManagerSigner(logger, db, network, domain, builderProposals)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create signer of ssv roots")
+		}
+	}
+	signerStore := NewSignerStorage(db, network, logger)
+	client := newWeb3SignerClient(opts.RemoteSignerURL, opts.RemoteSignerTimeout)
+
+	keys, err := client.listKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list remote signer keys")
+	}
+	logger.Info("connected to remote signer", zap.String("url", opts.RemoteSignerURL), zap.Int("keys", len(keys)),
+		zap.Bool("local_ssv_keys", opts.RemoteSignerLocalSSVKeys))
+
+	return &remoteKeyManager{
+		client:            client,
+		storage:           signerStore,
+		forkInfo:          forkInfo,
+		domain:            domain,
+		slashingProtector: slashingprotection.NewNormalProtection(signerStore),
+		builderProposals:  builderProposals,
+		ssvSigner:         ssvSigner,
+		protectionLock:    &sync.Mutex{},
+		sharesLock:        &sync.Mutex{},
+	}, nil
+}
+
+func (km *remoteKeyManager) SignBeaconObject(obj ssz.HashRoot, domain phase0.Domain, pk []byte, domainType phase0.DomainType) (spectypes.Signature, [32]byte, error) {
+	root, err := spectypes.ComputeETHSigningRoot(obj, domain)
+	if err != nil {
+		return nil, [32]byte{}, errors.Wrap(err, "could not compute signing root")
+	}
+	req, epoch, err := km.signRequest(obj, pk, domainType)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	req.SigningRoot = encodeHex(root[:])
+	// validator registrations are signed with the genesis fork version, so they have no fork info
+	if req.Type != web3SignerTypeValidatorRegistration {
+		fork, genesisRoot, err := km.forkInfo.ForkInfo(epoch)
+		if err != nil {
+			return nil, [32]byte{}, errors.Wrap(err, "could not get fork info")
+		}
+		req.ForkInfo = &web3SignerForkInfo{Fork: fork, GenesisValidatorsRoot: encodeHex(genesisRoot[:])}
+	}
+
+	sig, err := km.sign(pk, root, req)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	return sig, root, nil
+}
+
+// signRequest creates the sign request of the given object and returns the epoch of its fork info,
+// slashable objects are checked and saved by the local slashing protection
+func (km *remoteKeyManager) signRequest(obj ssz.HashRoot, pk []byte, domainType phase0.DomainType) (*web3SignerSignRequest, phase0.Epoch, error) {
+	network := km.storage.BeaconNetwork()
+	switch domainType {
+	case spectypes.DomainAttester:
+		data, ok := obj.(*phase0.AttestationData)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to AttestationData")
+		}
+		if err := km.protectAttestation(pk, data); err != nil {
+			return nil, 0, err
+		}
+		return &web3SignerSignRequest{Type: web3SignerTypeAttestation, Attestation: data}, data.Target.Epoch, nil
+	case spectypes.DomainProposer:
+		slot, block, err := km.beaconBlock(obj)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := km.protectProposal(pk, slot); err != nil {
+			return nil, 0, err
+		}
+		return &web3SignerSignRequest{Type: web3SignerTypeBlockV2, BeaconBlock: block}, network.EstimatedEpochAtSlot(slot), nil
+	case spectypes.DomainAggregateAndProof:
+		data, ok := obj.(*phase0.AggregateAndProof)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to AggregateAndProof")
+		}
+		epoch := network.EstimatedEpochAtSlot(data.Aggregate.Data.Slot)
+		return &web3SignerSignRequest{Type: web3SignerTypeAggregateAndProof, AggregateAndProof: data}, epoch, nil
+	case spectypes.DomainSelectionProof:
+		data, ok := obj.(spectypes.SSZUint64)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to SSZUint64")
+		}
+		return &web3SignerSignRequest{
+			Type:            web3SignerTypeAggregationSlot,
+			AggregationSlot: &web3SignerAggregationSlot{Slot: phase0.Slot(data)},
+		}, network.EstimatedEpochAtSlot(phase0.Slot(data)), nil
+	case spectypes.DomainRandao:
+		data, ok := obj.(spectypes.SSZUint64)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to SSZUint64")
+		}
+		return &web3SignerSignRequest{
+			Type:         web3SignerTypeRandaoReveal,
+			RandaoReveal: &web3SignerRandaoReveal{Epoch: phase0.Epoch(data)},
+		}, phase0.Epoch(data), nil
+	case spectypes.DomainSyncCommittee:
+		data, ok := obj.(spectypes.SSZBytes)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to SSZBytes")
+		}
+		// the block root doesn't carry its slot, sync committee messages are signed in the slot of their duty
+		slot := network.EstimatedCurrentSlot()
+		return &web3SignerSignRequest{
+			Type:                 web3SignerTypeSyncCommitteeMessage,
+			SyncCommitteeMessage: &web3SignerSyncCommitteeMessage{BeaconBlockRoot: encodeHex(data), Slot: slot},
+		}, network.EstimatedEpochAtSlot(slot), nil
+	case spectypes.DomainSyncCommitteeSelectionProof:
+		data, ok := obj.(*altair.SyncAggregatorSelectionData)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to SyncAggregatorSelectionData")
+		}
+		epoch := network.EstimatedEpochAtSlot(data.Slot)
+		return &web3SignerSignRequest{Type: web3SignerTypeSyncCommitteeSelectionProof, SyncAggregatorSelectionData: data}, epoch, nil
+	case spectypes.DomainContributionAndProof:
+		data, ok := obj.(*altair.ContributionAndProof)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to ContributionAndProof")
+		}
+		epoch := network.EstimatedEpochAtSlot(data.Contribution.Slot)
+		return &web3SignerSignRequest{Type: web3SignerTypeSyncCommitteeContributionAndProof, ContributionAndProof: data}, epoch, nil
+	case spectypes.DomainApplicationBuilder:
+		data, ok := obj.(*eth2apiv1.ValidatorRegistration)
+		if !ok {
+			return nil, 0, fmt.Errorf("obj type is unknown: %T", obj)
+		}
+		return &web3SignerSignRequest{Type: web3SignerTypeValidatorRegistration, ValidatorRegistration: data}, 0, nil
+	case spectypes.DomainVoluntaryExit:
+		data, ok := obj.(*phase0.VoluntaryExit)
+		if !ok {
+			return nil, 0, errors.New("could not cast obj to VoluntaryExit")
+		}
+		return &web3SignerSignRequest{Type: web3SignerTypeVoluntaryExit, VoluntaryExit: data}, data.Epoch, nil
+	default:
+		return nil, 0, errors.New("domain unknown")
+	}
+}
+
+// beaconBlock returns the slot and the Web3Signer representation of the given block,
+// blocks from bellatrix onwards are sent as headers
+func (km *remoteKeyManager) beaconBlock(obj ssz.HashRoot) (phase0.Slot, *web3SignerBeaconBlock, error) {
+	if km.builderProposals {
+		switch v := obj.(type) {
+		case *apiv1bellatrix.BlindedBeaconBlock:
+			header, err := beaconBlockHeader(v.Slot, v.ProposerIndex, v.ParentRoot, v.StateRoot, v.Body)
+			return v.Slot, &web3SignerBeaconBlock{Version: "BELLATRIX", BlockHeader: header}, err
+		case *apiv1capella.BlindedBeaconBlock:
+			header, err := beaconBlockHeader(v.Slot, v.ProposerIndex, v.ParentRoot, v.StateRoot, v.Body)
+			return v.Slot, &web3SignerBeaconBlock{Version: "CAPELLA", BlockHeader: header}, err
+		}
+	}
+
+	switch v := obj.(type) {
+	case *phase0.BeaconBlock:
+		return v.Slot, &web3SignerBeaconBlock{Version: "PHASE0", Block: v}, nil
+	case *altair.BeaconBlock:
+		return v.Slot, &web3SignerBeaconBlock{Version: "ALTAIR", Block: v}, nil
+	case *bellatrix.BeaconBlock:
+		header, err := beaconBlockHeader(v.Slot, v.ProposerIndex, v.ParentRoot, v.StateRoot, v.Body)
+		return v.Slot, &web3SignerBeaconBlock{Version: "BELLATRIX", BlockHeader: header}, err
+	case *capella.BeaconBlock:
+		header, err := beaconBlockHeader(v.Slot, v.ProposerIndex, v.ParentRoot, v.StateRoot, v.Body)
+		return v.Slot, &web3SignerBeaconBlock{Version: "CAPELLA", BlockHeader: header}, err
+	default:
+		return 0, nil, fmt.Errorf("obj type is unknown: %T", obj)
+	}
+}
+
+func beaconBlockHeader(slot phase0.Slot, proposerIndex phase0.ValidatorIndex, parentRoot, stateRoot phase0.Root, body ssz.HashRoot) (*phase0.BeaconBlockHeader, error) {
+	bodyRoot, err := body.HashTreeRoot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block body root")
+	}
+	return &phase0.BeaconBlockHeader{
+		Slot:          slot,
+		ProposerIndex: proposerIndex,
+		ParentRoot:    parentRoot,
+		StateRoot:     stateRoot,
+		BodyRoot:      bodyRoot,
+	}, nil
+}
+
+// protectAttestation checks that the given attestation is not slashable and saves it as the highest attestation
+func (km *remoteKeyManager) protectAttestation(pk []byte, data *phase0.AttestationData) error {
+	km.protectionLock.Lock()
+	defer km.protectionLock.Unlock()
+
+	if !km.storage.BeaconNetwork().IsValidFarFutureEpoch(data.Target.Epoch) {
+		return errors.New("target epoch too far into the future")
+	}
+	if !km.storage.BeaconNetwork().IsValidFarFutureEpoch(data.Source.Epoch) {
+		return errors.New("source epoch too far into the future")
+	}
+	if err := km.IsAttestationSlashable(pk, data); err != nil {
+		return err
+	}
+	return km.slashingProtector.UpdateHighestAttestation(pk, data)
+}
+
+// protectProposal checks that a proposal in the given slot is not slashable and saves it as the highest proposal
+func (km *remoteKeyManager) protectProposal(pk []byte, slot phase0.Slot) error {
+	km.protectionLock.Lock()
+	defer km.protectionLock.Unlock()
+
+	if !km.storage.BeaconNetwork().IsValidFarFutureSlot(slot) {
+		return errors.New("proposed block slot too far into the future")
+	}
+	if err := km.IsBeaconBlockSlashable(pk, slot); err != nil {
+		return err
+	}
+	return km.slashingProtector.UpdateHighestProposal(pk, slot)
+}
+
+func (km *remoteKeyManager) IsAttestationSlashable(pk []byte, data *phase0.AttestationData) error {
+	if val, err := km.slashingProtector.IsSlashableAttestation(pk, data); err != nil || val != nil {
+		if err != nil {
+			return err
+		}
+		return errors.Errorf("slashable attestation (%s), not signing", val.Status)
+	}
+	return nil
+}
+
+func (km *remoteKeyManager) IsBeaconBlockSlashable(pk []byte, slot phase0.Slot) error {
+	status, err := km.slashingProtector.IsSlashableProposal(pk, slot)
+	if err != nil {
+		return err
+	}
+	if status.Status != core.ValidProposal {
+		return errors.Errorf("slashable proposal (%s), not signing", status.Status)
+	}
+	return nil
+}
+
+func (km *remoteKeyManager) SignRoot(data spectypes.Root, sigType spectypes.SignatureType, pk []byte) (spectypes.Signature, error) {
+	if km.ssvSigner != nil {
+		return km.ssvSigner.SignRoot(data, sigType, pk)
+	}
+	root, err := spectypes.ComputeSigningRoot(data, spectypes.ComputeSignatureDomain(km.domain, sigType))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	return km.sign(pk, root, &web3SignerSignRequest{
+		Type:        web3SignerTypeSSV,
+		SigningRoot: encodeHex(root[:]),
+	})
+}
+
+// sign sends the given request to the remote signer and verifies the returned signature
+func (km *remoteKeyManager) sign(pk []byte, root [32]byte, req *web3SignerSignRequest) (spectypes.Signature, error) {
+	sig, err := km.client.sign(pk, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not sign with remote signer")
+	}
+
+	pubKey := &bls.PublicKey{}
+	if err := pubKey.Deserialize(pk); err != nil {
+		return nil, errors.Wrap(err, "could not deserialize public key")
+	}
+	blsSig := &bls.Sign{}
+	if err := blsSig.Deserialize(sig); err != nil {
+		return nil, errors.Wrap(err, "could not deserialize remote signature")
+	}
+	if !blsSig.VerifyByte(pubKey, root[:]) {
+		return nil, errors.New("invalid remote signature")
+	}
+	return sig, nil
+}
+
+func (km *remoteKeyManager) AddShare(shareKey *bls.SecretKey) error {
+	km.sharesLock.Lock()
+	defer km.sharesLock.Unlock()
+
+	if km.ssvSigner != nil {
+		if err := km.ssvSigner.saveShareKey(shareKey); err != nil {
+			return errors.Wrap(err, "could not save share to sign ssv roots")
+		}
+	}
+
+	pk := shareKey.GetPublicKey().Serialize()
+	keys, err := km.client.listKeys()
+	if err != nil {
+		return errors.Wrap(err, "could not check share existence")
+	}
+	if _, ok := keys[encodeHex(pk)]; ok {
+		return nil
+	}
+	if err := saveMinimalSlashingProtection(km.storage, pk); err != nil {
+		return errors.Wrap(err, "could not save minimal slashing protection")
+	}
+	keystore, password, err := newShareKeystore(shareKey)
+	if err != nil {
+		return errors.Wrap(err, "could not create share keystore")
+	}
+	if err := km.client.importKeystore(keystore, password); err != nil {
+		return errors.Wrap(err, "could not import share to remote signer")
+	}
+	return nil
+}
+
+func (km *remoteKeyManager) RemoveShare(pubKey string) error {
+	km.sharesLock.Lock()
+	defer km.sharesLock.Unlock()
+
+	if km.ssvSigner != nil {
+		if err := km.ssvSigner.deleteShareKey(pubKey); err != nil {
+			return errors.Wrap(err, "could not delete share that signs ssv roots")
+		}
+	}
+
+	pkDecoded, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not hex decode share public key")
+	}
+	keys, err := km.client.listKeys()
+	if err != nil {
+		return errors.Wrap(err, "could not check share existence")
+	}
+	if _, ok := keys[encodeHex(pkDecoded)]; !ok {
+		return nil
+	}
+	if err := km.storage.RemoveHighestAttestation(pkDecoded); err != nil {
+		return errors.Wrap(err, "could not remove highest attestation")
+	}
+	if err := km.storage.RemoveHighestProposal(pkDecoded); err != nil {
+		return errors.Wrap(err, "could not remove highest proposal")
+	}
+	if err := km.client.deleteKeys(encodeHex(pkDecoded)); err != nil {
+		return errors.Wrap(err, "could not delete share from remote signer")
+	}
+	return nil
+}
+
+// newShareKeystore encrypts the given share key into an EIP-2335 keystore with a random password
+func newShareKeystore(shareKey *bls.SecretKey) (map[string]interface{}, string, error) {
+	passwordBytes := make([]byte, 32)
+	if _, err := rand.Read(passwordBytes); err != nil {
+		return nil, "", errors.Wrap(err, "could not generate password")
+	}
+	password := hex.EncodeToString(passwordBytes)
+
+	encryptor := keystorev4.New()
+	crypto, err := encryptor.Encrypt(shareKey.Serialize(), password)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not encrypt share key")
+	}
+	return map[string]interface{}{
+		"crypto":  crypto,
+		"pubkey":  shareKey.GetPublicKey().SerializeToHexStr(),
+		"path":    "",
+		"uuid":    uuid.New().String(),
+		"version": encryptor.Version(),
+	}, password, nil
+}
@@ -0,0 +1,432 @@
+package ekm
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/encryptor/keystorev4"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/utils/threshold"
+)
+
+// web3SignerPayloads are the payload fields of the sign types in the Web3Signer API
+var web3SignerPayloads = map[string]string{
+	web3SignerTypeAttestation:                       "attestation",
+	web3SignerTypeBlockV2:                           "beacon_block",
+	web3SignerTypeAggregateAndProof:                 "aggregate_and_proof",
+	web3SignerTypeAggregationSlot:                   "aggregation_slot",
+	web3SignerTypeRandaoReveal:                      "randao_reveal",
+	web3SignerTypeSyncCommitteeMessage:              "sync_committee_message",
+	web3SignerTypeSyncCommitteeSelectionProof:       "sync_aggregator_selection_data",
+	web3SignerTypeSyncCommitteeContributionAndProof: "contribution_and_proof",
+	web3SignerTypeValidatorRegistration:             "validator_registration",
+	web3SignerTypeVoluntaryExit:                     "voluntary_exit",
+}
+
+// testForkInfo is the fork info of the test network
+type testForkInfo struct{}
+
+func (testForkInfo) ForkInfo(epoch phase0.Epoch) (*phase0.Fork, phase0.Root, error) {
+	return &phase0.Fork{PreviousVersion: phase0.Version{0, 0, 16, 32}, CurrentVersion: phase0.Version{1, 0, 16, 32}}, phase0.Root{1, 2, 3}, nil
+}
+
+// testRemoteSigner is an in-process stand-in of a Web3Signer, which rejects sign requests that don't match its schema
+type testRemoteSigner struct {
+	t     *testing.T
+	lock  sync.Mutex
+	keys  map[string]*bls.SecretKey
+	types []string
+	// ssvRoots makes the signer support the non-standard SSV sign type
+	ssvRoots bool
+}
+
+func newTestRemoteSigner(t *testing.T, ssvRoots bool) (*testRemoteSigner, *httptest.Server) {
+	rs := &testRemoteSigner{t: t, keys: make(map[string]*bls.SecretKey), ssvRoots: ssvRoots}
+	mux := http.NewServeMux()
+	mux.HandleFunc(web3SignerKeystoresPath, rs.handleKeystores)
+	mux.HandleFunc(web3SignerSignPath, rs.handleSign)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return rs, srv
+}
+
+func (rs *testRemoteSigner) handleKeystores(w http.ResponseWriter, r *http.Request) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		res := web3SignerListKeysResponse{Data: []web3SignerKey{}}
+		for pk := range rs.keys {
+			res.Data = append(res.Data, web3SignerKey{ValidatingPubkey: pk})
+		}
+		rs.respond(w, res)
+	case http.MethodPost:
+		req := web3SignerImportRequest{}
+		require.NoError(rs.t, json.NewDecoder(r.Body).Decode(&req))
+		res := web3SignerStatusResponse{}
+		for i, raw := range req.Keystores {
+			keystore := map[string]interface{}{}
+			require.NoError(rs.t, json.Unmarshal([]byte(raw), &keystore))
+			secret, err := keystorev4.New().Decrypt(keystore["crypto"].(map[string]interface{}), req.Passwords[i])
+			require.NoError(rs.t, err)
+			sk := &bls.SecretKey{}
+			require.NoError(rs.t, sk.Deserialize(secret))
+			pk := encodeHex(sk.GetPublicKey().Serialize())
+			require.Equal(rs.t, "0x"+keystore["pubkey"].(string), pk)
+			if _, ok := rs.keys[pk]; ok {
+				res.Data = append(res.Data, web3SignerStatus{Status: web3SignerStatusDuplicate})
+				continue
+			}
+			rs.keys[pk] = sk
+			res.Data = append(res.Data, web3SignerStatus{Status: web3SignerStatusImported})
+		}
+		rs.respond(w, res)
+	case http.MethodDelete:
+		req := web3SignerDeleteRequest{}
+		require.NoError(rs.t, json.NewDecoder(r.Body).Decode(&req))
+		res := web3SignerStatusResponse{}
+		for _, pk := range req.Pubkeys {
+			if _, ok := rs.keys[pk]; !ok {
+				res.Data = append(res.Data, web3SignerStatus{Status: web3SignerStatusNotFound})
+				continue
+			}
+			delete(rs.keys, pk)
+			res.Data = append(res.Data, web3SignerStatus{Status: web3SignerStatusDeleted})
+		}
+		rs.respond(w, res)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (rs *testRemoteSigner) handleSign(w http.ResponseWriter, r *http.Request) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	sk, ok := rs.keys[strings.TrimPrefix(r.URL.Path, web3SignerSignPath)]
+	if !ok {
+		http.Error(w, "public key not found", http.StatusNotFound)
+		return
+	}
+	raw, err := io.ReadAll(r.Body)
+	require.NoError(rs.t, err)
+	req := web3SignerSignRequest{}
+	require.NoError(rs.t, json.Unmarshal(raw, &req))
+	if err := rs.checkSchema(req.Type, raw); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	root, err := decodeHex(req.SigningRoot)
+	require.NoError(rs.t, err)
+	rs.types = append(rs.types, req.Type)
+	rs.respond(w, web3SignerSignResponse{Signature: encodeHex(sk.SignByte(root).Serialize())})
+}
+
+// checkSchema checks that the given sign request has the payload and fork info that its type requires
+func (rs *testRemoteSigner) checkSchema(signType string, raw []byte) error {
+	body := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return err
+	}
+	if signType == web3SignerTypeSSV {
+		if !rs.ssvRoots {
+			return errors.New("unknown sign type SSV")
+		}
+		return nil
+	}
+	payload, ok := web3SignerPayloads[signType]
+	if !ok {
+		return errors.Errorf("unknown sign type %s", signType)
+	}
+	if _, ok := body[payload]; !ok {
+		return errors.Errorf("missing %s", payload)
+	}
+	if signType == web3SignerTypeSyncCommitteeMessage {
+		msg := map[string]string{}
+		if err := json.Unmarshal(body[payload], &msg); err != nil {
+			return err
+		}
+		if msg["slot"] == "" || msg["beacon_block_root"] == "" {
+			return errors.New("missing sync committee message fields")
+		}
+	}
+	if signType == web3SignerTypeValidatorRegistration {
+		return nil
+	}
+	forkInfo := struct {
+		Fork *struct {
+			PreviousVersion string `json:"previous_version"`
+			CurrentVersion  string `json:"current_version"`
+			Epoch           string `json:"epoch"`
+		} `json:"fork"`
+		GenesisValidatorsRoot string `json:"genesis_validators_root"`
+	}{}
+	if err := json.Unmarshal(body["fork_info"], &forkInfo); err != nil {
+		return errors.Wrap(err, "invalid fork_info")
+	}
+	if forkInfo.Fork == nil || forkInfo.Fork.PreviousVersion == "" || forkInfo.Fork.CurrentVersion == "" || forkInfo.Fork.Epoch == "" {
+		return errors.New("missing fork_info.fork")
+	}
+	if len(forkInfo.GenesisValidatorsRoot) != 66 {
+		return errors.New("missing fork_info.genesis_validators_root")
+	}
+	return nil
+}
+
+func (rs *testRemoteSigner) respond(w http.ResponseWriter, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(rs.t, json.NewEncoder(w).Encode(res))
+}
+
+func (rs *testRemoteSigner) signedTypes() []string {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	return append([]string{}, rs.types...)
+}
+
+func testRemoteKeyManager(t *testing.T, localSSVKeys bool) (*remoteKeyManager, *testRemoteSigner) {
+	threshold.Init()
+	logger := logging.TestLogger(t)
+
+	db, err := getBaseStorage(logger)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close(logger)
+	})
+
+	rs, srv := newTestRemoteSigner(t, !localSSVKeys)
+	km, err := NewRemoteKeyManager(logger, db, beacon.NewNetwork(core.PraterNetwork, 0), testForkInfo{}, types.GetDefaultDomain(), true, Options{
+		RemoteSignerURL:          srv.URL,
+		RemoteSignerTimeout:      5 * time.Second,
+		RemoteSignerLocalSSVKeys: localSSVKeys,
+	})
+	require.NoError(t, err)
+	return km.(*remoteKeyManager), rs
+}
+
+func TestRemoteKeyManager_Shares(t *testing.T) {
+	km, rs := testRemoteKeyManager(t, false)
+
+	sk := &bls.SecretKey{}
+	require.NoError(t, sk.SetHexString(sk1Str))
+	pk := sk.GetPublicKey().Serialize()
+
+	require.NoError(t, km.AddShare(sk))
+	require.NoError(t, km.AddShare(sk)) // existing share is ignored
+	require.Len(t, rs.keys, 1)
+	// share keys are only kept in the node with RemoteSignerLocalSSVKeys
+	require.Nil(t, km.ssvSigner)
+	_, found, err := km.storage.RetrieveHighestAttestation(pk)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	require.NoError(t, km.RemoveShare(pk1Str))
+	require.Len(t, rs.keys, 0)
+	_, found, err = km.storage.RetrieveHighestAttestation(pk)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = km.storage.RetrieveHighestProposal(pk)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// removing a share that doesn't exist is not an error
+	require.NoError(t, km.RemoveShare(pk1Str))
+}
+
+func TestRemoteKeyManager_Slashing(t *testing.T) {
+	km, rs := testRemoteKeyManager(t, false)
+
+	sk := &bls.SecretKey{}
+	require.NoError(t, sk.SetHexString(sk1Str))
+	pk := sk.GetPublicKey().Serialize()
+	require.NoError(t, km.AddShare(sk))
+
+	currentSlot := km.storage.Network().EstimatedCurrentSlot()
+	currentEpoch := km.storage.Network().EstimatedEpochAtSlot(currentSlot)
+
+	attestationData := &phase0.AttestationData{
+		Slot:            currentSlot,
+		Index:           1,
+		BeaconBlockRoot: [32]byte{1, 2, 3},
+		Source:          &phase0.Checkpoint{Epoch: currentEpoch},
+		Target:          &phase0.Checkpoint{Epoch: currentEpoch + 1},
+	}
+	beaconBlock := &phase0.BeaconBlock{
+		Slot:          currentSlot + 1,
+		ProposerIndex: 1,
+		Body: &phase0.BeaconBlockBody{
+			ETH1Data: &phase0.ETH1Data{BlockHash: make([]byte, 32)},
+		},
+	}
+
+	t.Run("sign attestation once", func(t *testing.T) {
+		sig, root, err := km.SignBeaconObject(attestationData, phase0.Domain{}, pk, spectypes.DomainAttester)
+		require.NoError(t, err)
+		expected, err := spectypes.ComputeETHSigningRoot(attestationData, phase0.Domain{})
+		require.NoError(t, err)
+		require.Equal(t, [32]byte(expected), root)
+		require.NotNil(t, sig)
+	})
+	t.Run("slashable attestation, fail", func(t *testing.T) {
+		_, _, err := km.SignBeaconObject(attestationData, phase0.Domain{}, pk, spectypes.DomainAttester)
+		require.EqualError(t, err, "slashable attestation (HighestAttestationVote), not signing")
+	})
+	t.Run("sign block once", func(t *testing.T) {
+		sig, _, err := km.SignBeaconObject(beaconBlock, phase0.Domain{}, pk, spectypes.DomainProposer)
+		require.NoError(t, err)
+		require.NotNil(t, sig)
+	})
+	t.Run("slashable block, fail", func(t *testing.T) {
+		_, _, err := km.SignBeaconObject(beaconBlock, phase0.Domain{}, pk, spectypes.DomainProposer)
+		require.EqualError(t, err, "slashable proposal (HighestProposalVote), not signing")
+	})
+
+	// slashable objects never reach the remote signer
+	require.Equal(t, []string{web3SignerTypeAttestation, web3SignerTypeBlockV2}, rs.signedTypes())
+}
+
+func TestRemoteKeyManager_VoluntaryExit(t *testing.T) {
+	km, rs := testRemoteKeyManager(t, false)
+
+	sk := &bls.SecretKey{}
+	require.NoError(t, sk.SetHexString(sk1Str))
+	pk := sk.GetPublicKey().Serialize()
+	require.NoError(t, km.AddShare(sk))
+
+	exit := &phase0.VoluntaryExit{Epoch: 1, ValidatorIndex: 2}
+	sig, root, err := km.SignBeaconObject(exit, phase0.Domain{}, pk, spectypes.DomainVoluntaryExit)
+	require.NoError(t, err)
+	expected, err := spectypes.ComputeETHSigningRoot(exit, phase0.Domain{})
+	require.NoError(t, err)
+	require.Equal(t, [32]byte(expected), root)
+	require.NotNil(t, sig)
+	require.Equal(t, []string{web3SignerTypeVoluntaryExit}, rs.signedTypes())
+}
+
+func TestRemoteKeyManager_SignTypes(t *testing.T) {
+	km, rs := testRemoteKeyManager(t, false)
+
+	sk := &bls.SecretKey{}
+	require.NoError(t, sk.SetHexString(sk1Str))
+	pk := sk.GetPublicKey().Serialize()
+	require.NoError(t, km.AddShare(sk))
+
+	slot := km.storage.BeaconNetwork().EstimatedCurrentSlot()
+	objects := []struct {
+		obj        ssz.HashRoot
+		domainType phase0.DomainType
+	}{
+		{spectypes.SSZUint64(slot), spectypes.DomainSelectionProof},
+		{spectypes.SSZUint64(1), spectypes.DomainRandao},
+		{spectypes.SSZBytes(make([]byte, 32)), spectypes.DomainSyncCommittee},
+		{&altair.SyncAggregatorSelectionData{Slot: slot, SubcommitteeIndex: 1}, spectypes.DomainSyncCommitteeSelectionProof},
+		{&eth2apiv1.ValidatorRegistration{GasLimit: 1, Timestamp: time.Unix(1, 0)}, spectypes.DomainApplicationBuilder},
+		{&phase0.VoluntaryExit{Epoch: 1, ValidatorIndex: 2}, spectypes.DomainVoluntaryExit},
+	}
+	for _, o := range objects {
+		_, _, err := km.SignBeaconObject(o.obj, phase0.Domain{}, pk, o.domainType)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{
+		web3SignerTypeAggregationSlot,
+		web3SignerTypeRandaoReveal,
+		web3SignerTypeSyncCommitteeMessage,
+		web3SignerTypeSyncCommitteeSelectionProof,
+		web3SignerTypeValidatorRegistration,
+		web3SignerTypeVoluntaryExit,
+	}, rs.signedTypes())
+
+	t.Run("fork info", func(t *testing.T) {
+		_, epoch, err := km.signRequest(&phase0.VoluntaryExit{Epoch: 7}, pk, spectypes.DomainVoluntaryExit)
+		require.NoError(t, err)
+		require.Equal(t, phase0.Epoch(7), epoch)
+
+		forkInfo := web3SignerForkInfo{Fork: &phase0.Fork{PreviousVersion: phase0.Version{0, 0, 16, 32}, CurrentVersion: phase0.Version{1, 0, 16, 32}, Epoch: 5}}
+		raw, err := json.Marshal(forkInfo)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"fork":{"previous_version":"0x00001020","current_version":"0x01001020","epoch":"5"},"genesis_validators_root":""}`, string(raw))
+	})
+}
+
+func TestRemoteKeyManager_SignRoot(t *testing.T) {
+	msg := specqbft.Message{
+		MsgType:    specqbft.CommitMsgType,
+		Height:     specqbft.Height(1),
+		Round:      specqbft.Round(3),
+		Identifier: []byte("identifier2"),
+		Root:       [32]byte{4, 5, 6},
+	}
+	verify := func(t *testing.T, sig spectypes.Signature, pk []byte) {
+		signed := &specqbft.SignedMessage{
+			Signature: sig,
+			Signers:   []spectypes.OperatorID{1},
+			Message:   msg,
+		}
+		require.NoError(t, signed.GetSignature().VerifyByOperators(signed, types.GetDefaultDomain(), spectypes.QBFTSignatureType,
+			[]*spectypes.Operator{{OperatorID: spectypes.OperatorID(1), PubKey: pk}}))
+	}
+	sk := &bls.SecretKey{}
+	require.NoError(t, sk.SetHexString(sk2Str))
+	pk := sk.GetPublicKey().Serialize()
+	other := &bls.SecretKey{}
+	require.NoError(t, other.SetHexString(sk1Str))
+
+	t.Run("signed in the node", func(t *testing.T) {
+		km, rs := testRemoteKeyManager(t, true)
+		require.NoError(t, km.AddShare(sk))
+
+		sig, err := km.SignRoot(&msg, spectypes.QBFTSignatureType, pk)
+		require.NoError(t, err)
+		verify(t, sig, pk)
+		require.Empty(t, rs.signedTypes())
+
+		_, err = km.SignRoot(&msg, spectypes.QBFTSignatureType, other.GetPublicKey().Serialize())
+		require.ErrorContains(t, err, "could not get signing account")
+
+		require.NoError(t, km.RemoveShare(sk.GetPublicKey().SerializeToHexStr()))
+		_, err = km.SignRoot(&msg, spectypes.QBFTSignatureType, pk)
+		require.ErrorContains(t, err, "could not get signing account")
+	})
+
+	t.Run("signed remotely", func(t *testing.T) {
+		km, rs := testRemoteKeyManager(t, false)
+		require.NoError(t, km.AddShare(sk))
+
+		sig, err := km.SignRoot(&msg, spectypes.QBFTSignatureType, pk)
+		require.NoError(t, err)
+		verify(t, sig, pk)
+		require.Equal(t, []string{web3SignerTypeSSV}, rs.signedTypes())
+
+		_, err = km.SignRoot(&msg, spectypes.QBFTSignatureType, other.GetPublicKey().Serialize())
+		require.ErrorContains(t, err, "status 404")
+	})
+
+	t.Run("remote signer without ssv type", func(t *testing.T) {
+		km, rs := testRemoteKeyManager(t, false)
+		rs.ssvRoots = false
+		require.NoError(t, km.AddShare(sk))
+
+		_, err := km.SignRoot(&msg, spectypes.QBFTSignatureType, pk)
+		require.ErrorContains(t, err, "status 400")
+	})
+}
@@ -2,6 +2,7 @@ package ekm
 
 import (
 	"testing"
+	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/altair"
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
@@ -195,6 +196,51 @@ func TestSlashing(t *testing.T) {
 	})
 }
 
+func TestCustomNetwork(t *testing.T) {
+	threshold.Init()
+	logger := logging.TestLogger(t)
+
+	db, err := getBaseStorage(logger)
+	require.NoError(t, err)
+
+	// a devnet that started 100 epochs ago, which core doesn't know
+	genesis := uint64(time.Now().Add(-100 * 32 * 12 * time.Second).Unix())
+	network := beacon.NewCustomNetwork("devnet", genesis, phase0.Version{0x00, 0x00, 0x00, 0x69})
+	km, err := NewETHKeyManagerSigner(logger, db, network, types.GetDefaultDomain(), true)
+	require.NoError(t, err)
+
+	sk1 := &bls.SecretKey{}
+	require.NoError(t, sk1.SetHexString(sk1Str))
+	require.NoError(t, km.AddShare(sk1))
+
+	currentEpoch := network.EstimatedCurrentEpoch()
+	require.InDelta(t, 100, uint64(currentEpoch), 1)
+	highestAtt, found, err := km.(*ethKeyManagerSigner).storage.RetrieveHighestAttestation(sk1.GetPublicKey().Serialize())
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, currentEpoch+minimalAttSlashingProtectionEpochDistance, highestAtt.Target.Epoch)
+
+	attestationData := func(target phase0.Epoch) *phase0.AttestationData {
+		return &phase0.AttestationData{
+			Slot:   network.FirstSlotAtEpoch(target),
+			Source: &phase0.Checkpoint{Epoch: target - 1},
+			Target: &phase0.Checkpoint{Epoch: target},
+		}
+	}
+
+	// the far future check uses the devnet genesis rather than of the fallback signer network
+	_, _, err = km.(*ethKeyManagerSigner).SignBeaconObject(attestationData(currentEpoch+10), phase0.Domain{}, sk1.GetPublicKey().Serialize(), spectypes.DomainAttester)
+	require.EqualError(t, err, "target epoch too far into the future")
+
+	_, sig, err := km.(*ethKeyManagerSigner).SignBeaconObject(attestationData(currentEpoch+1), phase0.Domain{}, sk1.GetPublicKey().Serialize(), spectypes.DomainAttester)
+	require.NoError(t, err)
+	require.NotZero(t, sig)
+
+	beaconBlock := &bellatrix.BeaconBlock{Slot: network.FirstSlotAtEpoch(currentEpoch + 10)}
+	_, _, err = km.(*ethKeyManagerSigner).SignBeaconObject(beaconBlock, phase0.Domain{}, sk1.GetPublicKey().Serialize(), spectypes.DomainProposer)
+	require.EqualError(t, err, "proposed block slot too far into the future")
+}
+
 func TestSignRoot(t *testing.T) {
 
 	require.NoError(t, bls.Init(bls.BLS12_381))
@@ -39,6 +39,7 @@ type Storage interface {
 
 	RemoveHighestAttestation(pubKey []byte) error
 	RemoveHighestProposal(pubKey []byte) error
+	BeaconNetwork() beacon.Network
 }
 
 type storage struct {
@@ -75,6 +76,12 @@ func (s *storage) Network() core.Network {
 	return s.network.Network
 }
 
+// BeaconNetwork returns the network storage is related to, including the genesis of custom networks.
+// It should be used for slot and epoch computations, as core.Network exits on networks it doesn't know.
+func (s *storage) BeaconNetwork() beacon.Network {
+	return s.network
+}
+
 // SaveWallet stores the given wallet.
 func (s *storage) SaveWallet(wallet core.Wallet) error {
 	s.lock.Lock()
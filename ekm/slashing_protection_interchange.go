@@ -0,0 +1,250 @@
+package ekm
+
+import (
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// InterchangeFormatVersion is the supported version of the EIP-3076 slashing protection interchange format
+const InterchangeFormatVersion = "5"
+
+// Interchange is the EIP-3076 slashing protection interchange format,
+// see https://eips.ethereum.org/EIPS/eip-3076
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []InterchangeData   `json:"data"`
+}
+
+// InterchangeMetadata is the metadata of an Interchange
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// InterchangeData holds the slashing protection records of a single public key
+type InterchangeData struct {
+	Pubkey             string                   `json:"pubkey"`
+	SignedBlocks       []InterchangeBlock       `json:"signed_blocks"`
+	SignedAttestations []InterchangeAttestation `json:"signed_attestations"`
+}
+
+// InterchangeBlock is a signed block record, numbers are encoded as decimal strings
+type InterchangeBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// InterchangeAttestation is a signed attestation record, numbers are encoded as decimal strings
+type InterchangeAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// ExportSlashingProtection exports the slashing protection of all the shares in the given storage.
+// only the highest attestation and proposal are kept by the storage, therefore the exported data
+// is in the minimal form of the interchange format (without signing roots).
+func ExportSlashingProtection(storage Storage, genesisValidatorsRoot phase0.Root) (*Interchange, error) {
+	accounts, err := storage.ListAccounts()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list accounts")
+	}
+	interchange := &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    encodeHex(genesisValidatorsRoot[:]),
+		},
+		Data: make([]InterchangeData, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		pk := account.ValidatorPublicKey()
+		data := InterchangeData{
+			Pubkey:             encodeHex(pk),
+			SignedBlocks:       []InterchangeBlock{},
+			SignedAttestations: []InterchangeAttestation{},
+		}
+		slot, found, err := storage.RetrieveHighestProposal(pk)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not retrieve highest proposal of %s", data.Pubkey)
+		}
+		if found {
+			data.SignedBlocks = append(data.SignedBlocks, InterchangeBlock{
+				Slot: strconv.FormatUint(uint64(slot), 10),
+			})
+		}
+		att, found, err := storage.RetrieveHighestAttestation(pk)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not retrieve highest attestation of %s", data.Pubkey)
+		}
+		if found && att != nil && att.Source != nil && att.Target != nil {
+			data.SignedAttestations = append(data.SignedAttestations, InterchangeAttestation{
+				SourceEpoch: strconv.FormatUint(uint64(att.Source.Epoch), 10),
+				TargetEpoch: strconv.FormatUint(uint64(att.Target.Epoch), 10),
+			})
+		}
+		interchange.Data = append(interchange.Data, data)
+	}
+	sort.Slice(interchange.Data, func(i, j int) bool {
+		return interchange.Data[i].Pubkey < interchange.Data[j].Pubkey
+	})
+	return interchange, nil
+}
+
+// ImportSlashingProtection imports the given interchange into the storage, returns the amount of imported public keys.
+// records of public keys that are not shares in the storage yet are imported as well,
+// so that they protect shares that are added afterwards, e.g. once they are synced from the contract.
+//
+// records are merged with the existing ones according to the conservative rules of EIP-3076,
+// i.e. the highest slot, source epoch and target epoch of both the existing and the imported records are kept,
+// so that nothing at or below them could be signed afterwards.
+func ImportSlashingProtection(logger *zap.Logger, storage Storage, interchange *Interchange, genesisValidatorsRoot phase0.Root) (int, error) {
+	if interchange.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return 0, errors.Errorf("unsupported interchange format version %s", interchange.Metadata.InterchangeFormatVersion)
+	}
+	gvr, err := decodeHex(interchange.Metadata.GenesisValidatorsRoot)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not decode genesis validators root")
+	}
+	if encodeHex(gvr) != encodeHex(genesisValidatorsRoot[:]) {
+		return 0, errors.Errorf("genesis validators root %s doesn't match %s",
+			interchange.Metadata.GenesisValidatorsRoot, encodeHex(genesisValidatorsRoot[:]))
+	}
+
+	accounts, err := storage.ListAccounts()
+	if err != nil {
+		return 0, errors.Wrap(err, "could not list accounts")
+	}
+	shares := make(map[string]struct{}, len(accounts))
+	for _, account := range accounts {
+		shares[encodeHex(account.ValidatorPublicKey())] = struct{}{}
+	}
+
+	imported := 0
+	for _, data := range interchange.Data {
+		pk, err := decodeHex(data.Pubkey)
+		if err != nil {
+			return imported, errors.Wrapf(err, "could not decode public key %s", data.Pubkey)
+		}
+		if _, ok := shares[encodeHex(pk)]; !ok {
+			logger.Debug("importing slashing protection of a share that was not added yet", zap.String("pubKey", data.Pubkey))
+		}
+		if err := importBlocks(storage, pk, data.SignedBlocks); err != nil {
+			return imported, errors.Wrapf(err, "could not import signed blocks of %s", data.Pubkey)
+		}
+		if err := importAttestations(storage, pk, data.SignedAttestations); err != nil {
+			return imported, errors.Wrapf(err, "could not import signed attestations of %s", data.Pubkey)
+		}
+		imported++
+	}
+	return imported, nil
+}
+
+// importBlocks saves the highest slot among the given blocks, unless the existing highest proposal is higher
+func importBlocks(storage Storage, pk []byte, blocks []InterchangeBlock) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	var highest phase0.Slot
+	for _, block := range blocks {
+		slot, err := strconv.ParseUint(block.Slot, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid slot %s", block.Slot)
+		}
+		if phase0.Slot(slot) > highest {
+			highest = phase0.Slot(slot)
+		}
+	}
+	if highest == 0 {
+		// nothing to protect, as slot 0 could never be proposed again
+		return nil
+	}
+	return mergeHighestProposal(storage, pk, highest)
+}
+
+// importAttestations saves the highest source and target epochs among the given attestations and the existing one
+func importAttestations(storage Storage, pk []byte, attestations []InterchangeAttestation) error {
+	if len(attestations) == 0 {
+		return nil
+	}
+	var highestSource, highestTarget phase0.Epoch
+	for _, att := range attestations {
+		source, err := strconv.ParseUint(att.SourceEpoch, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid source epoch %s", att.SourceEpoch)
+		}
+		target, err := strconv.ParseUint(att.TargetEpoch, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid target epoch %s", att.TargetEpoch)
+		}
+		if source > target {
+			return errors.Errorf("source epoch %d is higher than target epoch %d", source, target)
+		}
+		if phase0.Epoch(source) > highestSource {
+			highestSource = phase0.Epoch(source)
+		}
+		if phase0.Epoch(target) > highestTarget {
+			highestTarget = phase0.Epoch(target)
+		}
+	}
+	return mergeHighestAttestation(storage, pk, highestSource, highestTarget)
+}
+
+// mergeHighestProposal saves the given slot as the highest proposal, unless the existing highest proposal is higher
+func mergeHighestProposal(storage Storage, pk []byte, slot phase0.Slot) error {
+	existing, found, err := storage.RetrieveHighestProposal(pk)
+	if err != nil {
+		return err
+	}
+	if found && existing >= slot {
+		return nil
+	}
+	return storage.SaveHighestProposal(pk, slot)
+}
+
+// mergeHighestAttestation saves the highest source and target epochs among the given ones and the existing attestation
+func mergeHighestAttestation(storage Storage, pk []byte, source, target phase0.Epoch) error {
+	existing, found, err := storage.RetrieveHighestAttestation(pk)
+	if err != nil {
+		return err
+	}
+	if found && existing != nil && existing.Source != nil && existing.Target != nil {
+		if existing.Source.Epoch >= source && existing.Target.Epoch >= target {
+			return nil
+		}
+		if existing.Source.Epoch > source {
+			source = existing.Source.Epoch
+		}
+		if existing.Target.Epoch > target {
+			target = existing.Target.Epoch
+		}
+	}
+	return storage.SaveHighestAttestation(pk, minimalAttProtectionData(source, target))
+}
+
+// ParseGenesisValidatorsRoot parses a 0x-prefixed hex encoded genesis validators root
+func ParseGenesisValidatorsRoot(s string) (phase0.Root, error) {
+	var root phase0.Root
+	b, err := decodeHex(s)
+	if err != nil {
+		return root, errors.Wrap(err, "could not decode genesis validators root")
+	}
+	if len(b) != len(root) {
+		return root, errors.Errorf("genesis validators root must be %d bytes", len(root))
+	}
+	copy(root[:], b)
+	return root, nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
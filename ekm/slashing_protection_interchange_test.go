@@ -0,0 +1,192 @@
+package ekm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	"github.com/bloxapp/eth2-key-manager/wallets/hd"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/utils/threshold"
+)
+
+const praterGenesisValidatorsRoot = "0x043db0d9a83813551ee2f33450d23797757d430911a9320530ad8a0eabc43efb"
+
+func newInterchangeStorageForTest(t *testing.T) Storage {
+	threshold.Init()
+
+	storage, done := newStorageForTest(t)
+	t.Cleanup(done)
+
+	wallet := hd.NewWallet(&core.WalletContext{Storage: storage})
+	require.NoError(t, storage.SaveWallet(wallet))
+	for _, skStr := range []string{sk1Str, sk2Str} {
+		sk := &bls.SecretKey{}
+		require.NoError(t, sk.SetHexString(skStr))
+		_, err := wallet.CreateValidatorAccountFromPrivateKey(sk.Serialize(), nil)
+		require.NoError(t, err)
+	}
+	return storage
+}
+
+func readInterchangeFixture(t *testing.T, name string) (*Interchange, []byte) {
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	interchange := &Interchange{}
+	require.NoError(t, json.Unmarshal(raw, interchange))
+	return interchange, raw
+}
+
+func TestSlashingProtectionInterchange_RoundTrip(t *testing.T) {
+	logger := logging.TestLogger(t)
+	gvr, err := ParseGenesisValidatorsRoot(praterGenesisValidatorsRoot)
+	require.NoError(t, err)
+
+	toImport, _ := readInterchangeFixture(t, "slashing_protection_import.json")
+	_, expected := readInterchangeFixture(t, "slashing_protection_export.json")
+
+	storage := newInterchangeStorageForTest(t)
+	imported, err := ImportSlashingProtection(logger, storage, toImport, gvr)
+	require.NoError(t, err)
+	require.Equal(t, 3, imported) // the last record is not a share, but is imported as well
+
+	exported, err := ExportSlashingProtection(storage, gvr)
+	require.NoError(t, err)
+	raw, err := json.Marshal(exported)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(raw))
+
+	// importing the exported data into a new storage should produce the same export
+	other := newInterchangeStorageForTest(t)
+	imported, err = ImportSlashingProtection(logger, other, exported, gvr)
+	require.NoError(t, err)
+	require.Equal(t, 2, imported) // only shares are exported
+	reexported, err := ExportSlashingProtection(other, gvr)
+	require.NoError(t, err)
+	require.Equal(t, exported, reexported)
+}
+
+func TestSlashingProtectionInterchange_Merge(t *testing.T) {
+	logger := logging.TestLogger(t)
+	gvr, err := ParseGenesisValidatorsRoot(praterGenesisValidatorsRoot)
+	require.NoError(t, err)
+	toImport, _ := readInterchangeFixture(t, "slashing_protection_import.json")
+
+	storage := newInterchangeStorageForTest(t)
+	pk1, err := hex.DecodeString(pk1Str)
+	require.NoError(t, err)
+	pk2, err := hex.DecodeString(pk2Str)
+	require.NoError(t, err)
+
+	// existing records that are higher than the imported ones are kept
+	require.NoError(t, storage.SaveHighestProposal(pk1, 90000))
+	require.NoError(t, storage.SaveHighestAttestation(pk1, minimalAttProtectionData(2000, 4000)))
+	// existing records that are lower than the imported ones are replaced
+	require.NoError(t, storage.SaveHighestProposal(pk2, 50))
+	require.NoError(t, storage.SaveHighestAttestation(pk2, minimalAttProtectionData(1, 2)))
+
+	_, err = ImportSlashingProtection(logger, storage, toImport, gvr)
+	require.NoError(t, err)
+
+	slot, found, err := storage.RetrieveHighestProposal(pk1)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 90000, slot)
+	att, found, err := storage.RetrieveHighestAttestation(pk1)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 2291, att.Source.Epoch)
+	require.EqualValues(t, 4000, att.Target.Epoch)
+
+	slot, found, err = storage.RetrieveHighestProposal(pk2)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 100, slot)
+	att, found, err = storage.RetrieveHighestAttestation(pk2)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 1, att.Source.Epoch)
+	require.EqualValues(t, 2, att.Target.Epoch)
+}
+
+func TestSlashingProtectionInterchange_ImportBeforeShare(t *testing.T) {
+	threshold.Init()
+	logger := logging.TestLogger(t)
+	gvr, err := ParseGenesisValidatorsRoot(praterGenesisValidatorsRoot)
+	require.NoError(t, err)
+
+	db, err := getBaseStorage(logger)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close(logger)
+	})
+	km, err := NewETHKeyManagerSigner(logger, db, beacon.NewNetwork(core.PraterNetwork, 0), types.GetDefaultDomain(), true)
+	require.NoError(t, err)
+	storage := km.(*ethKeyManagerSigner).storage
+
+	sk := &bls.SecretKey{}
+	require.NoError(t, sk.SetHexString(sk1Str))
+	pk := sk.GetPublicKey().Serialize()
+
+	// the imported records are higher than the minimal slashing protection of a new share
+	currentSlot := storage.Network().EstimatedCurrentSlot()
+	currentEpoch := storage.Network().EstimatedEpochAtSlot(currentSlot)
+	toImport := &Interchange{
+		Metadata: InterchangeMetadata{InterchangeFormatVersion: InterchangeFormatVersion, GenesisValidatorsRoot: praterGenesisValidatorsRoot},
+		Data: []InterchangeData{{
+			Pubkey:             encodeHex(pk),
+			SignedBlocks:       []InterchangeBlock{{Slot: strconv.FormatUint(uint64(currentSlot+1000), 10)}},
+			SignedAttestations: []InterchangeAttestation{{SourceEpoch: strconv.FormatUint(uint64(currentEpoch+99), 10), TargetEpoch: strconv.FormatUint(uint64(currentEpoch+100), 10)}},
+		}},
+	}
+	imported, err := ImportSlashingProtection(logger, storage, toImport, gvr)
+	require.NoError(t, err)
+	require.Equal(t, 1, imported)
+
+	require.NoError(t, km.AddShare(sk))
+
+	slot, found, err := storage.RetrieveHighestProposal(pk)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, currentSlot+1000, slot)
+	att, found, err := storage.RetrieveHighestAttestation(pk)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, currentEpoch+99, att.Source.Epoch)
+	require.Equal(t, currentEpoch+100, att.Target.Epoch)
+}
+
+func TestSlashingProtectionInterchange_InvalidMetadata(t *testing.T) {
+	logger := logging.TestLogger(t)
+	gvr, err := ParseGenesisValidatorsRoot(praterGenesisValidatorsRoot)
+	require.NoError(t, err)
+	storage := newInterchangeStorageForTest(t)
+
+	t.Run("version", func(t *testing.T) {
+		toImport, _ := readInterchangeFixture(t, "slashing_protection_import.json")
+		toImport.Metadata.InterchangeFormatVersion = "4"
+		_, err := ImportSlashingProtection(logger, storage, toImport, gvr)
+		require.ErrorContains(t, err, "unsupported interchange format version")
+	})
+
+	t.Run("genesis validators root", func(t *testing.T) {
+		toImport, _ := readInterchangeFixture(t, "slashing_protection_import.json")
+		var otherGvr [32]byte
+		_, err := ImportSlashingProtection(logger, storage, toImport, otherGvr)
+		require.ErrorContains(t, err, "doesn't match")
+	})
+
+	t.Run("invalid root", func(t *testing.T) {
+		_, err := ParseGenesisValidatorsRoot("0x1234")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,239 @@
+package ekm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	web3SignerSignPath      = "/api/v1/eth2/sign/"
+	web3SignerKeystoresPath = "/eth/v1/keystores"
+)
+
+// signing types of the Web3Signer API
+const (
+	web3SignerTypeAttestation                       = "ATTESTATION"
+	web3SignerTypeBlockV2                           = "BLOCK_V2"
+	web3SignerTypeAggregateAndProof                 = "AGGREGATE_AND_PROOF"
+	web3SignerTypeAggregationSlot                   = "AGGREGATION_SLOT"
+	web3SignerTypeRandaoReveal                      = "RANDAO_REVEAL"
+	web3SignerTypeSyncCommitteeMessage              = "SYNC_COMMITTEE_MESSAGE"
+	web3SignerTypeSyncCommitteeSelectionProof       = "SYNC_COMMITTEE_SELECTION_PROOF"
+	web3SignerTypeSyncCommitteeContributionAndProof = "SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF"
+	web3SignerTypeValidatorRegistration             = "VALIDATOR_REGISTRATION"
+	web3SignerTypeVoluntaryExit                     = "VOLUNTARY_EXIT"
+	// web3SignerTypeSSV is a non-standard type of SSV message roots, which have no type in the Web3Signer API.
+	// remote signers must sign them by the given signing root, unless RemoteSignerLocalSSVKeys is set.
+	web3SignerTypeSSV = "SSV"
+)
+
+// statuses of the keymanager API
+const (
+	web3SignerStatusImported  = "imported"
+	web3SignerStatusDuplicate = "duplicate"
+	web3SignerStatusDeleted   = "deleted"
+	web3SignerStatusNotActive = "not_active"
+	web3SignerStatusNotFound  = "not_found"
+)
+
+// web3SignerSignRequest is the body of a sign request, only the payload that matches the type is set.
+// the remote signer computes the signing root from the payload and the fork info, the signing root
+// that was computed locally is sent along and the returned signature is verified against it.
+type web3SignerSignRequest struct {
+	Type                        string                              `json:"type"`
+	SigningRoot                 string                              `json:"signing_root"`
+	ForkInfo                    *web3SignerForkInfo                 `json:"fork_info,omitempty"`
+	Attestation                 *phase0.AttestationData             `json:"attestation,omitempty"`
+	BeaconBlock                 *web3SignerBeaconBlock              `json:"beacon_block,omitempty"`
+	AggregateAndProof           *phase0.AggregateAndProof           `json:"aggregate_and_proof,omitempty"`
+	AggregationSlot             *web3SignerAggregationSlot          `json:"aggregation_slot,omitempty"`
+	RandaoReveal                *web3SignerRandaoReveal             `json:"randao_reveal,omitempty"`
+	SyncCommitteeMessage        *web3SignerSyncCommitteeMessage     `json:"sync_committee_message,omitempty"`
+	SyncAggregatorSelectionData *altair.SyncAggregatorSelectionData `json:"sync_aggregator_selection_data,omitempty"`
+	ContributionAndProof        *altair.ContributionAndProof        `json:"contribution_and_proof,omitempty"`
+	ValidatorRegistration       *eth2apiv1.ValidatorRegistration    `json:"validator_registration,omitempty"`
+	VoluntaryExit               *phase0.VoluntaryExit               `json:"voluntary_exit,omitempty"`
+}
+
+// web3SignerForkInfo is the fork of the signed object's epoch, it is required by all types but VALIDATOR_REGISTRATION
+type web3SignerForkInfo struct {
+	Fork                  *phase0.Fork `json:"fork"`
+	GenesisValidatorsRoot string       `json:"genesis_validators_root"`
+}
+
+// web3SignerBeaconBlock holds a phase0/altair block, or the header of a later block
+type web3SignerBeaconBlock struct {
+	Version     string                    `json:"version"`
+	Block       interface{}               `json:"block,omitempty"`
+	BlockHeader *phase0.BeaconBlockHeader `json:"block_header,omitempty"`
+}
+
+type web3SignerAggregationSlot struct {
+	Slot phase0.Slot `json:"slot,string"`
+}
+
+type web3SignerRandaoReveal struct {
+	Epoch phase0.Epoch `json:"epoch,string"`
+}
+
+type web3SignerSyncCommitteeMessage struct {
+	BeaconBlockRoot string      `json:"beacon_block_root"`
+	Slot            phase0.Slot `json:"slot,string"`
+}
+
+type web3SignerSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+type web3SignerKey struct {
+	ValidatingPubkey string `json:"validating_pubkey"`
+	DerivationPath   string `json:"derivation_path,omitempty"`
+	Readonly         bool   `json:"readonly,omitempty"`
+}
+
+type web3SignerListKeysResponse struct {
+	Data []web3SignerKey `json:"data"`
+}
+
+type web3SignerImportRequest struct {
+	Keystores []string `json:"keystores"`
+	Passwords []string `json:"passwords"`
+}
+
+type web3SignerDeleteRequest struct {
+	Pubkeys []string `json:"pubkeys"`
+}
+
+type web3SignerStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type web3SignerStatusResponse struct {
+	Data []web3SignerStatus `json:"data"`
+}
+
+// web3SignerClient is a client of the Web3Signer HTTP API (EIP-3030) and its keymanager endpoints (EIP-3042)
+type web3SignerClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newWeb3SignerClient(baseURL string, timeout time.Duration) *web3SignerClient {
+	return &web3SignerClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// listKeys returns the 0x-prefixed public keys held by the remote signer
+func (c *web3SignerClient) listKeys() (map[string]struct{}, error) {
+	res := web3SignerListKeysResponse{}
+	if err := c.do(http.MethodGet, web3SignerKeystoresPath, nil, &res); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]struct{}, len(res.Data))
+	for _, key := range res.Data {
+		keys[strings.ToLower(key.ValidatingPubkey)] = struct{}{}
+	}
+	return keys, nil
+}
+
+// importKeystore imports the given EIP-2335 keystore, a keystore that already exists is not considered an error
+func (c *web3SignerClient) importKeystore(keystore map[string]interface{}, password string) error {
+	raw, err := json.Marshal(keystore)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal keystore")
+	}
+	res := web3SignerStatusResponse{}
+	req := web3SignerImportRequest{Keystores: []string{string(raw)}, Passwords: []string{password}}
+	if err := c.do(http.MethodPost, web3SignerKeystoresPath, req, &res); err != nil {
+		return err
+	}
+	return checkStatuses(res.Data, web3SignerStatusImported, web3SignerStatusDuplicate)
+}
+
+// deleteKeys deletes the given 0x-prefixed public keys, keys that don't exist are not considered an error
+func (c *web3SignerClient) deleteKeys(pubKeys ...string) error {
+	res := web3SignerStatusResponse{}
+	if err := c.do(http.MethodDelete, web3SignerKeystoresPath, web3SignerDeleteRequest{Pubkeys: pubKeys}, &res); err != nil {
+		return err
+	}
+	return checkStatuses(res.Data, web3SignerStatusDeleted, web3SignerStatusNotActive, web3SignerStatusNotFound)
+}
+
+// sign requests a signature of the given public key
+func (c *web3SignerClient) sign(pk []byte, req *web3SignerSignRequest) (spectypes.Signature, error) {
+	res := web3SignerSignResponse{}
+	if err := c.do(http.MethodPost, web3SignerSignPath+encodeHex(pk), req, &res); err != nil {
+		return nil, err
+	}
+	sig, err := decodeHex(res.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode signature")
+	}
+	return sig, nil
+}
+
+func (c *web3SignerClient) do(method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "could not marshal request")
+		}
+		reqBody = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "could not send request to %s", path)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "could not read response")
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.Errorf("remote signer responded with status %d: %s", res.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return errors.Wrap(err, "could not unmarshal response")
+	}
+	return nil
+}
+
+// checkStatuses returns an error if any of the given statuses is not one of the expected
+func checkStatuses(statuses []web3SignerStatus, expected ...string) error {
+	for _, s := range statuses {
+		ok := false
+		for _, e := range expected {
+			if s.Status == e {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return errors.Errorf("remote signer responded with status %s: %s", s.Status, s.Message)
+		}
+	}
+	return nil
+}
@@ -13,7 +13,9 @@ import (
 
 // Event represents an eth1 event log in the system
 type Event struct {
-	// Log is the raw event log
+	// Log is the raw event log.
+	// Log.Removed is set for events that were already handled and then removed by a chain reorg,
+	// observers are expected to revert the changes of such events.
 	Log types.Log
 	// Name is the event name used for internal representation.
 	Name string
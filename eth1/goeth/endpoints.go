@@ -0,0 +1,125 @@
+package goeth
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"net/url"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+)
+
+var errNoNodeAddrs = errors.New("no eth1 node address was provided")
+
+// ethClient is the subset of go-ethereum's client that is used to read contract events
+type ethClient interface {
+	ethereum.LogFilterer
+	ethereum.ChainSyncReader
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	Close()
+}
+
+// dialFunc connects to the eth1 node with the given address
+type dialFunc func(ctx context.Context, addr string) (ethClient, error)
+
+// dialEthClient connects to an eth1 node using go-ethereum's client, the transport is picked by the address scheme
+func dialEthClient(ctx context.Context, addr string) (ethClient, error) {
+	conn, err := ethclient.DialContext(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// supportsSubscriptions returns true if the transport of the given address can push logs (WebSocket or IPC),
+// otherwise logs have to be polled.
+func supportsSubscriptions(addr string) bool {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "ws", "wss", "":
+		return true
+	default:
+		return false
+	}
+}
+
+// redactAddr removes the parts of the address that might contain credentials (e.g. provider API keys)
+func redactAddr(addr string) string {
+	u, err := url.Parse(addr)
+	if err != nil || len(u.Host) == 0 {
+		return addr
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// logCursor tracks the position of the latest handled log,
+// so events streaming could resume after failover without missing or repeating logs.
+type logCursor struct {
+	mu          sync.Mutex
+	blockNumber uint64
+	index       uint
+	set         bool
+}
+
+// advance moves the cursor to the given log, returns false if the log was already handled
+func (c *logCursor) advance(vLog types.Log) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.set && (vLog.BlockNumber < c.blockNumber || (vLog.BlockNumber == c.blockNumber && vLog.Index <= c.index)) {
+		return false
+	}
+	c.blockNumber = vLog.BlockNumber
+	c.index = vLog.Index
+	c.set = true
+	return true
+}
+
+// setBlock marks all the logs up to (including) the given block as handled
+func (c *logCursor) setBlock(blockNumber uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.set && blockNumber < c.blockNumber {
+		return
+	}
+	c.blockNumber = blockNumber
+	c.index = math.MaxUint
+	c.set = true
+}
+
+// rewind moves the cursor back to the end of the given block, if it is ahead of it
+func (c *logCursor) rewind(blockNumber uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.set && c.blockNumber <= blockNumber {
+		return
+	}
+	c.blockNumber = blockNumber
+	c.index = math.MaxUint
+	c.set = true
+}
+
+// resumeBlock returns the block to resume fetching logs from, or false if no log was handled yet
+func (c *logCursor) resumeBlock() (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.set {
+		return 0, false
+	}
+	if c.index == math.MaxUint {
+		return c.blockNumber + 1, true
+	}
+	return c.blockNumber, true
+}
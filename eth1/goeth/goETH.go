@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bloxapp/ssv/logging/fields"
@@ -19,37 +20,67 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/async/event"
 	"go.uber.org/zap"
 )
 
 const (
-	healthCheckTimeout        = 10 * time.Second
-	blocksInBatch      uint64 = 100000
+	healthCheckTimeout             = 10 * time.Second
+	blocksInBatch           uint64 = 100000
+	defaultPollingInterval         = 12 * time.Second
+	defaultFailbackInterval        = time.Minute
 )
 
 // ClientOptions are the options for the client
 type ClientOptions struct {
 	Ctx                  context.Context
-	NodeAddr             string
+	NodeAddrs            []string
 	RegistryContractAddr string
 	ContractABI          string
 	ConnectionTimeout    time.Duration
+	PollingInterval      time.Duration
+	// FailbackInterval is the interval of checking whether a more preferred node is available again
+	FailbackInterval time.Duration
+	// FollowDistance is the number of confirmations required before handling logs
+	FollowDistance uint64
+	// FollowFinalized makes the client handle logs only once their block is finalized
+	FollowFinalized bool
+	// RecentLogsStorage persists the logs that were handled within the reorg window, optional
+	RecentLogsStorage eth1.RecentLogsStorage
 
 	AbiVersion eth1.Version
 }
 
 // eth1Client is the internal implementation of Client
 type eth1Client struct {
-	ctx  context.Context
-	conn *ethclient.Client
+	ctx context.Context
 
-	nodeAddr             string
+	connLock   sync.RWMutex
+	conn       ethClient
+	activeNode int
+
+	nodeAddrs            []string
+	dial                 dialFunc
 	registryContractAddr string
 	contractABI          string
 	connectionTimeout    time.Duration
+	pollingInterval      time.Duration
+	failbackInterval     time.Duration
+	followDistance       uint64
+	followFinalized      bool
+
+	// cursor is the position of the latest handled log
+	cursor logCursor
+	// recentLogs are the logs that were handled within the reorg window, ordered by their position in the chain
+	recentLogs        []types.Log
+	recentLogsLock    sync.Mutex
+	recentLogsStorage eth1.RecentLogsStorage
+
+	// streamLock serializes switching nodes, streamCancel stops streaming from the active node
+	streamLock   sync.Mutex
+	streamCancel context.CancelFunc
 
 	eventsFeed *event.Feed
 
@@ -59,19 +90,53 @@ type eth1Client struct {
 // verifies that the client implements HealthCheckAgent
 var _ metrics.HealthCheckAgent = &eth1Client{}
 
-// NewEth1Client creates a new instance
+// NewEth1Client creates a new instance.
+// Nodes are used in the given order of preference, the client fails back to a more preferred node once it's available again.
 func NewEth1Client(logger *zap.Logger, opts ClientOptions) (eth1.Client, error) {
+	ec, err := newEth1ClientWithDialer(logger, opts, dialEthClient)
+	if err != nil {
+		return nil, err
+	}
+	return ec, nil
+}
+
+func newEth1ClientWithDialer(logger *zap.Logger, opts ClientOptions, dial dialFunc) (*eth1Client, error) {
+	if len(opts.NodeAddrs) == 0 {
+		return nil, errNoNodeAddrs
+	}
+	pollingInterval := opts.PollingInterval
+	if pollingInterval == 0 {
+		pollingInterval = defaultPollingInterval
+	}
+	failbackInterval := opts.FailbackInterval
+	if failbackInterval == 0 {
+		failbackInterval = defaultFailbackInterval
+	}
 	ec := eth1Client{
 		ctx:                  opts.Ctx,
-		nodeAddr:             opts.NodeAddr,
+		nodeAddrs:            opts.NodeAddrs,
+		dial:                 dial,
 		registryContractAddr: opts.RegistryContractAddr,
 		contractABI:          opts.ContractABI,
 		connectionTimeout:    opts.ConnectionTimeout,
+		pollingInterval:      pollingInterval,
+		failbackInterval:     failbackInterval,
+		followDistance:       opts.FollowDistance,
+		followFinalized:      opts.FollowFinalized,
+		recentLogsStorage:    opts.RecentLogsStorage,
 		eventsFeed:           new(event.Feed),
 		abiVersion:           opts.AbiVersion,
 	}
 
-	if err := ec.connect(logger); err != nil {
+	if ec.recentLogsStorage != nil {
+		recentLogs, err := ec.recentLogsStorage.GetRecentLogs()
+		if err != nil {
+			logger.Warn("could not load recent logs, reorgs that happened while the node was down won't be reverted", zap.Error(err))
+		}
+		ec.recentLogs = recentLogs
+	}
+
+	if err := ec.connect(logger, 0); err != nil {
 		logger.Error("failed to connect to the execution client", zap.Error(err))
 		return nil, err
 	}
@@ -87,11 +152,17 @@ func (ec *eth1Client) EventsFeed() *event.Feed {
 // Start streams events from the contract
 func (ec *eth1Client) Start(logger *zap.Logger) error {
 	logger = logger.Named(logging.NameEthClient)
+	ec.streamLock.Lock()
 	err := ec.streamSmartContractEvents(logger)
+	ec.streamLock.Unlock()
 	if err != nil {
 		logger.Error("Failed to init operator contract address subject", zap.Error(err))
+		return err
 	}
-	return err
+	if len(ec.nodeAddrs) > 1 {
+		go ec.failback(logger)
+	}
+	return nil
 }
 
 // Sync reads events history
@@ -105,20 +176,22 @@ func (ec *eth1Client) Sync(logger *zap.Logger, fromBlock *big.Int) error {
 
 // HealthCheck provides health status of eth1 node
 func (ec *eth1Client) HealthCheck() []string {
-	if ec.conn == nil {
+	conn, index := ec.activeConn()
+	if conn == nil {
 		return []string{"not connected to eth1 node"}
 	}
+	node := fmt.Sprintf("eth1 node %d (%s)", index, redactAddr(ec.nodeAddrs[index]))
 	ctx, cancel := context.WithTimeout(ec.ctx, healthCheckTimeout)
 	defer cancel()
-	sp, err := ec.conn.SyncProgress(ctx)
+	sp, err := conn.SyncProgress(ctx)
 	if err != nil {
 		reportNodeStatus(statusUnknown)
-		return []string{"could not get eth1 node sync progress"}
+		return []string{fmt.Sprintf("%s: could not get sync progress", node)}
 	}
 	if sp != nil {
 		reportNodeStatus(statusSyncing)
-		return []string{fmt.Sprintf("eth1 node is currently syncing: starting=%d, current=%d, highest=%d",
-			sp.StartingBlock, sp.CurrentBlock, sp.HighestBlock)}
+		return []string{fmt.Sprintf("%s: currently syncing: starting=%d, current=%d, highest=%d",
+			node, sp.StartingBlock, sp.CurrentBlock, sp.HighestBlock)}
 	}
 	// eth1 node is connected and synced
 	reportNodeStatus(statusOK)
@@ -126,28 +199,138 @@ func (ec *eth1Client) HealthCheck() []string {
 	return []string{}
 }
 
-// connect connects to eth1 client
-func (ec *eth1Client) connect(logger *zap.Logger) error {
-	// Create an IPC based RPC connection to a remote node
-	logger.Info("execution client: connecting", fields.Address(ec.nodeAddr))
-	ctx, cancel := context.WithTimeout(context.Background(), ec.connectionTimeout)
-	defer cancel()
-	conn, err := ethclient.DialContext(ctx, ec.nodeAddr)
+// activeConn returns the connection to the active node and its index
+func (ec *eth1Client) activeConn() (ethClient, int) {
+	ec.connLock.RLock()
+	defer ec.connLock.RUnlock()
+
+	return ec.conn, ec.activeNode
+}
+
+// setConn replaces the active connection, the previous one is closed
+func (ec *eth1Client) setConn(conn ethClient, index int) {
+	ec.connLock.Lock()
+	defer ec.connLock.Unlock()
+
+	if ec.conn != nil && ec.conn != conn {
+		ec.conn.Close()
+	}
+	ec.conn = conn
+	ec.activeNode = index
+	metricsEth1ActiveNode.Set(float64(index))
+}
+
+// nextNode returns the index of the node that follows the active node
+func (ec *eth1Client) nextNode() int {
+	_, index := ec.activeConn()
+	return (index + 1) % len(ec.nodeAddrs)
+}
+
+// connect connects to the first available eth1 node, starting from the given index
+func (ec *eth1Client) connect(logger *zap.Logger, start int) error {
+	var err error
+	for i := range ec.nodeAddrs {
+		index := (start + i) % len(ec.nodeAddrs)
+		if err = ec.connectNode(logger, index); err == nil {
+			return nil
+		}
+		logger.Warn("execution client: can't connect", zap.Int("node", index), zap.Error(err))
+	}
+	return err
+}
+
+// connectNode connects to the eth1 node with the given index, and checks it's reachable
+func (ec *eth1Client) connectNode(logger *zap.Logger, index int) error {
+	logger.Info("execution client: connecting", zap.Int("node", index), fields.Address(redactAddr(ec.nodeAddrs[index])))
+	conn, err := ec.dialNode(index, false)
 	if err != nil {
-		logger.Error("execution client: can't connect", zap.Error(err))
 		return err
 	}
-	logger.Info("execution client: connected")
-	ec.conn = conn
+	logger.Info("execution client: connected", zap.Int("node", index))
+	ec.setConn(conn, index)
 	return nil
 }
 
-// reconnect tries to reconnect multiple times with an exponent interval
+// dialNode connects to the eth1 node with the given index and checks it's reachable,
+// and if requested that it's synced.
+func (ec *eth1Client) dialNode(index int, synced bool) (ethClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ec.connectionTimeout)
+	defer cancel()
+	conn, err := ec.dial(ctx, ec.nodeAddrs[index])
+	if err != nil {
+		return nil, err
+	}
+	// HTTP connections are lazy, a request is needed to ensure the node is reachable
+	if _, err := conn.BlockNumber(ctx); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to get current block")
+	}
+	if synced {
+		sp, err := conn.SyncProgress(ctx)
+		if err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed to get sync progress")
+		}
+		if sp != nil {
+			conn.Close()
+			return nil, errors.New("node is syncing")
+		}
+	}
+	return conn, nil
+}
+
+// failback periodically checks whether a node that is preferred over the active node is available again,
+// and if so moves streaming to it, until the context is done
+func (ec *eth1Client) failback(logger *zap.Logger) {
+	ticker := time.NewTicker(ec.failbackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ec.ctx.Done():
+			return
+		case <-ticker.C:
+			ec.failbackOnce(logger)
+		}
+	}
+}
+
+// failbackOnce moves streaming to the most preferred synced node, if it's preferred over the active node
+func (ec *eth1Client) failbackOnce(logger *zap.Logger) {
+	ec.streamLock.Lock()
+	defer ec.streamLock.Unlock()
+
+	_, active := ec.activeConn()
+	for index := 0; index < active; index++ {
+		conn, err := ec.dialNode(index, true)
+		if err != nil {
+			continue
+		}
+		logger.Info("execution client: failing back to preferred node", zap.Int("node", index), zap.Int("active_node", active))
+		ec.stopStreaming()
+		ec.setConn(conn, index)
+		if err := ec.streamSmartContractEvents(logger); err != nil {
+			logger.Warn("could not stream events from preferred node", zap.Int("node", index), zap.Error(err))
+			go ec.reconnect(logger)
+		}
+		return
+	}
+}
+
+// reconnect fails over to the next available node and resumes streaming events,
+// it tries multiple times with an exponent interval
 func (ec *eth1Client) reconnect(logger *zap.Logger) {
 	limit := 64 * time.Second
 	tasks.ExecWithInterval(func(lastTick time.Duration) (stop bool, cont bool) {
 		logger.Info("reconnecting")
-		if err := ec.connect(logger); err != nil {
+		ec.streamLock.Lock()
+		ec.stopStreaming()
+		err := ec.connect(logger, ec.nextNode())
+		if err == nil {
+			err = ec.streamSmartContractEvents(logger)
+		}
+		ec.streamLock.Unlock()
+		if err != nil {
 			// continue until reaching to limit, and then panic as eth1 connection is required
 			if lastTick >= limit {
 				logger.Panic("failed to reconnect", zap.Error(err))
@@ -159,10 +342,6 @@ func (ec *eth1Client) reconnect(logger *zap.Logger) {
 		return true, false
 	}, 1*time.Second, limit+(1*time.Second))
 	logger.Debug("managed to reconnect")
-	if err := ec.streamSmartContractEvents(logger); err != nil {
-		// TODO: panic?
-		logger.Error("failed to stream events after reconnection", zap.Error(err))
-	}
 }
 
 // fireEvent notifies observers about some contract event
@@ -173,7 +352,18 @@ func (ec *eth1Client) fireEvent(log types.Log, name string, data interface{}) {
 	// logger.Debug("events was sent to subscribers", zap.Int("num of subscribers", n))
 }
 
-// streamSmartContractEvents sync events history of the given contract
+// stopStreaming stops streaming events from the active node, the caller must hold streamLock
+func (ec *eth1Client) stopStreaming() {
+	if ec.streamCancel != nil {
+		ec.streamCancel()
+		ec.streamCancel = nil
+	}
+}
+
+// streamSmartContractEvents streams new events of the given contract from the active node, the caller must hold streamLock.
+// Logs are subscribed to when following the head of the chain, otherwise new heads are subscribed to
+// and logs are fetched once they have enough confirmations. If the node doesn't support subscriptions, logs are polled.
+// Streaming fails over to the next node on error, unless it was stopped.
 func (ec *eth1Client) streamSmartContractEvents(logger *zap.Logger) error {
 	logger.Debug("streaming smart contract events")
 
@@ -182,13 +372,34 @@ func (ec *eth1Client) streamSmartContractEvents(logger *zap.Logger) error {
 		return errors.Wrap(err, "failed to parse ABI interface")
 	}
 
-	sub, logs, err := ec.subscribeToLogs(logger)
-	if err != nil {
-		return errors.Wrap(err, "Failed to subscribe to logs")
+	conn, index := ec.activeConn()
+	if conn == nil {
+		return errors.New("not connected to eth1 node")
 	}
 
+	if supportsSubscriptions(ec.nodeAddrs[index]) {
+		if ec.followsHead() {
+			err = ec.streamSubscribedLogs(logger, conn, contractAbi)
+		} else {
+			err = ec.streamOnNewHeads(logger, conn, contractAbi)
+		}
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			return err
+		}
+		logger.Warn("eth1 node doesn't support subscriptions, falling back to polling", zap.Int("node", index))
+	}
+
+	logger.Debug("polling for contract events", zap.Duration("interval", ec.pollingInterval))
+	if err := ec.fetchNewLogs(logger, conn, contractAbi); err != nil {
+		return errors.Wrap(err, "failed to fetch missed logs")
+	}
+	ctx, cancel := context.WithCancel(ec.ctx)
+	ec.streamCancel = cancel
 	go func() {
-		if err := ec.listenToSubscription(logger, logs, sub, contractAbi); err != nil {
+		if err := ec.pollLogs(ctx, logger, conn, contractAbi); err != nil && ctx.Err() == nil {
 			ec.reconnect(logger)
 		}
 	}()
@@ -196,13 +407,58 @@ func (ec *eth1Client) streamSmartContractEvents(logger *zap.Logger) error {
 	return nil
 }
 
-func (ec *eth1Client) subscribeToLogs(logger *zap.Logger) (ethereum.Subscription, chan types.Log, error) {
+// streamSubscribedLogs handles the logs that are pushed by the node as soon as they are included in a block
+func (ec *eth1Client) streamSubscribedLogs(logger *zap.Logger, conn ethClient, contractAbi abi.ABI) error {
+	sub, logs, err := ec.subscribeToLogs(logger, conn)
+	if err != nil {
+		return err
+	}
+	// logs that were missed while switching nodes are fetched after subscribing,
+	// the ones that are received from both are handled once
+	if err := ec.fetchNewLogs(logger, conn, contractAbi); err != nil {
+		sub.Unsubscribe()
+		return errors.Wrap(err, "failed to fetch missed logs")
+	}
+	ctx, cancel := context.WithCancel(ec.ctx)
+	ec.streamCancel = cancel
+	go func() {
+		if err := ec.listenToSubscription(ctx, logger, logs, sub, contractAbi); err != nil && ctx.Err() == nil {
+			ec.reconnect(logger)
+		}
+	}()
+	return nil
+}
+
+// streamOnNewHeads fetches new logs whenever the node announces a new block,
+// only the logs up to the safe head are handled so the follow distance is respected
+func (ec *eth1Client) streamOnNewHeads(logger *zap.Logger, conn ethClient, contractAbi abi.ABI) error {
+	heads := make(chan *types.Header)
+	sub, err := conn.SubscribeNewHead(ec.ctx, heads)
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe to new heads")
+	}
+	logger.Debug("subscribed to new heads")
+	if err := ec.fetchNewLogs(logger, conn, contractAbi); err != nil {
+		sub.Unsubscribe()
+		return errors.Wrap(err, "failed to fetch missed logs")
+	}
+	ctx, cancel := context.WithCancel(ec.ctx)
+	ec.streamCancel = cancel
+	go func() {
+		if err := ec.listenToNewHeads(ctx, logger, conn, heads, sub, contractAbi); err != nil && ctx.Err() == nil {
+			ec.reconnect(logger)
+		}
+	}()
+	return nil
+}
+
+func (ec *eth1Client) subscribeToLogs(logger *zap.Logger, conn ethClient) (ethereum.Subscription, chan types.Log, error) {
 	contractAddress := common.HexToAddress(ec.registryContractAddr)
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{contractAddress},
 	}
 	logs := make(chan types.Log)
-	sub, err := ec.conn.SubscribeFilterLogs(ec.ctx, query, logs)
+	sub, err := conn.SubscribeFilterLogs(ec.ctx, query, logs)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "Failed to subscribe to logs")
 	}
@@ -211,32 +467,313 @@ func (ec *eth1Client) subscribeToLogs(logger *zap.Logger) (ethereum.Subscription
 	return sub, logs, nil
 }
 
-// listenToSubscription listen to new event logs from the contract
-func (ec *eth1Client) listenToSubscription(logger *zap.Logger, logs chan types.Log, sub ethereum.Subscription, contractAbi abi.ABI) error {
+// listenToSubscription listen to new event logs from the contract, until the given context is done
+func (ec *eth1Client) listenToSubscription(ctx context.Context, logger *zap.Logger, logs chan types.Log, sub ethereum.Subscription, contractAbi abi.ABI) error {
 	for {
 		select {
+		case <-ctx.Done():
+			sub.Unsubscribe()
+			return nil
 		case err := <-sub.Err():
 			logger.Warn("failed to read logs from subscription", zap.Error(err))
 			return err
 		case vLog := <-logs:
-			if vLog.Removed {
-				continue
+			ec.handleNewLog(logger, vLog, contractAbi)
+		}
+	}
+}
+
+// listenToNewHeads fetches new event logs from the contract on every new head, until the given context is done
+func (ec *eth1Client) listenToNewHeads(ctx context.Context, logger *zap.Logger, conn ethClient, heads chan *types.Header, sub ethereum.Subscription, contractAbi abi.ABI) error {
+	for {
+		select {
+		case <-ctx.Done():
+			sub.Unsubscribe()
+			return nil
+		case err := <-sub.Err():
+			logger.Warn("failed to read new heads from subscription", zap.Error(err))
+			return err
+		case <-heads:
+			if err := ec.fetchNewLogs(logger, conn, contractAbi); err != nil {
+				logger.Warn("failed to fetch new logs", zap.Error(err))
+				sub.Unsubscribe()
+				return err
 			}
-			logger.Debug("received contract event from stream")
-			eventName, err := ec.handleEvent(logger, vLog, contractAbi)
-			if err != nil {
-				logger.Warn("could not parse ongoing event, the event is malformed",
-					fields.EventName(eventName),
-					fields.BlockNumber(vLog.BlockNumber),
-					fields.TxHash(vLog.TxHash),
-					zap.Error(err),
-				)
-				continue
+		}
+	}
+}
+
+// pollLogs periodically fetches new event logs from the contract, until the given context is done
+func (ec *eth1Client) pollLogs(ctx context.Context, logger *zap.Logger, conn ethClient, contractAbi abi.ABI) error {
+	ticker := time.NewTicker(ec.pollingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := ec.fetchNewLogs(logger, conn, contractAbi); err != nil {
+				logger.Warn("failed to poll logs", zap.Error(err))
+				return err
 			}
 		}
 	}
 }
 
+// followsHead returns true if logs are handled as soon as they are included in a block
+func (ec *eth1Client) followsHead() bool {
+	return ec.followDistance == 0 && !ec.followFinalized
+}
+
+// safeHead returns the latest block whose logs can be handled, according to the follow distance
+func (ec *eth1Client) safeHead(conn ethClient) (uint64, error) {
+	if ec.followFinalized {
+		header, err := conn.HeaderByNumber(ec.ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get finalized block")
+		}
+		return header.Number.Uint64(), nil
+	}
+	currentBlock, err := conn.BlockNumber(ec.ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get current block")
+	}
+	if currentBlock < ec.followDistance {
+		return 0, nil
+	}
+	return currentBlock - ec.followDistance, nil
+}
+
+// fetchNewLogs fetches and handles the logs since the latest handled log, up to the safe head.
+// Logs that were handled within the reorg window are checked first, and reverted if they were removed from the chain.
+func (ec *eth1Client) fetchNewLogs(logger *zap.Logger, conn ethClient, contractAbi abi.ABI) error {
+	safeHead, err := ec.safeHead(conn)
+	if err != nil {
+		return err
+	}
+	fromBlock, ok := ec.cursor.resumeBlock()
+	if !ok {
+		// nothing was handled yet, streaming starts from the safe head
+		ec.cursor.setBlock(safeHead)
+		return nil
+	}
+	if err := ec.checkReorg(logger, conn, contractAbi); err != nil {
+		return errors.Wrap(err, "failed to check for reorgs")
+	}
+	fromBlock, _ = ec.cursor.resumeBlock()
+	contractAddress := common.HexToAddress(ec.registryContractAddr)
+	for fromBlock <= safeHead {
+		toBlock := safeHead
+		if toBlock-fromBlock > blocksInBatch {
+			toBlock = fromBlock + blocksInBatch
+		}
+		logs, err := conn.FilterLogs(ec.ctx, ethereum.FilterQuery{
+			Addresses: []common.Address{contractAddress},
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get event logs")
+		}
+		for _, vLog := range logs {
+			ec.handleNewLog(logger, vLog, contractAbi)
+		}
+		ec.cursor.setBlock(toBlock)
+		fromBlock = toBlock + 1
+	}
+	ec.pruneRecentLogs(logger, safeHead)
+	return nil
+}
+
+// checkReorg fetches again the logs of the reorg window, and reverts the handled logs that are no longer part of the chain
+func (ec *eth1Client) checkReorg(logger *zap.Logger, conn ethClient, contractAbi abi.ABI) error {
+	ec.recentLogsLock.Lock()
+	if len(ec.recentLogs) == 0 {
+		ec.recentLogsLock.Unlock()
+		return nil
+	}
+	fromBlock := ec.recentLogs[0].BlockNumber
+	toBlock := ec.recentLogs[len(ec.recentLogs)-1].BlockNumber
+	ec.recentLogsLock.Unlock()
+
+	logs, err := conn.FilterLogs(ec.ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{common.HexToAddress(ec.registryContractAddr)},
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get event logs")
+	}
+	canonical := make(map[logKey]bool, len(logs))
+	for _, vLog := range logs {
+		canonical[keyOf(vLog)] = true
+	}
+
+	ec.recentLogsLock.Lock()
+	var removed []types.Log
+	for _, vLog := range ec.recentLogs {
+		if !canonical[keyOf(vLog)] {
+			removed = append(removed, vLog)
+		}
+	}
+	ec.recentLogsLock.Unlock()
+
+	// reverting in reverse order, so the changes of each log are undone on top of the state it created
+	for i := len(removed) - 1; i >= 0; i-- {
+		vLog := removed[i]
+		vLog.Removed = true
+		ec.handleNewLog(logger, vLog, contractAbi)
+	}
+	return nil
+}
+
+// logKey identifies a log in a specific block
+type logKey struct {
+	blockHash common.Hash
+	txHash    common.Hash
+	index     uint
+}
+
+func keyOf(vLog types.Log) logKey {
+	return logKey{blockHash: vLog.BlockHash, txHash: vLog.TxHash, index: vLog.Index}
+}
+
+// trackRecentLog keeps the given handled log, so it could be reverted in case of a reorg
+func (ec *eth1Client) trackRecentLog(logger *zap.Logger, vLog types.Log) {
+	ec.recentLogsLock.Lock()
+	defer ec.recentLogsLock.Unlock()
+
+	key := keyOf(vLog)
+	i := len(ec.recentLogs)
+	for i > 0 && !logBefore(ec.recentLogs[i-1], vLog) {
+		if keyOf(ec.recentLogs[i-1]) == key {
+			// already tracked, e.g. a log that was tracked before a restart and was synced again
+			return
+		}
+		i--
+	}
+	ec.recentLogs = append(ec.recentLogs, types.Log{})
+	copy(ec.recentLogs[i+1:], ec.recentLogs[i:])
+	ec.recentLogs[i] = vLog
+	ec.saveRecentLogs(logger)
+}
+
+// isRecentLog returns true if the given log was already handled within the reorg window
+func (ec *eth1Client) isRecentLog(vLog types.Log) bool {
+	ec.recentLogsLock.Lock()
+	defer ec.recentLogsLock.Unlock()
+
+	key := keyOf(vLog)
+	for _, recentLog := range ec.recentLogs {
+		if keyOf(recentLog) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// untrackRecentLog removes the given log from the recent logs, returns false if it wasn't tracked
+func (ec *eth1Client) untrackRecentLog(logger *zap.Logger, vLog types.Log) bool {
+	ec.recentLogsLock.Lock()
+	defer ec.recentLogsLock.Unlock()
+
+	key := keyOf(vLog)
+	for i, recentLog := range ec.recentLogs {
+		if keyOf(recentLog) == key {
+			ec.recentLogs = append(ec.recentLogs[:i], ec.recentLogs[i+1:]...)
+			ec.saveRecentLogs(logger)
+			return true
+		}
+	}
+	return false
+}
+
+// pruneRecentLogs stops tracking logs that are out of the reorg window
+func (ec *eth1Client) pruneRecentLogs(logger *zap.Logger, safeHead uint64) {
+	ec.recentLogsLock.Lock()
+	defer ec.recentLogsLock.Unlock()
+
+	i := 0
+	for i < len(ec.recentLogs) && ec.recentLogs[i].BlockNumber+eth1.ReorgWindow < safeHead {
+		i++
+	}
+	if i == 0 {
+		return
+	}
+	ec.recentLogs = ec.recentLogs[i:]
+	ec.saveRecentLogs(logger)
+}
+
+// saveRecentLogs persists the recent logs, so reorgs that happen while the node is down are reverted once it's back up.
+// The caller must hold recentLogsLock.
+func (ec *eth1Client) saveRecentLogs(logger *zap.Logger) {
+	if ec.recentLogsStorage == nil {
+		return
+	}
+	if err := ec.recentLogsStorage.SaveRecentLogs(ec.recentLogs); err != nil {
+		logger.Warn("could not save recent logs", zap.Error(err))
+	}
+}
+
+// logBefore returns true if the log a comes before the log b in the chain
+func logBefore(a, b types.Log) bool {
+	return a.BlockNumber < b.BlockNumber || (a.BlockNumber == b.BlockNumber && a.Index < b.Index)
+}
+
+// handleNewLog handles a log that was received after the history sync.
+// Logs that were already handled are skipped, removed logs are reverted if they were handled.
+func (ec *eth1Client) handleNewLog(logger *zap.Logger, vLog types.Log, contractAbi abi.ABI) {
+	if vLog.Removed {
+		ec.revertLog(logger, vLog, contractAbi)
+		return
+	}
+	if !ec.cursor.advance(vLog) {
+		return
+	}
+	// the cursor is rewound to the first reverted log, so the logs after it that weren't removed are fetched again
+	if ec.isRecentLog(vLog) {
+		return
+	}
+	logger.Debug("received contract event from stream")
+	eventName, err := ec.handleEvent(logger, vLog, contractAbi)
+	if err != nil {
+		logger.Warn("could not parse ongoing event, the event is malformed",
+			fields.EventName(eventName),
+			fields.BlockNumber(vLog.BlockNumber),
+			fields.TxHash(vLog.TxHash),
+			zap.Error(err),
+		)
+		return
+	}
+	ec.trackRecentLog(logger, vLog)
+	ec.pruneRecentLogs(logger, vLog.BlockNumber)
+}
+
+// revertLog notifies observers about a handled log that was removed by a reorg,
+// the cursor is moved back so the logs of the new chain are handled from the reorged block,
+// while the logs after it that are still tracked are not handled again
+func (ec *eth1Client) revertLog(logger *zap.Logger, vLog types.Log, contractAbi abi.ABI) {
+	if !ec.untrackRecentLog(logger, vLog) {
+		return
+	}
+	logger.Warn("contract event was removed by a chain reorg, reverting",
+		fields.BlockNumber(vLog.BlockNumber),
+		fields.TxHash(vLog.TxHash),
+	)
+	if vLog.BlockNumber > 0 {
+		ec.cursor.rewind(vLog.BlockNumber - 1)
+	}
+	if eventName, err := ec.handleEvent(logger, vLog, contractAbi); err != nil {
+		logger.Warn("could not parse removed event",
+			fields.EventName(eventName),
+			fields.BlockNumber(vLog.BlockNumber),
+			fields.TxHash(vLog.TxHash),
+			zap.Error(err),
+		)
+	}
+}
+
 // syncSmartContractsEvents sync events history of the given contract
 func (ec *eth1Client) syncSmartContractsEvents(logger *zap.Logger, fromBlock *big.Int) error {
 	logger.Debug("syncing smart contract events", fields.FromBlock(fromBlock))
@@ -245,20 +782,32 @@ func (ec *eth1Client) syncSmartContractsEvents(logger *zap.Logger, fromBlock *bi
 	if err != nil {
 		return errors.Wrap(err, "failed to parse ABI interface")
 	}
-	currentBlock, err := ec.conn.BlockNumber(ec.ctx)
+	conn, _ := ec.activeConn()
+	if conn == nil {
+		return errors.New("not connected to eth1 node")
+	}
+	// logs that were handled before a restart are reverted first in case a reorg removed them while the node was down,
+	// so the history sync would handle the logs of the new chain on top of the reverted state
+	if err := ec.checkReorg(logger, conn, contractAbi); err != nil {
+		return errors.Wrap(err, "failed to check for reorgs")
+	}
+	// logs of blocks without enough confirmations are handled later on, when streaming
+	safeHead, err := ec.safeHead(conn)
 	if err != nil {
-		return errors.Wrap(err, "failed to get current block")
+		return err
 	}
 	var logs []types.Log
 	var nSuccess int
-	for {
+	for fromBlock.Uint64() <= safeHead {
 		var toBlock *big.Int
-		if currentBlock-fromBlock.Uint64() > blocksInBatch {
+		last := false
+		if safeHead-fromBlock.Uint64() > blocksInBatch {
 			toBlock = big.NewInt(int64(fromBlock.Uint64() + blocksInBatch))
-		} else { // no more batches are required -> setting toBlock to nil
-			toBlock = nil
+		} else { // no more batches are required -> syncing up to the safe head
+			toBlock = new(big.Int).SetUint64(safeHead)
+			last = true
 		}
-		_logs, _nSuccess, err := ec.fetchAndProcessEvents(logger, fromBlock, toBlock, contractAbi)
+		_logs, _nSuccess, err := ec.fetchAndProcessEvents(logger, conn, fromBlock, toBlock, contractAbi)
 		if err != nil {
 			// in case request exceeded limit, try again with less blocks
 			// will stop after log(blocksInBatch) tries
@@ -266,12 +815,13 @@ func (ec *eth1Client) syncSmartContractsEvents(logger *zap.Logger, fromBlock *bi
 				return errors.Wrap(err, "failed to get events")
 			}
 			currentBatchSize := int64(blocksInBatch)
+			last = false
 		retryLoop:
 			for currentBatchSize > 1 {
 				currentBatchSize /= 2
 				logger.Debug("using a lower batch size", zap.Int64("currentBatchSize", currentBatchSize))
 				toBlock = big.NewInt(int64(fromBlock.Uint64()) + currentBatchSize)
-				_logs, _nSuccess, err = ec.fetchAndProcessEvents(logger, fromBlock, toBlock, contractAbi)
+				_logs, _nSuccess, err = ec.fetchAndProcessEvents(logger, conn, fromBlock, toBlock, contractAbi)
 				if err != nil {
 					if !strings.Contains(err.Error(), "websocket: read limit exceeded") {
 						return errors.Wrap(err, "failed to get events")
@@ -285,11 +835,19 @@ func (ec *eth1Client) syncSmartContractsEvents(logger *zap.Logger, fromBlock *bi
 		}
 		nSuccess += _nSuccess
 		logs = append(logs, _logs...)
-		if toBlock == nil { // finished
+		if last { // finished
 			break
 		}
 		fromBlock = toBlock
 	}
+	// logs up to the safe head were handled, newer logs are handled when streaming
+	for _, vLog := range logs {
+		ec.cursor.advance(vLog)
+		if vLog.BlockNumber+eth1.ReorgWindow >= safeHead {
+			ec.trackRecentLog(logger, vLog)
+		}
+	}
+	ec.cursor.setBlock(safeHead)
 	logger.Debug("finished syncing registry contract",
 		zap.Int("total events", len(logs)), zap.Int("total success", nSuccess))
 	// publishing SyncEndedEvent so other components could track the sync
@@ -298,19 +856,17 @@ func (ec *eth1Client) syncSmartContractsEvents(logger *zap.Logger, fromBlock *bi
 	return nil
 }
 
-func (ec *eth1Client) fetchAndProcessEvents(logger *zap.Logger, fromBlock, toBlock *big.Int, contractAbi abi.ABI) ([]types.Log, int, error) {
+func (ec *eth1Client) fetchAndProcessEvents(logger *zap.Logger, conn ethClient, fromBlock, toBlock *big.Int, contractAbi abi.ABI) ([]types.Log, int, error) {
 	logger = logger.With(fields.FromBlock(fromBlock))
 	contractAddress := common.HexToAddress(ec.registryContractAddr)
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{contractAddress},
 		FromBlock: fromBlock,
+		ToBlock:   toBlock,
 	}
-	if toBlock != nil {
-		query.ToBlock = toBlock
-		logger = logger.With(fields.ToBlock(toBlock))
-	}
+	logger = logger.With(fields.ToBlock(toBlock))
 	logger.Debug("fetching event logs")
-	logs, err := ec.conn.FilterLogs(ec.ctx, query)
+	logs, err := conn.FilterLogs(ec.ctx, query)
 	if err != nil {
 		return nil, 0, errors.Wrap(err, "failed to get event logs")
 	}
@@ -366,13 +922,12 @@ func (ec *eth1Client) handleEvent(logger *zap.Logger, vLog types.Log, contractAb
 		}
 		ec.fireEvent(vLog, ev.Name, *parsed)
 	case abiparser.OperatorRemoved:
-		_, err = abiParser.ParseOperatorRemovedEvent(vLog, contractAbi)
+		parsed, err := abiParser.ParseOperatorRemovedEvent(vLog, contractAbi)
 		reportSyncEvent(ev.Name, err)
 		if err != nil {
 			return ev.Name, err
 		}
-		// skip
-		//ec.fireEvent(vLog, ev.Name, *parsed)
+		ec.fireEvent(vLog, ev.Name, *parsed)
 	case abiparser.ValidatorAdded:
 		parsed, err := abiParser.ParseValidatorAddedEvent(vLog, contractAbi)
 		reportSyncEvent(ev.Name, err)
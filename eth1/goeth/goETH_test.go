@@ -3,14 +3,21 @@ package goeth
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"math/big"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/bloxapp/ssv/logging"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/async/event"
 	"github.com/stretchr/testify/require"
 
@@ -96,6 +103,687 @@ func newEth1Client(abiVersion eth1.Version) *eth1Client {
 	return &ec
 }
 
+// simChain is a simulated chain, shared by the fake eth1 nodes
+type simChain struct {
+	mu        sync.Mutex
+	blocks    []simBlock
+	finalized uint64
+	forks     int
+	subs      []*fakeSubscription
+}
+
+type simBlock struct {
+	hash common.Hash
+	logs []types.Log
+}
+
+func newSimChain() *simChain {
+	c := &simChain{}
+	c.blocks = append(c.blocks, simBlock{hash: c.blockHash(0)})
+	return c
+}
+
+func (c *simChain) blockHash(number uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("block %d fork %d", number, c.forks)))
+}
+
+func (c *simChain) head() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return uint64(len(c.blocks) - 1)
+}
+
+// mine adds a block with the given logs and pushes them to subscribers
+func (c *simChain) mine(logs ...types.Log) {
+	c.mu.Lock()
+	number := uint64(len(c.blocks))
+	block := simBlock{hash: c.blockHash(number)}
+	for i, vLog := range logs {
+		vLog.BlockNumber = number
+		vLog.BlockHash = block.hash
+		vLog.Index = uint(i)
+		block.logs = append(block.logs, vLog)
+	}
+	c.blocks = append(c.blocks, block)
+	subs := append([]*fakeSubscription{}, c.subs...)
+	c.mu.Unlock()
+
+	c.push(subs, block.logs)
+	c.pushHead(subs, &types.Header{Number: new(big.Int).SetUint64(number)})
+}
+
+// mineEmpty adds n blocks without logs
+func (c *simChain) mineEmpty(n int) {
+	for i := 0; i < n; i++ {
+		c.mine()
+	}
+}
+
+// reorg drops the given number of blocks from the head, subscribers are notified about the removed logs
+func (c *simChain) reorg(depth int) {
+	c.mu.Lock()
+	var removed []types.Log
+	for i := len(c.blocks) - 1; i >= len(c.blocks)-depth; i-- {
+		for j := len(c.blocks[i].logs) - 1; j >= 0; j-- {
+			vLog := c.blocks[i].logs[j]
+			vLog.Removed = true
+			removed = append(removed, vLog)
+		}
+	}
+	c.blocks = c.blocks[:len(c.blocks)-depth]
+	c.forks++
+	subs := append([]*fakeSubscription{}, c.subs...)
+	c.mu.Unlock()
+
+	c.push(subs, removed)
+}
+
+// removeLogs drops the logs of the given block, while the blocks after it are kept
+func (c *simChain) removeLogs(number uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.blocks[number].logs = nil
+}
+
+func (c *simChain) finalize(number uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.finalized = number
+}
+
+func (c *simChain) push(subs []*fakeSubscription, logs []types.Log) {
+	for _, sub := range subs {
+		if sub.logs == nil {
+			continue
+		}
+		for _, vLog := range logs {
+			select {
+			case sub.logs <- vLog:
+			case <-sub.done:
+			}
+		}
+	}
+}
+
+func (c *simChain) pushHead(subs []*fakeSubscription, header *types.Header) {
+	for _, sub := range subs {
+		if sub.heads == nil {
+			continue
+		}
+		select {
+		case sub.heads <- header:
+		case <-sub.done:
+		}
+	}
+}
+
+func (c *simChain) subscribe(sub *fakeSubscription) *fakeSubscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub.chain = c
+	sub.errs = make(chan error, 1)
+	sub.done = make(chan struct{})
+	c.subs = append(c.subs, sub)
+	return sub
+}
+
+func (c *simChain) unsubscribe(sub *fakeSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, s := range c.subs {
+		if s == sub {
+			c.subs = append(c.subs[:i], c.subs[i+1:]...)
+			// pushing to a subscription that was already picked is dropped, like go-ethereum does
+			close(sub.done)
+			return
+		}
+	}
+}
+
+func (c *simChain) filterLogs(from, to uint64) []types.Log {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var logs []types.Log
+	for number := from; number <= to && number < uint64(len(c.blocks)); number++ {
+		logs = append(logs, c.blocks[number].logs...)
+	}
+	return logs
+}
+
+type fakeSubscription struct {
+	chain *simChain
+	logs  chan<- types.Log
+	heads chan<- *types.Header
+	errs  chan error
+	done  chan struct{}
+}
+
+func (s *fakeSubscription) Unsubscribe() {
+	s.chain.unsubscribe(s)
+}
+
+func (s *fakeSubscription) Err() <-chan error {
+	return s.errs
+}
+
+var errNodeDown = errors.New("node is down")
+
+// fakeEthClient is an eth1 node of the simulated chain
+type fakeEthClient struct {
+	chain *simChain
+
+	mu              sync.Mutex
+	down            bool
+	noSubscriptions bool
+	syncing         bool
+	sub             *fakeSubscription
+}
+
+func newFakeEthClient(chain *simChain) *fakeEthClient {
+	return &fakeEthClient{chain: chain}
+}
+
+func (c *fakeEthClient) isDown() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.down
+}
+
+func (c *fakeEthClient) BlockNumber(ctx context.Context) (uint64, error) {
+	if c.isDown() {
+		return 0, errNodeDown
+	}
+	return c.chain.head(), nil
+}
+
+func (c *fakeEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if c.isDown() {
+		return nil, errNodeDown
+	}
+	n := c.chain.head()
+	if number != nil && number.Int64() == int64(rpc.FinalizedBlockNumber) {
+		c.chain.mu.Lock()
+		n = c.chain.finalized
+		c.chain.mu.Unlock()
+	} else if number != nil {
+		n = number.Uint64()
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(n)}, nil
+}
+
+func (c *fakeEthClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if c.isDown() {
+		return nil, errNodeDown
+	}
+	from, to := uint64(0), c.chain.head()
+	if q.FromBlock != nil {
+		from = q.FromBlock.Uint64()
+	}
+	if q.ToBlock != nil {
+		to = q.ToBlock.Uint64()
+	}
+	return c.chain.filterLogs(from, to), nil
+}
+
+func (c *fakeEthClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.noSubscriptions {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	if c.down {
+		return nil, errNodeDown
+	}
+	c.sub = c.chain.subscribe(&fakeSubscription{logs: ch})
+	return c.sub, nil
+}
+
+func (c *fakeEthClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.noSubscriptions {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	if c.down {
+		return nil, errNodeDown
+	}
+	c.sub = c.chain.subscribe(&fakeSubscription{heads: ch})
+	return c.sub, nil
+}
+
+func (c *fakeEthClient) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.down {
+		return nil, errNodeDown
+	}
+	if c.syncing {
+		return &ethereum.SyncProgress{StartingBlock: 1, CurrentBlock: 5, HighestBlock: 10}, nil
+	}
+	return nil, nil
+}
+
+func (c *fakeEthClient) Close() {}
+
+// fail takes the node down and breaks its subscription
+func (c *fakeEthClient) fail() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.down = true
+	if c.sub != nil {
+		c.chain.unsubscribe(c.sub)
+		c.sub.errs <- errNodeDown
+	}
+}
+
+// recover brings the node back up
+func (c *fakeEthClient) recover() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.down = false
+	c.sub = nil
+}
+
+func newTestEth1Client(t *testing.T, opts ClientOptions, nodes map[string]*fakeEthClient) *eth1Client {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	opts.Ctx = ctx
+	opts.ContractABI = eth1.ContractABI(eth1.V1)
+	opts.ConnectionTimeout = time.Second
+	opts.PollingInterval = 10 * time.Millisecond
+	opts.AbiVersion = eth1.V1
+	ec, err := newEth1ClientWithDialer(logging.TestLogger(t), opts, func(ctx context.Context, addr string) (ethClient, error) {
+		return nodes[addr], nil
+	})
+	require.NoError(t, err)
+	return ec
+}
+
+// validatorAddedLog returns a ValidatorAdded log, its position is set when it's mined
+func validatorAddedLog(t *testing.T) types.Log {
+	var vLog types.Log
+	require.NoError(t, json.Unmarshal([]byte(rawValidatorAdded), &vLog))
+	return vLog
+}
+
+// collectEvents returns a function that waits for the given number of contract events,
+// and returns their blocks (prefixed with "-" for removed events)
+func collectEvents(t *testing.T, ec *eth1Client) func(n int) []string {
+	cn := make(chan *eth1.Event, 32)
+	sub := ec.EventsFeed().Subscribe(cn)
+	t.Cleanup(sub.Unsubscribe)
+
+	return func(n int) []string {
+		var events []string
+		for len(events) < n {
+			select {
+			case e := <-cn:
+				if e.Name != abiparser.ValidatorAdded {
+					continue
+				}
+				if e.Log.Removed {
+					events = append(events, fmt.Sprintf("-%d", e.Log.BlockNumber))
+				} else {
+					events = append(events, fmt.Sprintf("%d", e.Log.BlockNumber))
+				}
+			case <-time.After(5 * time.Second):
+				require.FailNow(t, "timed out waiting for events", "received %v", events)
+			}
+		}
+		// no other events are expected
+		for {
+			select {
+			case e := <-cn:
+				if e.Name == abiparser.ValidatorAdded {
+					require.FailNow(t, "unexpected event", "at block %d (removed: %t)", e.Log.BlockNumber, e.Log.Removed)
+				}
+			case <-time.After(50 * time.Millisecond):
+				return events
+			}
+		}
+	}
+}
+
+func TestEth1Client_Failover(t *testing.T) {
+	logger := logging.TestLogger(t)
+	chain := newSimChain()
+	primary := newFakeEthClient(chain)
+	secondary := newFakeEthClient(chain)
+	chain.mine(validatorAddedLog(t))
+	ec := newTestEth1Client(t, ClientOptions{
+		NodeAddrs: []string{"ws://primary", "ws://secondary"},
+	}, map[string]*fakeEthClient{
+		"ws://primary":   primary,
+		"ws://secondary": secondary,
+	})
+	events := collectEvents(t, ec)
+
+	require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+	require.Equal(t, []string{"1"}, events(1))
+	require.NoError(t, ec.Start(logger))
+
+	chain.mine(validatorAddedLog(t))
+	require.Equal(t, []string{"2"}, events(1))
+
+	// logs that were missed during failover are fetched from the next node, without repeating handled logs
+	primary.fail()
+	chain.mine(validatorAddedLog(t), validatorAddedLog(t))
+	require.Equal(t, []string{"3", "3"}, events(2))
+	_, index := ec.activeConn()
+	require.Equal(t, 1, index)
+
+	chain.mine(validatorAddedLog(t))
+	require.Equal(t, []string{"4"}, events(1))
+}
+
+func TestEth1Client_PollingFallback(t *testing.T) {
+	logger := logging.TestLogger(t)
+	tests := []struct {
+		name            string
+		addr            string
+		noSubscriptions bool
+	}{
+		{
+			name: "http node",
+			addr: "http://node",
+		},
+		{
+			name:            "ws node without subscriptions",
+			addr:            "ws://node",
+			noSubscriptions: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			chain := newSimChain()
+			node := newFakeEthClient(chain)
+			node.noSubscriptions = test.noSubscriptions
+			chain.mine(validatorAddedLog(t))
+			ec := newTestEth1Client(t, ClientOptions{NodeAddrs: []string{test.addr}}, map[string]*fakeEthClient{test.addr: node})
+			events := collectEvents(t, ec)
+
+			require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+			require.Equal(t, []string{"1"}, events(1))
+			require.NoError(t, ec.Start(logger))
+
+			chain.mine(validatorAddedLog(t))
+			chain.mine(validatorAddedLog(t))
+			require.Equal(t, []string{"2", "3"}, events(2))
+		})
+	}
+}
+
+func TestEth1Client_FollowDistance(t *testing.T) {
+	logger := logging.TestLogger(t)
+	chain := newSimChain()
+	node := newFakeEthClient(chain)
+	chain.mine(validatorAddedLog(t))
+	chain.mine(validatorAddedLog(t))
+	ec := newTestEth1Client(t, ClientOptions{
+		NodeAddrs:      []string{"ws://node"},
+		FollowDistance: 1,
+	}, map[string]*fakeEthClient{"ws://node": node})
+	events := collectEvents(t, ec)
+
+	// the log of the head block doesn't have enough confirmations
+	require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+	require.Equal(t, []string{"1"}, events(1))
+	require.NoError(t, ec.Start(logger))
+
+	// a reorg within the follow distance has no effect
+	chain.reorg(1)
+	chain.mine(validatorAddedLog(t), validatorAddedLog(t))
+	require.Empty(t, events(0))
+
+	chain.mine()
+	require.Equal(t, []string{"2", "2"}, events(2))
+}
+
+func TestEth1Client_FollowFinalized(t *testing.T) {
+	logger := logging.TestLogger(t)
+	chain := newSimChain()
+	node := newFakeEthClient(chain)
+	chain.mine(validatorAddedLog(t))
+	chain.mine(validatorAddedLog(t))
+	chain.mine(validatorAddedLog(t))
+	chain.finalize(1)
+	ec := newTestEth1Client(t, ClientOptions{
+		NodeAddrs:       []string{"http://node"},
+		FollowFinalized: true,
+	}, map[string]*fakeEthClient{"http://node": node})
+	events := collectEvents(t, ec)
+
+	require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+	require.Equal(t, []string{"1"}, events(1))
+	require.NoError(t, ec.Start(logger))
+
+	chain.finalize(3)
+	require.Equal(t, []string{"2", "3"}, events(2))
+}
+
+func TestEth1Client_Reorg(t *testing.T) {
+	logger := logging.TestLogger(t)
+	tests := []struct {
+		name string
+		addr string
+	}{
+		{
+			name: "subscription",
+			addr: "ws://node",
+		},
+		{
+			name: "polling",
+			addr: "http://node",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			chain := newSimChain()
+			node := newFakeEthClient(chain)
+			chain.mine(validatorAddedLog(t))
+			ec := newTestEth1Client(t, ClientOptions{NodeAddrs: []string{test.addr}}, map[string]*fakeEthClient{test.addr: node})
+			events := collectEvents(t, ec)
+
+			require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+			require.Equal(t, []string{"1"}, events(1))
+			require.NoError(t, ec.Start(logger))
+
+			chain.mine(validatorAddedLog(t))
+			chain.mine(validatorAddedLog(t), validatorAddedLog(t))
+			require.Equal(t, []string{"2", "3", "3"}, events(3))
+
+			// handled logs that were removed are reverted in reverse order, and the logs of the new chain are handled
+			chain.reorg(2)
+			chain.mine()
+			chain.mine(validatorAddedLog(t))
+			require.Equal(t, []string{"-3", "-3", "-2", "3"}, events(4))
+
+			// logs that were synced are reverted as well
+			chain.reorg(3)
+			chain.mine()
+			require.Equal(t, []string{"-3", "-1"}, events(2))
+		})
+	}
+}
+
+func TestEth1Client_ReorgKeepsLaterLogs(t *testing.T) {
+	logger := logging.TestLogger(t)
+	chain := newSimChain()
+	node := newFakeEthClient(chain)
+	chain.mine(validatorAddedLog(t))
+	ec := newTestEth1Client(t, ClientOptions{NodeAddrs: []string{"http://node"}}, map[string]*fakeEthClient{"http://node": node})
+	events := collectEvents(t, ec)
+
+	require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+	require.Equal(t, []string{"1"}, events(1))
+	require.NoError(t, ec.Start(logger))
+	chain.mine(validatorAddedLog(t))
+	chain.mine(validatorAddedLog(t))
+	require.Equal(t, []string{"2", "3"}, events(2))
+
+	// an earlier log is removed while a later one is still part of the chain, the later one is not handled again
+	chain.removeLogs(2)
+	chain.mine()
+	require.Equal(t, []string{"-2"}, events(1))
+	chain.mine(validatorAddedLog(t))
+	require.Equal(t, []string{"5"}, events(1))
+}
+
+func TestEth1Client_ReorgWhileDown(t *testing.T) {
+	logger := logging.TestLogger(t)
+	chain := newSimChain()
+	node := newFakeEthClient(chain)
+	storage := &memRecentLogsStorage{}
+	chain.mine(validatorAddedLog(t))
+	ec := newTestEth1Client(t, ClientOptions{NodeAddrs: []string{"ws://node"}, RecentLogsStorage: storage}, map[string]*fakeEthClient{"ws://node": node})
+	events := collectEvents(t, ec)
+
+	require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+	require.Equal(t, []string{"1"}, events(1))
+	require.NoError(t, ec.Start(logger))
+	chain.mine(validatorAddedLog(t))
+	require.Equal(t, []string{"2"}, events(1))
+
+	// the node goes down, and the handled log is removed by a reorg in the meantime
+	ec.streamLock.Lock()
+	ec.stopStreaming()
+	ec.streamLock.Unlock()
+	chain.reorg(1)
+	chain.mine()
+	chain.mine(validatorAddedLog(t))
+
+	// the removed log is reverted before the history is synced again
+	ec = newTestEth1Client(t, ClientOptions{NodeAddrs: []string{"ws://node"}, RecentLogsStorage: storage}, map[string]*fakeEthClient{"ws://node": node})
+	events = collectEvents(t, ec)
+	require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+	require.Equal(t, []string{"-2", "1", "3"}, events(3))
+
+	logs, err := storage.GetRecentLogs()
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Equal(t, uint64(1), logs[0].BlockNumber)
+	require.Equal(t, uint64(3), logs[1].BlockNumber)
+}
+
+// memRecentLogsStorage keeps the recent logs in memory
+type memRecentLogsStorage struct {
+	mu   sync.Mutex
+	logs []types.Log
+}
+
+func (s *memRecentLogsStorage) SaveRecentLogs(logs []types.Log) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logs = append([]types.Log{}, logs...)
+	return nil
+}
+
+func (s *memRecentLogsStorage) GetRecentLogs() ([]types.Log, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]types.Log{}, s.logs...), nil
+}
+
+func TestEth1Client_HealthCheck(t *testing.T) {
+	chain := newSimChain()
+	primary := newFakeEthClient(chain)
+	secondary := newFakeEthClient(chain)
+	ec := newTestEth1Client(t, ClientOptions{
+		NodeAddrs: []string{"https://primary.example/secret-key", "https://secondary.example/secret-key"},
+	}, map[string]*fakeEthClient{
+		"https://primary.example/secret-key":   primary,
+		"https://secondary.example/secret-key": secondary,
+	})
+	require.Empty(t, ec.HealthCheck())
+
+	primary.syncing = true
+	issues := ec.HealthCheck()
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0], "eth1 node 0 (https://primary.example): currently syncing")
+
+	primary.fail()
+	require.NoError(t, ec.connect(logging.TestLogger(t), ec.nextNode()))
+	require.Empty(t, ec.HealthCheck())
+
+	secondary.fail()
+	issues = ec.HealthCheck()
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0], "eth1 node 1 (https://secondary.example): could not get sync progress")
+	require.NotContains(t, issues[0], "secret-key")
+}
+
+func TestEth1Client_Failback(t *testing.T) {
+	logger := logging.TestLogger(t)
+	chain := newSimChain()
+	primary := newFakeEthClient(chain)
+	secondary := newFakeEthClient(chain)
+	chain.mine(validatorAddedLog(t))
+	// the configured order is kept, even though only the secondary supports subscriptions
+	ec := newTestEth1Client(t, ClientOptions{
+		NodeAddrs: []string{"https://primary", "ws://secondary"},
+	}, map[string]*fakeEthClient{
+		"https://primary": primary,
+		"ws://secondary":  secondary,
+	})
+	events := collectEvents(t, ec)
+	_, index := ec.activeConn()
+	require.Equal(t, 0, index)
+
+	require.NoError(t, ec.Sync(logger, big.NewInt(0)))
+	require.Equal(t, []string{"1"}, events(1))
+	require.NoError(t, ec.Start(logger))
+
+	primary.fail()
+	chain.mine(validatorAddedLog(t))
+	require.Equal(t, []string{"2"}, events(1))
+	_, index = ec.activeConn()
+	require.Equal(t, 1, index)
+
+	// the preferred node isn't used while it's down or syncing
+	ec.failbackOnce(logger)
+	_, index = ec.activeConn()
+	require.Equal(t, 1, index)
+
+	primary.recover()
+	primary.syncing = true
+	ec.failbackOnce(logger)
+	_, index = ec.activeConn()
+	require.Equal(t, 1, index)
+
+	primary.syncing = false
+	ec.failbackOnce(logger)
+	_, index = ec.activeConn()
+	require.Equal(t, 0, index)
+
+	// streaming moved to the preferred node, without repeating or missing logs
+	chain.mine(validatorAddedLog(t))
+	require.Equal(t, []string{"3"}, events(1))
+	secondary.fail()
+	chain.mine(validatorAddedLog(t))
+	require.Equal(t, []string{"4"}, events(1))
+	_, index = ec.activeConn()
+	require.Equal(t, 0, index)
+}
+
 var rawOperatorAdded = `{
   "address": "0x3A23a7F455E853058d900f5dc86f1Bb1589b54F9",
   "topics": [
@@ -22,6 +22,10 @@ var (
 		Name: "ssv_eth1_status",
 		Help: "Status of the connected eth1 node",
 	})
+	metricsEth1ActiveNode = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv_eth1_active_node",
+		Help: "Index of the eth1 node that is currently in use",
+	})
 	statusUnknown eth1NodeStatus = 0
 	statusSyncing eth1NodeStatus = 1
 	statusOK      eth1NodeStatus = 2
@@ -37,6 +41,9 @@ func init() {
 	if err := prometheus.Register(metricSyncEventsCountSuccess); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricsEth1ActiveNode); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 }
 
 func reportSyncEvent(eventType string, err error) {
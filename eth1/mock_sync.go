@@ -5,6 +5,7 @@
 package eth1
 
 import (
+	types "github.com/ethereum/go-ethereum/core/types"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -61,3 +62,55 @@ func (mr *MockSyncOffsetStorageMockRecorder) GetSyncOffset() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncOffset", reflect.TypeOf((*MockSyncOffsetStorage)(nil).GetSyncOffset))
 }
+
+// MockRecentLogsStorage is a mock of RecentLogsStorage interface
+type MockRecentLogsStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecentLogsStorageMockRecorder
+}
+
+// MockRecentLogsStorageMockRecorder is the mock recorder for MockRecentLogsStorage
+type MockRecentLogsStorageMockRecorder struct {
+	mock *MockRecentLogsStorage
+}
+
+// NewMockRecentLogsStorage creates a new mock instance
+func NewMockRecentLogsStorage(ctrl *gomock.Controller) *MockRecentLogsStorage {
+	mock := &MockRecentLogsStorage{ctrl: ctrl}
+	mock.recorder = &MockRecentLogsStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRecentLogsStorage) EXPECT() *MockRecentLogsStorageMockRecorder {
+	return m.recorder
+}
+
+// SaveRecentLogs mocks base method
+func (m *MockRecentLogsStorage) SaveRecentLogs(logs []types.Log) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRecentLogs", logs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRecentLogs indicates an expected call of SaveRecentLogs
+func (mr *MockRecentLogsStorageMockRecorder) SaveRecentLogs(logs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRecentLogs", reflect.TypeOf((*MockRecentLogsStorage)(nil).SaveRecentLogs), logs)
+}
+
+// GetRecentLogs mocks base method
+func (m *MockRecentLogsStorage) GetRecentLogs() ([]types.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecentLogs")
+	ret0, _ := ret[0].([]types.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecentLogs indicates an expected call of GetRecentLogs
+func (mr *MockRecentLogsStorageMockRecorder) GetRecentLogs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentLogs", reflect.TypeOf((*MockRecentLogsStorage)(nil).GetRecentLogs))
+}
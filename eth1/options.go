@@ -1,14 +1,28 @@
 package eth1
 
-import "time"
+import (
+	"time"
+
+	"github.com/bloxapp/ssv/utils/format"
+)
 
 // Options configurations related to eth1
 type Options struct {
-	ETH1Addr              string        `yaml:"ETH1Addr" env:"ETH_1_ADDR" env-required:"true" env-description:"ETH1 node WebSocket address"`
+	ETH1Addr              string        `yaml:"ETH1Addr" env:"ETH_1_ADDR" env-description:"ETH1 node WebSocket or HTTP address"`
+	ETH1Addrs             []string      `yaml:"ETH1Addrs" env:"ETH_1_ADDRS" env-separator:";" env-description:"ETH1 node addresses (WebSocket or HTTP), in order of preference, used for failover"`
 	ETH1SyncOffset        string        `yaml:"ETH1SyncOffset" env:"ETH_1_SYNC_OFFSET" env-default:"8661727" env-description:"block number to start the sync from"`
 	ETH1ConnectionTimeout time.Duration `yaml:"ETH1ConnectionTimeout" env:"ETH_1_CONNECTION_TIMEOUT" env-default:"10s" env-description:"eth1 node connection timeout"`
+	ETH1PollingInterval   time.Duration `yaml:"ETH1PollingInterval" env:"ETH_1_POLLING_INTERVAL" env-default:"12s" env-description:"interval of polling for contract events when connected to an HTTP endpoint"`
+	ETH1FollowDistance    uint64        `yaml:"ETH1FollowDistance" env:"ETH_1_FOLLOW_DISTANCE" env-default:"0" env-description:"number of confirmations required before handling contract events, events are handled once their block is mined if 0"`
+	ETH1FollowFinalized   bool          `yaml:"ETH1FollowFinalized" env:"ETH_1_FOLLOW_FINALIZED" env-default:"false" env-description:"handle contract events only once their block is finalized, overrides ETH1FollowDistance"`
 	RegistryContractAddr  string        `yaml:"RegistryContractAddr" env:"REGISTRY_CONTRACT_ADDR_KEY" env-default:"0xAfdb141Dd99b5a101065f40e3D7636262dce65b3" env-description:"registry contract address"`
 	RegistryContractABI   string        `yaml:"RegistryContractABI" env:"REGISTRY_CONTRACT_ABI" env-description:"registry contract abi json file"`
 	CleanRegistryData     bool          `yaml:"CleanRegistryData" env:"CLEAN_REGISTRY_DATA" env-default:"false" env-description:"cleans registry contract data (validator shares) and forces re-sync"`
 	AbiVersion            Version       `yaml:"AbiVersion" env:"ABI_VERSION" env-default:"0" env-description:"smart contract abi version (format)"`
 }
+
+// Addresses returns the configured eth1 node addresses without duplicates,
+// ETH1Addr comes first if set.
+func (o Options) Addresses() []string {
+	return format.Addresses(append([]string{o.ETH1Addr}, o.ETH1Addrs...)...)
+}
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
@@ -17,6 +18,9 @@ import (
 const (
 	// prod contract genesis block
 	defaultPraterSyncOffset string = "8661727"
+	// ReorgWindow is the number of blocks, below the followed head, in which handled events are kept track of
+	// so they could be reverted in case a chain reorg removes them
+	ReorgWindow uint64 = 64
 )
 
 // SyncOffset is the type of variable used for passing around the offset
@@ -33,6 +37,15 @@ type SyncOffsetStorage interface {
 	GetSyncOffset() (*SyncOffset, bool, error)
 }
 
+// RecentLogsStorage represents the interface for persisting the logs that were handled within the reorg window,
+// so that logs which were removed by a reorg while the node was down are reverted once it's back up
+type RecentLogsStorage interface {
+	// SaveRecentLogs saves the recently handled logs
+	SaveRecentLogs(logs []types.Log) error
+	// GetRecentLogs returns the recently handled logs
+	GetRecentLogs() ([]types.Log, error)
+}
+
 // DefaultSyncOffset returns the default value (block number of the first event from the contract)
 func DefaultSyncOffset() *SyncOffset {
 	return StringToSyncOffset(defaultPraterSyncOffset)
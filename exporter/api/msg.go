@@ -7,6 +7,10 @@ import (
 
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
 	"github.com/bloxapp/ssv-spec/types"
+
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	ssvtypes "github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
 )
 
 // Message represents an exporter message
@@ -85,15 +89,65 @@ func DecidedAPIData(msgs ...*specqbft.SignedMessage) (interface{}, error) {
 	return apiMsgs, nil
 }
 
+// OperatorAPI is the exported information of an operator
+type OperatorAPI struct {
+	ID           types.OperatorID `json:"id"`
+	PublicKey    string           `json:"publicKey"`
+	OwnerAddress string           `json:"ownerAddress"`
+}
+
+// NewOperatorAPI creates a new OperatorAPI from the given operator data
+func NewOperatorAPI(od *registrystorage.OperatorData) *OperatorAPI {
+	return &OperatorAPI{
+		ID:           od.ID,
+		PublicKey:    string(od.PublicKey),
+		OwnerAddress: od.OwnerAddress.String(),
+	}
+}
+
+// CommitteeMemberAPI is the exported information of a validator's committee member
+type CommitteeMemberAPI struct {
+	OperatorID types.OperatorID `json:"operatorId"`
+	PublicKey  string           `json:"publicKey"`
+}
+
+// ValidatorAPI is the exported information of a validator
+type ValidatorAPI struct {
+	PublicKey    string                            `json:"publicKey"`
+	OwnerAddress string                            `json:"ownerAddress"`
+	Liquidated   bool                              `json:"liquidated"`
+	Committee    []CommitteeMemberAPI              `json:"committee"`
+	Metadata     *beaconprotocol.ValidatorMetadata `json:"metadata,omitempty"`
+}
+
+// NewValidatorAPI creates a new ValidatorAPI from the given share
+func NewValidatorAPI(share *ssvtypes.SSVShare) *ValidatorAPI {
+	committee := make([]CommitteeMemberAPI, 0, len(share.Committee))
+	for _, op := range share.Committee {
+		committee = append(committee, CommitteeMemberAPI{
+			OperatorID: op.OperatorID,
+			PublicKey:  hex.EncodeToString(op.PubKey),
+		})
+	}
+	return &ValidatorAPI{
+		PublicKey:    hex.EncodeToString(share.ValidatorPubKey),
+		OwnerAddress: share.OwnerAddress.String(),
+		Liquidated:   share.Liquidated,
+		Committee:    committee,
+		Metadata:     share.BeaconMetadata,
+	}
+}
+
 // MessageFilter is a criteria for query in request messages and projection in responses
 type MessageFilter struct {
 	// From is the starting index of the desired data
 	From uint64 `json:"from"`
-	// To is the ending index of the desired data
+	// To is the ending index of the desired data, for validators and operators zero means no upper bound
 	To uint64 `json:"to"`
 	// Role is the duty type, optional as it's relevant for IBFT data
 	Role string `json:"role,omitempty"`
-	// PublicKey is optional, used for fetching decided messages or information about specific validator/operator
+	// PublicKey is optional, used for fetching decided messages or information about specific validator/operator.
+	// validators are identified by the hex encoded public key, operators by the encoded public key (as in the contract)
 	PublicKey string `json:"publicKey,omitempty"`
 }
 
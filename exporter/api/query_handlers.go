@@ -1,8 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
+	"sort"
 
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
 	spectypes "github.com/bloxapp/ssv-spec/types"
@@ -12,6 +14,7 @@ import (
 	"github.com/bloxapp/ssv/logging/fields"
 	"github.com/bloxapp/ssv/protocol/v2/message"
 	"github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
 )
 
 const (
@@ -77,6 +80,114 @@ func HandleDecidedQuery(logger *zap.Logger, qbftStorage *storage.QBFTStores, nm
 	nm.Msg = res
 }
 
+// HandleValidatorQuery handles TypeValidator queries.
+// a specific validator is returned if a public key was provided,
+// otherwise validators are ordered by public key and paginated by their position (From/To).
+func HandleValidatorQuery(logger *zap.Logger, sharesStorage registrystorage.Shares, nm *NetworkMessage) {
+	logger.Debug("handles validator request",
+		zap.Uint64("from", nm.Msg.Filter.From),
+		zap.Uint64("to", nm.Msg.Filter.To),
+		zap.String("pk", nm.Msg.Filter.PublicKey))
+	res := Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+
+	if len(nm.Msg.Filter.PublicKey) > 0 {
+		pkRaw, err := hex.DecodeString(nm.Msg.Filter.PublicKey)
+		if err != nil {
+			logger.Debug("failed to decode validator public key", zap.Error(err))
+			res.Data = []string{"invalid public key"}
+			nm.Msg = res
+			return
+		}
+		share, found, err := sharesStorage.GetShare(pkRaw)
+		if err != nil {
+			logger.Warn("failed to get validator share", zap.Error(err))
+			res.Data = []string{"internal error - could not get validator"}
+		} else if !found {
+			res.Data = []string{"validator not found"}
+		} else {
+			res.Data = []*ValidatorAPI{NewValidatorAPI(share)}
+		}
+		nm.Msg = res
+		return
+	}
+
+	shares, err := sharesStorage.GetAllShares(logger)
+	if err != nil {
+		logger.Warn("failed to get validator shares", zap.Error(err))
+		res.Data = []string{"internal error - could not get validators"}
+		nm.Msg = res
+		return
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return bytes.Compare(shares[i].ValidatorPubKey, shares[j].ValidatorPubKey) < 0
+	})
+	validators := make([]*ValidatorAPI, 0)
+	for i, share := range shares {
+		if inRange(uint64(i), nm.Msg.Filter.From, nm.Msg.Filter.To) {
+			validators = append(validators, NewValidatorAPI(share))
+		}
+	}
+	res.Data = validators
+
+	nm.Msg = res
+}
+
+// HandleOperatorQuery handles TypeOperator queries.
+// a specific operator is returned if a public key was provided,
+// otherwise operators are ordered and paginated by their ID (From/To).
+func HandleOperatorQuery(logger *zap.Logger, operatorsStorage registrystorage.Operators, nm *NetworkMessage) {
+	logger.Debug("handles operator request",
+		zap.Uint64("from", nm.Msg.Filter.From),
+		zap.Uint64("to", nm.Msg.Filter.To),
+		zap.String("pk", nm.Msg.Filter.PublicKey))
+	res := Message{
+		Type:   nm.Msg.Type,
+		Filter: nm.Msg.Filter,
+	}
+
+	if len(nm.Msg.Filter.PublicKey) > 0 {
+		od, found, err := operatorsStorage.GetOperatorDataByPubKey(logger, []byte(nm.Msg.Filter.PublicKey))
+		if err != nil {
+			logger.Warn("failed to get operator data", zap.Error(err))
+			res.Data = []string{"internal error - could not get operator"}
+		} else if !found {
+			res.Data = []string{"operator not found"}
+		} else {
+			res.Data = []*OperatorAPI{NewOperatorAPI(od)}
+		}
+		nm.Msg = res
+		return
+	}
+
+	operators, err := operatorsStorage.ListOperators(logger, nm.Msg.Filter.From, nm.Msg.Filter.To)
+	if err != nil {
+		logger.Warn("failed to list operators", zap.Error(err))
+		res.Data = []string{"internal error - could not get operators"}
+		nm.Msg = res
+		return
+	}
+	sort.Slice(operators, func(i, j int) bool {
+		return operators[i].ID < operators[j].ID
+	})
+	data := make([]*OperatorAPI, 0, len(operators))
+	for i := range operators {
+		if inRange(operators[i].ID, nm.Msg.Filter.From, nm.Msg.Filter.To) {
+			data = append(data, NewOperatorAPI(&operators[i]))
+		}
+	}
+	res.Data = data
+
+	nm.Msg = res
+}
+
+// inRange checks whether the given index is in the range [from, to], where zero 'to' means no upper bound
+func inRange(i, from, to uint64) bool {
+	return i >= from && (to == 0 || i <= to)
+}
+
 // HandleErrorQuery handles TypeError queries.
 func HandleErrorQuery(logger *zap.Logger, nm *NetworkMessage) {
 	logger.Warn("handles error message")
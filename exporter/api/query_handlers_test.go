@@ -1,13 +1,17 @@
 package api
 
 import (
+	"encoding/hex"
+	"fmt"
 	"math"
 	"testing"
 
 	"github.com/bloxapp/ssv/logging"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
 	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -16,8 +20,10 @@ import (
 	qbftstorage "github.com/bloxapp/ssv/ibft/storage"
 	"github.com/bloxapp/ssv/operator/storage"
 	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	protocoltesting "github.com/bloxapp/ssv/protocol/v2/testing"
 	"github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
 	ssvstorage "github.com/bloxapp/ssv/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
 )
@@ -166,6 +172,145 @@ func TestHandleDecidedQuery(t *testing.T) {
 	})
 }
 
+func TestHandleValidatorQuery(t *testing.T) {
+	logger := logging.TestLogger(t)
+
+	db, l, done := newDBAndLoggerForTest(logger)
+	defer done()
+	nodeStorage, _ := newStorageForTest(db, l)
+
+	owner := common.HexToAddress("0x1")
+	for _, pk := range []string{"03", "01", "02"} {
+		pkRaw, err := hex.DecodeString(pk)
+		require.NoError(t, err)
+		require.NoError(t, nodeStorage.SaveShare(l, &types.SSVShare{
+			Share: spectypes.Share{
+				ValidatorPubKey: pkRaw,
+				Committee:       []*spectypes.Operator{{OperatorID: 1, PubKey: []byte{1}}, {OperatorID: 2, PubKey: []byte{2}}},
+			},
+			Metadata: types.Metadata{
+				OwnerAddress:   owner,
+				Liquidated:     pk == "02",
+				BeaconMetadata: &beaconprotocol.ValidatorMetadata{Index: 10},
+			},
+		}))
+	}
+
+	t.Run("all validators", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeValidator, "", 0, 0)
+		HandleValidatorQuery(l, nodeStorage, nm)
+		validators, ok := nm.Msg.Data.([]*ValidatorAPI)
+		require.True(t, ok, "expected []*ValidatorAPI, got %+v", nm.Msg.Data)
+		require.Len(t, validators, 3)
+		require.Equal(t, "01", validators[0].PublicKey)
+		require.Equal(t, "03", validators[2].PublicKey)
+	})
+
+	t.Run("paginated validators", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeValidator, "", 1, 1)
+		HandleValidatorQuery(l, nodeStorage, nm)
+		validators, ok := nm.Msg.Data.([]*ValidatorAPI)
+		require.True(t, ok, "expected []*ValidatorAPI, got %+v", nm.Msg.Data)
+		require.Len(t, validators, 1)
+		require.Equal(t, "02", validators[0].PublicKey)
+		require.True(t, validators[0].Liquidated)
+	})
+
+	t.Run("validator by public key", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeValidator, "03", 0, 0)
+		HandleValidatorQuery(l, nodeStorage, nm)
+		validators, ok := nm.Msg.Data.([]*ValidatorAPI)
+		require.True(t, ok, "expected []*ValidatorAPI, got %+v", nm.Msg.Data)
+		require.Len(t, validators, 1)
+		require.Equal(t, owner.String(), validators[0].OwnerAddress)
+		require.Equal(t, []CommitteeMemberAPI{{OperatorID: 1, PublicKey: "01"}, {OperatorID: 2, PublicKey: "02"}}, validators[0].Committee)
+		require.Equal(t, phase0.ValidatorIndex(10), validators[0].Metadata.Index)
+	})
+
+	t.Run("non-existing validator", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeValidator, "04", 0, 0)
+		HandleValidatorQuery(l, nodeStorage, nm)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "validator not found", errs[0])
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeValidator, "xyz", 0, 0)
+		HandleValidatorQuery(l, nodeStorage, nm)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "invalid public key", errs[0])
+	})
+}
+
+func TestHandleOperatorQuery(t *testing.T) {
+	logger := logging.TestLogger(t)
+
+	db, l, done := newDBAndLoggerForTest(logger)
+	defer done()
+	nodeStorage, _ := newStorageForTest(db, l)
+
+	for i := 1; i <= 12; i++ {
+		_, err := nodeStorage.SaveOperatorData(l, &registrystorage.OperatorData{
+			ID:           spectypes.OperatorID(i),
+			PublicKey:    []byte(fmt.Sprintf("pk-%d", i)),
+			OwnerAddress: common.HexToAddress("0x1"),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("all operators", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeOperator, "", 0, 0)
+		HandleOperatorQuery(l, nodeStorage, nm)
+		operators, ok := nm.Msg.Data.([]*OperatorAPI)
+		require.True(t, ok, "expected []*OperatorAPI, got %+v", nm.Msg.Data)
+		require.Len(t, operators, 12)
+		for i, op := range operators {
+			require.Equal(t, spectypes.OperatorID(i+1), op.ID)
+		}
+	})
+
+	t.Run("paginated operators", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeOperator, "", 9, 11)
+		HandleOperatorQuery(l, nodeStorage, nm)
+		operators, ok := nm.Msg.Data.([]*OperatorAPI)
+		require.True(t, ok, "expected []*OperatorAPI, got %+v", nm.Msg.Data)
+		require.Len(t, operators, 3)
+		require.Equal(t, spectypes.OperatorID(9), operators[0].ID)
+		require.Equal(t, spectypes.OperatorID(11), operators[2].ID)
+	})
+
+	t.Run("operator by public key", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeOperator, "pk-5", 0, 0)
+		HandleOperatorQuery(l, nodeStorage, nm)
+		operators, ok := nm.Msg.Data.([]*OperatorAPI)
+		require.True(t, ok, "expected []*OperatorAPI, got %+v", nm.Msg.Data)
+		require.Equal(t, []*OperatorAPI{{ID: 5, PublicKey: "pk-5", OwnerAddress: common.HexToAddress("0x1").String()}}, operators)
+	})
+
+	t.Run("non-existing operator", func(t *testing.T) {
+		nm := newQueryAPIMsg(TypeOperator, "pk-13", 0, 0)
+		HandleOperatorQuery(l, nodeStorage, nm)
+		errs, ok := nm.Msg.Data.([]string)
+		require.True(t, ok)
+		require.Equal(t, "operator not found", errs[0])
+	})
+}
+
+func newQueryAPIMsg(msgType MessageType, pk string, from, to uint64) *NetworkMessage {
+	return &NetworkMessage{
+		Msg: Message{
+			Type: msgType,
+			Filter: MessageFilter{
+				PublicKey: pk,
+				From:      from,
+				To:        to,
+			},
+		},
+	}
+}
+
 func newDecidedAPIMsg(pk string, role spectypes.BeaconRole, from, to uint64) *NetworkMessage {
 	return &NetworkMessage{
 		Msg: Message{
@@ -198,7 +343,7 @@ func newDBAndLoggerForTest(logger *zap.Logger) (basedb.IDb, *zap.Logger, func())
 func newStorageForTest(db basedb.IDb, logger *zap.Logger, roles ...spectypes.BeaconRole) (storage.Storage, *qbftstorage.QBFTStores) {
 	sExporter := storage.NewNodeStorage(db)
 
-	storageMap := qbftstorage.NewStores()
+	storageMap := qbftstorage.NewStores(db)
 	for _, role := range roles {
 		storageMap.Add(role, qbftstorage.New(db, role.String(), forksprotocol.GenesisForkVersion))
 	}
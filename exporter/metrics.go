@@ -18,12 +18,19 @@ var (
 		Name: "ssv:exporter:operator_index",
 		Help: "operator footprint",
 	}, []string{"pubKey", "index"})
+	metricOperatorsRemoved = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssv:exporter:operators_removed",
+		Help: "Counts operators that were removed from the registry",
+	})
 )
 
 func init() {
 	if err := prometheus.Register(metricOperatorIndex); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricOperatorsRemoved); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 }
 
 // ReportOperatorIndex reporting of new or exist operators
@@ -32,3 +39,11 @@ func ReportOperatorIndex(logger *zap.Logger, op *registrystorage.OperatorData) {
 	metricOperatorIndex.WithLabelValues(pkHash, strconv.FormatUint(op.ID, 10)).Set(float64(op.ID))
 	logger.Debug("report operator", zap.String("pkHash", pkHash), zap.Uint64("id", op.ID))
 }
+
+// ReportOperatorRemoved reporting of removed operators
+func ReportOperatorRemoved(logger *zap.Logger, op *registrystorage.OperatorData) {
+	pkHash := fmt.Sprintf("%x", sha256.Sum256(op.PublicKey))
+	metricOperatorIndex.DeleteLabelValues(pkHash, strconv.FormatUint(op.ID, 10))
+	metricOperatorsRemoved.Inc()
+	logger.Debug("report removed operator", zap.String("pkHash", pkHash), zap.Uint64("id", op.ID))
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+var (
+	metricsPrunedInstances = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:storage:decided_pruned",
+		Help: "Count of historical decided instances that were pruned",
+	}, []string{"role"})
+	metricsPruningDuration = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:storage:decided_pruning_duration_seconds",
+		Help: "Duration of the last decided pruning cycle",
+	})
+)
+
+func init() {
+	if err := prometheus.Register(metricsPrunedInstances); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricsPruningDuration); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
+
+// RetentionOptions is the retention policy of historical decided instances.
+// when both heights and epochs are set, instances are kept as long as they are within one of the bounds.
+type RetentionOptions struct {
+	Heights  uint64        `yaml:"Heights" env:"DECIDED_RETENTION_HEIGHTS" env-description:"Amount of decided heights to keep per validator and role, 0 to disable"`
+	Epochs   uint64        `yaml:"Epochs" env:"DECIDED_RETENTION_EPOCHS" env-description:"Amount of epochs of decided history to keep per validator and role, 0 to disable"`
+	Interval time.Duration `yaml:"Interval" env:"DECIDED_PRUNING_INTERVAL" env-default:"10m" env-description:"Interval between decided pruning cycles"`
+}
+
+// Enabled returns true if any retention bound was set
+func (o RetentionOptions) Enabled() bool {
+	return o.Heights > 0 || o.Epochs > 0
+}
+
+// ValidatorsProvider returns the public keys of the validators which decided history should be pruned
+type ValidatorsProvider func(logger *zap.Logger) ([][]byte, error)
+
+// Pruner removes historical decided instances that are out of the retention policy
+type Pruner struct {
+	ctx        context.Context
+	db         basedb.IDb
+	stores     *QBFTStores
+	network    beaconprotocol.Network
+	validators ValidatorsProvider
+	opts       RetentionOptions
+}
+
+// NewPruner creates a new instance of Pruner
+func NewPruner(ctx context.Context, db basedb.IDb, stores *QBFTStores, network beaconprotocol.Network, validators ValidatorsProvider, opts RetentionOptions) *Pruner {
+	return &Pruner{
+		ctx:        ctx,
+		db:         db,
+		stores:     stores,
+		network:    network,
+		validators: validators,
+		opts:       opts,
+	}
+}
+
+// Start runs pruning cycles periodically, until the context is done
+func (p *Pruner) Start(logger *zap.Logger) {
+	logger = logger.Named("DecidedPruner")
+	logger.Info("starting decided pruner",
+		zap.Uint64("heights", p.opts.Heights),
+		zap.Uint64("epochs", p.opts.Epochs),
+		zap.Duration("interval", p.opts.Interval))
+
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+	for {
+		start := time.Now()
+		n, err := p.Prune(logger)
+		metricsPruningDuration.Set(time.Since(start).Seconds())
+		if err != nil {
+			logger.Warn("failed to prune decided history", zap.Error(err))
+		} else {
+			logger.Debug("pruned decided history", zap.Int("count", n), zap.Duration("took", time.Since(start)))
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune runs a single pruning cycle over all the validators and roles, returns the amount of removed instances.
+// the storage engine is asked to reclaim the freed space once instances were removed.
+func (p *Pruner) Prune(logger *zap.Logger) (int, error) {
+	pks, err := p.validators(logger)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get validators")
+	}
+
+	total := 0
+	p.stores.Each(func(role spectypes.BeaconRole, store qbftstorage.QBFTStore) {
+		for _, pk := range pks {
+			if p.ctx.Err() != nil {
+				return
+			}
+			msgID := spectypes.NewMsgID(types.GetDefaultDomain(), pk, role)
+			n, err := p.pruneIdentifier(store, msgID[:])
+			if n > 0 {
+				total += n
+				metricsPrunedInstances.WithLabelValues(role.String()).Add(float64(n))
+			}
+			if err != nil {
+				logger.Warn("failed to prune decided history", zap.String("identifier", msgID.String()), zap.Error(err))
+			}
+		}
+	})
+
+	if total > 0 {
+		if gc, ok := p.db.(basedb.GarbageCollector); ok {
+			if err := gc.QuickGC(p.ctx); err != nil {
+				logger.Debug("could not collect garbage after pruning", zap.Error(err))
+			}
+		}
+	}
+	return total, nil
+}
+
+// pruneIdentifier removes the instances of the given identifier that are out of the retention policy,
+// the highest instance is always kept.
+func (p *Pruner) pruneIdentifier(store qbftstorage.QBFTStore, identifier []byte) (int, error) {
+	highest, err := store.GetHighestInstance(identifier)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get highest instance")
+	}
+	if highest == nil || highest.State == nil {
+		return 0, nil
+	}
+	below := highest.State.Height
+	if p.opts.Heights > 0 {
+		if uint64(highest.State.Height)+1 <= p.opts.Heights {
+			return 0, nil
+		}
+		below = highest.State.Height + 1 - specqbft.Height(p.opts.Heights)
+	}
+	if p.opts.Epochs > 0 {
+		below, err = p.epochsBound(store, identifier, below)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return store.PruneInstances(identifier, below)
+}
+
+// epochsBound returns the lowest height (up to the given one) of an instance that is within the retained epochs,
+// by scanning the instances from the last pruned height.
+func (p *Pruner) epochsBound(store qbftstorage.QBFTStore, identifier []byte, upTo specqbft.Height) (specqbft.Height, error) {
+	currentEpoch := p.network.EstimatedCurrentEpoch()
+	if uint64(currentEpoch) < p.opts.Epochs {
+		return 0, nil
+	}
+	minSlot := p.network.GetEpochFirstSlot(currentEpoch - phase0.Epoch(p.opts.Epochs))
+
+	from, err := store.GetPrunedHeight(identifier)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get pruned height")
+	}
+	for h := from; h < upTo; h++ {
+		inst, err := store.GetInstance(identifier, h)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not get instance")
+		}
+		if inst == nil {
+			continue
+		}
+		slot, ok := instanceSlot(inst)
+		if !ok || slot >= minSlot {
+			return h, nil
+		}
+	}
+	return upTo, nil
+}
+
+// instanceSlot returns the duty slot of the given instance, or false if it couldn't be decoded
+func instanceSlot(inst *qbftstorage.StoredInstance) (phase0.Slot, bool) {
+	if inst.DecidedMessage == nil || len(inst.DecidedMessage.FullData) == 0 {
+		return 0, false
+	}
+	cd := &spectypes.ConsensusData{}
+	if err := cd.UnmarshalSSZ(inst.DecidedMessage.FullData); err != nil {
+		return 0, false
+	}
+	return cd.Duty.Slot, true
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging"
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	ssvstorage "github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+func TestPruner(t *testing.T) {
+	logger := logging.TestLogger(t)
+	network := beaconprotocol.NewNetwork(core.PraterNetwork, 0)
+	currentSlot := network.EstimatedCurrentSlot()
+	pks := [][]byte{[]byte("pk1"), []byte("pk2")}
+
+	// heights are one slot apart, the highest height is at the current slot
+	generateInstance := func(t *testing.T, id spectypes.MessageID, h, highest specqbft.Height) *qbftstorage.StoredInstance {
+		cd := &spectypes.ConsensusData{
+			Duty: spectypes.Duty{
+				Type: spectypes.BNRoleAttester,
+				Slot: currentSlot - phase0.Slot(highest-h),
+			},
+		}
+		fullData, err := cd.MarshalSSZ()
+		require.NoError(t, err)
+		return &qbftstorage.StoredInstance{
+			State: &specqbft.State{ID: id[:], Height: h},
+			DecidedMessage: &specqbft.SignedMessage{
+				Signers: []spectypes.OperatorID{1},
+				Message: specqbft.Message{
+					MsgType:    specqbft.CommitMsgType,
+					Height:     h,
+					Identifier: id[:],
+				},
+				FullData: fullData,
+			},
+		}
+	}
+
+	setup := func(t *testing.T, heights int) (basedb.IDb, *QBFTStores) {
+		db, err := ssvstorage.GetStorageFactory(logger, basedb.Options{Type: "badger-memory"})
+		require.NoError(t, err)
+		stores := NewStores(db)
+		store := New(db, spectypes.BNRoleAttester.String(), forksprotocol.GenesisForkVersion)
+		stores.Add(spectypes.BNRoleAttester, store)
+		for _, pk := range pks {
+			msgID := spectypes.NewMsgID(types.GetDefaultDomain(), pk, spectypes.BNRoleAttester)
+			highest := specqbft.Height(heights - 1)
+			for h := specqbft.Height(0); h <= highest; h++ {
+				require.NoError(t, store.SaveInstance(generateInstance(t, msgID, h, highest)))
+			}
+			require.NoError(t, store.SaveHighestInstance(generateInstance(t, msgID, highest, highest)))
+		}
+		return db, stores
+	}
+
+	validators := func(logger *zap.Logger) ([][]byte, error) {
+		return pks, nil
+	}
+
+	countInstances := func(t *testing.T, stores *QBFTStores, pk []byte, heights int) int {
+		msgID := spectypes.NewMsgID(types.GetDefaultDomain(), pk, spectypes.BNRoleAttester)
+		res, err := stores.Get(spectypes.BNRoleAttester).GetInstancesInRange(msgID[:], 0, specqbft.Height(heights))
+		require.NoError(t, err)
+		return len(res)
+	}
+
+	t.Run("heights", func(t *testing.T) {
+		db, stores := setup(t, 100)
+		defer db.Close(logger)
+
+		pruner := NewPruner(context.Background(), db, stores, network, validators, RetentionOptions{Heights: 10})
+		n, err := pruner.Prune(logger)
+		require.NoError(t, err)
+		require.Equal(t, 180, n)
+		for _, pk := range pks {
+			require.Equal(t, 10, countInstances(t, stores, pk, 100))
+		}
+
+		// the next cycle continues from the pruned height
+		n, err = pruner.Prune(logger)
+		require.NoError(t, err)
+		require.Equal(t, 0, n)
+	})
+
+	t.Run("epochs", func(t *testing.T) {
+		db, stores := setup(t, 100)
+		defer db.Close(logger)
+
+		// 2 epochs back from the current epoch, so between 65 and 96 slots are retained
+		pruner := NewPruner(context.Background(), db, stores, network, validators, RetentionOptions{Epochs: 2})
+		_, err := pruner.Prune(logger)
+		require.NoError(t, err)
+		minSlot := network.GetEpochFirstSlot(network.EstimatedCurrentEpoch() - 2)
+		for _, pk := range pks {
+			require.Equal(t, int(currentSlot-minSlot)+1, countInstances(t, stores, pk, 100))
+		}
+	})
+
+	t.Run("heights or epochs", func(t *testing.T) {
+		db, stores := setup(t, 100)
+		defer db.Close(logger)
+
+		pruner := NewPruner(context.Background(), db, stores, network, validators, RetentionOptions{Heights: 10, Epochs: 1})
+		_, err := pruner.Prune(logger)
+		require.NoError(t, err)
+		minSlot := network.GetEpochFirstSlot(network.EstimatedCurrentEpoch() - 1)
+		expected := int(currentSlot-minSlot) + 1
+		if expected < 10 {
+			expected = 10
+		}
+		for _, pk := range pks {
+			require.Equal(t, expected, countInstances(t, stores, pk, 100))
+		}
+	})
+}
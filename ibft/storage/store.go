@@ -22,6 +22,9 @@ import (
 const (
 	highestInstanceKey = "highest_instance"
 	instanceKey        = "instance"
+	prunedHeightKey    = "pruned_height"
+	// pruneBatchSize is the amount of heights that are removed in a single transaction
+	pruneBatchSize = 1000
 )
 
 var (
@@ -141,20 +144,28 @@ func (i *ibftStorage) GetInstance(identifier []byte, height specqbft.Height) (*q
 }
 
 // GetInstancesInRange returns historical StoredInstance's in the given range.
+// heights are ordered in the keys, so the instances are fetched with a single range scan.
 func (i *ibftStorage) GetInstancesInRange(identifier []byte, from specqbft.Height, to specqbft.Height) ([]*qbftstorage.StoredInstance, error) {
 	i.forkLock.RLock()
 	defer i.forkLock.RUnlock()
 
 	instances := make([]*qbftstorage.StoredInstance, 0)
+	if to < from {
+		return instances, nil
+	}
 
-	for seq := from; seq <= to; seq++ {
-		instance, err := i.GetInstance(identifier, seq)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get instance")
-		}
-		if instance != nil {
-			instances = append(instances, instance)
+	prefix := make([]byte, 0, len(i.prefix)+len(identifier)+len(instanceKey))
+	prefix = append(append(append(prefix, i.prefix...), identifier...), instanceKey...)
+	err := i.db.GetRange(prefix, uInt64ToByteSlice(uint64(from)), uInt64ToByteSlice(uint64(to)), func(obj basedb.Obj) error {
+		instance := &qbftstorage.StoredInstance{}
+		if err := instance.Decode(obj.Value); err != nil {
+			return errors.Wrap(err, "could not decode instance")
 		}
+		instances = append(instances, instance)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get instances")
 	}
 
 	return instances, nil
@@ -165,20 +176,90 @@ func (i *ibftStorage) CleanAllInstances(logger *zap.Logger, msgID []byte) error
 	i.forkLock.RLock()
 	defer i.forkLock.RUnlock()
 
-	prefix := i.prefix
-	prefix = append(prefix, msgID[:]...)
-	prefix = append(prefix, []byte(instanceKey)...)
-	n, err := i.db.DeleteByPrefix(prefix)
+	// exact capacity, as the prefix is reused within a transaction (see basedb.Txn)
+	prefix := make([]byte, 0, len(i.prefix)+len(msgID))
+	prefix = append(append(prefix, i.prefix...), msgID...)
+
+	// historical instances are deleted in batches, as there might be too many for a single transaction
+	n, err := i.db.DeleteByPrefix(append(prefix, instanceKey...))
 	if err != nil {
 		return errors.Wrap(err, "failed to remove decided")
 	}
+	err = i.db.Update(func(txn basedb.Txn) error {
+		if err := txn.Delete(prefix, []byte(highestInstanceKey)); err != nil {
+			return errors.Wrap(err, "failed to remove last decided")
+		}
+		if err := txn.Delete(prefix, []byte(prunedHeightKey)); err != nil {
+			return errors.Wrap(err, "failed to remove pruned height")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
 	logger.Debug("removed decided", zap.Int("count", n))
+	return nil
+}
+
+// GetPrunedHeight returns the lowest height of the given identifier that wasn't pruned.
+func (i *ibftStorage) GetPrunedHeight(identifier []byte) (specqbft.Height, error) {
+	i.forkLock.RLock()
+	defer i.forkLock.RUnlock()
 
-	if err := i.delete(highestInstanceKey, msgID[:]); err != nil {
-		return errors.Wrap(err, "failed to remove last decided")
+	val, found, err := i.get(prunedHeightKey, identifier[:])
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	if !found || len(val) != 8 {
+		return 0, nil
+	}
+	return specqbft.Height(binary.BigEndian.Uint64(val)), nil
+}
+
+// PruneInstances removes the historical instances of the given identifier below the given height,
+// starting from the last pruned height. Returns the amount of removed instances.
+func (i *ibftStorage) PruneInstances(identifier []byte, below specqbft.Height) (int, error) {
+	from, err := i.GetPrunedHeight(identifier)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get pruned height")
+	}
+
+	i.forkLock.RLock()
+	defer i.forkLock.RUnlock()
+
+	// exact capacity, as the prefix is reused for every height in the batch (see basedb.Txn)
+	prefix := make([]byte, 0, len(i.prefix)+len(identifier))
+	prefix = append(append(prefix, i.prefix...), identifier...)
+	removed := 0
+	for from < below {
+		to := from + pruneBatchSize
+		if to > below {
+			to = below
+		}
+		err := i.db.Update(func(txn basedb.Txn) error {
+			for h := from; h < to; h++ {
+				key := i.key(instanceKey, uInt64ToByteSlice(uint64(h)))
+				_, found, err := txn.Get(prefix, key)
+				if err != nil {
+					return err
+				}
+				if !found {
+					continue
+				}
+				if err := txn.Delete(prefix, key); err != nil {
+					return err
+				}
+				removed++
+			}
+			return txn.Set(prefix, i.key(prunedHeightKey), uInt64ToByteSlice(uint64(to)))
+		})
+		if err != nil {
+			return removed, errors.Wrap(err, "could not prune instances")
+		}
+		from = to
+	}
+	return removed, nil
 }
 
 func (i *ibftStorage) save(value []byte, id string, pk []byte, keyParams ...[]byte) error {
@@ -200,12 +281,6 @@ func (i *ibftStorage) get(id string, pk []byte, keyParams ...[]byte) ([]byte, bo
 	return obj.Value, found, nil
 }
 
-func (i *ibftStorage) delete(id string, pk []byte, keyParams ...[]byte) error {
-	prefix := append(i.prefix, pk...)
-	key := i.key(id, keyParams...)
-	return i.db.Delete(prefix, key)
-}
-
 func (i *ibftStorage) key(id string, params ...[]byte) []byte {
 	ret := []byte(id)
 	for _, p := range params {
@@ -214,8 +289,9 @@ func (i *ibftStorage) key(id string, params ...[]byte) []byte {
 	return ret
 }
 
+// uInt64ToByteSlice encodes the given number as big-endian, so the keys of heights are lexicographically ordered
 func uInt64ToByteSlice(n uint64) []byte {
 	b := make([]byte, 8)
-	binary.LittleEndian.PutUint64(b, n)
+	binary.BigEndian.PutUint64(b, n)
 	return b
 }
@@ -181,3 +181,40 @@ func newTestIbftStorage(logger *zap.Logger, prefix string, forkVersion forksprot
 	}
 	return New(db, prefix, forkVersion), nil
 }
+
+func TestPruneInstances(t *testing.T) {
+	logger := logging.TestLogger(t)
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleAttester)
+	storage, err := newTestIbftStorage(logger, "test", forksprotocol.GenesisForkVersion)
+	require.NoError(t, err)
+
+	msgsCount := pruneBatchSize + 10
+	for i := 0; i < msgsCount; i++ {
+		require.NoError(t, storage.SaveInstance(&qbftstorage.StoredInstance{
+			State: &specqbft.State{ID: msgID[:], Height: specqbft.Height(i)},
+		}))
+	}
+
+	n, err := storage.PruneInstances(msgID[:], specqbft.Height(msgsCount-5))
+	require.NoError(t, err)
+	require.Equal(t, msgsCount-5, n)
+
+	pruned, err := storage.GetPrunedHeight(msgID[:])
+	require.NoError(t, err)
+	require.Equal(t, specqbft.Height(msgsCount-5), pruned)
+
+	res, err := storage.GetInstancesInRange(msgID[:], 0, specqbft.Height(msgsCount))
+	require.NoError(t, err)
+	require.Len(t, res, 5)
+	require.Equal(t, specqbft.Height(msgsCount-5), res[0].State.Height)
+
+	// pruning below the pruned height is a no-op
+	n, err = storage.PruneInstances(msgID[:], 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	require.NoError(t, storage.CleanAllInstances(logger, msgID[:]))
+	pruned, err = storage.GetPrunedHeight(msgID[:])
+	require.NoError(t, err)
+	require.Equal(t, specqbft.Height(0), pruned)
+}
@@ -4,13 +4,22 @@ import (
 	"sync"
 
 	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
 	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+	"github.com/bloxapp/ssv/protocol/v2/types"
 	"github.com/bloxapp/ssv/storage/basedb"
 )
 
+// pendingCleanupPrefix is the prefix of the validators whose decided instances are being removed,
+// so that a removal that was interrupted is completed later on (see QBFTStores.ResumeCleanups)
+var pendingCleanupPrefix = []byte("pending_decided_cleanup/")
+
 func NewStoresFromRoles(db basedb.IDb, roles ...spectypes.BeaconRole) *QBFTStores {
-	stores := NewStores()
+	stores := NewStores(db)
 
 	for _, role := range roles {
 		stores.Add(role, New(db, role.String(), forksprotocol.GenesisForkVersion))
@@ -22,11 +31,14 @@ func NewStoresFromRoles(db basedb.IDb, roles ...spectypes.BeaconRole) *QBFTStore
 // QBFTStores wraps sync map with cast functions to qbft store
 type QBFTStores struct {
 	m sync.Map
+	// db holds the pending cleanups of validators
+	db basedb.IDb
 }
 
-func NewStores() *QBFTStores {
+func NewStores(db basedb.IDb) *QBFTStores {
 	return &QBFTStores{
-		m: sync.Map{},
+		m:  sync.Map{},
+		db: db,
 	}
 }
 
@@ -44,3 +56,56 @@ func (qs *QBFTStores) Get(role spectypes.BeaconRole) qbftstorage.QBFTStore {
 func (qs *QBFTStores) Add(role spectypes.BeaconRole, store qbftstorage.QBFTStore) {
 	qs.m.Store(role, store)
 }
+
+// Each calls the given function for each store
+func (qs *QBFTStores) Each(f func(role spectypes.BeaconRole, store qbftstorage.QBFTStore)) {
+	qs.m.Range(func(key, value any) bool {
+		f(key.(spectypes.BeaconRole), value.(qbftstorage.QBFTStore))
+		return true
+	})
+}
+
+// CleanAllInstances removes the decided instances of the given validator in all the role stores.
+// there might be too many instances for a single transaction, so the cleanup is marked as pending
+// until all the role stores are cleaned, and a cleanup that fails part-way is completed by ResumeCleanups.
+func (qs *QBFTStores) CleanAllInstances(logger *zap.Logger, pubKey []byte) error {
+	if err := qs.db.Set(pendingCleanupPrefix, pubKey, []byte{1}); err != nil {
+		return errors.Wrap(err, "could not save pending cleanup")
+	}
+	var err error
+	qs.Each(func(role spectypes.BeaconRole, store qbftstorage.QBFTStore) {
+		if err != nil {
+			return
+		}
+		msgID := spectypes.NewMsgID(types.GetDefaultDomain(), pubKey, role)
+		if cleanErr := store.CleanAllInstances(logger, msgID[:]); cleanErr != nil {
+			err = errors.Wrapf(cleanErr, "could not clean %s instances", role.String())
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := qs.db.Delete(pendingCleanupPrefix, pubKey); err != nil {
+		return errors.Wrap(err, "could not remove pending cleanup")
+	}
+	return nil
+}
+
+// ResumeCleanups completes the cleanups of validators that were interrupted, e.g. by a failure or a restart
+func (qs *QBFTStores) ResumeCleanups(logger *zap.Logger) error {
+	var pubKeys [][]byte
+	err := qs.db.GetAll(logger, pendingCleanupPrefix, func(i int, obj basedb.Obj) error {
+		pubKeys = append(pubKeys, append([]byte{}, obj.Key...))
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not get pending cleanups")
+	}
+	for _, pubKey := range pubKeys {
+		logger.Info("resuming decided cleanup", fields.PubKey(pubKey))
+		if err := qs.CleanAllInstances(logger, pubKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
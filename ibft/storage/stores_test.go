@@ -3,14 +3,21 @@ package storage
 import (
 	"testing"
 
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
 	"github.com/bloxapp/ssv-spec/types"
 	"github.com/bloxapp/ssv/logging"
 	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+	protocoltypes "github.com/bloxapp/ssv/protocol/v2/types"
+	ssvstorage "github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestQBFTStores(t *testing.T) {
-	qbftMap := NewStores()
+	qbftMap := NewStores(nil)
 
 	store, err := newTestIbftStorage(logging.TestLogger(t), "", forksprotocol.GenesisForkVersion)
 	require.NoError(t, err)
@@ -20,3 +27,103 @@ func TestQBFTStores(t *testing.T) {
 	require.NotNil(t, qbftMap.Get(types.BNRoleAttester))
 	require.NotNil(t, qbftMap.Get(types.BNRoleProposer))
 }
+
+func TestQBFTStores_CleanAllInstances(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := ssvstorage.GetStorageFactory(logger, basedb.Options{Type: "badger-memory"})
+	require.NoError(t, err)
+	defer db.Close(logger)
+
+	roles := []types.BeaconRole{types.BNRoleAttester, types.BNRoleProposer, types.BNRoleSyncCommittee, types.BNRoleSyncCommitteeContribution}
+	stores := NewStoresFromRoles(db, roles...)
+
+	pks := [][]byte{[]byte("pk1"), []byte("pk2")}
+	for _, role := range roles {
+		for _, pk := range pks {
+			msgID := types.NewMsgID(protocoltypes.GetDefaultDomain(), pk, role)
+			for h := specqbft.Height(0); h < 5; h++ {
+				instance := &qbftstorage.StoredInstance{State: &specqbft.State{ID: msgID[:], Height: h}}
+				require.NoError(t, stores.Get(role).SaveInstance(instance))
+				require.NoError(t, stores.Get(role).SaveHighestInstance(instance))
+			}
+		}
+	}
+
+	require.NoError(t, stores.CleanAllInstances(logger, pks[0]))
+
+	for _, role := range roles {
+		for i, pk := range pks {
+			msgID := types.NewMsgID(protocoltypes.GetDefaultDomain(), pk, role)
+			instances, err := stores.Get(role).GetInstancesInRange(msgID[:], 0, 5)
+			require.NoError(t, err)
+			highest, err := stores.Get(role).GetHighestInstance(msgID[:])
+			require.NoError(t, err)
+			if i == 0 {
+				require.Len(t, instances, 0)
+				require.Nil(t, highest)
+			} else {
+				require.Len(t, instances, 5)
+				require.NotNil(t, highest)
+			}
+		}
+	}
+}
+
+// failingStore fails to clean instances while fail is set
+type failingStore struct {
+	qbftstorage.QBFTStore
+	fail bool
+}
+
+func (s *failingStore) CleanAllInstances(logger *zap.Logger, msgID []byte) error {
+	if s.fail {
+		return errors.New("failed")
+	}
+	return s.QBFTStore.CleanAllInstances(logger, msgID)
+}
+
+func TestQBFTStores_CleanAllInstancesFailure(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := ssvstorage.GetStorageFactory(logger, basedb.Options{Type: "badger-memory"})
+	require.NoError(t, err)
+	defer db.Close(logger)
+
+	roles := []types.BeaconRole{types.BNRoleAttester, types.BNRoleProposer, types.BNRoleSyncCommittee}
+	stores := NewStoresFromRoles(db, roles...)
+	failing := &failingStore{QBFTStore: stores.Get(types.BNRoleProposer), fail: true}
+	stores.Add(types.BNRoleProposer, failing)
+
+	pk := []byte("pk1")
+	for _, role := range roles {
+		msgID := types.NewMsgID(protocoltypes.GetDefaultDomain(), pk, role)
+		instance := &qbftstorage.StoredInstance{State: &specqbft.State{ID: msgID[:], Height: 1}}
+		require.NoError(t, stores.Get(role).SaveInstance(instance))
+		require.NoError(t, stores.Get(role).SaveHighestInstance(instance))
+	}
+
+	// the cleanup fails part-way, so it stays pending
+	require.ErrorContains(t, stores.CleanAllInstances(logger, pk), "could not clean PROPOSER instances")
+	_, found, err := db.Get(pendingCleanupPrefix, pk)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// the pending cleanup is completed once the failure is resolved
+	failing.fail = false
+	require.NoError(t, stores.ResumeCleanups(logger))
+	for _, role := range roles {
+		msgID := types.NewMsgID(protocoltypes.GetDefaultDomain(), pk, role)
+		highest, err := stores.Get(role).GetHighestInstance(msgID[:])
+		require.NoError(t, err)
+		require.Nil(t, highest)
+		instances, err := stores.Get(role).GetInstancesInRange(msgID[:], 0, 5)
+		require.NoError(t, err)
+		require.Len(t, instances, 0)
+	}
+	_, found, err = db.Get(pendingCleanupPrefix, pk)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// nothing is pending anymore
+	failing.fail = true
+	require.NoError(t, stores.ResumeCleanups(logger))
+}
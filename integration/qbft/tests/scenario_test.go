@@ -176,7 +176,7 @@ func newStores(logger *zap.Logger) *qbftstorage.QBFTStores {
 		panic(err)
 	}
 
-	storageMap := qbftstorage.NewStores()
+	storageMap := qbftstorage.NewStores(db)
 
 	roles := []spectypes.BeaconRole{
 		spectypes.BNRoleAttester,
@@ -204,8 +204,9 @@ func createValidator(t *testing.T, pCtx context.Context, id spectypes.OperatorID
 	require.NoError(t, err)
 
 	options := protocolvalidator.Options{
-		Storage: newStores(logger),
-		Network: node,
+		Storage:       newStores(logger),
+		Network:       node,
+		BeaconNetwork: spectypes.PraterNetwork,
 		SSVShare: &types.SSVShare{
 			Share: *testingShare(keySet, id),
 			Metadata: types.Metadata{
@@ -193,7 +193,7 @@ func Round(round specqbft.Round) zap.Field {
 }
 
 func Role(val spectypes.BeaconRole) zap.Field {
-	return zap.Stringer(FieldRole, val)
+	return zap.String(FieldRole, message.BeaconRoleToString(val))
 }
 
 func MessageID(val spectypes.MessageID) zap.Field {
@@ -293,7 +293,7 @@ func BuilderProposals(v bool) zap.Field {
 }
 
 func FormatDutyID(epoch phase0.Epoch, duty *spectypes.Duty) string {
-	return fmt.Sprintf("%v-e%v-s%v-v%v", duty.Type.String(), epoch, duty.Slot, duty.ValidatorIndex)
+	return fmt.Sprintf("%v-e%v-s%v-v%v", message.BeaconRoleToString(duty.Type), epoch, duty.Slot, duty.ValidatorIndex)
 }
 
 func Root(r [32]byte) zap.Field {
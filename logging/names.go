@@ -13,13 +13,14 @@ const (
 	NameValidator        = "Validator"
 	NameWSServer         = "WSServer"
 
-	NameBadgerDBLog       = "BadgerDBLog"
-	NameBadgerDBReporting = "BadgerDBReporting"
-	NameCreateThreshold   = "CreateThreshold"
-	NameDiscoveryV5Logger = "DiscoveryV5Logger"
-	NameExportKeys        = "ExportKeys"
-	NameOnFork            = "OnFork"
-	NameP2PStorage        = "P2PStorage"
-	NamePubsubTrace       = "PubsubTrace"
-	NameScoreInspector    = "ScoreInspector"
+	NameBadgerDBLog        = "BadgerDBLog"
+	NameBadgerDBReporting  = "BadgerDBReporting"
+	NameCreateThreshold    = "CreateThreshold"
+	NameDiscoveryV5Logger  = "DiscoveryV5Logger"
+	NameExportKeys         = "ExportKeys"
+	NameOnFork             = "OnFork"
+	NameP2PStorage         = "P2PStorage"
+	NamePubsubTrace        = "PubsubTrace"
+	NameScoreInspector     = "ScoreInspector"
+	NameSlashingProtection = "SlashingProtection"
 )
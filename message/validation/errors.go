@@ -0,0 +1,47 @@
+package validation
+
+// Error is a message validation failure.
+// Rejected messages penalize the peer that propagated them, while ignored messages are only dropped.
+type Error struct {
+	reason string
+	reject bool
+}
+
+func (e *Error) Error() string {
+	return "invalid message: " + e.reason
+}
+
+// Reason returns a short description of the failure, that is used as a metric label
+func (e *Error) Reason() string {
+	return e.reason
+}
+
+// Reject returns true if the message should be rejected rather than ignored
+func (e *Error) Reject() bool {
+	return e.reject
+}
+
+var (
+	// ErrEventMessage is returned for internal event messages that were sent over the network
+	ErrEventMessage = &Error{reason: "event_msg", reject: true}
+	// ErrUnknownMsgType is returned for message types that are not propagated over pubsub
+	ErrUnknownMsgType = &Error{reason: "unknown_msg_type", reject: true}
+	// ErrWrongDomain is returned for messages of another network
+	ErrWrongDomain = &Error{reason: "wrong_domain", reject: true}
+	// ErrMalformedMessage is returned when the message data couldn't be decoded or is not well-formed
+	ErrMalformedMessage = &Error{reason: "malformed", reject: true}
+	// ErrUnknownValidator is returned when the share of the validator is not known (yet) to this node
+	ErrUnknownValidator = &Error{reason: "unknown_validator"}
+	// ErrLiquidatedValidator is returned for messages of liquidated validators
+	ErrLiquidatedValidator = &Error{reason: "liquidated_validator"}
+	// ErrSignerNotInCommittee is returned when a signer is not an operator of the validator
+	ErrSignerNotInCommittee = &Error{reason: "signer_not_in_committee", reject: true}
+	// ErrInvalidSignature is returned when the signature doesn't match the signers
+	ErrInvalidSignature = &Error{reason: "signature", reject: true}
+	// ErrEarlySlot is returned for messages of slots that didn't start yet
+	ErrEarlySlot = &Error{reason: "early_slot"}
+	// ErrLateSlot is returned for messages of slots that are too old
+	ErrLateSlot = &Error{reason: "late_slot"}
+	// ErrLateHeight is returned for consensus messages of heights that are too old
+	ErrLateHeight = &Error{reason: "late_height"}
+)
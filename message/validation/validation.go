@@ -0,0 +1,294 @@
+// Package validation validates the content of SSV messages that are received over pubsub,
+// before they are propagated to other peers and processed by the validators.
+package validation
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/protocol/v2/message"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+const (
+	// DefaultLateSlots is the amount of slots that messages are accepted after their duty slot
+	DefaultLateSlots = 34
+	// DefaultEarlySlots is the amount of slots that messages are accepted before their duty slot,
+	// to allow some clock disparity between peers
+	DefaultEarlySlots = 1
+	// DefaultLateHeights is the amount of heights that consensus messages are accepted below the highest known height
+	DefaultLateHeights = 10
+)
+
+// Mode determines how the results of message validation are applied
+type Mode string
+
+const (
+	// ModeEnabled rejects or ignores messages that fail validation
+	ModeEnabled Mode = "enabled"
+	// ModeLogOnly only logs validation failures and accepts the messages, used when rolling out validation
+	ModeLogOnly Mode = "log-only"
+	// ModeDisabled turns off the validation of message content
+	ModeDisabled Mode = "disabled"
+)
+
+// ParseMode parses the given validation mode, an empty value means ModeEnabled
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case "":
+		return ModeEnabled, nil
+	case ModeEnabled, ModeLogOnly, ModeDisabled:
+		return mode, nil
+	default:
+		return "", errors.Errorf("unknown message validation mode %q", s)
+	}
+}
+
+// SharesStorage is the storage of validator shares that is used to validate messages
+type SharesStorage interface {
+	GetShare(key []byte) (*types.SSVShare, bool, error)
+}
+
+// Options are the options of MessageValidator
+type Options struct {
+	Network     beaconprotocol.Network
+	Shares      SharesStorage
+	LateSlots   uint64
+	EarlySlots  uint64
+	LateHeights uint64
+	Mode        Mode
+}
+
+// MessageValidator validates the content of SSV messages:
+// the type of message, the committee of the signers, the slot or height and the signature.
+type MessageValidator struct {
+	network     beaconprotocol.Network
+	shares      SharesStorage
+	lateSlots   phase0.Slot
+	earlySlots  phase0.Slot
+	lateHeights specqbft.Height
+	logOnly     bool
+
+	// heights holds the highest height of valid consensus messages by validator and identifier,
+	// entries of removed or liquidated validators and entries that weren't updated within heightsTTL are evicted
+	heights     map[string]map[spectypes.MessageID]heightEntry
+	heightsLock sync.RWMutex
+	heightsTTL  time.Duration
+	nextPrune   time.Time
+	now         func() time.Time
+}
+
+// heightEntry is the highest height of an identifier and the time it was last updated
+type heightEntry struct {
+	height  specqbft.Height
+	updated time.Time
+}
+
+// NewMessageValidator creates a new instance of MessageValidator
+func NewMessageValidator(opts Options) *MessageValidator {
+	if opts.LateSlots == 0 {
+		opts.LateSlots = DefaultLateSlots
+	}
+	if opts.EarlySlots == 0 {
+		opts.EarlySlots = DefaultEarlySlots
+	}
+	if opts.LateHeights == 0 {
+		opts.LateHeights = DefaultLateHeights
+	}
+	return &MessageValidator{
+		network:     opts.Network,
+		shares:      opts.Shares,
+		lateSlots:   phase0.Slot(opts.LateSlots),
+		earlySlots:  phase0.Slot(opts.EarlySlots),
+		lateHeights: specqbft.Height(opts.LateHeights),
+		logOnly:     opts.Mode == ModeLogOnly,
+		heights:     make(map[string]map[spectypes.MessageID]heightEntry),
+		// an identifier that had no messages within the window of late slots has no running duty
+		heightsTTL: time.Duration(opts.LateSlots) * opts.Network.SlotDurationSec(),
+		now:        time.Now,
+	}
+}
+
+// LogOnly returns true if validation failures should only be logged, without dropping the messages
+func (mv *MessageValidator) LogOnly() bool {
+	return mv.logOnly
+}
+
+// removeValidator evicts the tracked heights of the given validator,
+// it is called once messages of the validator show that it was removed or liquidated
+func (mv *MessageValidator) removeValidator(pk []byte) {
+	mv.heightsLock.Lock()
+	defer mv.heightsLock.Unlock()
+
+	delete(mv.heights, string(pk))
+}
+
+// Validate validates the given message, a returned error is of type *Error
+func (mv *MessageValidator) Validate(msg *spectypes.SSVMessage) error {
+	switch msg.MsgType {
+	case spectypes.SSVConsensusMsgType, spectypes.SSVPartialSignatureMsgType:
+	case message.SSVEventMsgType:
+		return ErrEventMessage
+	default:
+		return ErrUnknownMsgType
+	}
+
+	domain := types.GetDefaultDomain()
+	if !bytes.Equal(msg.MsgID.GetDomain(), domain[:]) {
+		return ErrWrongDomain
+	}
+	pk := msg.MsgID.GetPubKey()
+	share, found, err := mv.shares.GetShare(pk)
+	if err != nil {
+		return ErrUnknownValidator
+	}
+	if !found {
+		mv.removeValidator(pk)
+		return ErrUnknownValidator
+	}
+	if share.Liquidated {
+		mv.removeValidator(pk)
+		return ErrLiquidatedValidator
+	}
+
+	if msg.MsgType == spectypes.SSVConsensusMsgType {
+		return mv.validateConsensusMessage(share, msg)
+	}
+	return mv.validatePartialSignatureMessage(share, msg)
+}
+
+func (mv *MessageValidator) validateConsensusMessage(share *types.SSVShare, msg *spectypes.SSVMessage) error {
+	signedMsg := &specqbft.SignedMessage{}
+	if err := signedMsg.Decode(msg.Data); err != nil {
+		return errors.Wrap(ErrMalformedMessage, err.Error())
+	}
+	if err := signedMsg.Validate(); err != nil {
+		return errors.Wrap(ErrMalformedMessage, err.Error())
+	}
+	if specqbft.ControllerIdToMessageID(signedMsg.Message.Identifier) != msg.MsgID {
+		return errors.Wrap(ErrMalformedMessage, "identifier mismatch")
+	}
+	if err := checkSigners(share, signedMsg.Signers...); err != nil {
+		return err
+	}
+
+	mv.heightsLock.RLock()
+	highest, known := mv.heights[string(msg.MsgID.GetPubKey())][msg.MsgID]
+	mv.heightsLock.RUnlock()
+	if known && signedMsg.Message.Height+mv.lateHeights < highest.height {
+		return ErrLateHeight
+	}
+	// the full data of proposals and decided messages holds the duty
+	if len(signedMsg.FullData) > 0 {
+		cd := &spectypes.ConsensusData{}
+		if err := cd.UnmarshalSSZ(signedMsg.FullData); err == nil {
+			if err := mv.checkSlot(cd.Duty.Slot); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := types.VerifyByOperators(signedMsg.Signature, signedMsg, share.DomainType, spectypes.QBFTSignatureType, share.Committee); err != nil {
+		return errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+
+	mv.updateHeight(msg.MsgID, signedMsg.Message.Height)
+	return nil
+}
+
+// updateHeight tracks the given height if it's the highest of the identifier,
+// and evicts the entries that expired once in heightsTTL
+func (mv *MessageValidator) updateHeight(msgID spectypes.MessageID, height specqbft.Height) {
+	mv.heightsLock.Lock()
+	defer mv.heightsLock.Unlock()
+
+	now := mv.now()
+	if now.After(mv.nextPrune) {
+		mv.pruneHeights(now.Add(-mv.heightsTTL))
+		mv.nextPrune = now.Add(mv.heightsTTL)
+	}
+
+	pk := string(msgID.GetPubKey())
+	validatorHeights, ok := mv.heights[pk]
+	if !ok {
+		validatorHeights = make(map[spectypes.MessageID]heightEntry)
+		mv.heights[pk] = validatorHeights
+	}
+	entry := validatorHeights[msgID]
+	if height >= entry.height {
+		entry.height = height
+		entry.updated = now
+		validatorHeights[msgID] = entry
+	}
+}
+
+// pruneHeights removes the entries that were not updated since the given time, heightsLock must be held
+func (mv *MessageValidator) pruneHeights(before time.Time) {
+	for pk, validatorHeights := range mv.heights {
+		for msgID, entry := range validatorHeights {
+			if entry.updated.Before(before) {
+				delete(validatorHeights, msgID)
+			}
+		}
+		if len(validatorHeights) == 0 {
+			delete(mv.heights, pk)
+		}
+	}
+}
+
+func (mv *MessageValidator) validatePartialSignatureMessage(share *types.SSVShare, msg *spectypes.SSVMessage) error {
+	signedMsg := &spectypes.SignedPartialSignatureMessage{}
+	if err := signedMsg.Decode(msg.Data); err != nil {
+		return errors.Wrap(ErrMalformedMessage, err.Error())
+	}
+	if err := signedMsg.Validate(); err != nil {
+		return errors.Wrap(ErrMalformedMessage, err.Error())
+	}
+	if err := checkSigners(share, signedMsg.Signer); err != nil {
+		return err
+	}
+	if err := mv.checkSlot(signedMsg.Message.Slot); err != nil {
+		return err
+	}
+
+	if err := types.VerifyByOperators(signedMsg.Signature, signedMsg, share.DomainType, spectypes.PartialSignatureType, share.Committee); err != nil {
+		return errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	return nil
+}
+
+// checkSlot checks that the given slot is within the window of accepted slots
+func (mv *MessageValidator) checkSlot(slot phase0.Slot) error {
+	currentSlot := mv.network.EstimatedCurrentSlot()
+	if slot > currentSlot+mv.earlySlots {
+		return ErrEarlySlot
+	}
+	if slot+mv.lateSlots < currentSlot {
+		return ErrLateSlot
+	}
+	return nil
+}
+
+// checkSigners checks that all the given signers are operators in the committee of the share
+func checkSigners(share *types.SSVShare, signers ...spectypes.OperatorID) error {
+	for _, signer := range signers {
+		found := false
+		for _, operator := range share.Committee {
+			if operator.OperatorID == signer {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrSignerNotInCommittee
+		}
+	}
+	return nil
+}
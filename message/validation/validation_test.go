@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/bloxapp/eth2-key-manager/core"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	spectestingutils "github.com/bloxapp/ssv-spec/types/testingutils"
+	"github.com/stretchr/testify/require"
+
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/protocol/v2/message"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+type mockShares map[string]*types.SSVShare
+
+func (m mockShares) GetShare(key []byte) (*types.SSVShare, bool, error) {
+	share, ok := m[hex.EncodeToString(key)]
+	return share, ok, nil
+}
+
+// networkAtSlot returns a beacon network which current slot is the given one
+func networkAtSlot(slot uint64) beaconprotocol.Network {
+	genesis := uint64(time.Now().Unix()) - slot*uint64(core.PraterNetwork.SlotDurationSec().Seconds())
+	return beaconprotocol.NewNetwork(core.PraterNetwork, genesis)
+}
+
+func TestMessageValidator(t *testing.T) {
+	ks := spectestingutils.Testing4SharesSet()
+	share := &types.SSVShare{Share: *spectestingutils.TestingShare(ks)}
+	pk := ks.ValidatorPK.Serialize()
+	shares := mockShares{hex.EncodeToString(pk): share}
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), pk, spectypes.BNRoleAttester)
+
+	consensusMsg := func(t *testing.T, signer spectypes.OperatorID, sharesIdx spectypes.OperatorID, height specqbft.Height) *spectypes.SSVMessage {
+		signed := spectestingutils.SignQBFTMsg(ks.Shares[sharesIdx], signer, &specqbft.Message{
+			MsgType:    specqbft.PrepareMsgType,
+			Height:     height,
+			Round:      specqbft.FirstRound,
+			Identifier: msgID[:],
+			Root:       spectestingutils.TestingQBFTRootData,
+		})
+		data, err := signed.Encode()
+		require.NoError(t, err)
+		return &spectypes.SSVMessage{MsgType: spectypes.SSVConsensusMsgType, MsgID: msgID, Data: data}
+	}
+	partialSigMsg := func(t *testing.T) *spectypes.SSVMessage {
+		signed := spectestingutils.PostConsensusAttestationMsg(ks.Shares[1], 1, specqbft.FirstHeight)
+		data, err := signed.Encode()
+		require.NoError(t, err)
+		return &spectypes.SSVMessage{MsgType: spectypes.SSVPartialSignatureMsgType, MsgID: msgID, Data: data}
+	}
+
+	t.Run("valid consensus message", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		require.NoError(t, mv.Validate(consensusMsg(t, 1, 1, 2)))
+	})
+
+	t.Run("valid partial signature message", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		require.NoError(t, mv.Validate(partialSigMsg(t)))
+	})
+
+	t.Run("event message", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		err := mv.Validate(&spectypes.SSVMessage{MsgType: message.SSVEventMsgType, MsgID: msgID})
+		require.ErrorIs(t, err, ErrEventMessage)
+		require.True(t, ErrEventMessage.Reject())
+	})
+
+	t.Run("unknown validator", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: mockShares{}})
+		err := mv.Validate(consensusMsg(t, 1, 1, 2))
+		require.ErrorIs(t, err, ErrUnknownValidator)
+		require.False(t, ErrUnknownValidator.Reject())
+	})
+
+	t.Run("wrong domain", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		msg := consensusMsg(t, 1, 1, 2)
+		msg.MsgID = spectypes.NewMsgID(spectypes.DomainType{0xff, 0xff, 0xff, 0xff}, pk, spectypes.BNRoleAttester)
+		require.ErrorIs(t, mv.Validate(msg), ErrWrongDomain)
+	})
+
+	t.Run("malformed message", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		msg := consensusMsg(t, 1, 1, 2)
+		msg.Data = []byte{1, 2, 3}
+		require.ErrorIs(t, mv.Validate(msg), ErrMalformedMessage)
+	})
+
+	t.Run("signer not in committee", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		require.ErrorIs(t, mv.Validate(consensusMsg(t, 5, 1, 2)), ErrSignerNotInCommittee)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		require.ErrorIs(t, mv.Validate(consensusMsg(t, 2, 1, 2)), ErrInvalidSignature)
+	})
+
+	t.Run("late height", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		require.NoError(t, mv.Validate(consensusMsg(t, 1, 1, 20)))
+		require.NoError(t, mv.Validate(consensusMsg(t, 1, 1, 10)))
+		require.ErrorIs(t, mv.Validate(consensusMsg(t, 1, 1, 9)), ErrLateHeight)
+	})
+
+	t.Run("late slot", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot + 100), Shares: shares})
+		err := mv.Validate(partialSigMsg(t))
+		require.ErrorIs(t, err, ErrLateSlot)
+		require.False(t, ErrLateSlot.Reject())
+	})
+
+	t.Run("early slot", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot - 5), Shares: shares})
+		require.ErrorIs(t, mv.Validate(partialSigMsg(t)), ErrEarlySlot)
+	})
+
+	t.Run("evict heights of removed validator", func(t *testing.T) {
+		current := mockShares{hex.EncodeToString(pk): share}
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: current})
+		require.NoError(t, mv.Validate(consensusMsg(t, 1, 1, 20)))
+		require.Len(t, mv.heights, 1)
+
+		delete(current, hex.EncodeToString(pk))
+		require.ErrorIs(t, mv.Validate(consensusMsg(t, 1, 1, 20)), ErrUnknownValidator)
+		require.Empty(t, mv.heights)
+	})
+
+	t.Run("evict old heights", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares})
+		now := time.Now()
+		mv.now = func() time.Time { return now }
+		require.NoError(t, mv.Validate(consensusMsg(t, 1, 1, 20)))
+
+		now = now.Add(mv.heightsTTL + time.Second)
+		mv.updateHeight(spectypes.NewMsgID(types.GetDefaultDomain(), pk, spectypes.BNRoleProposer), 1)
+		require.Len(t, mv.heights[string(pk)], 1)
+		require.NoError(t, mv.Validate(consensusMsg(t, 1, 1, 9)))
+	})
+
+	t.Run("log only", func(t *testing.T) {
+		mv := NewMessageValidator(Options{Network: networkAtSlot(spectestingutils.TestingDutySlot), Shares: shares, Mode: ModeLogOnly})
+		require.True(t, mv.LogOnly())
+		require.ErrorIs(t, mv.Validate(consensusMsg(t, 2, 1, 2)), ErrInvalidSignature)
+	})
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("")
+	require.NoError(t, err)
+	require.Equal(t, ModeEnabled, mode)
+	mode, err = ParseMode("log-only")
+	require.NoError(t, err)
+	require.Equal(t, ModeLogOnly, mode)
+	_, err = ParseMode("off")
+	require.Error(t, err)
+}
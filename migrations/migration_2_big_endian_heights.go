@@ -0,0 +1,131 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+const (
+	// decidedInstanceKey and decidedPrunedHeightKey are the keys of the decided storage (ibft/storage),
+	// which are stored as <role><identifier><key>[height]
+	decidedInstanceKey     = "instance"
+	decidedPrunedHeightKey = "pruned_height"
+	// decidedKeysBatchSize is the amount of keys that are rewritten in a single transaction
+	decidedKeysBatchSize = 1000
+)
+
+// decidedRoles are the roles of the decided storage, each role is stored under its own prefix
+var decidedRoles = []spectypes.BeaconRole{
+	spectypes.BNRoleAttester,
+	spectypes.BNRoleProposer,
+	spectypes.BNRoleAggregator,
+	spectypes.BNRoleSyncCommittee,
+	spectypes.BNRoleSyncCommitteeContribution,
+	spectypes.BNRoleValidatorRegistration,
+}
+
+// migrationBigEndianHeights rewrites the keys of historical decided instances,
+// which heights were encoded as little-endian, to big-endian so the keys are ordered by height.
+// The height is decoded from the stored instance, so keys that were already rewritten are skipped
+// in case the migration is executed again after a failure.
+var migrationBigEndianHeights = Migration{
+	Name: "migration_2_big_endian_heights",
+	Run: func(ctx context.Context, logger *zap.Logger, opt Options, key []byte) error {
+		for _, role := range decidedRoles {
+			n, err := migrateDecidedKeys(ctx, logger, opt.Db, role)
+			if err != nil {
+				return errors.Wrapf(err, "could not migrate %s decided keys", role.String())
+			}
+			logger.Debug("migrated decided keys", zap.String("role", role.String()), zap.Int("count", n))
+		}
+		return opt.Db.Set(migrationsPrefix, key, migrationCompleted)
+	},
+}
+
+func migrateDecidedKeys(ctx context.Context, logger *zap.Logger, db basedb.IDb, role spectypes.BeaconRole) (int, error) {
+	// exact capacity, as the prefix is reused for the deletes and sets of each flush (see basedb.Txn)
+	prefix := make([]byte, 0, len(role.String()))
+	prefix = append(prefix, role.String()...)
+
+	var sets, deletes []basedb.Obj
+	migrated := 0
+	flush := func() error {
+		if len(sets) == 0 && len(deletes) == 0 {
+			return nil
+		}
+		err := db.Update(func(txn basedb.Txn) error {
+			for _, obj := range deletes {
+				if err := txn.Delete(prefix, obj.Key); err != nil {
+					return err
+				}
+			}
+			for _, obj := range sets {
+				if err := txn.Set(prefix, obj.Key, obj.Value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		migrated += len(sets)
+		sets, deletes = sets[:0], deletes[:0]
+		return nil
+	}
+
+	err := db.GetAll(logger, prefix, func(i int, obj basedb.Obj) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		identifierLen := len(spectypes.MessageID{})
+		if len(obj.Key) < identifierLen {
+			return nil
+		}
+		// other roles might have this role as prefix (e.g. SYNC_COMMITTEE)
+		msgID := spectypes.MessageIDFromBytes(obj.Key[:identifierLen])
+		if msgID.GetRoleType() != role {
+			return nil
+		}
+		suffix := obj.Key[identifierLen:]
+
+		switch {
+		case bytes.Equal(suffix, []byte(decidedPrunedHeightKey)):
+			// the pruned height is encoded as little-endian as well, the pruner would scan from the first height
+			deletes = append(deletes, basedb.Obj{Key: obj.Key})
+		case len(suffix) == len(decidedInstanceKey)+8 && bytes.HasPrefix(suffix, []byte(decidedInstanceKey)):
+			instance := &qbftstorage.StoredInstance{}
+			if err := instance.Decode(obj.Value); err != nil || instance.State == nil {
+				logger.Warn("could not decode decided instance, skipping", zap.Error(err))
+				return nil
+			}
+			newKey := make([]byte, len(obj.Key))
+			copy(newKey, obj.Key)
+			binary.BigEndian.PutUint64(newKey[len(newKey)-8:], uint64(instance.State.Height))
+			if bytes.Equal(newKey, obj.Key) {
+				return nil
+			}
+			deletes = append(deletes, basedb.Obj{Key: obj.Key})
+			sets = append(sets, basedb.Obj{Key: newKey, Value: obj.Value})
+		default:
+			return nil
+		}
+
+		if len(deletes) >= decidedKeysBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return migrated, err
+	}
+	return migrated, flush()
+}
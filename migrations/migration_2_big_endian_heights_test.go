@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	ibftstorage "github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/logging"
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+func TestMigrationBigEndianHeights(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.TestLogger(t)
+	opt, err := setupOptions(ctx, t)
+	require.NoError(t, err)
+
+	syncCommitteeID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleSyncCommittee)
+	contributionID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleSyncCommitteeContribution)
+	saveLegacy := func(role spectypes.BeaconRole, id spectypes.MessageID, h specqbft.Height) {
+		instance := &qbftstorage.StoredInstance{State: &specqbft.State{ID: id[:], Height: h}}
+		value, err := instance.Encode()
+		require.NoError(t, err)
+		height := make([]byte, 8)
+		binary.LittleEndian.PutUint64(height, uint64(h))
+		key := append(append(id[:], decidedInstanceKey...), height...)
+		require.NoError(t, opt.Db.Set([]byte(role.String()), key, value))
+	}
+	heights := 300
+	for h := 0; h < heights; h++ {
+		saveLegacy(spectypes.BNRoleSyncCommittee, syncCommitteeID, specqbft.Height(h))
+		saveLegacy(spectypes.BNRoleSyncCommitteeContribution, contributionID, specqbft.Height(h))
+	}
+	require.NoError(t, opt.Db.Set([]byte(spectypes.BNRoleSyncCommittee.String()),
+		append(syncCommitteeID[:], decidedPrunedHeightKey...), []byte{1, 0, 0, 0, 0, 0, 0, 0}))
+
+	applied, err := Migrations{migrationBigEndianHeights}.Run(ctx, logger, opt)
+	require.NoError(t, err)
+	require.Equal(t, 1, applied)
+
+	// running the migration function again doesn't change migrated keys
+	require.NoError(t, migrationBigEndianHeights.Run(ctx, logger, opt, []byte(migrationBigEndianHeights.Name)))
+
+	check := func(role spectypes.BeaconRole, id spectypes.MessageID) {
+		store := ibftstorage.New(opt.Db, role.String(), forksprotocol.GenesisForkVersion)
+		instances, err := store.GetInstancesInRange(id[:], 10, 19)
+		require.NoError(t, err)
+		require.Len(t, instances, 10)
+		for i, instance := range instances {
+			require.Equal(t, specqbft.Height(10+i), instance.State.Height)
+		}
+		instances, err = store.GetInstancesInRange(id[:], 0, specqbft.Height(heights))
+		require.NoError(t, err)
+		require.Len(t, instances, heights)
+
+		pruned, err := store.GetPrunedHeight(id[:])
+		require.NoError(t, err)
+		require.Equal(t, specqbft.Height(0), pruned)
+	}
+	check(spectypes.BNRoleSyncCommittee, syncCommitteeID)
+	check(spectypes.BNRoleSyncCommitteeContribution, contributionID)
+}
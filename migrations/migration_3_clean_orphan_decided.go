@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"context"
+	"encoding/hex"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+// migrationCleanOrphanDecided removes the decided instances of validators that don't exist in the registry.
+// Earlier versions removed only the attester instances of removed validators,
+// so the instances of other roles were left in the db.
+var migrationCleanOrphanDecided = Migration{
+	Name: "migration_3_clean_orphan_decided",
+	Run: func(ctx context.Context, logger *zap.Logger, opt Options, key []byte) error {
+		shares, err := opt.nodeStorage().GetAllShares(logger)
+		if err != nil {
+			return errors.Wrap(err, "could not get validator shares")
+		}
+		// without shares (e.g. the registry wasn't synced yet) there is no way to tell which instances are orphans
+		if len(shares) > 0 {
+			validators := make(map[string]struct{}, len(shares))
+			for _, share := range shares {
+				validators[hex.EncodeToString(share.ValidatorPubKey)] = struct{}{}
+			}
+			for _, role := range decidedRoles {
+				n, err := cleanOrphanDecided(ctx, logger, opt.Db, role, validators)
+				if err != nil {
+					return errors.Wrapf(err, "could not clean %s orphan decided", role.String())
+				}
+				logger.Debug("removed orphan decided", zap.String("role", role.String()), zap.Int("count", n))
+			}
+		}
+		return opt.Db.Set(migrationsPrefix, key, migrationCompleted)
+	},
+}
+
+func cleanOrphanDecided(ctx context.Context, logger *zap.Logger, db basedb.IDb, role spectypes.BeaconRole, validators map[string]struct{}) (int, error) {
+	// exact capacity, as the prefix is reused for the deletes of each flush (see basedb.Txn)
+	prefix := make([]byte, 0, len(role.String()))
+	prefix = append(prefix, role.String()...)
+
+	var keys [][]byte
+	removed := 0
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		err := db.Update(func(txn basedb.Txn) error {
+			for _, k := range keys {
+				if err := txn.Delete(prefix, k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		removed += len(keys)
+		keys = keys[:0]
+		return nil
+	}
+
+	err := db.GetAll(logger, prefix, func(i int, obj basedb.Obj) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		identifierLen := len(spectypes.MessageID{})
+		if len(obj.Key) < identifierLen {
+			return nil
+		}
+		// other roles might have this role as prefix (e.g. SYNC_COMMITTEE)
+		msgID := spectypes.MessageIDFromBytes(obj.Key[:identifierLen])
+		if msgID.GetRoleType() != role {
+			return nil
+		}
+		if _, ok := validators[hex.EncodeToString(msgID.GetPubKey())]; ok {
+			return nil
+		}
+		keys = append(keys, obj.Key)
+		if len(keys) >= decidedKeysBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+	return removed, flush()
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	ibftstorage "github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/logging"
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+func TestMigrationCleanOrphanDecided(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.TestLogger(t)
+	opt, err := setupOptions(ctx, t)
+	require.NoError(t, err)
+
+	existing := bytes.Repeat([]byte{1}, 48)
+	removed := bytes.Repeat([]byte{2}, 48)
+	require.NoError(t, opt.nodeStorage().SaveShare(logger, &types.SSVShare{
+		Share: spectypes.Share{ValidatorPubKey: existing},
+	}))
+
+	stores := ibftstorage.NewStoresFromRoles(opt.Db, decidedRoles...)
+	for _, role := range decidedRoles {
+		for _, pk := range [][]byte{existing, removed} {
+			msgID := spectypes.NewMsgID(types.GetDefaultDomain(), pk, role)
+			for h := specqbft.Height(0); h < 3; h++ {
+				instance := &qbftstorage.StoredInstance{State: &specqbft.State{ID: msgID[:], Height: h}}
+				require.NoError(t, stores.Get(role).SaveInstance(instance))
+				require.NoError(t, stores.Get(role).SaveHighestInstance(instance))
+			}
+		}
+	}
+
+	applied, err := Migrations{migrationCleanOrphanDecided}.Run(ctx, logger, opt)
+	require.NoError(t, err)
+	require.Equal(t, 1, applied)
+
+	for _, role := range decidedRoles {
+		msgID := spectypes.NewMsgID(types.GetDefaultDomain(), existing, role)
+		instances, err := stores.Get(role).GetInstancesInRange(msgID[:], 0, 3)
+		require.NoError(t, err)
+		require.Len(t, instances, 3)
+
+		msgID = spectypes.NewMsgID(types.GetDefaultDomain(), removed, role)
+		instances, err = stores.Get(role).GetInstancesInRange(msgID[:], 0, 3)
+		require.NoError(t, err)
+		require.Len(t, instances, 0)
+		highest, err := stores.Get(role).GetHighestInstance(msgID[:])
+		require.NoError(t, err)
+		require.Nil(t, highest)
+	}
+}
@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"context"
+	"crypto/rsa"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+)
+
+// migrationEncryptOperatorKey moves a plaintext operator private key out of the db into the configured keystore.
+// while no keystore is configured, the key is kept in the db and the migration is deferred to the next start.
+var migrationEncryptOperatorKey = Migration{
+	Name: "migration_4_encrypt_operator_key",
+	Run: func(ctx context.Context, logger *zap.Logger, opt Options, key []byte) error {
+		nodeStorage := opt.nodeStorage()
+		sk, found, err := nodeStorage.GetPrivateKey()
+		if err != nil {
+			return errors.Wrap(err, "could not get operator private key")
+		}
+		if found {
+			if len(opt.OperatorKeystoreFile) == 0 || len(opt.OperatorPasswordFile) == 0 {
+				logger.Warn("operator private key is stored in plaintext, configure a keystore to encrypt it")
+				return nil
+			}
+			if err := moveToKeystore(logger, sk, opt.OperatorKeystoreFile, opt.OperatorPasswordFile); err != nil {
+				return err
+			}
+			if err := nodeStorage.DeletePrivateKey(); err != nil {
+				return errors.Wrap(err, "could not delete plaintext operator private key")
+			}
+		}
+		return opt.Db.Set(migrationsPrefix, key, migrationCompleted)
+	},
+}
+
+// moveToKeystore writes the given key to the keystore file, or makes sure that an existing keystore holds the same key
+func moveToKeystore(logger *zap.Logger, sk *rsa.PrivateKey, keystoreFile, passwordFile string) error {
+	if _, err := os.Stat(keystoreFile); err == nil {
+		existing, err := rsaencryption.LoadKeystore(keystoreFile, passwordFile)
+		if err != nil {
+			return errors.Wrap(err, "could not load existing keystore")
+		}
+		if !existing.Equal(sk) {
+			return errors.New("existing keystore doesn't match the operator private key in the db")
+		}
+		logger.Info("operator private key already exists in keystore", zap.String("file", keystoreFile))
+		return nil
+	} else if !os.IsNotExist(err) {
+		return errors.Wrap(err, "could not check keystore file")
+	}
+
+	password, err := rsaencryption.ReadPasswordFile(passwordFile)
+	if err != nil {
+		return err
+	}
+	ks, err := rsaencryption.EncryptKeystore(rsaencryption.PrivateKeyToByte(sk), password)
+	if err != nil {
+		return err
+	}
+	if err := rsaencryption.WriteKeystore(keystoreFile, ks); err != nil {
+		return errors.Wrap(err, "could not write keystore")
+	}
+	logger.Info("moved operator private key to keystore", zap.String("file", keystoreFile))
+	return nil
+}
@@ -0,0 +1,84 @@
+package migrations
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/utils/rsaencryption"
+	testingspace "github.com/bloxapp/ssv/utils/rsaencryption/testingspace"
+)
+
+func TestMigrationEncryptOperatorKey(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.TestLogger(t)
+	opt, err := setupOptions(ctx, t)
+	require.NoError(t, err)
+
+	_, err = opt.nodeStorage().SetupPrivateKey(logger, base64.StdEncoding.EncodeToString([]byte(testingspace.SkPem)), false)
+	require.NoError(t, err)
+	sk, err := rsaencryption.ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+
+	migrations := Migrations{migrationEncryptOperatorKey}
+
+	// without a keystore the key is kept in the db and the migration is deferred
+	applied, err := migrations.Run(ctx, logger, opt)
+	require.NoError(t, err)
+	require.Equal(t, 0, applied)
+	_, found, err := opt.nodeStorage().GetPrivateKey()
+	require.NoError(t, err)
+	require.True(t, found)
+
+	dir := t.TempDir()
+	opt.OperatorKeystoreFile = filepath.Join(dir, "encrypted_private_key.json")
+	opt.OperatorPasswordFile = filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(opt.OperatorPasswordFile, []byte("password"), 0600))
+
+	applied, err = migrations.Run(ctx, logger, opt)
+	require.NoError(t, err)
+	require.Equal(t, 1, applied)
+
+	_, found, err = opt.nodeStorage().GetPrivateKey()
+	require.NoError(t, err)
+	require.False(t, found)
+	decrypted, err := rsaencryption.LoadKeystore(opt.OperatorKeystoreFile, opt.OperatorPasswordFile)
+	require.NoError(t, err)
+	require.True(t, sk.Equal(decrypted))
+
+	applied, err = migrations.Run(ctx, logger, opt)
+	require.NoError(t, err)
+	require.Equal(t, 0, applied)
+}
+
+func TestMigrationEncryptOperatorKey_KeystoreMismatch(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.TestLogger(t)
+	opt, err := setupOptions(ctx, t)
+	require.NoError(t, err)
+
+	_, err = opt.nodeStorage().SetupPrivateKey(logger, base64.StdEncoding.EncodeToString([]byte(testingspace.SkPem)), false)
+	require.NoError(t, err)
+
+	// an existing keystore of another key must not be overridden
+	_, otherSk, err := rsaencryption.GenerateKeys()
+	require.NoError(t, err)
+	ks, err := rsaencryption.EncryptKeystore(otherSk, "password")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	opt.OperatorKeystoreFile = filepath.Join(dir, "encrypted_private_key.json")
+	opt.OperatorPasswordFile = filepath.Join(dir, "password")
+	require.NoError(t, rsaencryption.WriteKeystore(opt.OperatorKeystoreFile, ks))
+	require.NoError(t, os.WriteFile(opt.OperatorPasswordFile, []byte("password"), 0600))
+
+	_, err = Migrations{migrationEncryptOperatorKey}.Run(ctx, logger, opt)
+	require.ErrorContains(t, err, "doesn't match")
+	_, found, err := opt.nodeStorage().GetPrivateKey()
+	require.NoError(t, err)
+	require.True(t, found)
+}
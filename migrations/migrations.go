@@ -24,6 +24,9 @@ var (
 	defaultMigrations = Migrations{
 		migrationExample1,
 		migrationExample2,
+		migrationBigEndianHeights,
+		migrationCleanOrphanDecided,
+		migrationEncryptOperatorKey,
 	}
 )
 
@@ -50,6 +53,9 @@ type Options struct {
 	Db      basedb.IDb
 	DbPath  string
 	Network beacon.Network
+	// OperatorKeystoreFile and OperatorPasswordFile are the keystore of the operator private key
+	OperatorKeystoreFile string
+	OperatorPasswordFile string
 }
 
 // nolint
@@ -88,6 +94,16 @@ func (m Migrations) Run(ctx context.Context, logger *zap.Logger, opt Options) (a
 		if err != nil {
 			return applied, errors.Wrapf(err, "migration %q failed", migration.Name)
 		}
+		// A migration that can't be completed yet returns without marking itself as completed,
+		// so it runs again on the next start.
+		obj, _, err = opt.Db.Get(migrationsPrefix, []byte(migration.Name))
+		if err != nil {
+			return applied, err
+		}
+		if !bytes.Equal(obj.Value, migrationCompleted) {
+			logger.Info("migration deferred", fields.Name(migration.Name))
+			continue
+		}
 		applied++
 
 		logger.Info("migration applied successfully", fields.Name(migration.Name), fields.Duration(start))
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/network/peers"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -83,6 +84,7 @@ func (mh *metricsHandler) Start(logger *zap.Logger, mux *http.ServeMux, addr str
 	))
 	mux.HandleFunc("/database/count-by-collection", mh.handleCountByCollection)
 	mux.HandleFunc("/health", mh.handleHealth)
+	mux.HandleFunc("/p2p/peers/reputation", mh.handlePeersReputation(logger))
 
 	go func() {
 		// TODO: enable lint (G114: Use of net/http serve function that has no support for setting timeouts (gosec))
@@ -132,6 +134,43 @@ func (mh *metricsHandler) handleCountByCollection(w http.ResponseWriter, r *http
 	}
 }
 
+// handlePeersReputation responds with the persisted reputation of peers (read-only).
+// Peer can be specified in query to get the reputation of a specific peer.
+func (mh *metricsHandler) handlePeersReputation(logger *zap.Logger) http.HandlerFunc {
+	// ttl is not relevant as the store is used only for reading
+	store := peers.NewReputationStore(mh.db, 0)
+	return func(w http.ResponseWriter, r *http.Request) {
+		var response struct {
+			Peers []*peers.PeerReputation `json:"peers"`
+		}
+
+		if pid := r.URL.Query().Get("peer"); pid != "" {
+			rep, found, err := store.Get(pid)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !found {
+				http.Error(w, "peer reputation not found", http.StatusNotFound)
+				return
+			}
+			response.Peers = []*peers.PeerReputation{rep}
+		} else {
+			reps, err := store.GetAll(logger)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			response.Peers = reps
+		}
+
+		if err := json.NewEncoder(w).Encode(&response); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 func (mh *metricsHandler) handleHealth(res http.ResponseWriter, req *http.Request) {
 	if errs := mh.healthChecker.HealthCheck(); len(errs) > 0 {
 		metricsNodeStatus.Set(float64(statusNotHealthy))
@@ -1,6 +1,7 @@
 package discovery
 
 import (
+	forksfactory "github.com/bloxapp/ssv/network/forks/factory"
 	"github.com/bloxapp/ssv/network/records"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
@@ -34,15 +35,28 @@ func (dvs *DiscV5Service) badNodeFilter(logger *zap.Logger) func(node *enode.Nod
 	}
 }
 
+// subnetsVersionFilter checks if the node maps validators to subnets in the same way as the current fork,
+// otherwise the subnets in its record are not comparable with ours
+func (dvs *DiscV5Service) subnetsVersionFilter(node *enode.Node) bool {
+	forkv, err := records.GetForkVersionEntry(node.Record())
+	if err != nil {
+		return false
+	}
+	return forksfactory.NewFork(forkv).SubnetsVersion() == dvs.fork.SubnetsVersion()
+}
+
 // subnetFilter checks if the node has an interest in the given subnet
 func (dvs *DiscV5Service) subnetFilter(subnets ...uint64) func(node *enode.Node) bool {
 	return func(node *enode.Node) bool {
+		if !dvs.subnetsVersionFilter(node) {
+			return false
+		}
 		fromEntry, err := records.GetSubnetsEntry(node.Record())
 		if err != nil {
 			return false
 		}
 		for _, subnet := range subnets {
-			if fromEntry[subnet] > 0 {
+			if subnet < uint64(len(fromEntry)) && fromEntry[subnet] > 0 {
 				return true
 			}
 		}
@@ -56,14 +70,18 @@ func (dvs *DiscV5Service) sharedSubnetsFilter(n int) func(node *enode.Node) bool
 		if n == 0 {
 			return true
 		}
-		if len(dvs.subnets) == 0 {
+		subnets := dvs.getSubnets()
+		if len(subnets) == 0 {
 			return true
 		}
+		if !dvs.subnetsVersionFilter(node) {
+			return false
+		}
 		nodeSubnets, err := records.GetSubnetsEntry(node.Record())
 		if err != nil {
 			return false
 		}
-		shared := records.SharedSubnets(dvs.subnets, nodeSubnets, n)
+		shared := records.SharedSubnets(subnets, nodeSubnets, n)
 		// logger.Debug("shared subnets", zap.Ints("shared", shared),
 		//	zap.String("node", node.String()))
 
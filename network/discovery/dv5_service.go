@@ -1,9 +1,10 @@
 package discovery
 
 import (
-	"bytes"
 	"context"
 	"net"
+	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -26,6 +27,10 @@ import (
 var (
 	defaultDiscoveryInterval = time.Second
 	publishENRTimeout        = time.Minute
+	// weakSubnetsSearchInterval is the interval in which weak subnets are detected and searched for
+	weakSubnetsSearchInterval = 30 * time.Second
+	// weakSubnetsSearchTimeout is the duration of a single search for peers of weak subnets
+	weakSubnetsSearchTimeout = 10 * time.Second
 
 	publishStateReady   = int32(0)
 	publishStatePending = int32(1)
@@ -57,9 +62,15 @@ type DiscV5Service struct {
 	publishState int32
 	conn         *net.UDPConn
 
-	fork    forks.Fork
-	forkv   forksprotocol.ForkVersion
-	subnets []byte
+	fork  forks.Fork
+	forkv forksprotocol.ForkVersion
+
+	// subnets are the subnets of this node, updated upon (de)registration
+	subnets     []byte
+	subnetsLock sync.RWMutex
+
+	// weakSubnetsSearch makes sure that weak subnets search is started once, even if Bootstrap is retried
+	weakSubnetsSearch sync.Once
 }
 
 func newDiscV5Service(pctx context.Context, logger *zap.Logger, discOpts *Options) (Service, error) {
@@ -145,7 +156,9 @@ func (dvs *DiscV5Service) Node(logger *zap.Logger, info peer.AddrInfo) (*enode.N
 // if we reached peers limit, make sure to accept peers with more than 1 shared subnet,
 // which lets other components to determine whether we'll want to connect to this node or not.
 func (dvs *DiscV5Service) Bootstrap(logger *zap.Logger, handler HandleNewPeer) error {
-	zeroSubnets, _ := records.Subnets{}.FromString(records.ZeroSubnets)
+	dvs.weakSubnetsSearch.Do(func() {
+		go dvs.searchWeakSubnets(logger, handler)
+	})
 
 	dvs.discover(dvs.ctx, func(e PeerEvent) {
 		nodeSubnets, err := records.GetSubnetsEntry(e.Node.Record())
@@ -153,15 +166,18 @@ func (dvs *DiscV5Service) Bootstrap(logger *zap.Logger, handler HandleNewPeer) e
 			logger.Debug("could not read subnets", fields.ENR(e.Node))
 			return
 		}
-		if bytes.Equal(zeroSubnets, nodeSubnets) {
+		if len(nodeSubnets) > 0 && records.Subnets(nodeSubnets).Active() == 0 {
 			logger.Debug("skipping zero subnets", fields.ENR(e.Node))
 			return
 		}
-		updated := dvs.subnetsIdx.UpdatePeerSubnets(e.AddrInfo.ID, nodeSubnets)
-		if updated {
-			logger.Debug("[discv5] peer subnets were updated", fields.ENR(e.Node),
-				fields.PeerID(e.AddrInfo.ID),
-				fields.Subnets(records.Subnets(nodeSubnets)))
+		// subnets of nodes with a different subnets mapping are not indexed, as they are not comparable with ours
+		if dvs.subnetsVersionFilter(e.Node) {
+			updated := dvs.subnetsIdx.UpdatePeerSubnets(e.AddrInfo.ID, nodeSubnets)
+			if updated {
+				logger.Debug("[discv5] peer subnets were updated", fields.ENR(e.Node),
+					fields.PeerID(e.AddrInfo.ID),
+					fields.Subnets(records.Subnets(nodeSubnets)))
+			}
 		}
 		if !dvs.limitNodeFilter(e.Node) {
 			if !dvs.sharedSubnetsFilter(1)(e.Node) {
@@ -176,6 +192,47 @@ func (dvs *DiscV5Service) Bootstrap(logger *zap.Logger, handler HandleNewPeer) e
 	return nil
 }
 
+// searchWeakSubnets periodically detects subnets with fewer than peers.MinSubnetPeers connected peers,
+// and runs lookups that are filtered to nodes which are interested in those subnets.
+// found nodes are passed to the handler regardless of the peers limit.
+func (dvs *DiscV5Service) searchWeakSubnets(logger *zap.Logger, handler HandleNewPeer) {
+	ticker := time.NewTicker(weakSubnetsSearchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-dvs.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		weak := peers.WeakSubnets(dvs.subnetsIdx.GetSubnetsStats(), peers.MinSubnetPeers, dvs.getSubnets())
+		metricWeakSubnets.Set(float64(len(weak)))
+		if len(weak) == 0 {
+			continue
+		}
+		logger.Debug("searching for peers of weak subnets", zap.Ints("subnets", weak))
+		subnets := make([]uint64, len(weak))
+		for i, subnet := range weak {
+			subnets[i] = uint64(subnet)
+		}
+		ctx, cancel := context.WithTimeout(dvs.ctx, weakSubnetsSearchTimeout)
+		dvs.discover(ctx, func(e PeerEvent) {
+			nodeSubnets, err := records.GetSubnetsEntry(e.Node.Record())
+			if err != nil {
+				return
+			}
+			for _, subnet := range weak {
+				if subnet < len(nodeSubnets) && nodeSubnets[subnet] > 0 {
+					metricWeakSubnetsFoundNodes.WithLabelValues(strconv.Itoa(subnet)).Inc()
+				}
+			}
+			dvs.subnetsIdx.UpdatePeerSubnets(e.AddrInfo.ID, nodeSubnets)
+			handler(e)
+		}, time.Millisecond*10, dvs.badNodeFilter(logger), dvs.subnetFilter(subnets...))
+		cancel()
+	}
+}
+
 // initDiscV5Listener creates a new listener and starts it
 func (dvs *DiscV5Service) initDiscV5Listener(logger *zap.Logger, discOpts *Options) error {
 	opts := discOpts.DiscV5Opts
@@ -225,6 +282,11 @@ func (dvs *DiscV5Service) discover(ctx context.Context, handler HandleNewPeer, i
 	for _, f := range filters {
 		iterator = enode.Filter(iterator, f)
 	}
+	// closing the iterator once the context is done, so a pending lookup won't block
+	go func() {
+		<-ctx.Done()
+		iterator.Close()
+	}()
 	// selfID is used to exclude current node
 	selfID := dvs.dv5Listener.LocalNode().Node().ID().TerminalString()
 
@@ -268,7 +330,7 @@ func (dvs *DiscV5Service) RegisterSubnets(logger *zap.Logger, subnets ...int) er
 		return errors.Wrap(err, "could not update ENR")
 	}
 	if updated != nil {
-		dvs.subnets = updated
+		dvs.setSubnets(updated)
 		logger.Debug("updated subnets", fields.UpdatedENRLocalNode(dvs.dv5Listener.LocalNode()))
 		go dvs.publishENR(logger)
 	}
@@ -287,13 +349,27 @@ func (dvs *DiscV5Service) DeregisterSubnets(logger *zap.Logger, subnets ...int)
 		return errors.Wrap(err, "could not update ENR")
 	}
 	if updated != nil {
-		dvs.subnets = updated
+		dvs.setSubnets(updated)
 		logger.Debug("updated subnets", fields.UpdatedENRLocalNode(dvs.dv5Listener.LocalNode()))
 		go dvs.publishENR(logger)
 	}
 	return nil
 }
 
+func (dvs *DiscV5Service) getSubnets() []byte {
+	dvs.subnetsLock.RLock()
+	defer dvs.subnetsLock.RUnlock()
+
+	return dvs.subnets
+}
+
+func (dvs *DiscV5Service) setSubnets(subnets []byte) {
+	dvs.subnetsLock.Lock()
+	defer dvs.subnetsLock.Unlock()
+
+	dvs.subnets = subnets
+}
+
 // publishENR publishes the new ENR across the network
 func (dvs *DiscV5Service) publishENR(logger *zap.Logger) {
 	ctx, done := context.WithTimeout(dvs.ctx, publishENRTimeout)
@@ -322,7 +398,7 @@ func (dvs *DiscV5Service) publishENR(logger *zap.Logger) {
 
 func (dvs *DiscV5Service) createLocalNode(logger *zap.Logger, discOpts *Options, ipAddr net.IP) (*enode.LocalNode, error) {
 	opts := discOpts.DiscV5Opts
-	localNode, err := createLocalNode(opts.NetworkKey, opts.StoragePath, ipAddr, opts.Port, opts.TCPPort)
+	localNode, err := createLocalNode(opts.NetworkKey, opts.StoragePath, ipAddr, opts.Port, opts.TCPPort, opts.QUICPort)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create local node")
 	}
@@ -7,6 +7,7 @@ import (
 	"net"
 
 	"github.com/bloxapp/ssv/network/commons"
+	"github.com/bloxapp/ssv/network/records"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -14,8 +15,9 @@ import (
 	"github.com/pkg/errors"
 )
 
-// createLocalNode create a new enode.LocalNode instance
-func createLocalNode(privKey *ecdsa.PrivateKey, storagePath string, ipAddr net.IP, udpPort, tcpPort int) (*enode.LocalNode, error) {
+// createLocalNode create a new enode.LocalNode instance,
+// the quic entry is set only if a QUIC port was provided
+func createLocalNode(privKey *ecdsa.PrivateKey, storagePath string, ipAddr net.IP, udpPort, tcpPort, quicPort int) (*enode.LocalNode, error) {
 	db, err := enode.OpenDB(storagePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not open node's peer database")
@@ -25,6 +27,11 @@ func createLocalNode(privKey *ecdsa.PrivateKey, storagePath string, ipAddr net.I
 	localNode.Set(enr.IP(ipAddr))
 	localNode.Set(enr.UDP(udpPort))
 	localNode.Set(enr.TCP(tcpPort))
+	if quicPort > 0 {
+		if err := records.SetQUICEntry(localNode, quicPort); err != nil {
+			return nil, errors.Wrap(err, "could not set quic entry")
+		}
+	}
 	localNode.SetFallbackIP(ipAddr)
 	localNode.SetFallbackUDP(udpPort)
 
@@ -54,7 +61,10 @@ func addAddresses(localNode *enode.LocalNode, hostAddr, hostDNS string) error {
 	return nil
 }
 
-// ToPeer creates peer info from the given node
+// ToPeer creates peer info from the given node.
+// nodes that support QUIC are exposed with both QUIC and TCP addresses,
+// libp2p prefers dialing QUIC (UDP) addresses and falls back to TCP.
+// nodes with an invalid QUIC entry are exposed only with their TCP address.
 func ToPeer(node *enode.Node) (*peer.AddrInfo, error) {
 	m, err := ToMultiAddr(node)
 	if err != nil {
@@ -64,6 +74,12 @@ func ToPeer(node *enode.Node) (*peer.AddrInfo, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create peer info")
 	}
+	// a malformed quic entry doesn't make the peer unreachable, it is ignored and the peer is dialed over TCP
+	quicAddr, err := ToQUICMultiAddr(node)
+	if err == nil && quicAddr != nil {
+		transportAddr, _ := peer.SplitAddr(quicAddr)
+		pi.Addrs = append([]ma.Multiaddr{transportAddr}, pi.Addrs...)
+	}
 	return pi, nil
 }
 
@@ -76,8 +92,24 @@ func PeerID(node *enode.Node) (peer.ID, error) {
 	return peer.IDFromPublicKey(pk)
 }
 
-// ToMultiAddr returns the node's multiaddr.
+// ToMultiAddr returns the node's TCP multiaddr.
 func ToMultiAddr(node *enode.Node) (ma.Multiaddr, error) {
+	return toMultiAddr(node, "tcp", uint(node.TCP()), "")
+}
+
+// ToQUICMultiAddr returns the node's QUIC multiaddr, or nil if the node doesn't support QUIC.
+func ToQUICMultiAddr(node *enode.Node) (ma.Multiaddr, error) {
+	port, err := records.GetQUICEntry(node.Record())
+	if err != nil {
+		return nil, err
+	}
+	if port == 0 {
+		return nil, nil
+	}
+	return toMultiAddr(node, "udp", uint(port), "/quic-v1")
+}
+
+func toMultiAddr(node *enode.Node, transport string, port uint, suffix string) (ma.Multiaddr, error) {
 	id, err := PeerID(node)
 	if err != nil {
 		return nil, err
@@ -90,12 +122,11 @@ func ToMultiAddr(node *enode.Node) (ma.Multiaddr, error) {
 	if ip.To4() == nil && ip.To16() == nil {
 		return nil, errors.Errorf("invalid ip address: %s", ipAddr)
 	}
-	port := uint(node.TCP())
 	var s string
 	if ip.To4() != nil {
-		s = fmt.Sprintf("/ip4/%s/%s/%d/p2p/%s", ipAddr, "tcp", port, id.String())
+		s = fmt.Sprintf("/ip4/%s/%s/%d%s/p2p/%s", ipAddr, transport, port, suffix, id.String())
 	} else {
-		s = fmt.Sprintf("/ip6/%s/%s/%d/p2p/%s", ipAddr, "tcp", port, id.String())
+		s = fmt.Sprintf("/ip6/%s/%s/%d%s/p2p/%s", ipAddr, transport, port, suffix, id.String())
 	}
 	return ma.NewMultiaddr(s)
 }
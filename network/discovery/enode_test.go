@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/bloxapp/ssv/network/commons"
+	"github.com/bloxapp/ssv/network/records"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/stretchr/testify/require"
 )
@@ -29,6 +31,34 @@ func Test_ToPeer(t *testing.T) {
 	require.Equal(t, 1, len(ai.Addrs))
 }
 
+func Test_ToPeerQUIC(t *testing.T) {
+	node := localNodeMock(t)
+	require.NoError(t, records.SetQUICEntry(node, 14000))
+
+	quicAddr, err := ToQUICMultiAddr(node.Node())
+	require.NoError(t, err)
+	require.True(t, strings.Contains(quicAddr.String(), "/udp/14000/quic-v1/p2p/"))
+
+	ai, err := ToPeer(node.Node())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(ai.Addrs))
+	require.True(t, strings.HasSuffix(ai.Addrs[0].String(), "/udp/14000/quic-v1"))
+	require.True(t, strings.HasSuffix(ai.Addrs[1].String(), "/tcp/13000"))
+}
+
+func Test_ToPeerMalformedQUIC(t *testing.T) {
+	node := localNodeMock(t)
+	node.Set(enr.WithEntry("quic", "not-a-port"))
+
+	_, err := ToQUICMultiAddr(node.Node())
+	require.Error(t, err)
+
+	ai, err := ToPeer(node.Node())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(ai.Addrs))
+	require.True(t, strings.HasSuffix(ai.Addrs[0].String(), "/tcp/13000"))
+}
+
 func Test_ParseENR(t *testing.T) {
 	nodes, err := ParseENR(nil, true,
 		"enr:-Km4QH9oua5xsG_0IN3oxiv5PBb10QXMkMvDeg2IrSSDlRxtONu9hShTmAZm2LjjADQOxGzBxd8VzXYFukmJULzcwrkBh2"+
@@ -47,7 +77,7 @@ func localNodeMock(t *testing.T) *enode.LocalNode {
 	require.NoError(t, err)
 	ip, err := commons.IPAddr()
 	require.NoError(t, err)
-	node, err := createLocalNode(pk, "", ip, 12000, 13000)
+	node, err := createLocalNode(pk, "", ip, 12000, 13000, 0)
 	require.NoError(t, err)
 	return node
 }
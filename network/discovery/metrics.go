@@ -24,6 +24,14 @@ var (
 		Name: "ssv:network:discovery:enr_pong",
 		Help: "Counts the number of pong responses we got as part of ENR publishing",
 	})
+	metricWeakSubnets = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssv:network:discovery:weak_subnets",
+		Help: "Counts our subnets that have fewer connected peers than the desired minimum",
+	})
+	metricWeakSubnetsFoundNodes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:discovery:weak_subnets:found",
+		Help: "Counts nodes that were found by searching for peers of weak subnets",
+	}, []string{"subnet"})
 )
 
 func init() {
@@ -39,4 +47,10 @@ func init() {
 	if err := prometheus.Register(metricPublishEnrPongs); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricWeakSubnets); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+	if err := prometheus.Register(metricWeakSubnetsFoundNodes); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 }
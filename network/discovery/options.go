@@ -26,6 +26,8 @@ type DiscV5Options struct {
 	Port int
 	// TCPPort is the TCP port exposed in the ENR
 	TCPPort int
+	// QUICPort is the UDP port of the QUIC transport exposed in the ENR (optional)
+	QUICPort int
 	// NetworkKey is the private key used to create the peer.ID if the node
 	NetworkKey *ecdsa.PrivateKey
 	// Bootnodes is a list of bootstrapper nodes
@@ -58,6 +60,9 @@ func (opts *DiscV5Options) Validate() error {
 	if opts.Port == 0 {
 		return errors.New("missing udp port")
 	}
+	if opts.QUICPort != 0 && opts.QUICPort == opts.Port {
+		return errors.New("quic port must be different than the discovery udp port")
+	}
 	return nil
 }
 
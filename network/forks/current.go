@@ -0,0 +1,101 @@
+package forks
+
+import (
+	"sync/atomic"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+	p2pprotocol "github.com/bloxapp/ssv/protocol/v2/p2p"
+)
+
+// Current is a Fork that delegates to the fork that is currently active,
+// it enables components to keep a single reference while the fork is replaced upon a fork event.
+type Current struct {
+	fork atomic.Value
+}
+
+// forkHolder wraps the fork as atomic.Value requires a consistent concrete type
+type forkHolder struct {
+	Fork
+}
+
+// NewCurrent creates a new instance of Current with the given fork
+func NewCurrent(fork Fork) *Current {
+	c := &Current{}
+	c.Set(fork)
+	return c
+}
+
+// Set replaces the active fork
+func (c *Current) Set(fork Fork) {
+	c.fork.Store(forkHolder{fork})
+}
+
+// Get returns the active fork
+func (c *Current) Get() Fork {
+	return c.fork.Load().(forkHolder).Fork
+}
+
+// EncodeNetworkMsg encodes the given message
+func (c *Current) EncodeNetworkMsg(msg *spectypes.SSVMessage) ([]byte, error) {
+	return c.Get().EncodeNetworkMsg(msg)
+}
+
+// DecodeNetworkMsg decodes the given message
+func (c *Current) DecodeNetworkMsg(data []byte) (*spectypes.SSVMessage, error) {
+	return c.Get().DecodeNetworkMsg(data)
+}
+
+// ValidatorTopicID maps the given validator public key to the corresponding pubsub topic
+func (c *Current) ValidatorTopicID(pk []byte) []string {
+	return c.Get().ValidatorTopicID(pk)
+}
+
+// GetTopicFullName returns the topic full name, including prefix
+func (c *Current) GetTopicFullName(baseName string) string {
+	return c.Get().GetTopicFullName(baseName)
+}
+
+// GetTopicBaseName return the base topic name of the topic, w/o ssv prefix
+func (c *Current) GetTopicBaseName(topicName string) string {
+	return c.Get().GetTopicBaseName(topicName)
+}
+
+// ValidatorSubnet returns the subnet for the given validator
+func (c *Current) ValidatorSubnet(validatorPKHex string) int {
+	return c.Get().ValidatorSubnet(validatorPKHex)
+}
+
+// SubnetsVersion returns the version that introduced the validators to subnets mapping of the active fork
+func (c *Current) SubnetsVersion() forksprotocol.ForkVersion {
+	return c.Get().SubnetsVersion()
+}
+
+// MsgID is the msgID function to use for pubsub
+func (c *Current) MsgID() MsgIDFunc {
+	return c.Get().MsgID()
+}
+
+// Subnets returns the subnets count for this fork
+func (c *Current) Subnets() int {
+	return c.Get().Subnets()
+}
+
+// ProtocolID returns the protocol id of given protocol, and the amount of peers for distribution
+func (c *Current) ProtocolID(prot p2pprotocol.SyncProtocol) (protocol.ID, int) {
+	return c.Get().ProtocolID(prot)
+}
+
+// DecorateNode will enrich the local node record with more entries, according to current fork
+func (c *Current) DecorateNode(node *enode.LocalNode, args map[string]interface{}) error {
+	return c.Get().DecorateNode(node, args)
+}
+
+// AddOptions enables to inject libp2p options according to the given fork
+func (c *Current) AddOptions(opts []libp2p.Option) []libp2p.Option {
+	return c.Get().AddOptions(opts)
+}
@@ -0,0 +1,42 @@
+package forks_test
+
+import (
+	"testing"
+
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/network/forks"
+	"github.com/bloxapp/ssv/network/forks/genesis"
+	forksv1 "github.com/bloxapp/ssv/network/forks/v1"
+	p2pprotocol "github.com/bloxapp/ssv/protocol/v2/p2p"
+)
+
+func TestCurrent(t *testing.T) {
+	msg := &spectypes.SSVMessage{
+		MsgType: spectypes.SSVConsensusMsgType,
+		MsgID:   specqbft.ControllerIdToMessageID([]byte("xxxxxxxxxxx_ATTESTER")),
+		Data:    []byte("data"),
+	}
+	current := forks.NewCurrent(&genesis.ForkGenesis{})
+	plain, err := current.EncodeNetworkMsg(msg)
+	require.NoError(t, err)
+	genesisPID, _ := current.ProtocolID(p2pprotocol.LastDecidedProtocol)
+
+	current.Set(&forksv1.ForkV1{})
+	_, ok := current.Get().(*forksv1.ForkV1)
+	require.True(t, ok)
+	compressed, err := current.EncodeNetworkMsg(msg)
+	require.NoError(t, err)
+	require.NotEqual(t, plain, compressed)
+	v1PID, _ := current.ProtocolID(p2pprotocol.LastDecidedProtocol)
+	require.NotEqual(t, genesisPID, v1PID)
+
+	// both compressed and uncompressed messages are decoded after the fork
+	for _, data := range [][]byte{plain, compressed} {
+		res, err := current.DecodeNetworkMsg(data)
+		require.NoError(t, err)
+		require.Equal(t, msg.MsgID, res.MsgID)
+	}
+}
@@ -3,6 +3,8 @@ package factory
 import (
 	"github.com/bloxapp/ssv/network/forks"
 	"github.com/bloxapp/ssv/network/forks/genesis"
+	forksv1 "github.com/bloxapp/ssv/network/forks/v1"
+	forksv2 "github.com/bloxapp/ssv/network/forks/v2"
 	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 )
 
@@ -11,6 +13,10 @@ func NewFork(forkVersion forksprotocol.ForkVersion) forks.Fork {
 	switch forkVersion {
 	case forksprotocol.GenesisForkVersion:
 		return &genesis.ForkGenesis{}
+	case forksprotocol.V1ForkVersion:
+		return &forksv1.ForkV1{}
+	case forksprotocol.V2ForkVersion:
+		return &forksv2.ForkV2{}
 	default:
 		return &genesis.ForkGenesis{}
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p/core/protocol"
 
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 	p2pprotocol "github.com/bloxapp/ssv/protocol/v2/p2p"
 )
 
@@ -33,6 +34,18 @@ type pubSubMapping interface {
 	GetTopicBaseName(topicName string) string
 	// ValidatorSubnet returns the subnet for the given validator
 	ValidatorSubnet(validatorPKHex string) int
+	// SubnetsVersion returns the version that introduced the validators to subnets mapping of this fork,
+	// subnets of nodes are comparable only if they share the same subnets version
+	SubnetsVersion() forksprotocol.ForkVersion
+}
+
+// CommitteeResolver returns the committee (operator IDs) of the given validator, or false if it is unknown
+type CommitteeResolver func(pk []byte) ([]spectypes.OperatorID, bool)
+
+// CommitteeAware is implemented by forks that map validators to subnets according to their committee
+type CommitteeAware interface {
+	// SetCommitteeResolver sets the function that is used to find the committee of validators
+	SetCommitteeResolver(resolver CommitteeResolver)
 }
 
 type pubSubConfig interface {
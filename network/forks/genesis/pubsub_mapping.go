@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 )
 
 const (
 	// UnknownSubnet is used when a validator public key is invalid
 	UnknownSubnet = "unknown"
+	// UnresolvedSubnet is used when the subnet of a validator can't be resolved yet,
+	// e.g. when its committee isn't known as the registry is still syncing
+	UnresolvedSubnet = "unresolved"
 
 	topicPrefix = "ssv.v2"
 )
@@ -51,6 +56,11 @@ func (genesis *ForkGenesis) ValidatorSubnet(validatorPKHex string) int {
 	return int(val % subnetsCount)
 }
 
+// SubnetsVersion returns the version that introduced the validators to subnets mapping of this fork
+func (genesis *ForkGenesis) SubnetsVersion() forksprotocol.ForkVersion {
+	return forksprotocol.GenesisForkVersion
+}
+
 func hexToUint64(hexStr string) uint64 {
 	result, err := strconv.ParseUint(hexStr, 16, 64)
 	if err != nil {
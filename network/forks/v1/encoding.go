@@ -0,0 +1,57 @@
+package v1
+
+import (
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// maxDecodedMsgSize is the maximum size of a decompressed network message,
+// aligned with the beacon chain gossip max size as proposals carry full blocks
+const maxDecodedMsgSize = 10 * 1 << 20
+
+// ErrMsgTooLarge is returned when the decompressed message exceeds the size limit
+var ErrMsgTooLarge = errors.New("network message is too large")
+
+// EncodeNetworkMsg encodes network message and compresses it with snappy
+func (f *ForkV1) EncodeNetworkMsg(msg *spectypes.SSVMessage) ([]byte, error) {
+	data, err := msg.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, data), nil
+}
+
+// DecodeNetworkMsg decompresses and decodes network message.
+// messages that are not compressed are decoded as in genesis,
+// so messages of peers that didn't reach the fork epoch yet are accepted during the transition.
+func (f *ForkV1) DecodeNetworkMsg(data []byte) (*spectypes.SSVMessage, error) {
+	msg, err := decodeCompressed(data)
+	if err == nil {
+		return msg, nil
+	}
+	if errors.Is(err, ErrMsgTooLarge) {
+		return nil, err
+	}
+	return f.ForkGenesis.DecodeNetworkMsg(data)
+}
+
+// decodeCompressed decompresses and decodes the given data, while enforcing the size limit
+func decodeCompressed(data []byte) (*spectypes.SSVMessage, error) {
+	size, err := snappy.DecodedLen(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read decoded length")
+	}
+	if size > maxDecodedMsgSize {
+		return nil, ErrMsgTooLarge
+	}
+	raw, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decompress message")
+	}
+	msg := spectypes.SSVMessage{}
+	if err := msg.Decode(raw); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"bytes"
+	"testing"
+
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/network/forks/genesis"
+)
+
+func TestForkV1_Encoding(t *testing.T) {
+	msg := &spectypes.SSVMessage{
+		MsgType: spectypes.SSVConsensusMsgType,
+		MsgID:   specqbft.ControllerIdToMessageID([]byte("xxxxxxxxxxx_ATTESTER")),
+		Data:    bytes.Repeat([]byte("data"), 1000),
+	}
+	f := &ForkV1{}
+
+	b, err := f.EncodeNetworkMsg(msg)
+	require.NoError(t, err)
+	plain, err := msg.Encode()
+	require.NoError(t, err)
+	require.Less(t, len(b), len(plain))
+
+	res, err := f.DecodeNetworkMsg(b)
+	require.NoError(t, err)
+	require.Equal(t, msg.MsgType, res.MsgType)
+	require.True(t, bytes.Equal(msg.Data, res.Data))
+
+	t.Run("uncompressed message", func(t *testing.T) {
+		b, err := (&genesis.ForkGenesis{}).EncodeNetworkMsg(msg)
+		require.NoError(t, err)
+		res, err := f.DecodeNetworkMsg(b)
+		require.NoError(t, err)
+		require.Equal(t, msg.MsgID, res.MsgID)
+		require.True(t, bytes.Equal(msg.Data, res.Data))
+	})
+
+	t.Run("too large message", func(t *testing.T) {
+		large := &spectypes.SSVMessage{
+			MsgType: spectypes.SSVConsensusMsgType,
+			MsgID:   msg.MsgID,
+			Data:    make([]byte, maxDecodedMsgSize),
+		}
+		raw, err := large.Encode()
+		require.NoError(t, err)
+		_, err = f.DecodeNetworkMsg(snappy.Encode(nil, raw))
+		require.ErrorIs(t, err, ErrMsgTooLarge)
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		_, err := f.DecodeNetworkMsg([]byte{1, 2, 3})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,29 @@
+package v1
+
+import (
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/bloxapp/ssv/network/forks"
+	"github.com/bloxapp/ssv/network/forks/genesis"
+	"github.com/bloxapp/ssv/network/records"
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+)
+
+// ForkV1 is the v1 implementation, it compresses network messages with snappy.
+// topics mapping, msg_id and libp2p options are the same as in genesis.
+type ForkV1 struct {
+	genesis.ForkGenesis
+}
+
+// New returns an instance of ForkV1
+func New() forks.Fork {
+	return &ForkV1{}
+}
+
+// DecorateNode will enrich the local node record with more entries, according to current fork
+func (f *ForkV1) DecorateNode(node *enode.LocalNode, args map[string]interface{}) error {
+	if err := f.ForkGenesis.DecorateNode(node, args); err != nil {
+		return err
+	}
+	return records.SetForkVersionEntry(node, forksprotocol.V1ForkVersion.String())
+}
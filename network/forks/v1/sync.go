@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	p2pprotocol "github.com/bloxapp/ssv/protocol/v2/p2p"
+)
+
+const (
+	lastDecidedProtocol = "/ssv/sync/decided/last/0.0.2"
+	historyProtocol     = "/ssv/sync/decided/history/0.0.2"
+
+	peersForSync = 10
+)
+
+// ProtocolID returns the protocol id of the given protocol,
+// and the amount of peers for distribution.
+// protocols were bumped as stream messages are compressed.
+func (f *ForkV1) ProtocolID(prot p2pprotocol.SyncProtocol) (protocol.ID, int) {
+	switch prot {
+	case p2pprotocol.LastDecidedProtocol:
+		return lastDecidedProtocol, peersForSync
+	case p2pprotocol.DecidedHistoryProtocol:
+		return historyProtocol, peersForSync
+	}
+	return "", 0
+}
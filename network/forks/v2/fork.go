@@ -0,0 +1,50 @@
+package v2
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/bloxapp/ssv/network/forks"
+	forksv1 "github.com/bloxapp/ssv/network/forks/v1"
+	"github.com/bloxapp/ssv/network/records"
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+)
+
+// ForkV2 is the v2 implementation, it maps validators to subnets according to their committee,
+// so all the validators of a cluster share a topic.
+// encoding, msg_id, sync protocols and libp2p options are the same as in v1.
+type ForkV2 struct {
+	forksv1.ForkV1
+
+	committees     forks.CommitteeResolver
+	committeesLock sync.RWMutex
+}
+
+// New returns an instance of ForkV2
+func New() forks.Fork {
+	return &ForkV2{}
+}
+
+// SetCommitteeResolver sets the function that is used to find the committee of validators
+func (f *ForkV2) SetCommitteeResolver(resolver forks.CommitteeResolver) {
+	f.committeesLock.Lock()
+	defer f.committeesLock.Unlock()
+
+	f.committees = resolver
+}
+
+// DecorateNode will enrich the local node record with more entries, according to current fork
+func (f *ForkV2) DecorateNode(node *enode.LocalNode, args map[string]interface{}) error {
+	if err := records.SetForkVersionEntry(node, forksprotocol.V2ForkVersion.String()); err != nil {
+		return err
+	}
+	var subnets []byte
+	raw, ok := args["subnets"]
+	if !ok {
+		subnets = make([]byte, subnetsCount)
+	} else {
+		subnets = raw.([]byte)
+	}
+	return records.SetSubnetsEntry(node, subnets)
+}
@@ -0,0 +1,84 @@
+package v2
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"strconv"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+
+	"github.com/bloxapp/ssv/network/forks/genesis"
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+)
+
+// subnetsCount is the subnet count for v2
+const subnetsCount = 128
+
+// ValidatorTopicID returns the topic to use for the given validator,
+// or genesis.UnresolvedSubnet if its committee is not known
+func (f *ForkV2) ValidatorTopicID(pk []byte) []string {
+	subnet := f.validatorSubnet(pk)
+	if subnet < 0 {
+		return []string{genesis.UnresolvedSubnet}
+	}
+	return []string{strconv.Itoa(subnet)}
+}
+
+// ValidatorSubnet returns the subnet for the given validator, which is the subnet of its committee
+func (f *ForkV2) ValidatorSubnet(validatorPKHex string) int {
+	pk, err := hex.DecodeString(validatorPKHex)
+	if err != nil {
+		return -1
+	}
+	return f.validatorSubnet(pk)
+}
+
+// Subnets returns the subnets count for this fork
+func (f *ForkV2) Subnets() int {
+	return subnetsCount
+}
+
+// SubnetsVersion returns the version that introduced the validators to subnets mapping of this fork
+func (f *ForkV2) SubnetsVersion() forksprotocol.ForkVersion {
+	return forksprotocol.V2ForkVersion
+}
+
+func (f *ForkV2) validatorSubnet(pk []byte) int {
+	f.committeesLock.RLock()
+	resolver := f.committees
+	f.committeesLock.RUnlock()
+
+	if resolver == nil {
+		return -1
+	}
+	committee, ok := resolver(pk)
+	if !ok || len(committee) == 0 {
+		return -1
+	}
+	return CommitteeSubnet(committee)
+}
+
+// CommitteeSubnet returns the subnet of the given committee,
+// which is the hash of the sorted operator IDs modulo the subnets count.
+func CommitteeSubnet(committee []spectypes.OperatorID) int {
+	id := CommitteeID(committee)
+	subnet := new(big.Int).Mod(new(big.Int).SetBytes(id[:]), big.NewInt(subnetsCount))
+	return int(subnet.Int64())
+}
+
+// CommitteeID returns a unique identifier of the given committee, regardless of the operators order
+func CommitteeID(committee []spectypes.OperatorID) [32]byte {
+	sorted := make([]spectypes.OperatorID, len(committee))
+	copy(sorted, committee)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	data := make([]byte, 0, len(sorted)*8)
+	for _, id := range sorted {
+		data = binary.LittleEndian.AppendUint64(data, uint64(id))
+	}
+	return sha256.Sum256(data)
+}
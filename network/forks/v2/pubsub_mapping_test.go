@@ -0,0 +1,62 @@
+package v2
+
+import (
+	"encoding/hex"
+	"testing"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/network/forks/genesis"
+)
+
+func TestForkV2_ValidatorSubnet(t *testing.T) {
+	pk1, _ := hex.DecodeString("b768cdc2b2e0a859052bf04d1cd66383c96d95096a5287d08151494ce709556ba39c1300fbb902a0e2ebb7c31dc4e400")
+	pk2, _ := hex.DecodeString("824b9024767a01b56790a72afb5f18bb0f97d5bddb946a7bd8dd35cc607c35a4d76be21f24f484d0d478b99dc63ed170")
+	pk3, _ := hex.DecodeString("9340b7b80983a412bbb42cad6f992e06983d53deb41166ba5e8a2b7d9c1cd4a5b8a5ca2c2b1a2b7c3f1a6b8e4c9d0e1f")
+
+	committees := map[string][]spectypes.OperatorID{
+		hex.EncodeToString(pk1): {1, 2, 3, 4},
+		hex.EncodeToString(pk2): {4, 3, 2, 1},
+		hex.EncodeToString(pk3): {5, 6, 7, 8},
+	}
+	f := &ForkV2{}
+
+	t.Run("without resolver", func(t *testing.T) {
+		require.Equal(t, -1, f.ValidatorSubnet(hex.EncodeToString(pk1)))
+		require.Equal(t, []string{genesis.UnresolvedSubnet}, f.ValidatorTopicID(pk1))
+	})
+
+	f.SetCommitteeResolver(func(pk []byte) ([]spectypes.OperatorID, bool) {
+		committee, ok := committees[hex.EncodeToString(pk)]
+		return committee, ok
+	})
+
+	t.Run("same committee", func(t *testing.T) {
+		subnet := f.ValidatorSubnet(hex.EncodeToString(pk1))
+		require.GreaterOrEqual(t, subnet, 0)
+		require.Less(t, subnet, f.Subnets())
+		require.Equal(t, subnet, f.ValidatorSubnet(hex.EncodeToString(pk2)))
+		require.Equal(t, f.ValidatorTopicID(pk1), f.ValidatorTopicID(pk2))
+	})
+
+	t.Run("different committee", func(t *testing.T) {
+		require.NotEqual(t, CommitteeID([]spectypes.OperatorID{1, 2, 3, 4}), CommitteeID([]spectypes.OperatorID{5, 6, 7, 8}))
+		require.Equal(t, CommitteeSubnet([]spectypes.OperatorID{5, 6, 7, 8}), f.ValidatorSubnet(hex.EncodeToString(pk3)))
+	})
+
+	t.Run("unknown validator", func(t *testing.T) {
+		require.Equal(t, -1, f.ValidatorSubnet("aabbcc"))
+		require.Equal(t, -1, f.ValidatorSubnet("xx"))
+		require.Equal(t, []string{genesis.UnresolvedSubnet}, f.ValidatorTopicID([]byte{1, 2, 3}))
+	})
+
+	t.Run("unresolved committee", func(t *testing.T) {
+		pk4, _ := hex.DecodeString("a297599ccf617c3b6118bbd248494d7072bb8c6c1cc342ea442a289415987d306bad34415f89469221450a2501a832ec")
+		require.Equal(t, -1, f.ValidatorSubnet(hex.EncodeToString(pk4)))
+		require.Equal(t, []string{genesis.UnresolvedSubnet}, f.ValidatorTopicID(pk4))
+
+		committees[hex.EncodeToString(pk4)] = []spectypes.OperatorID{5, 6, 7, 8}
+		require.Equal(t, f.ValidatorTopicID(pk3), f.ValidatorTopicID(pk4))
+	})
+}
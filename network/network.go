@@ -3,10 +3,12 @@ package network
 import (
 	"io"
 
+	"github.com/libp2p/go-libp2p/core/peer"
 	"go.uber.org/zap"
 
 	spectypes "github.com/bloxapp/ssv-spec/types"
 
+	"github.com/bloxapp/ssv/network/records"
 	protocolp2p "github.com/bloxapp/ssv/protocol/v2/p2p"
 )
 
@@ -33,6 +35,14 @@ type P2PNetwork interface {
 	Start(logger *zap.Logger) error
 	// UpdateSubnets will update the registered subnets according to active validators
 	UpdateSubnets(logger *zap.Logger)
+	// RevokeOperator disconnects the peers of the given operator, once it was removed from the registry
+	RevokeOperator(logger *zap.Logger, operatorPubKey []byte)
+	// ConnectedPeers returns the peers that are currently connected
+	ConnectedPeers() []peer.ID
+	// PeerSubnets returns the subnets of the given peer, as known from its handshake
+	PeerSubnets(id peer.ID) records.Subnets
+	// ActiveSubnets returns the subnets of this node
+	ActiveSubnets() records.Subnets
 }
 
 // GetValidatorStats returns stats of validators, including the following:
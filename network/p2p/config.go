@@ -8,15 +8,21 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/p2p/security/noise"
+	libp2pquic "github.com/libp2p/go-libp2p/p2p/transport/quic"
 	libp2ptcp "github.com/libp2p/go-libp2p/p2p/transport/tcp"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/message/validation"
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/commons"
+	"github.com/bloxapp/ssv/network/discovery"
 	"github.com/bloxapp/ssv/network/forks"
+	"github.com/bloxapp/ssv/network/peers"
+	"github.com/bloxapp/ssv/network/topics"
 	"github.com/bloxapp/ssv/operator/storage"
 	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 	uc "github.com/bloxapp/ssv/utils/commons"
@@ -33,9 +39,14 @@ type Config struct {
 	// prod enr
 	Bootnodes string `yaml:"Bootnodes" env:"BOOTNODES" env-description:"Bootnodes to use to start discovery, seperated with ';'" env-default:"enr:-Li4QO2k62g1tiwitaoFVMT8zN-sSNPp8cg8Kv-5lg6_6VLjVZREhxVMSmerOTptlKbBaO2iszi7rvKBYzbGf38HpcSGAYLoed50h2F0dG5ldHOIAAAAAAAAAACEZXRoMpD1pf1CAAAAAP__________gmlkgnY0gmlwhCLdWuKJc2VjcDI1NmsxoQITQ1OchoBl5XW9RfBembdN9Er1qNEOIc5ohrQ0rT9B-YN0Y3CCE4iDdWRwgg-g;enr:-Li4QAxqhjjQN2zMAAEtOF5wlcr2SFnPKINvvlwMXztJhClrfRYLrqNy2a_dMUwDPKcvM7bebq3uptRoGSV0LpYEJuyGAYRZG5n5h2F0dG5ldHOIAAAAAAAAAACEZXRoMpD1pf1CAAAAAP__________gmlkgnY0gmlwhBLb3g2Jc2VjcDI1NmsxoQLbXMJi_Pq3imTq11EwH8MbxmXlHYvH2Drz_rsqP1rNyoN0Y3CCE4iDdWRwgg-g"`
 	Discovery string `yaml:"Discovery" env:"P2P_DISCOVERY" env-description:"Discovery system to use" env-default:"discv5"`
+	// StaticPeers are dialed upon start and redialed on disconnect, they are never trimmed and bypass the peers limit
+	StaticPeers string `yaml:"StaticPeers" env:"P2P_STATIC_PEERS" env-description:"Peers (multiaddrs or ENRs) to always stay connected to, seperated with ';'"`
+	// TrustedPeers are static peers that are also exempt from permissioned handshake filtering
+	TrustedPeers string `yaml:"TrustedPeers" env:"P2P_TRUSTED_PEERS" env-description:"Static peers (multiaddrs or ENRs) that are exempt from permissioned handshake filtering, seperated with ';'"`
 
 	TCPPort     int    `yaml:"TcpPort" env:"TCP_PORT" env-default:"13001" env-description:"TCP port for p2p transport"`
 	UDPPort     int    `yaml:"UdpPort" env:"UDP_PORT" env-default:"12001" env-description:"UDP port for discovery"`
+	QUICPort    int    `yaml:"QuicPort" env:"QUIC_PORT" env-description:"UDP port for QUIC p2p transport, in addition to TCP (disabled if not set)"`
 	HostAddress string `yaml:"HostAddress" env:"HOST_ADDRESS" env-description:"External ip node is exposed for discovery"`
 	HostDNS     string `yaml:"HostDNS" env:"HOST_DNS" env-description:"External DNS node is exposed for discovery"`
 
@@ -43,6 +54,13 @@ type Config struct {
 	MaxBatchResponse uint64        `yaml:"MaxBatchResponse" env:"P2P_MAX_BATCH_RESPONSE" env-default:"25" env-description:"Maximum number of returned objects in a batch"`
 	MaxPeers         int           `yaml:"MaxPeers" env:"P2P_MAX_PEERS" env-default:"60" env-description:"Connected peers limit for connections"`
 	TopicMaxPeers    int           `yaml:"TopicMaxPeers" env:"P2P_TOPIC_MAX_PEERS" env-default:"10" env-description:"Connected peers limit per pubsub topic"`
+	// SyncRateLimit is also the burst of requests that a peer can send at once.
+	// upon startup, nodes sync the highest decided of each validator and role (5) from peers of its subnet,
+	// so the default covers the startup sync of 1200 validators that share subnets with this node.
+	SyncRateLimit int `yaml:"SyncRateLimit" env:"P2P_SYNC_RATE_LIMIT" env-default:"6000" env-description:"Maximum number of last decided requests per minute from a single peer, requests above the limit are dropped (unlimited if not set)"`
+	// SyncHistoryRateLimit is also the burst of history requests that a peer can send at once.
+	// history is only requested by peers that fell behind, and each request reads up to MaxBatchResponse instances.
+	SyncHistoryRateLimit int `yaml:"SyncHistoryRateLimit" env:"P2P_SYNC_HISTORY_RATE_LIMIT" env-default:"300" env-description:"Maximum number of decided history requests per minute from a single peer, requests above the limit are dropped (unlimited if not set)"`
 
 	// Subnets is a static bit list of subnets that this node will register upon start.
 	Subnets string `yaml:"Subnets" env:"SUBNETS" env-description:"Hex string that represents the subnets that this node will join upon start"`
@@ -78,6 +96,23 @@ type Config struct {
 
 	GetValidatorStats network.GetValidatorStats
 
+	// MessageValidator validates the content of pubsub messages,
+	// if not set then only the structure and topic of messages are validated
+	MessageValidator *validation.MessageValidator
+	// MessageValidation is the mode of message validation (enabled, log-only or disabled)
+	MessageValidation string `yaml:"MessageValidation" env:"P2P_MESSAGE_VALIDATION" env-default:"enabled" env-description:"Mode of pubsub message content validation: enabled, log-only (failures are logged and messages are accepted) or disabled"`
+	// ValidatorsFilter drops pubsub messages of validators that are unknown or liquidated,
+	// if not set then messages are not filtered according to the registry
+	ValidatorsFilter *topics.ValidatorsFilter
+	// ValidatorsFilterCacheSize is the amount of validators that are cached by ValidatorsFilter
+	ValidatorsFilterCacheSize int `yaml:"ValidatorsFilterCacheSize" env:"P2P_VALIDATORS_FILTER_CACHE_SIZE" env-description:"Amount of cached validators for filtering pubsub messages of unknown or liquidated validators (disabled if not set)"`
+
+	// ReputationStore persists the reputation of peers across restarts,
+	// if not set then the reputation of peers is kept only in memory
+	ReputationStore *peers.ReputationStore
+	// ReputationTTL is the duration in which the reputation of a peer is kept by ReputationStore
+	ReputationTTL time.Duration `yaml:"ReputationTTL" env:"P2P_REPUTATION_TTL" env-default:"24h" env-description:"How long the reputation of peers (scores, prune state and subnets) is persisted across restarts (disabled if 0)"`
+
 	PermissionedActivateEpoch   uint64 `yaml:"PermissionedActivateEpoch" env:"PERMISSIONED_ACTIVE_EPOCH" env-default:"99999999999999" env-description:"On which epoch to start only accepting peers that are operators registered in the contract"`
 	PermissionedDeactivateEpoch uint64 `yaml:"PermissionedDeactivateEpoch" env:"PERMISSIONED_DEACTIVE_EPOCH" env-default:"0" env-description:"On which epoch to start accepting operators all peers"`
 
@@ -109,6 +144,13 @@ func (c *Config) Libp2pOptions(logger *zap.Logger, fork forks.Fork) ([]libp2p.Op
 		libp2p.Transport(libp2ptcp.NewTCPTransport),
 		libp2p.UserAgent(c.UserAgent),
 	}
+	if c.QUICPort > 0 {
+		if c.QUICPort == c.UDPPort {
+			return nil, errors.New("quic port must be different than the discovery udp port")
+		}
+		// QUIC is secured by its own TLS handshake, noise is used only for TCP
+		opts = append(opts, libp2p.Transport(libp2pquic.NewTransport))
+	}
 
 	opts, err = c.configureAddrs(logger, opts)
 	if err != nil {
@@ -129,6 +171,13 @@ func (c *Config) configureAddrs(logger *zap.Logger, opts []libp2p.Option) ([]lib
 		return opts, errors.Wrap(err, "could not build multi address for zero address")
 	}
 	addrs = append(addrs, maZero)
+	if c.QUICPort > 0 {
+		maZeroQUIC, err := buildQUICMultiAddress("0.0.0.0", uint(c.QUICPort))
+		if err != nil {
+			return opts, errors.Wrap(err, "could not build quic multi address for zero address")
+		}
+		addrs = append(addrs, maZeroQUIC)
+	}
 	ipAddr, err := commons.IPAddr()
 	if err != nil {
 		return opts, errors.Wrap(err, "could not get ip addr")
@@ -140,6 +189,13 @@ func (c *Config) configureAddrs(logger *zap.Logger, opts []libp2p.Option) ([]lib
 			return opts, errors.Wrap(err, "could not build multi address for zero address")
 		}
 		addrs = append(addrs, maIP)
+		if c.QUICPort > 0 {
+			maIPQUIC, err := buildQUICMultiAddress(ipAddr.String(), uint(c.QUICPort))
+			if err != nil {
+				return opts, errors.Wrap(err, "could not build quic multi address")
+			}
+			addrs = append(addrs, maIPQUIC)
+		}
 	}
 	opts = append(opts, libp2p.ListenAddrs(addrs...))
 
@@ -152,6 +208,14 @@ func (c *Config) configureAddrs(logger *zap.Logger, opts []libp2p.Option) ([]lib
 			} else {
 				addrs = append(addrs, external)
 			}
+			if c.QUICPort > 0 {
+				externalQUIC, err := buildQUICMultiAddress(c.HostAddress, uint(c.QUICPort))
+				if err != nil {
+					logger.Error("unable to create external quic multiaddress", zap.Error(err))
+				} else {
+					addrs = append(addrs, externalQUIC)
+				}
+			}
 			return addrs
 		}))
 	}
@@ -164,6 +228,14 @@ func (c *Config) configureAddrs(logger *zap.Logger, opts []libp2p.Option) ([]lib
 			} else {
 				addrs = append(addrs, external)
 			}
+			if c.QUICPort > 0 {
+				externalQUIC, err := ma.NewMultiaddr(fmt.Sprintf("/dns4/%s/udp/%d/quic-v1", c.HostDNS, c.QUICPort))
+				if err != nil {
+					logger.Warn("unable to create external quic multiaddress", zap.Error(err))
+				} else {
+					addrs = append(addrs, externalQUIC)
+				}
+			}
 			return addrs
 		}))
 	}
@@ -171,6 +243,15 @@ func (c *Config) configureAddrs(logger *zap.Logger, opts []libp2p.Option) ([]lib
 	return opts, nil
 }
 
+// buildQUICMultiAddress creates a QUIC (v1) multiaddr for the given ip and udp port
+func buildQUICMultiAddress(ipAddr string, port uint) (ma.Multiaddr, error) {
+	udpAddr, err := commons.BuildMultiAddress(ipAddr, "udp", port, "")
+	if err != nil {
+		return nil, err
+	}
+	return udpAddr.Encapsulate(ma.StringCast("/quic-v1")), nil
+}
+
 // TransformBootnodes converts bootnodes string and convert it to slice
 func (c *Config) TransformBootnodes() []string {
 	items := strings.Split(c.Bootnodes, ";")
@@ -188,6 +269,53 @@ func (c *Config) TransformBootnodes() []string {
 	return items
 }
 
+// TransformStaticPeers parses the static and trusted peers into StaticPeers
+func (c *Config) TransformStaticPeers() (*peers.StaticPeers, error) {
+	staticPeers := peers.NewStaticPeers()
+	for _, item := range splitPeers(c.StaticPeers) {
+		info, err := parsePeer(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse static peer %s", item)
+		}
+		staticPeers.Add(*info, false)
+	}
+	for _, item := range splitPeers(c.TrustedPeers) {
+		info, err := parsePeer(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse trusted peer %s", item)
+		}
+		staticPeers.Add(*info, true)
+	}
+	return staticPeers, nil
+}
+
+// splitPeers splits the given ';' separated list of peers
+func splitPeers(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parsePeer parses a peer from either an ENR or a multiaddr that includes the peer id
+func parsePeer(s string) (*peer.AddrInfo, error) {
+	if strings.HasPrefix(s, "enr:") {
+		nodes, err := discovery.ParseENR(nil, true, s)
+		if err != nil {
+			return nil, err
+		}
+		return discovery.ToPeer(nodes[0])
+	}
+	addr, err := ma.NewMultiaddr(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse multiaddr")
+	}
+	return peer.AddrInfoFromP2pAddr(addr)
+}
+
 func userAgent(fromCfg string) string {
 	if len(fromCfg) > 0 {
 		return fromCfg
@@ -1,17 +1,146 @@
 package p2pv1
 
 import (
-	"fmt"
+	"encoding/hex"
 
-	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
+	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/network/forks"
+	forksfactory "github.com/bloxapp/ssv/network/forks/factory"
+	"github.com/bloxapp/ssv/network/records"
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 )
 
-// OnFork handles a fork event, it will close the current p2p network
-// and recreate it with while preserving previous state (active validators)
-// NOTE: ths method MUST be called once per fork version, otherwise we are just restarting the network
+// OnFork handles a fork event, it replaces the fork of the running network components
+// while preserving the current state (connections, subscriptions and active validators).
+// sync handlers are registered with the protocols of the new fork,
+// while the protocols of the previous fork are still served for peers that didn't fork yet.
+// NOTE: ths method MUST be called once per fork version
 func (n *p2pNetwork) OnFork(logger *zap.Logger, forkVersion forksprotocol.ForkVersion) error {
+	logger = logger.With(fields.Fork(forkVersion))
 	logger.Info("forking network")
-	return errors.New(fmt.Sprintf("no handler for fork - %s", forkVersion.String()))
+
+	prev := n.fork.Get()
+	fork := n.newFork(forkVersion)
+	n.fork.Set(fork)
+
+	n.syncHandlersLock.Lock()
+	n.registerSyncHandlers(logger, fork, n.syncHandlers...)
+	n.syncHandlersLock.Unlock()
+
+	if n.idx != nil {
+		self := records.NewNodeInfo(forkVersion, n.cfg.NetworkID)
+		self.Metadata = n.idx.Self().Metadata
+		n.idx.UpdateSelfRecord(self)
+	}
+	if n.disc != nil {
+		if err := n.disc.UpdateForkVersion(logger, forkVersion); err != nil {
+			return errors.Wrap(err, "could not update fork version in discovery")
+		}
+	}
+	if prev.SubnetsVersion() != fork.SubnetsVersion() && n.isReady() {
+		n.updateSubnetsMapping(logger, prev)
+	}
+	return nil
+}
+
+// newFork creates the fork of the given version,
+// forks that map validators to subnets by their committee are resolving committees from the node storage.
+func (n *p2pNetwork) newFork(forkVersion forksprotocol.ForkVersion) forks.Fork {
+	fork := forksfactory.NewFork(forkVersion)
+	if committeeAware, ok := fork.(forks.CommitteeAware); ok {
+		committeeAware.SetCommitteeResolver(n.validatorCommittee)
+	}
+	return fork
+}
+
+// validatorCommittee returns the committee of the given validator, according to its share in the node storage
+func (n *p2pNetwork) validatorCommittee(pk []byte) ([]spectypes.OperatorID, bool) {
+	if n.nodeStorage == nil {
+		return nil, false
+	}
+	share, found, err := n.nodeStorage.GetShare(pk)
+	if err != nil || !found {
+		return nil, false
+	}
+	committee := make([]spectypes.OperatorID, 0, len(share.Committee))
+	for _, operator := range share.Committee {
+		committee = append(committee, operator.OperatorID)
+	}
+	return committee, true
+}
+
+// updateSubnetsMapping moves the subscriptions of the active validators to their subnets in the current fork,
+// it is called when the previous fork had a different mapping of validators to subnets.
+func (n *p2pNetwork) updateSubnetsMapping(logger *zap.Logger, prev forks.Fork) {
+	prevTopics := make(map[string]bool)
+	currentTopics := make(map[string]bool)
+	newSubnets := make([]byte, n.fork.Subnets())
+	n.activeValidators.Range(func(pkHex string, status validatorStatus) bool {
+		if status != validatorStatusSubscribed {
+			return true
+		}
+		pk, err := hex.DecodeString(pkHex)
+		if err != nil {
+			return true
+		}
+		for _, topic := range prev.ValidatorTopicID(pk) {
+			prevTopics[topic] = true
+		}
+		for _, topic := range n.fork.ValidatorTopicID(pk) {
+			currentTopics[topic] = true
+		}
+		if subnet := n.fork.ValidatorSubnet(pkHex); subnet >= 0 {
+			newSubnets[subnet] = byte(1)
+		}
+		if err := n.subscribe(logger, pk); err != nil {
+			logger.Warn("could not subscribe to validator topics", fields.PubKey(pk), zap.Error(err))
+		}
+		return true
+	})
+
+	last := records.Subnets(n.subnets).Clone()
+	if len(last) > 0 && records.Subnets(last).Active() == len(last) {
+		// nodes that are subscribed to all subnets stay subscribed to all subnets
+		return
+	}
+
+	for topic := range prevTopics {
+		if currentTopics[topic] {
+			continue
+		}
+		if err := n.topicsCtrl.Unsubscribe(logger, topic, false); err != nil {
+			logger.Warn("could not unsubscribe from topic", zap.String("topic", topic), zap.Error(err))
+		}
+	}
+
+	var added, removed []int
+	for subnet, val := range newSubnets {
+		if val > 0 && (subnet >= len(last) || last[subnet] == 0) {
+			added = append(added, subnet)
+		}
+	}
+	for subnet, val := range last {
+		if val > 0 && (subnet >= len(newSubnets) || newSubnets[subnet] == 0) {
+			removed = append(removed, subnet)
+		}
+	}
+	n.subnets = newSubnets
+
+	self := n.idx.Self()
+	self.Metadata.Subnets = records.Subnets(n.subnets).String()
+	n.idx.UpdateSelfRecord(self)
+
+	discLogger := logger.Named(logging.NameDiscoveryService)
+	if err := n.disc.DeregisterSubnets(discLogger, removed...); err != nil {
+		logger.Warn("could not deregister subnets", zap.Error(err))
+	}
+	if err := n.disc.RegisterSubnets(discLogger, added...); err != nil {
+		logger.Warn("could not register subnets", zap.Error(err))
+	}
+	logger.Debug("updated subnets mapping", zap.Ints("added", added), zap.Ints("removed", removed))
 }
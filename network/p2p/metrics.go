@@ -34,6 +34,10 @@ var (
 		Name: "ssv:network:router:in",
 		Help: "Counts incoming messages",
 	}, []string{"identifier", "mt"})
+	metricsSyncRequestsRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssv:network:sync:req:rate_limited",
+		Help: "Counts sync requests that were rejected due to rate limits",
+	}, []string{"pid"})
 )
 
 func init() {
@@ -49,6 +53,9 @@ func init() {
 	if err := prometheus.Register(metricsRouterIncoming); err != nil {
 		log.Println("could not register prometheus collector")
 	}
+	if err := prometheus.Register(metricsSyncRequestsRateLimited); err != nil {
+		log.Println("could not register prometheus collector")
+	}
 }
 
 var unknown = "unknown"
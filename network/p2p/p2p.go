@@ -20,7 +20,6 @@ import (
 	"github.com/bloxapp/ssv/network"
 	"github.com/bloxapp/ssv/network/discovery"
 	"github.com/bloxapp/ssv/network/forks"
-	forksfactory "github.com/bloxapp/ssv/network/forks/factory"
 	"github.com/bloxapp/ssv/network/peers"
 	"github.com/bloxapp/ssv/network/peers/connections"
 	"github.com/bloxapp/ssv/network/records"
@@ -28,6 +27,7 @@ import (
 	"github.com/bloxapp/ssv/network/syncing"
 	"github.com/bloxapp/ssv/network/topics"
 	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	p2pprotocol "github.com/bloxapp/ssv/protocol/v2/p2p"
 	"github.com/bloxapp/ssv/utils/async"
 	"github.com/bloxapp/ssv/utils/tasks"
 )
@@ -44,9 +44,12 @@ const (
 	connManagerGCInterval           = time.Minute
 	connManagerGCTimeout            = time.Minute
 	peerIndexGCInterval             = 15 * time.Minute
+	peersReputationInterval         = 5 * time.Minute
 	peersReportingInterval          = 60 * time.Second
 	peerIdentitiesReportingInterval = 5 * time.Minute
 	topicsReportingInterval         = 180 * time.Second
+	syncRateLimitInterval           = time.Minute
+	syncRateLimiterGCInterval       = 5 * time.Minute
 )
 
 // p2pNetwork implements network.P2PNetwork
@@ -56,7 +59,7 @@ type p2pNetwork struct {
 	cancel    context.CancelFunc
 
 	interfaceLogger *zap.Logger // struct logger to log in interface methods that do not accept a logger
-	fork            forks.Fork
+	fork            *forks.Current
 	cfg             *Config
 
 	host        host.Host
@@ -67,6 +70,9 @@ type p2pNetwork struct {
 	msgRouter   network.MessageRouter
 	msgResolver topics.MsgPeersResolver
 	connHandler connections.ConnHandler
+	allowlist   *connections.OperatorsAllowlist
+	// staticPeers are the peers that the node should always be connected to
+	staticPeers *peers.StaticPeers
 
 	state int32
 
@@ -78,6 +84,16 @@ type p2pNetwork struct {
 	syncer           syncing.Syncer
 	nodeStorage      operatorstorage.Storage
 	operatorPKCache  sync.Map
+
+	// syncHandlers are kept to be registered again with the protocols of a new fork
+	syncHandlers     []*p2pprotocol.SyncHandler
+	syncHandlersLock sync.Mutex
+	// syncRateLimiter limits the last decided requests of each peer, it is nil if they are not limited
+	syncRateLimiter *streams.RateLimiter
+	// historyRateLimiter limits the decided history requests of each peer, it is nil if they are not limited
+	historyRateLimiter *streams.RateLimiter
+	// syncRequests maps the sync requests that are being handled to the peers that sent them
+	syncRequests *syncRequests
 }
 
 // New creates a new p2p network
@@ -86,19 +102,22 @@ func New(logger *zap.Logger, cfg *Config) network.P2PNetwork {
 
 	logger = logger.Named(logging.NameP2PNetwork)
 
-	return &p2pNetwork{
+	n := &p2pNetwork{
 		parentCtx:        cfg.Ctx,
 		ctx:              ctx,
 		cancel:           cancel,
 		interfaceLogger:  logger,
-		fork:             forksfactory.NewFork(cfg.ForkVersion),
 		cfg:              cfg,
 		msgRouter:        cfg.Router,
 		state:            stateClosed,
 		activeValidators: hashmap.New[string, validatorStatus](),
 		nodeStorage:      cfg.NodeStorage,
 		operatorPKCache:  sync.Map{},
+		allowlist:        connections.NewOperatorsAllowlist(),
+		syncRequests:     newSyncRequests(),
 	}
+	n.fork = forks.NewCurrent(n.newFork(cfg.ForkVersion))
+	return n
 }
 
 // Host implements HostProvider
@@ -117,6 +136,7 @@ func (n *p2pNetwork) Close() error {
 	if err := n.disc.Close(); err != nil {
 		n.interfaceLogger.Warn("could not close discovery", zap.Error(err))
 	}
+	n.idx.SaveReputation(n.interfaceLogger)
 	if err := n.idx.Close(); err != nil {
 		n.interfaceLogger.Warn("could not close index", zap.Error(err))
 	}
@@ -139,16 +159,31 @@ func (n *p2pNetwork) Start(logger *zap.Logger) error {
 
 	go n.startDiscovery(logger)
 
+	n.setupStaticPeers(logger)
+	n.connectStaticPeers(logger)()
+	async.Interval(n.ctx, staticPeersRedialInterval, n.connectStaticPeers(logger))
+
 	async.Interval(n.ctx, connManagerGCInterval, n.peersBalancing(logger))
 
 	async.Interval(n.ctx, peerIndexGCInterval, n.idx.GC)
 
+	async.Interval(n.ctx, peersReputationInterval, func() {
+		n.idx.SaveReputation(logger)
+	})
+
 	async.Interval(n.ctx, peersReportingInterval, n.reportAllPeers(logger))
 
 	async.Interval(n.ctx, peerIdentitiesReportingInterval, n.reportPeerIdentities(logger))
 
 	async.Interval(n.ctx, topicsReportingInterval, n.reportTopics(logger))
 
+	if n.syncRateLimiter != nil {
+		async.Interval(n.ctx, syncRateLimiterGCInterval, n.syncRateLimiter.GC)
+	}
+	if n.historyRateLimiter != nil {
+		async.Interval(n.ctx, syncRateLimiterGCInterval, n.historyRateLimiter.GC)
+	}
+
 	if err := n.subscribeToSubnets(logger); err != nil {
 		return err
 	}
@@ -163,7 +198,8 @@ func (n *p2pNetwork) Start(logger *zap.Logger) error {
 
 func (n *p2pNetwork) peersBalancing(logger *zap.Logger) func() {
 	return func() {
-		allPeers := n.host.Network().Peers()
+		// static peers are not counted, as they bypass the peers limit
+		allPeers := n.staticPeers.Filter(n.host.Network().Peers())
 		currentCount := len(allPeers)
 		if currentCount < n.cfg.MaxPeers {
 			_ = n.idx.GetSubnetsStats() // trigger metrics update
@@ -172,7 +208,7 @@ func (n *p2pNetwork) peersBalancing(logger *zap.Logger) func() {
 		ctx, cancel := context.WithTimeout(n.ctx, connManagerGCTimeout)
 		defer cancel()
 
-		connMgr := peers.NewConnManager(logger, n.libConnManager, n.idx)
+		connMgr := peers.NewConnManager(logger, n.libConnManager, n.idx, n.idx)
 		mySubnets := records.Subnets(n.subnets).Clone()
 		connMgr.TagBestPeers(logger, n.cfg.MaxPeers-1, mySubnets, allPeers, n.cfg.TopicMaxPeers)
 		connMgr.TrimPeers(ctx, logger, n.host.Network())
@@ -210,6 +246,47 @@ func (n *p2pNetwork) isReady() bool {
 	return atomic.LoadInt32(&n.state) == stateReady
 }
 
+// RevokeOperator disconnects the peers that were accepted by the permissioned handshake with the given operator key,
+// the peers are pruned so the handshake (and the registered operators filter) would be applied again on reconnect.
+func (n *p2pNetwork) RevokeOperator(logger *zap.Logger, operatorPubKey []byte) {
+	for _, key := range n.cfg.WhitelistedOperatorKeys {
+		if key == string(operatorPubKey) {
+			return
+		}
+	}
+	for _, pid := range n.allowlist.Revoke(operatorPubKey) {
+		if err := n.idx.Prune(pid); err != nil {
+			logger.Debug("could not prune peer", fields.PeerID(pid), zap.Error(err))
+		}
+		if err := n.host.Network().ClosePeer(pid); err != nil {
+			logger.Debug("could not close peer", fields.PeerID(pid), zap.Error(err))
+			continue
+		}
+		logger.Debug("disconnected peer of removed operator", fields.PeerID(pid))
+	}
+}
+
+// ConnectedPeers implements network.P2PNetwork
+func (n *p2pNetwork) ConnectedPeers() []peer.ID {
+	if n.host == nil {
+		return nil
+	}
+	return n.host.Network().Peers()
+}
+
+// PeerSubnets implements network.P2PNetwork
+func (n *p2pNetwork) PeerSubnets(id peer.ID) records.Subnets {
+	if n.idx == nil {
+		return nil
+	}
+	return n.idx.GetPeerSubnets(id)
+}
+
+// ActiveSubnets implements network.P2PNetwork
+func (n *p2pNetwork) ActiveSubnets() records.Subnets {
+	return records.Subnets(n.subnets).Clone()
+}
+
 // UpdateSubnets will update the registered subnets according to active validators
 // NOTE: it won't subscribe to the subnets (use subscribeToSubnets for that)
 func (n *p2pNetwork) UpdateSubnets(logger *zap.Logger) {
@@ -255,8 +332,7 @@ func (n *p2pNetwork) UpdateSubnets(logger *zap.Logger) {
 		logger.Warn("could not register subnets", zap.Error(err))
 		return
 	}
-	allSubs, _ := records.Subnets{}.FromString(records.AllSubnets)
-	subnetsList := records.SharedSubnets(allSubs, n.subnets, 0)
+	subnetsList := records.SharedSubnets(records.AllSubnetsOf(len(n.subnets)), n.subnets, 0)
 	logger.Debug("updated subnets (node-info)", zap.Any("subnets", subnetsList))
 }
 
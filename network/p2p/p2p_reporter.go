@@ -6,6 +6,7 @@ import (
 	"github.com/bloxapp/ssv/logging/fields"
 
 	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"go.uber.org/zap"
 
 	ssvpeers "github.com/bloxapp/ssv/network/peers"
@@ -18,6 +19,10 @@ func (n *p2pNetwork) ReportValidation(logger *zap.Logger, msg *spectypes.SSVMess
 	if !n.isReady() {
 		return
 	}
+	if sender, ok := n.syncRequests.sender(msg); ok {
+		n.reportSyncRequestValidation(logger, sender, res)
+		return
+	}
 	data, err := n.fork.EncodeNetworkMsg(msg)
 	if err != nil {
 		logger.Warn("could not encode message", zap.Error(err))
@@ -33,6 +38,19 @@ func (n *p2pNetwork) ReportValidation(logger *zap.Logger, msg *spectypes.SSVMess
 	}
 }
 
+// reportSyncRequestValidation reports the validation result of a sync request to the peer that sent it,
+// rejected requests are also counted as sync violations.
+func (n *p2pNetwork) reportSyncRequestValidation(logger *zap.Logger, sender peer.ID, res protocolp2p.MsgValidationResult) {
+	err := n.idx.Score(sender, &ssvpeers.NodeScore{Name: "validation", Value: msgValidationScore(res)})
+	if err != nil {
+		logger.Warn("could not score peer", fields.PeerID(sender), zap.Error(err))
+	}
+	switch res {
+	case protocolp2p.ValidationRejectLow, protocolp2p.ValidationRejectMedium, protocolp2p.ValidationRejectHigh:
+		n.reportSyncViolation(logger, sender)
+	}
+}
+
 const (
 	validationScoreLow = 5.0
 )
@@ -151,6 +151,12 @@ func (n *p2pNetwork) SetupServices(logger *zap.Logger) error {
 
 func (n *p2pNetwork) setupStreamCtrl(logger *zap.Logger) error {
 	n.streamCtrl = streams.NewStreamController(n.ctx, n.host, n.fork, n.cfg.RequestTimeout)
+	if n.cfg.SyncRateLimit > 0 {
+		n.syncRateLimiter = streams.NewRateLimiter(n.cfg.SyncRateLimit, syncRateLimitInterval)
+	}
+	if n.cfg.SyncHistoryRateLimit > 0 {
+		n.historyRateLimiter = streams.NewRateLimiter(n.cfg.SyncHistoryRateLimit, syncRateLimitInterval)
+	}
 	logger.Debug("stream controller is ready")
 	return nil
 }
@@ -161,6 +167,11 @@ func (n *p2pNetwork) setupPeerServices(logger *zap.Logger) error {
 		return err
 	}
 
+	n.staticPeers, err = n.cfg.TransformStaticPeers()
+	if err != nil {
+		return errors.Wrap(err, "could not parse static peers")
+	}
+
 	self := records.NewNodeInfo(n.cfg.ForkVersion, n.cfg.NetworkID)
 	self.Metadata = &records.NodeMetadata{
 		OperatorID:  n.cfg.OperatorID,
@@ -171,7 +182,12 @@ func (n *p2pNetwork) setupPeerServices(logger *zap.Logger) error {
 		return libPrivKey
 	}
 
-	n.idx = peers.NewPeersIndex(logger, n.host.Network(), self, n.getMaxPeers, getPrivKey, n.fork.Subnets(), 10*time.Minute)
+	n.idx = peers.NewPeersIndex(logger, n.host.Network(), self, n.getMaxPeers, getPrivKey, n.fork.Subnets(), 10*time.Minute, n.staticPeers)
+	if n.cfg.ReputationStore != nil {
+		if err := n.idx.LoadReputation(logger, n.cfg.ReputationStore); err != nil {
+			logger.Warn("could not load peers reputation", zap.Error(err))
+		}
+	}
 	logger.Debug("peers index is ready", fields.Fork(n.cfg.ForkVersion))
 
 	var ids identify.IDService
@@ -194,10 +210,11 @@ func (n *p2pNetwork) setupPeerServices(logger *zap.Logger) error {
 		}
 
 		if n.cfg.Permissioned() {
+			// trusted peers are exempt from permissioned filtering
 			filters = append(filters,
-				connections.SenderRecipientIPsCheckFilter(n.host.ID()),
-				connections.SignatureCheckFilter(),
-				connections.RegisteredOperatorsFilter(logger, n.nodeStorage, n.cfg.WhitelistedOperatorKeys))
+				connections.TrustedPeersExemption(n.staticPeers, connections.SenderRecipientIPsCheckFilter(n.host.ID())),
+				connections.TrustedPeersExemption(n.staticPeers, connections.SignatureCheckFilter()),
+				connections.TrustedPeersExemption(n.staticPeers, connections.RegisteredOperatorsFilter(logger, n.nodeStorage, n.cfg.WhitelistedOperatorKeys)))
 		}
 		return filters
 	}
@@ -213,12 +230,14 @@ func (n *p2pNetwork) setupPeerServices(logger *zap.Logger) error {
 		SubnetsProvider: subnetsProvider,
 		NodeStorage:     n.nodeStorage,
 		Permissioned:    n.cfg.Permissioned,
+		Allowlist:       n.allowlist,
+		StaticPeers:     n.staticPeers,
 	}, filters)
 
 	n.host.SetStreamHandler(peers.NodeInfoProtocol, handshaker.Handler(logger))
 	logger.Debug("handshaker is ready")
 
-	n.connHandler = connections.NewConnHandler(n.ctx, handshaker, subnetsProvider, n.idx, n.idx)
+	n.connHandler = connections.NewConnHandler(n.ctx, handshaker, subnetsProvider, n.idx, n.idx, n.staticPeers)
 	n.host.Network().Notify(n.connHandler.Handle(logger))
 	logger.Debug("connection handler is ready")
 
@@ -237,6 +256,7 @@ func (n *p2pNetwork) setupDiscovery(logger *zap.Logger) error {
 			BindIP:        net.IPv4zero.String(),
 			Port:          n.cfg.UDPPort,
 			TCPPort:       n.cfg.TCPPort,
+			QUICPort:      n.cfg.QUICPort,
 			NetworkKey:    n.cfg.NetworkPrivateKey,
 			Bootnodes:     n.cfg.TransformBootnodes(),
 			OperatorID:    n.cfg.OperatorID,
@@ -274,7 +294,7 @@ func (n *p2pNetwork) setupPubsub(logger *zap.Logger) error {
 		Host:     n.host,
 		TraceLog: n.cfg.PubSubTrace,
 		MsgValidatorFactory: func(s string) topics.MsgValidatorFunc {
-			return topics.NewSSVMsgValidator(n.fork)
+			return topics.NewSSVMsgValidator(logger, n.fork, n.cfg.ValidatorsFilter, n.cfg.MessageValidator)
 		},
 		MsgHandler: n.handlePubsubMessages(logger),
 		ScoreIndex: n.idx,
@@ -0,0 +1,80 @@
+package p2pv1
+
+import (
+	"context"
+	"time"
+
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/network/peers"
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"go.uber.org/zap"
+)
+
+const (
+	// staticPeersRedialInterval is the interval in which disconnected static peers are redialed,
+	// in addition to redialing upon disconnection
+	staticPeersRedialInterval = 15 * time.Second
+	// staticPeerDialTimeout is the timeout used for dialing a static peer
+	staticPeerDialTimeout = 15 * time.Second
+	// staticPeerRedialDelay is the time to wait after a static peer was disconnected before redialing it
+	staticPeerRedialDelay = time.Second
+)
+
+// setupStaticPeers adds the static peers to the peerstore and protects them in the connection manager,
+// static peers are redialed once they are disconnected.
+func (n *p2pNetwork) setupStaticPeers(logger *zap.Logger) {
+	for _, info := range n.staticPeers.Peers() {
+		n.host.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.PermanentAddrTTL)
+		n.libConnManager.Protect(info.ID, peers.StaticPeerTag)
+		logger.Debug("added static peer", fields.PeerID(info.ID),
+			zap.Bool("trusted", n.staticPeers.IsTrusted(info.ID)))
+	}
+	if len(n.staticPeers.Peers()) == 0 {
+		return
+	}
+	n.host.Network().Notify(&libp2pnetwork.NotifyBundle{
+		DisconnectedF: func(net libp2pnetwork.Network, conn libp2pnetwork.Conn) {
+			if conn == nil || !n.staticPeers.IsStatic(conn.RemotePeer()) {
+				return
+			}
+			go n.redialStaticPeer(logger, conn.RemotePeer())
+		},
+	})
+}
+
+// connectStaticPeers dials the static peers that are not connected
+func (n *p2pNetwork) connectStaticPeers(logger *zap.Logger) func() {
+	return func() {
+		for _, info := range n.staticPeers.Peers() {
+			if n.host.Network().Connectedness(info.ID) == libp2pnetwork.Connected {
+				continue
+			}
+			go n.dialStaticPeer(logger, info)
+		}
+	}
+}
+
+// redialStaticPeer dials the given static peer after a short delay, if it is still disconnected
+func (n *p2pNetwork) redialStaticPeer(logger *zap.Logger, id peer.ID) {
+	select {
+	case <-n.ctx.Done():
+		return
+	case <-time.After(staticPeerRedialDelay):
+	}
+	if n.host.Network().Connectedness(id) == libp2pnetwork.Connected {
+		return
+	}
+	logger.Debug("redialing disconnected static peer", fields.PeerID(id))
+	// the addresses of static peers are kept in the peerstore
+	n.dialStaticPeer(logger, peer.AddrInfo{ID: id})
+}
+
+func (n *p2pNetwork) dialStaticPeer(logger *zap.Logger, info peer.AddrInfo) {
+	ctx, cancel := context.WithTimeout(n.ctx, staticPeerDialTimeout)
+	defer cancel()
+	if err := n.host.Connect(ctx, info); err != nil {
+		logger.Debug("could not connect to static peer", fields.PeerID(info.ID), zap.Error(err))
+	}
+}
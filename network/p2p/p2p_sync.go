@@ -18,6 +18,9 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/network/forks"
+	"github.com/bloxapp/ssv/network/peers"
+	"github.com/bloxapp/ssv/network/streams"
 	"github.com/bloxapp/ssv/protocol/v2/message"
 	p2pprotocol "github.com/bloxapp/ssv/protocol/v2/p2p"
 )
@@ -137,25 +140,44 @@ func (n *p2pNetwork) GetHistory(logger *zap.Logger, mid spectypes.MessageID, fro
 
 // RegisterHandlers registers the given handlers
 func (n *p2pNetwork) RegisterHandlers(logger *zap.Logger, handlers ...*p2pprotocol.SyncHandler) {
+	n.syncHandlersLock.Lock()
+	defer n.syncHandlersLock.Unlock()
+
+	n.syncHandlers = append(n.syncHandlers, handlers...)
+	n.registerSyncHandlers(logger, n.fork.Get(), handlers...)
+}
+
+// registerSyncHandlers registers the given handlers with the protocols and encoding of the given fork
+func (n *p2pNetwork) registerSyncHandlers(logger *zap.Logger, fork forks.Fork, handlers ...*p2pprotocol.SyncHandler) {
 	m := make(map[libp2p_protocol.ID][]p2pprotocol.RequestHandler)
+	limiters := make(map[libp2p_protocol.ID]*streams.RateLimiter)
 	for _, handler := range handlers {
-		pid, _ := n.fork.ProtocolID(handler.Protocol)
+		pid, _ := fork.ProtocolID(handler.Protocol)
 		current, ok := m[pid]
 		if !ok {
 			current = make([]p2pprotocol.RequestHandler, 0)
 		}
 		current = append(current, handler.Handler)
 		m[pid] = current
+		limiters[pid] = n.rateLimiter(handler.Protocol)
 	}
 
 	for pid, phandlers := range m {
-		n.registerHandlers(logger, pid, phandlers...)
+		n.registerHandlers(logger, fork, pid, limiters[pid], phandlers...)
 	}
 }
 
-func (n *p2pNetwork) registerHandlers(logger *zap.Logger, pid libp2p_protocol.ID, handlers ...p2pprotocol.RequestHandler) {
+// rateLimiter returns the rate limiter of the requests of the given protocol, nil if they are not limited
+func (n *p2pNetwork) rateLimiter(protocol p2pprotocol.SyncProtocol) *streams.RateLimiter {
+	if protocol == p2pprotocol.DecidedHistoryProtocol {
+		return n.historyRateLimiter
+	}
+	return n.syncRateLimiter
+}
+
+func (n *p2pNetwork) registerHandlers(logger *zap.Logger, fork forks.Fork, pid libp2p_protocol.ID, limiter *streams.RateLimiter, handlers ...p2pprotocol.RequestHandler) {
 	handler := p2pprotocol.CombineRequestHandlers(handlers...)
-	streamHandler := n.handleStream(logger, handler)
+	streamHandler := n.handleStream(logger, fork, limiter, handler)
 	n.host.SetStreamHandler(pid, func(stream libp2pnetwork.Stream) {
 		err := streamHandler(stream)
 		if err != nil {
@@ -164,23 +186,41 @@ func (n *p2pNetwork) registerHandlers(logger *zap.Logger, pid libp2p_protocol.ID
 	})
 }
 
-func (n *p2pNetwork) handleStream(logger *zap.Logger, handler p2pprotocol.RequestHandler) func(stream libp2pnetwork.Stream) error {
+// handleStream returns a stream handler that decodes requests and encodes responses with the given fork,
+// so peers are served according to the protocol they requested.
+// requests are limited by the given rate limiter, unless it is nil.
+func (n *p2pNetwork) handleStream(logger *zap.Logger, fork forks.Fork, limiter *streams.RateLimiter, handler p2pprotocol.RequestHandler) func(stream libp2pnetwork.Stream) error {
 	return func(stream libp2pnetwork.Stream) error {
+		sender := stream.Conn().RemotePeer()
+		// requests above the rate limit are dropped without penalizing the peer,
+		// as honest peers might burst when syncing many validators (e.g. upon startup)
+		if limiter != nil && !limiter.Allow(sender, stream.Protocol()) {
+			metricsSyncRequestsRateLimited.WithLabelValues(string(stream.Protocol())).Inc()
+			if err := stream.Reset(); err != nil {
+				logger.Debug("could not reset stream", zap.Error(err))
+			}
+			return errors.New("sync requests rate limit exceeded")
+		}
+
 		req, respond, done, err := n.streamCtrl.HandleStream(logger, stream)
 		defer done()
 
 		if err != nil {
 			return errors.Wrap(err, "could not handle stream")
 		}
-		smsg, err := n.fork.DecodeNetworkMsg(req)
+		smsg, err := fork.DecodeNetworkMsg(req)
 		if err != nil {
+			n.reportSyncViolation(logger, sender)
 			return errors.Wrap(err, "could not decode msg from stream")
 		}
+		// the sender is kept while the request is handled, so validation results could be reported to it
+		n.syncRequests.add(smsg, sender)
 		result, err := handler(smsg)
+		n.syncRequests.remove(smsg, sender)
 		if err != nil {
 			return errors.Wrap(err, "could not handle msg from stream")
 		}
-		resultBytes, err := n.fork.EncodeNetworkMsg(result)
+		resultBytes, err := fork.EncodeNetworkMsg(result)
 		if err != nil {
 			return errors.Wrap(err, "could not encode msg")
 		}
@@ -191,6 +231,27 @@ func (n *p2pNetwork) handleStream(logger *zap.Logger, handler p2pprotocol.Reques
 	}
 }
 
+// reportSyncViolation counts an invalid sync request of the given peer,
+// peers that reach the max amount of violations are pruned and disconnected, unless they are static peers.
+func (n *p2pNetwork) reportSyncViolation(logger *zap.Logger, pid peer.ID) {
+	logger = logger.With(fields.PeerID(pid))
+	violations, err := peers.AddSyncViolation(n.idx, pid)
+	if err != nil {
+		logger.Warn("could not score peer", zap.Error(err))
+		return
+	}
+	if violations < peers.MaxSyncViolations || n.staticPeers.IsStatic(pid) {
+		return
+	}
+	logger.Debug("pruning peer due to sync violations", zap.Int("violations", violations))
+	if err := n.idx.Prune(pid); err != nil {
+		logger.Debug("could not prune peer", zap.Error(err))
+	}
+	if err := n.host.Network().ClosePeer(pid); err != nil {
+		logger.Debug("could not close peer", zap.Error(err))
+	}
+}
+
 // getSubsetOfPeers returns a subset of the peers from that topic
 func (n *p2pNetwork) getSubsetOfPeers(logger *zap.Logger, vpk spectypes.ValidatorPK, peerCount int, filter func(peer.ID) bool) (peers []peer.ID, err error) {
 	var ps []peer.ID
@@ -0,0 +1,72 @@
+package p2pv1
+
+import (
+	"sync"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// syncRequests tracks the sync requests that are being handled by their message ID,
+// so validation results of the request handlers could be reported to the peers that sent them.
+type syncRequests struct {
+	requests map[spectypes.MessageID][]syncRequest
+	lock     sync.Mutex
+}
+
+type syncRequest struct {
+	msg    *spectypes.SSVMessage
+	sender peer.ID
+}
+
+func newSyncRequests() *syncRequests {
+	return &syncRequests{
+		requests: make(map[spectypes.MessageID][]syncRequest),
+	}
+}
+
+// add tracks the given request until it is removed
+func (sr *syncRequests) add(msg *spectypes.SSVMessage, sender peer.ID) {
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+
+	sr.requests[msg.MsgID] = append(sr.requests[msg.MsgID], syncRequest{msg: msg, sender: sender})
+}
+
+// remove stops tracking the given request
+func (sr *syncRequests) remove(msg *spectypes.SSVMessage, sender peer.ID) {
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+
+	requests := sr.requests[msg.MsgID]
+	for i, req := range requests {
+		if req.msg == msg && req.sender == sender {
+			requests = append(requests[:i], requests[i+1:]...)
+			break
+		}
+	}
+	if len(requests) == 0 {
+		delete(sr.requests, msg.MsgID)
+		return
+	}
+	sr.requests[msg.MsgID] = requests
+}
+
+// sender returns the peer that sent the given request.
+// if the same message ID is requested by several peers at once, the request is matched by the message itself,
+// otherwise it is not attributed to any of them.
+func (sr *syncRequests) sender(msg *spectypes.SSVMessage) (peer.ID, bool) {
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+
+	requests := sr.requests[msg.MsgID]
+	if len(requests) == 1 {
+		return requests[0].sender, true
+	}
+	for _, req := range requests {
+		if req.msg == msg {
+			return req.sender, true
+		}
+	}
+	return "", false
+}
@@ -12,28 +12,63 @@ import (
 
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
 	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/libp2p/go-libp2p/core/host"
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/network/forks"
 	forksfactory "github.com/bloxapp/ssv/network/forks/factory"
+	nettesting "github.com/bloxapp/ssv/network/testing"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
 	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 	protcolp2p "github.com/bloxapp/ssv/protocol/v2/p2p"
 	"github.com/bloxapp/ssv/protocol/v2/types"
+	ssvstorage "github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
 )
 
 func TestGetMaxPeers(t *testing.T) {
 	n := &p2pNetwork{
 		cfg:  &Config{MaxPeers: 40, TopicMaxPeers: 8},
-		fork: forksfactory.NewFork(forksprotocol.GenesisForkVersion),
+		fork: forks.NewCurrent(forksfactory.NewFork(forksprotocol.GenesisForkVersion)),
 	}
 
 	require.Equal(t, 40, n.getMaxPeers(""))
 	require.Equal(t, 8, n.getMaxPeers("100"))
 }
 
+func TestSyncRequests(t *testing.T) {
+	sr := newSyncRequests()
+	mid := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleAttester)
+	msg1 := &spectypes.SSVMessage{MsgType: spectypes.SSVConsensusMsgType, MsgID: mid}
+	msg2 := &spectypes.SSVMessage{MsgType: spectypes.SSVConsensusMsgType, MsgID: mid}
+	copied := *msg1
+
+	sr.add(msg1, "peer1")
+	sender, ok := sr.sender(&copied)
+	require.True(t, ok)
+	require.Equal(t, peer.ID("peer1"), sender)
+
+	// the same message ID is requested by another peer at once
+	sr.add(msg2, "peer2")
+	sender, ok = sr.sender(msg2)
+	require.True(t, ok)
+	require.Equal(t, peer.ID("peer2"), sender)
+	_, ok = sr.sender(&copied)
+	require.False(t, ok)
+
+	sr.remove(msg1, "peer1")
+	sr.remove(msg2, "peer2")
+	_, ok = sr.sender(msg1)
+	require.False(t, ok)
+	require.Empty(t, sr.requests)
+}
+
 func TestP2pNetwork_SubscribeBroadcast(t *testing.T) {
 	n := 4
 	ctx, cancel := context.WithCancel(context.Background())
@@ -107,6 +142,93 @@ func TestP2pNetwork_SubscribeBroadcast(t *testing.T) {
 	}
 }
 
+func TestP2pNetwork_QUIC(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	logger := logging.TestLogger(t)
+
+	// the first two nodes enable QUIC, the third is a TCP-only node
+	ln, err := newLocalNet(ctx, logger, 3, 2, forksprotocol.GenesisForkVersion, false)
+	require.NoError(t, err)
+	defer func() {
+		for _, node := range ln.Nodes {
+			require.NoError(t, node.(*p2pNetwork).Close())
+		}
+	}()
+	for _, node := range ln.Nodes {
+		require.NoError(t, node.Start(logger))
+	}
+
+	hosts := make([]host.Host, len(ln.Nodes))
+	for i, node := range ln.Nodes {
+		hosts[i] = node.(HostProvider).Host()
+	}
+	require.Eventually(t, func() bool {
+		return len(hosts[0].Network().ConnsToPeer(hosts[1].ID())) > 0 &&
+			len(hosts[0].Network().ConnsToPeer(hosts[2].ID())) > 0 &&
+			len(hosts[1].Network().ConnsToPeer(hosts[2].ID())) > 0
+	}, 20*time.Second, 100*time.Millisecond)
+
+	isQUIC := func(conn libp2pnetwork.Conn) bool {
+		_, err := conn.RemoteMultiaddr().ValueForProtocol(ma.P_QUIC_V1)
+		return err == nil
+	}
+	// QUIC nodes prefer QUIC, while the TCP-only node remains reachable over TCP
+	quicConns := 0
+	for _, conn := range hosts[0].Network().ConnsToPeer(hosts[1].ID()) {
+		if isQUIC(conn) {
+			quicConns++
+		}
+	}
+	require.Greater(t, quicConns, 0)
+	for _, conn := range hosts[2].Network().Conns() {
+		require.False(t, isQUIC(conn))
+	}
+}
+
+func TestP2pNetwork_KeystoreOperatorKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	logger := logging.TestLogger(t)
+
+	keys, err := nettesting.CreateKeys(2)
+	require.NoError(t, err)
+
+	// the operator key of the second node is held in memory only, as it is when loaded from a keystore
+	db, err := ssvstorage.GetStorageFactory(logger, basedb.Options{Type: "badger-memory", Ctx: ctx})
+	require.NoError(t, err)
+	defer db.Close(logger)
+	nodeStorage := operatorstorage.NewNodeStorage(db)
+	_, err = nodeStorage.SetupKeystorePrivateKey(logger, keys[1].OperatorKey)
+	require.NoError(t, err)
+
+	ln := &LocalNet{udpRand: make(nettesting.UDPPortsRandomizer)}
+	nodes := make([]network.P2PNetwork, 2)
+	nodes[0], err = ln.newTestP2pNetwork(ctx, keys[0], logger.Named("node-1"), forksprotocol.GenesisForkVersion, 2, false, nil)
+	require.NoError(t, err)
+	nodes[1], err = ln.newTestP2pNetwork(ctx, keys[1], logger.Named("node-2"), forksprotocol.GenesisForkVersion, 2, false, nodeStorage)
+	require.NoError(t, err)
+	defer func() {
+		for _, node := range nodes {
+			require.NoError(t, node.(*p2pNetwork).Close())
+		}
+	}()
+	for _, node := range nodes {
+		require.NoError(t, node.Start(logger))
+	}
+
+	// both nodes sign their node info with their operator keys, so they only index each other once the handshake succeeds
+	first, second := nodes[0].(*p2pNetwork), nodes[1].(*p2pNetwork)
+	require.Eventually(t, func() bool {
+		ni, err := first.idx.GetNodeInfo(second.host.ID())
+		if err != nil || ni == nil {
+			return false
+		}
+		ni, err = second.idx.GetNodeInfo(first.host.ID())
+		return err == nil && ni != nil
+	}, 20*time.Second, 100*time.Millisecond)
+}
+
 func TestP2pNetwork_Stream(t *testing.T) {
 	n := 12
 	ctx, cancel := context.WithCancel(context.Background())
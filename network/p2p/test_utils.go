@@ -14,6 +14,7 @@ import (
 	"github.com/bloxapp/ssv/network/commons"
 	"github.com/bloxapp/ssv/network/discovery"
 	"github.com/bloxapp/ssv/network/testing"
+	"github.com/bloxapp/ssv/operator/storage"
 	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 	"github.com/bloxapp/ssv/utils/format"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
@@ -121,16 +122,28 @@ func CreateAndStartLocalNet(pCtx context.Context, logger *zap.Logger, forkVersio
 
 // NewTestP2pNetwork creates a new network.P2PNetwork instance
 func (ln *LocalNet) NewTestP2pNetwork(ctx context.Context, keys testing.NodeKeys, logger *zap.Logger, forkVersion forksprotocol.ForkVersion, maxPeers int) (network.P2PNetwork, error) {
+	return ln.newTestP2pNetwork(ctx, keys, logger, forkVersion, maxPeers, false, nil)
+}
+
+// newTestP2pNetwork creates a new network.P2PNetwork instance, with QUIC transport in addition to TCP if quic is true.
+// a mock of the node storage that holds the operator key is used if nodeStorage is nil
+func (ln *LocalNet) newTestP2pNetwork(ctx context.Context, keys testing.NodeKeys, logger *zap.Logger, forkVersion forksprotocol.ForkVersion, maxPeers int, quic bool, nodeStorage storage.Storage) (network.P2PNetwork, error) {
 	operatorPubkey, err := rsaencryption.ExtractPublicKey(keys.OperatorKey)
 	if err != nil {
 		return nil, err
 	}
 	cfg := NewNetConfig(keys.NetKey, format.OperatorID([]byte(operatorPubkey)), forkVersion, ln.Bootnode, testing.RandomTCPPort(12001, 12999), ln.udpRand.Next(13001, 13999), maxPeers)
+	if quic {
+		cfg.QUICPort = ln.udpRand.Next(14001, 14999)
+	}
 	cfg.Ctx = ctx
 	cfg.Subnets = "00000000000000000000020000000000" //PAY ATTENTION for future test scenarios which use more than one eth-validator we need to make this field dynamically changing
-	cfg.NodeStorage = mock.NodeStorage{
-		MockGetPrivateKey:               keys.OperatorKey,
-		RegisteredOperatorPublicKeyPEMs: []string{},
+	cfg.NodeStorage = nodeStorage
+	if cfg.NodeStorage == nil {
+		cfg.NodeStorage = mock.NodeStorage{
+			MockGetPrivateKey:               keys.OperatorKey,
+			RegisteredOperatorPublicKeyPEMs: []string{},
+		}
 	}
 
 	p := New(logger, cfg)
@@ -143,6 +156,11 @@ func (ln *LocalNet) NewTestP2pNetwork(ctx context.Context, keys testing.NodeKeys
 
 // NewLocalNet creates a new mdns network
 func NewLocalNet(ctx context.Context, logger *zap.Logger, n int, forkVersion forksprotocol.ForkVersion, useDiscv5 bool) (*LocalNet, error) {
+	return newLocalNet(ctx, logger, n, 0, forkVersion, useDiscv5)
+}
+
+// newLocalNet creates a new local network where the first quicNodes nodes enable the QUIC transport
+func newLocalNet(ctx context.Context, logger *zap.Logger, n, quicNodes int, forkVersion forksprotocol.ForkVersion, useDiscv5 bool) (*LocalNet, error) {
 	ln := &LocalNet{}
 	ln.udpRand = make(testing.UDPPortsRandomizer)
 	if useDiscv5 {
@@ -154,7 +172,7 @@ func NewLocalNet(ctx context.Context, logger *zap.Logger, n int, forkVersion for
 	nodes, keys, err := testing.NewLocalNetwork(ctx, n, func(pctx context.Context, keys testing.NodeKeys) network.P2PNetwork {
 		i++
 		logger := logger.Named(fmt.Sprintf("node-%d", i))
-		p, err := ln.NewTestP2pNetwork(pctx, keys, logger, forkVersion, n)
+		p, err := ln.newTestP2pNetwork(pctx, keys, logger, forkVersion, n, i <= quicNodes, nil)
 		if err != nil {
 			logger.Error("could not setup network", zap.Error(err))
 		}
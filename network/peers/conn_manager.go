@@ -15,7 +15,14 @@ import (
 )
 
 const (
+	// MinSubnetPeers is the desired minimum number of connected peers in each of our subnets,
+	// subnets with fewer connected peers are considered weak
+	MinSubnetPeers = 4
+
 	protectedTag = "ssv/subnets"
+	// gossipScoreWeight scales negative gossipsub scores to the range of subnets scores,
+	// so a peer that reached the gossip threshold (-4000) loses about as much as a few shared subnets are worth.
+	gossipScoreWeight = 0.001
 )
 
 type PeerScore float64
@@ -24,19 +31,22 @@ type PeerScore float64
 // exposing an abstract interface so we can have the flexibility of doing some stuff manually
 // rather than relaying on libp2p's connection manager.
 type ConnManager interface {
-	// TagBestPeers tags the best n peers from the given list, based on subnets distribution scores.
+	// TagBestPeers tags the best n peers from the given list, based on subnets distribution scores
+	// and gossipsub scores.
 	TagBestPeers(logger *zap.Logger, n int, mySubnets records.Subnets, allPeers []peer.ID, topicMaxPeers int)
-	// TrimPeers will trim unprotected peers.
+	// TrimPeers will trim unprotected peers and peers with a bad gossipsub score.
+	// static peers are never trimmed.
 	TrimPeers(ctx context.Context, logger *zap.Logger, net libp2pnetwork.Network)
 }
 
 // NewConnManager creates a new conn manager.
 // multiple instances can be created, but concurrency is not supported.
-func NewConnManager(logger *zap.Logger, connMgr connmgrcore.ConnManager, subnetsIdx SubnetsIndex) ConnManager {
+func NewConnManager(logger *zap.Logger, connMgr connmgrcore.ConnManager, subnetsIdx SubnetsIndex, scoreIdx ScoreIndex) ConnManager {
 	return &connManager{
 		logger:      logger,
 		connManager: connMgr,
 		subnetsIdx:  subnetsIdx,
+		scoreIdx:    scoreIdx,
 	}
 }
 
@@ -45,6 +55,7 @@ type connManager struct {
 	logger      *zap.Logger
 	connManager connmgrcore.ConnManager
 	subnetsIdx  SubnetsIndex
+	scoreIdx    ScoreIndex
 }
 
 func (c connManager) TagBestPeers(logger *zap.Logger, n int, mySubnets records.Subnets, allPeers []peer.ID, topicMaxPeers int) {
@@ -71,7 +82,10 @@ func (c connManager) TrimPeers(ctx context.Context, logger *zap.Logger, net libp
 	// TODO: use libp2p's conn manager once ready
 	// c.connManager.TrimOpenConns(ctx)
 	for _, pid := range allPeers {
-		if !c.connManager.IsProtected(pid, protectedTag) {
+		if c.connManager.IsProtected(pid, StaticPeerTag) {
+			continue
+		}
+		if !c.connManager.IsProtected(pid, protectedTag) || hasBadGossipScore(c.scoreIdx, pid) {
 			err := net.ClosePeer(pid)
 			logger.Debug("closing peer", zap.String("pid", pid.String()), zap.Error(err))
 			// if err != nil {
@@ -87,20 +101,26 @@ func (c connManager) TrimPeers(ctx context.Context, logger *zap.Logger, net libp
 // getBestPeers loop over all the existing peers and returns the best set
 // according to the number of shared subnets,
 // while considering subnets with low peer count to be more important.
+// peers with a negative gossipsub score are penalized, and peers with a bad gossipsub score are never selected.
 func (c connManager) getBestPeers(n int, mySubnets records.Subnets, allPeers []peer.ID, topicMaxPeers int) map[peer.ID]PeerScore {
 	peerScores := make(map[peer.ID]PeerScore)
 	if len(allPeers) < n {
 		for _, p := range allPeers {
+			if hasBadGossipScore(c.scoreIdx, p) {
+				continue
+			}
 			peerScores[p] = 1
 		}
 		return peerScores
 	}
 	stats := c.subnetsIdx.GetSubnetsStats()
-	minSubnetPeers := 4
-	subnetsScores := GetSubnetsDistributionScores(stats, minSubnetPeers, mySubnets, topicMaxPeers)
+	subnetsScores := GetSubnetsDistributionScores(stats, MinSubnetPeers, mySubnets, topicMaxPeers)
 
 	var peerLogs []peerLog
 	for _, pid := range allPeers {
+		if hasBadGossipScore(c.scoreIdx, pid) {
+			continue
+		}
 		peerSubnets := c.subnetsIdx.GetPeerSubnets(pid)
 		var score PeerScore
 		if len(peerSubnets) == 0 {
@@ -110,6 +130,9 @@ func (c connManager) getBestPeers(n int, mySubnets records.Subnets, allPeers []p
 		} else {
 			score = scorePeer(peerSubnets, subnetsScores)
 		}
+		if gs, ok := gossipScore(c.scoreIdx, pid); ok && gs < 0 {
+			score += PeerScore(gs * gossipScoreWeight)
+		}
 		peerScores[pid] = score
 		peerLogs = append(peerLogs, peerLog{
 			Peer:          pid,
@@ -21,7 +21,7 @@ func TestTagBestPeers(t *testing.T) {
 	allSubs, _ := records.Subnets{}.FromString(records.AllSubnets)
 	si := newSubnetsIndex(len(allSubs))
 
-	cm := NewConnManager(zap.NewNop(), connMgrMock, si).(*connManager)
+	cm := NewConnManager(zap.NewNop(), connMgrMock, si, newScoreIndex()).(*connManager)
 
 	pids, err := createPeerIDs(50)
 	require.NoError(t, err)
@@ -39,6 +39,40 @@ func TestTagBestPeers(t *testing.T) {
 	require.Equal(t, 20, len(connMgrMock.tags))
 }
 
+func TestGetBestPeersGossipScores(t *testing.T) {
+	connMgrMock := newConnMgr()
+
+	allSubs, _ := records.Subnets{}.FromString(records.AllSubnets)
+	si := newSubnetsIndex(len(allSubs))
+	scores := newScoreIndex()
+
+	cm := NewConnManager(zap.NewNop(), connMgrMock, si, scores).(*connManager)
+
+	pids, err := createPeerIDs(10)
+	require.NoError(t, err)
+	mySubnets := createRandomSubnets(10)
+	for _, pid := range pids {
+		si.UpdatePeerSubnets(pid, mySubnets.Clone())
+	}
+	require.NoError(t, scores.Score(pids[0], &NodeScore{Name: GossipScore, Value: badGossipScoreThreshold - 1}))
+	require.NoError(t, scores.Score(pids[1], &NodeScore{Name: GossipScore, Value: -4000}))
+	require.NoError(t, scores.Score(pids[2], &NodeScore{Name: GossipScore, Value: 100}))
+
+	best := cm.getBestPeers(8, mySubnets, pids, 10)
+	require.Len(t, best, 8)
+	_, ok := best[pids[0]]
+	require.False(t, ok, "peer with a bad gossip score should not be selected")
+	_, ok = best[pids[1]]
+	require.False(t, ok, "peer with a negative gossip score should be ranked last")
+	_, ok = best[pids[2]]
+	require.True(t, ok)
+
+	best = cm.getBestPeers(20, mySubnets, pids, 10)
+	require.Len(t, best, 9)
+	_, ok = best[pids[0]]
+	require.False(t, ok)
+}
+
 func createRandomSubnets(n int) records.Subnets {
 	subnets, _ := records.Subnets{}.FromString(records.ZeroSubnets)
 	size := len(subnets)
@@ -0,0 +1,49 @@
+package connections
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// OperatorsAllowlist keeps the peers that passed the permissioned handshake by their operator public key,
+// so they could be disconnected once the operator is removed from the registry.
+type OperatorsAllowlist struct {
+	lock  sync.Mutex
+	peers map[string]map[peer.ID]struct{}
+}
+
+// NewOperatorsAllowlist creates a new instance of OperatorsAllowlist
+func NewOperatorsAllowlist() *OperatorsAllowlist {
+	return &OperatorsAllowlist{
+		peers: make(map[string]map[peer.ID]struct{}),
+	}
+}
+
+// Add marks the given peer as allowed by the given operator public key
+func (a *OperatorsAllowlist) Add(operatorPubKey []byte, pid peer.ID) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	pids, ok := a.peers[string(operatorPubKey)]
+	if !ok {
+		pids = make(map[peer.ID]struct{})
+		a.peers[string(operatorPubKey)] = pids
+	}
+	pids[pid] = struct{}{}
+}
+
+// Revoke removes the given operator public key and returns the peers that were allowed by it
+func (a *OperatorsAllowlist) Revoke(operatorPubKey []byte) []peer.ID {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	pids := a.peers[string(operatorPubKey)]
+	delete(a.peers, string(operatorPubKey))
+
+	revoked := make([]peer.ID, 0, len(pids))
+	for pid := range pids {
+		revoked = append(revoked, pid)
+	}
+	return revoked
+}
@@ -0,0 +1,21 @@
+package connections
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorsAllowlist(t *testing.T) {
+	allowlist := NewOperatorsAllowlist()
+
+	allowlist.Add([]byte("operator-1"), peer.ID("peer-1"))
+	allowlist.Add([]byte("operator-1"), peer.ID("peer-2"))
+	allowlist.Add([]byte("operator-2"), peer.ID("peer-3"))
+
+	require.ElementsMatch(t, []peer.ID{"peer-1", "peer-2"}, allowlist.Revoke([]byte("operator-1")))
+	require.Empty(t, allowlist.Revoke([]byte("operator-1")))
+	require.Empty(t, allowlist.Revoke([]byte("operator-3")))
+	require.Equal(t, []peer.ID{"peer-3"}, allowlist.Revoke([]byte("operator-2")))
+}
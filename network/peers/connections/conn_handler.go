@@ -27,16 +27,19 @@ type connHandler struct {
 	subnetsProvider SubnetsProvider
 	subnetsIndex    peers.SubnetsIndex
 	connIdx         peers.ConnectionIndex
+	staticPeers     *peers.StaticPeers
 }
 
-// NewConnHandler creates a new connection handler
-func NewConnHandler(ctx context.Context, handshaker Handshaker, subnetsProvider SubnetsProvider, subnetsIndex peers.SubnetsIndex, connIdx peers.ConnectionIndex) ConnHandler {
+// NewConnHandler creates a new connection handler.
+// static peers (optional) bypass the peers limit and the subnets check.
+func NewConnHandler(ctx context.Context, handshaker Handshaker, subnetsProvider SubnetsProvider, subnetsIndex peers.SubnetsIndex, connIdx peers.ConnectionIndex, staticPeers *peers.StaticPeers) ConnHandler {
 	return &connHandler{
 		ctx:             ctx,
 		handshaker:      handshaker,
 		subnetsProvider: subnetsProvider,
 		subnetsIndex:    subnetsIndex,
 		connIdx:         connIdx,
+		staticPeers:     staticPeers,
 	}
 }
 
@@ -74,7 +77,13 @@ func (ch *connHandler) Handle(logger *zap.Logger) *libp2pnetwork.NotifyBundle {
 			disconnect(net, conn)
 			return err
 		}
-		if ch.connIdx.Limit(conn.Stat().Direction) {
+		if ch.staticPeers.IsStatic(id) {
+			metricsConnections.Inc()
+			return nil
+		}
+		// peers of weak subnets are temporarily allowed above the limit,
+		// they will be trimmed once the subnets are no longer weak (see peers.ConnManager)
+		if ch.connIdx.Limit(conn.Stat().Direction) && !ch.sharesWeakSubnets(conn) {
 			disconnect(net, conn)
 			return errors.New("reached peers limit")
 		}
@@ -139,6 +148,22 @@ func (ch *connHandler) handshake(logger *zap.Logger, conn libp2pnetwork.Conn) (b
 	return true, nil
 }
 
+// sharesWeakSubnets checks if the peer is interested in one of our weak subnets
+func (ch *connHandler) sharesWeakSubnets(conn libp2pnetwork.Conn) bool {
+	subnets := ch.subnetsIndex.GetPeerSubnets(conn.RemotePeer())
+	if len(subnets) == 0 {
+		return false
+	}
+	// the given peer is already counted as connected, therefore the threshold is increased by one
+	weak := peers.WeakSubnets(ch.subnetsIndex.GetSubnetsStats(), peers.MinSubnetPeers+1, ch.subnetsProvider())
+	for _, subnet := range weak {
+		if subnet < len(subnets) && subnets[subnet] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (ch *connHandler) checkSubnets(logger *zap.Logger, conn libp2pnetwork.Conn) bool {
 	pid := conn.RemotePeer()
 	subnets := ch.subnetsIndex.GetPeerSubnets(pid)
@@ -151,7 +176,7 @@ func (ch *connHandler) checkSubnets(logger *zap.Logger, conn libp2pnetwork.Conn)
 	logger = logger.With(fields.PeerID(pid), fields.Subnets(subnets),
 		zap.String("mySubnets", mySubnets.String()))
 
-	if mySubnets.String() == records.ZeroSubnets { // this node has no subnets
+	if mySubnets.Active() == 0 { // this node has no subnets
 		return true
 	}
 	shared := records.SharedSubnets(mySubnets, subnets, 1)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/bloxapp/ssv/network/peers"
 	"github.com/bloxapp/ssv/network/records"
 	"github.com/bloxapp/ssv/operator/storage"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
@@ -28,6 +29,16 @@ func NetworkIDFilter(networkID string) HandshakeFilter {
 	}
 }
 
+// TrustedPeersExemption wraps the given filter so it is skipped for trusted static peers
+func TrustedPeersExemption(staticPeers *peers.StaticPeers, filter HandshakeFilter) HandshakeFilter {
+	return func(sender peer.ID, ani records.AnyNodeInfo) error {
+		if staticPeers.IsTrusted(sender) {
+			return nil
+		}
+		return filter(sender, ani)
+	}
+}
+
 func SenderRecipientIPsCheckFilter(me peer.ID) HandshakeFilter { // for some reason we're loosing 'me' value
 	return func(sender peer.ID, ani records.AnyNodeInfo) error {
 		sni, ok := ani.(*records.SignedNodeInfo)
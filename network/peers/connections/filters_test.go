@@ -4,8 +4,11 @@ import (
 	"testing"
 
 	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/network/peers"
 	"github.com/bloxapp/ssv/network/peers/connections/mock"
 	"github.com/bloxapp/ssv/network/records"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -105,3 +108,22 @@ func TestRegisteredOperatorsFilter_ConfigWhitelist(t *testing.T) {
 	err = f("", &records.SignedNodeInfo{NodeInfo: &records.NodeInfo{}, HandshakeData: wrongSenderPubKeyPem, Signature: td.Signature})
 	require.Error(t, err)
 }
+
+func TestTrustedPeersExemption(t *testing.T) {
+	td := getTestingData(t)
+
+	staticPeers := peers.NewStaticPeers()
+	staticPeers.Add(peer.AddrInfo{ID: td.SenderPeerID}, true)
+
+	rejectAll := func(sender peer.ID, ani records.AnyNodeInfo) error {
+		return errors.New("rejected")
+	}
+	f := TrustedPeersExemption(staticPeers, rejectAll)
+
+	require.NoError(t, f(td.SenderPeerID, &records.SignedNodeInfo{}))
+	require.Error(t, f(td.RecipientPeerID, &records.SignedNodeInfo{}))
+
+	// static peers that are not trusted are still filtered
+	staticPeers.Add(peer.AddrInfo{ID: td.RecipientPeerID}, false)
+	require.Error(t, f(td.RecipientPeerID, &records.SignedNodeInfo{}))
+}
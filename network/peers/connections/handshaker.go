@@ -2,6 +2,7 @@ package connections
 
 import (
 	"context"
+	"crypto/rsa"
 	"time"
 
 	"github.com/bloxapp/ssv/logging/fields"
@@ -32,6 +33,9 @@ var errConsumingMessage = errors.New("error consuming message")
 // errPeerPruned is thrown when remote peer is pruned
 var errPeerPruned = errors.New("peer is pruned")
 
+// errOperatorKeyNotFound is thrown when the operator private key that signs the node info is not found
+var errOperatorKeyNotFound = errors.New("operator private key was not found")
+
 // HandshakeFilter can be used to filter nodes once we handshaked with them
 type HandshakeFilter func(senderID peer.ID, sni records.AnyNodeInfo) error
 
@@ -62,6 +66,8 @@ type handshaker struct {
 	ids         identify.IDService
 	net         libp2pnetwork.Network
 	nodeStorage storage.Storage
+	allowlist   *OperatorsAllowlist
+	staticPeers *peers.StaticPeers
 
 	subnetsProvider SubnetsProvider
 }
@@ -78,6 +84,10 @@ type HandshakerCfg struct {
 	NodeStorage     storage.Storage
 	SubnetsProvider SubnetsProvider
 	Permissioned    func() bool
+	// Allowlist (optional) tracks the operators of peers that passed the permissioned handshake
+	Allowlist *OperatorsAllowlist
+	// StaticPeers (optional) are handshaked even if they were pruned, so they could always be redialed
+	StaticPeers *peers.StaticPeers
 }
 
 // NewHandshaker creates a new instance of handshaker
@@ -95,6 +105,8 @@ func NewHandshaker(ctx context.Context, cfg *HandshakerCfg, filters func() []Han
 		net:             cfg.Network,
 		nodeStorage:     cfg.NodeStorage,
 		Permissioned:    cfg.Permissioned,
+		allowlist:       cfg.Allowlist,
+		staticPeers:     cfg.StaticPeers,
 	}
 	return h
 }
@@ -149,8 +161,8 @@ func (h *handshaker) Handler(logger *zap.Logger) libp2pnetwork.StreamHandler {
 			}
 		}()
 
-		privateKey, found, err := h.nodeStorage.GetPrivateKey()
-		if !found {
+		privateKey, err := h.operatorPrivateKey()
+		if err != nil {
 			logger.Warn("could not get private key", zap.Error(err))
 			return
 		}
@@ -177,6 +189,9 @@ func (h *handshaker) processIncomingNodeInfo(logger *zap.Logger, sender peer.ID,
 	if _, err := h.nodeInfoIdx.AddNodeInfo(logger, sender, ani.GetNodeInfo()); err != nil {
 		return err
 	}
+	if sni, ok := ani.(*records.SignedNodeInfo); ok && h.allowlist != nil {
+		h.allowlist.Add(sni.HandshakeData.SenderPublicKey, sender)
+	}
 	return nil
 }
 
@@ -234,6 +249,10 @@ func (h *handshaker) getNodeInfo(pid peer.ID) (*records.NodeInfo, error) {
 		case peers.StateIndexing:
 			return nil, errHandshakeInProcess
 		case peers.StatePruned:
+			if h.staticPeers.IsStatic(pid) {
+				// handshake again with static peers, e.g. if they were pruned before they became static
+				return nil, nil
+			}
 			return nil, errors.Wrap(errPeerPruned, pid.String())
 		case peers.StateReady:
 			return ni, nil
@@ -266,8 +285,8 @@ func (h *handshaker) nodeInfoFromStream(logger *zap.Logger, conn libp2pnetwork.C
 
 	permissioned := h.Permissioned()
 
-	privateKey, found, err := h.nodeStorage.GetPrivateKey()
-	if !found {
+	privateKey, err := h.operatorPrivateKey()
+	if err != nil {
 		return nil, err
 	}
 	data, err := h.nodeInfoIdx.SelfSealed(h.net.LocalPeer(), conn.RemotePeer(), permissioned, privateKey)
@@ -300,6 +319,18 @@ func (h *handshaker) nodeInfoFromStream(logger *zap.Logger, conn libp2pnetwork.C
 	return ani, nil
 }
 
+// operatorPrivateKey returns the operator private key that signs the node info
+func (h *handshaker) operatorPrivateKey() (*rsa.PrivateKey, error) {
+	privateKey, found, err := h.nodeStorage.GetPrivateKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get operator private key")
+	}
+	if !found {
+		return nil, errOperatorKeyNotFound
+	}
+	return privateKey, nil
+}
+
 func (h *handshaker) applyFilters(sender peer.ID, ani records.AnyNodeInfo) error {
 	fltrs := h.filters()
 	for i := range fltrs {
@@ -2,6 +2,7 @@ package connections
 
 import (
 	"context"
+	"crypto/rsa"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
 	"testing"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/bloxapp/ssv/network/peers"
 	"github.com/bloxapp/ssv/network/peers/connections/mock"
 	"github.com/bloxapp/ssv/network/records"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,6 +65,12 @@ func TestHandshakeTestData(t *testing.T) {
 		require.Error(t, td.Handshaker.Handshake(logging.TestLogger(t), td.Conn))
 	})
 
+	t.Run("missing operator key", func(t *testing.T) {
+		td := getTestingData(t)
+		td.Handshaker.nodeStorage = keylessNodeStorage{}
+		require.ErrorIs(t, td.Handshaker.Handshake(logging.TestLogger(t), td.Conn), errOperatorKeyNotFound)
+	})
+
 	t.Run("wrong StreamController", func(t *testing.T) {
 		td := getTestingData(t)
 		td.Handshaker.streams = mock.StreamController{}
@@ -117,6 +125,22 @@ func TestHandshakePeerIsKnownFlow(t *testing.T) {
 	}
 }
 
+// TestHandshakePrunedStaticPeer tests that pruned static peers are handshaked again
+func TestHandshakePrunedStaticPeer(t *testing.T) {
+	td := getTestingData(t)
+	td.Handshaker.nodeInfoIdx = mock.NodeInfoIndex{
+		MockNodeInfo: td.NodeInfo,
+	}
+	td.Handshaker.states = mock.NodeStates{
+		MockNodeState: peers.StatePruned,
+	}
+	require.ErrorIs(t, td.Handshaker.Handshake(logging.TestLogger(t), td.Conn), errPeerPruned)
+
+	td.Handshaker.staticPeers = peers.NewStaticPeers()
+	td.Handshaker.staticPeers.Add(peer.AddrInfo{ID: td.Conn.RemotePeer()}, false)
+	require.NotErrorIs(t, td.Handshaker.Handshake(logging.TestLogger(t), td.Conn), errPeerPruned)
+}
+
 // TestHandshakePermissionedFlow tests Handshake() Permissioned flow
 func TestHandshakePermissionedFlow(t *testing.T) {
 	td := getTestingData(t)
@@ -275,3 +299,12 @@ func TestHandshakeProcessIncomingNodeInfoFlow(t *testing.T) {
 		require.Equal(t, td.Handshaker.Handshake(logging.TestLogger(t), td.Conn).Error(), "AddNodeInfo error")
 	})
 }
+
+// keylessNodeStorage is a node storage that doesn't hold an operator private key
+type keylessNodeStorage struct {
+	mock.NodeStorage
+}
+
+func (keylessNodeStorage) GetPrivateKey() (*rsa.PrivateKey, bool, error) {
+	return nil, false, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/bloxapp/ssv/protocol/v2/types"
 	registrystorage "github.com/bloxapp/ssv/registry/storage"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -32,6 +33,26 @@ func (m NodeStorage) GetSyncOffset() (*eth1.SyncOffset, bool, error) {
 	panic("implement me")
 }
 
+func (m NodeStorage) SaveRecentLogs(logs []ethtypes.Log) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) GetRecentLogs() ([]ethtypes.Log, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) SaveEventJournal(entries []*storage.EventJournalEntry) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) GetEventJournal() ([]*storage.EventJournalEntry, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m NodeStorage) CleanRegistryData() error {
 	//TODO implement me
 	panic("implement me")
@@ -139,3 +160,13 @@ func (m NodeStorage) SetupPrivateKey(logger *zap.Logger, operatorKeyBase64 strin
 	//TODO implement me
 	panic("implement me")
 }
+
+func (m NodeStorage) SetupKeystorePrivateKey(logger *zap.Logger, sk *rsa.PrivateKey) ([]byte, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m NodeStorage) DeletePrivateKey() error {
+	//TODO implement me
+	panic("implement me")
+}
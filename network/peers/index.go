@@ -98,6 +98,15 @@ type SubnetsIndex interface {
 	GetSubnetsStats() *SubnetsStats
 }
 
+// ReputationIndex is an interface for persisting the reputation of peers across restarts
+type ReputationIndex interface {
+	// LoadReputation restores the reputation of peers from the given store,
+	// which is used from now on to persist the reputation of peers
+	LoadReputation(logger *zap.Logger, store *ReputationStore) error
+	// SaveReputation saves the reputation of all known peers and removes expired records
+	SaveReputation(logger *zap.Logger)
+}
+
 // Index is a facade interface of this package
 type Index interface {
 	ConnectionIndex
@@ -105,5 +114,6 @@ type Index interface {
 	NodeStates
 	ScoreIndex
 	SubnetsIndex
+	ReputationIndex
 	io.Closer
 }
@@ -124,14 +124,45 @@ func (ns *nodeStates) pruned(pid string) bool {
 	return false
 }
 
+// prunedAt returns the time in which the given peer was pruned, or false if it is not pruned
+func (ns *nodeStates) prunedAt(pid string) (time.Time, bool) {
+	ns.statesLock.RLock()
+	defer ns.statesLock.RUnlock()
+
+	so, ok := ns.states[pid]
+	if !ok || so.state != StatePruned {
+		return time.Time{}, false
+	}
+	return so.time, true
+}
+
+// prunedPeers returns the peers in pruned state
+func (ns *nodeStates) prunedPeers() []string {
+	ns.statesLock.RLock()
+	defer ns.statesLock.RUnlock()
+
+	var pids []string
+	for pid, so := range ns.states {
+		if so.state == StatePruned {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
 // setState updates the NodeState of the peer
 func (ns *nodeStates) setState(pid string, state NodeState) {
+	ns.setStateAt(pid, state, time.Now())
+}
+
+// setStateAt updates the NodeState of the peer with the given time
+func (ns *nodeStates) setStateAt(pid string, state NodeState, t time.Time) {
 	ns.statesLock.Lock()
 	defer ns.statesLock.Unlock()
 
 	so := nodeStateObj{
 		state: state,
-		time:  time.Now(),
+		time:  t,
 	}
 	ns.states[pid] = so
 }
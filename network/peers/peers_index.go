@@ -34,10 +34,19 @@ type peersIndex struct {
 	network        libp2pnetwork.Network
 
 	states        *nodeStates
-	scoreIdx      ScoreIndex
-	subnets       SubnetsIndex
+	scoreIdx      *scoresIndex
+	subnets       *subnetsIndex
 	nodeInfoStore *nodeInfoStore
 
+	// staticPeers are never pruned or considered as bad
+	staticPeers *StaticPeers
+
+	reputationLock *sync.RWMutex
+	reputation     *ReputationStore
+	// updatedAt holds the last time in which the reputation of each peer was updated,
+	// records of peers that were not updated within the TTL of the store are not saved again
+	updatedAt map[peer.ID]time.Time
+
 	selfLock *sync.RWMutex
 	self     *records.NodeInfo
 
@@ -46,13 +55,16 @@ type peersIndex struct {
 
 // NewPeersIndex creates a new Index
 func NewPeersIndex(logger *zap.Logger, network libp2pnetwork.Network, self *records.NodeInfo, maxPeers MaxPeersProvider,
-	netKeyProvider NetworkKeyProvider, subnetsCount int, pruneTTL time.Duration) *peersIndex {
+	netKeyProvider NetworkKeyProvider, subnetsCount int, pruneTTL time.Duration, staticPeers *StaticPeers) *peersIndex {
 	return &peersIndex{
 		network:        network,
 		states:         newNodeStates(pruneTTL),
 		scoreIdx:       newScoreIndex(),
 		subnets:        newSubnetsIndex(subnetsCount),
 		nodeInfoStore:  newNodeInfoStore(network),
+		staticPeers:    staticPeers,
+		reputationLock: &sync.RWMutex{},
+		updatedAt:      make(map[peer.ID]time.Time),
 		self:           self,
 		selfLock:       &sync.RWMutex{},
 		maxPeers:       maxPeers,
@@ -63,24 +75,24 @@ func NewPeersIndex(logger *zap.Logger, network libp2pnetwork.Network, self *reco
 // IsBad returns whether the given peer is bad.
 // a peer is considered to be bad if one of the following applies:
 // - pruned (that was not expired)
-// - bad score
+// - gossipsub score below the graylist threshold
+// - too many sync violations (invalid sync requests)
+// static peers are never considered as bad.
 func (pi *peersIndex) IsBad(logger *zap.Logger, id peer.ID) bool {
+	if pi.staticPeers.IsStatic(id) {
+		return false
+	}
 	if pi.states.pruned(id.String()) {
 		logger.Debug("bad peer (pruned)")
 		return true
 	}
-	// TODO: check scores
-	threshold := -10000.0
-	scores, err := pi.GetScore(id, "")
-	if err != nil {
-		// logger.Debug("could not read score", zap.Error(err))
-		return false
+	if hasBadGossipScore(pi.scoreIdx, id) {
+		logger.Debug("bad peer (low score)")
+		return true
 	}
-	for _, score := range scores {
-		if score.Value < threshold {
-			logger.Debug("bad peer (low score)")
-			return true
-		}
+	if hasTooManySyncViolations(pi.scoreIdx, id) {
+		logger.Debug("bad peer (sync violations)")
+		return true
 	}
 	return false
 }
@@ -214,7 +226,11 @@ func (pi *peersIndex) State(id peer.ID) NodeState {
 
 // Score adds score to the given peer
 func (pi *peersIndex) Score(id peer.ID, scores ...*NodeScore) error {
-	return pi.scoreIdx.Score(id, scores...)
+	if err := pi.scoreIdx.Score(id, scores...); err != nil {
+		return err
+	}
+	pi.setUpdatedAt(id, time.Now())
+	return nil
 }
 
 // GetScore returns the desired score for the given peer
@@ -234,14 +250,155 @@ func (pi *peersIndex) GetScore(id peer.ID, names ...string) ([]NodeScore, error)
 	return pi.scoreIdx.GetScore(id, names...)
 }
 
-// Prune set prune state for the given peer
+// Prune set prune state for the given peer, static peers are not pruned
 func (pi *peersIndex) Prune(id peer.ID) error {
-	return pi.states.Prune(id)
+	if pi.staticPeers.IsStatic(id) {
+		return nil
+	}
+	if err := pi.states.Prune(id); err != nil {
+		return err
+	}
+	pi.setUpdatedAt(id, time.Now())
+	// prune state is saved right away, so it won't be lost on a restart
+	return pi.saveReputation(id)
 }
 
 // EvictPruned changes to ready state instead of pruned
 func (pi *peersIndex) EvictPruned(id peer.ID) {
 	pi.states.EvictPruned(id)
+	pi.setUpdatedAt(id, time.Now())
+	_ = pi.saveReputation(id)
+}
+
+// LoadReputation restores the reputation of peers from the given store,
+// which is used from now on to persist the reputation of peers.
+func (pi *peersIndex) LoadReputation(logger *zap.Logger, store *ReputationStore) error {
+	pi.reputationLock.Lock()
+	pi.reputation = store
+	pi.reputationLock.Unlock()
+
+	reps, err := store.GetAll(logger)
+	if err != nil {
+		return errors.Wrap(err, "could not load peers reputation")
+	}
+	for _, rep := range reps {
+		id, err := peer.Decode(rep.PeerID)
+		if err != nil {
+			logger.Debug("could not decode peer id of reputation record", zap.String("peerID", rep.PeerID), zap.Error(err))
+			continue
+		}
+		// the loaded record keeps its update time, so it expires unless the reputation of the peer is updated again
+		pi.setUpdatedAt(id, rep.UpdatedAt)
+		if rep.Pruned() {
+			pi.states.setStateAt(rep.PeerID, StatePruned, rep.PrunedAt)
+		}
+		if len(rep.Scores) > 0 {
+			scores := make([]*NodeScore, len(rep.Scores))
+			for i := range rep.Scores {
+				scores[i] = &rep.Scores[i]
+			}
+			_ = pi.scoreIdx.Score(id, scores...)
+		}
+		if len(rep.Subnets) > 0 {
+			subnets, err := records.Subnets{}.FromString(rep.Subnets)
+			if err != nil {
+				logger.Debug("could not parse subnets of reputation record", zap.String("peerID", rep.PeerID), zap.Error(err))
+				continue
+			}
+			pi.subnets.UpdatePeerSubnets(id, subnets)
+		}
+	}
+	logger.Debug("loaded peers reputation", zap.Int("count", len(reps)))
+	return nil
+}
+
+// SaveReputation saves the reputation of all known peers and removes expired records.
+func (pi *peersIndex) SaveReputation(logger *zap.Logger) {
+	store := pi.reputationStore()
+	if store == nil {
+		return
+	}
+	ids := make(map[peer.ID]struct{})
+	for _, pid := range pi.states.prunedPeers() {
+		id, err := peer.Decode(pid)
+		if err != nil {
+			continue
+		}
+		ids[id] = struct{}{}
+	}
+	for _, id := range pi.scoreIdx.peers() {
+		ids[id] = struct{}{}
+	}
+	for _, id := range pi.subnets.peers() {
+		ids[id] = struct{}{}
+	}
+	reps := make([]*PeerReputation, 0, len(ids))
+	for id := range ids {
+		reps = append(reps, pi.reputationOf(id))
+	}
+	if err := store.Save(reps...); err != nil {
+		logger.Warn("could not save peers reputation", zap.Error(err))
+		return
+	}
+	removed, err := store.GC(logger)
+	if err != nil {
+		logger.Warn("could not remove expired peers reputation", zap.Error(err))
+		return
+	}
+	logger.Debug("saved peers reputation", zap.Int("count", len(reps)), zap.Int("expired", removed))
+}
+
+// saveReputation saves the reputation of the given peer
+func (pi *peersIndex) saveReputation(id peer.ID) error {
+	store := pi.reputationStore()
+	if store == nil {
+		return nil
+	}
+	if err := store.Save(pi.reputationOf(id)); err != nil {
+		return errors.Wrap(err, "could not save peer reputation")
+	}
+	return nil
+}
+
+// reputationOf creates a reputation record of the given peer from the current state of the index
+func (pi *peersIndex) reputationOf(id peer.ID) *PeerReputation {
+	rep := &PeerReputation{
+		PeerID:    id.String(),
+		Scores:    pi.scoreIdx.all(id),
+		UpdatedAt: pi.lastUpdated(id),
+	}
+	if prunedAt, ok := pi.states.prunedAt(rep.PeerID); ok {
+		rep.PrunedAt = prunedAt
+	}
+	if subnets := pi.subnets.GetPeerSubnets(id); len(subnets) > 0 {
+		rep.Subnets = subnets.String()
+	}
+	return rep
+}
+
+// setUpdatedAt sets the time in which the reputation of the given peer was updated
+func (pi *peersIndex) setUpdatedAt(id peer.ID, t time.Time) {
+	pi.reputationLock.Lock()
+	defer pi.reputationLock.Unlock()
+
+	if t.After(pi.updatedAt[id]) {
+		pi.updatedAt[id] = t
+	}
+}
+
+// lastUpdated returns the time in which the reputation of the given peer was last updated
+func (pi *peersIndex) lastUpdated(id peer.ID) time.Time {
+	pi.reputationLock.RLock()
+	defer pi.reputationLock.RUnlock()
+
+	return pi.updatedAt[id]
+}
+
+func (pi *peersIndex) reputationStore() *ReputationStore {
+	pi.reputationLock.RLock()
+	defer pi.reputationLock.RUnlock()
+
+	return pi.reputation
 }
 
 // GC does garbage collection on current peers and states
@@ -250,7 +407,11 @@ func (pi *peersIndex) GC() {
 }
 
 func (pi *peersIndex) UpdatePeerSubnets(id peer.ID, s records.Subnets) bool {
-	return pi.subnets.UpdatePeerSubnets(id, s)
+	updated := pi.subnets.UpdatePeerSubnets(id, s)
+	if updated {
+		pi.setUpdatedAt(id, time.Now())
+	}
+	return updated
 }
 
 func (pi *peersIndex) GetSubnetPeers(subnet int) []peer.ID {
@@ -274,7 +435,11 @@ func (pi *peersIndex) GetSubnetsStats() *SubnetsStats {
 	var sumConnected int
 	for subnet, count := range stats.PeersCount {
 		metricsSubnetsKnownPeers.WithLabelValues(strconv.Itoa(subnet)).Set(float64(count))
-		metricsMySubnets.WithLabelValues(strconv.Itoa(subnet)).Set(float64(mySubnets[subnet]))
+		var mySubnet byte
+		if subnet < len(mySubnets) {
+			mySubnet = mySubnets[subnet]
+		}
+		metricsMySubnets.WithLabelValues(strconv.Itoa(subnet)).Set(float64(mySubnet))
 		peers := pi.subnets.GetSubnetPeers(subnet)
 		connectedCount := 0
 		for _, p := range peers {
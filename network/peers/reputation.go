@@ -0,0 +1,139 @@
+package peers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	// reputationPrefix is the db prefix of peers reputation records
+	reputationPrefix = []byte("peers-reputation/")
+)
+
+// PeerReputation is the persisted reputation of a peer
+type PeerReputation struct {
+	PeerID string `json:"peer_id"`
+	// PrunedAt is the time in which the peer was pruned, zero if it wasn't pruned
+	PrunedAt time.Time   `json:"pruned_at"`
+	Scores   []NodeScore `json:"scores,omitempty"`
+	Subnets  string      `json:"subnets,omitempty"`
+	// UpdatedAt is the last time in which the reputation of the peer was updated
+	UpdatedAt time.Time `json:"updated_at"`
+	// ExpiresAt is the time after which the record is ignored and removed, which is ttl after UpdatedAt
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Pruned returns whether the peer was pruned
+func (pr *PeerReputation) Pruned() bool {
+	return !pr.PrunedAt.IsZero()
+}
+
+// ReputationStore persists the reputation of peers (scores, prune state and subnets),
+// so it survives restarts of the node.
+type ReputationStore struct {
+	db  basedb.IDb
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewReputationStore creates a new ReputationStore.
+// ttl is the duration in which saved records are kept.
+func NewReputationStore(db basedb.IDb, ttl time.Duration) *ReputationStore {
+	return &ReputationStore{
+		db:  db,
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// Save saves the given records, their expiry is set according to the time in which they were updated.
+// records without an update time are considered as updated now, while records that already expired are not saved.
+func (rs *ReputationStore) Save(reps ...*PeerReputation) error {
+	now := rs.now()
+	toSave := make([]*PeerReputation, 0, len(reps))
+	for _, rep := range reps {
+		if rep.UpdatedAt.IsZero() {
+			rep.UpdatedAt = now
+		}
+		rep.ExpiresAt = rep.UpdatedAt.Add(rs.ttl)
+		if !rs.expired(rep) {
+			toSave = append(toSave, rep)
+		}
+	}
+	if len(toSave) == 0 {
+		return nil
+	}
+	return rs.db.SetMany(reputationPrefix, len(toSave), func(i int) (basedb.Obj, error) {
+		rep := toSave[i]
+		raw, err := json.Marshal(rep)
+		if err != nil {
+			return basedb.Obj{}, errors.Wrap(err, "could not marshal peer reputation")
+		}
+		return basedb.Obj{Key: []byte(rep.PeerID), Value: raw}, nil
+	})
+}
+
+// Get returns the record of the given peer, expired records are not returned
+func (rs *ReputationStore) Get(pid string) (*PeerReputation, bool, error) {
+	obj, found, err := rs.db.Get(reputationPrefix, []byte(pid))
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, nil
+	}
+	rep := new(PeerReputation)
+	if err := json.Unmarshal(obj.Value, rep); err != nil {
+		return nil, false, errors.Wrap(err, "could not unmarshal peer reputation")
+	}
+	if rs.expired(rep) {
+		return nil, false, nil
+	}
+	return rep, true, nil
+}
+
+// GetAll returns all the records that didn't expire
+func (rs *ReputationStore) GetAll(logger *zap.Logger) ([]*PeerReputation, error) {
+	var reps []*PeerReputation
+	err := rs.db.GetAll(logger, reputationPrefix, func(i int, obj basedb.Obj) error {
+		rep := new(PeerReputation)
+		if err := json.Unmarshal(obj.Value, rep); err != nil {
+			logger.Debug("could not unmarshal peer reputation", zap.Error(err))
+			return nil
+		}
+		if !rs.expired(rep) {
+			reps = append(reps, rep)
+		}
+		return nil
+	})
+	return reps, err
+}
+
+// GC removes expired (or corrupted) records, returns the amount of removed records
+func (rs *ReputationStore) GC(logger *zap.Logger) (int, error) {
+	var keys [][]byte
+	err := rs.db.GetAll(logger, reputationPrefix, func(i int, obj basedb.Obj) error {
+		rep := new(PeerReputation)
+		if err := json.Unmarshal(obj.Value, rep); err != nil || rs.expired(rep) {
+			keys = append(keys, obj.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range keys {
+		if err := rs.db.Delete(reputationPrefix, key); err != nil {
+			return 0, errors.Wrap(err, "could not delete peer reputation")
+		}
+	}
+	return len(keys), nil
+}
+
+func (rs *ReputationStore) expired(rep *PeerReputation) bool {
+	return !rep.ExpiresAt.After(rs.now())
+}
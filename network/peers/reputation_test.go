@@ -0,0 +1,156 @@
+package peers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/network/records"
+	ssvstorage "github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReputationStore(t *testing.T, ttl time.Duration) *ReputationStore {
+	db, err := ssvstorage.GetStorageFactory(logging.TestLogger(t), basedb.Options{
+		Type: "badger-memory",
+		Path: "",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close(logging.TestLogger(t))
+	})
+	return NewReputationStore(db, ttl)
+}
+
+func newTestIndex() *peersIndex {
+	return &peersIndex{
+		states:         newNodeStates(10 * time.Minute),
+		scoreIdx:       newScoreIndex(),
+		subnets:        newSubnetsIndex(128),
+		reputationLock: &sync.RWMutex{},
+		updatedAt:      make(map[peer.ID]time.Time),
+	}
+}
+
+func TestReputationStore(t *testing.T) {
+	logger := logging.TestLogger(t)
+	store := newTestReputationStore(t, time.Hour)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Save(&PeerReputation{
+		PeerID:   "peer-1",
+		PrunedAt: now,
+		Scores:   []NodeScore{{Name: SyncViolations, Value: 3}},
+	}, &PeerReputation{
+		PeerID: "peer-2",
+	}))
+
+	rep, found, err := store.Get("peer-1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, rep.Pruned())
+	require.Len(t, rep.Scores, 1)
+	require.Equal(t, now.Add(time.Hour).Unix(), rep.ExpiresAt.Unix())
+
+	reps, err := store.GetAll(logger)
+	require.NoError(t, err)
+	require.Len(t, reps, 2)
+
+	t.Run("expiry", func(t *testing.T) {
+		now = now.Add(30 * time.Minute)
+		require.NoError(t, store.Save(&PeerReputation{PeerID: "peer-2"}))
+		now = now.Add(45 * time.Minute)
+
+		_, found, err := store.Get("peer-1")
+		require.NoError(t, err)
+		require.False(t, found)
+
+		reps, err := store.GetAll(logger)
+		require.NoError(t, err)
+		require.Len(t, reps, 1)
+		require.Equal(t, "peer-2", reps[0].PeerID)
+
+		removed, err := store.GC(logger)
+		require.NoError(t, err)
+		require.Equal(t, 1, removed)
+	})
+}
+
+func TestPeersIndex_Reputation(t *testing.T) {
+	logger := logging.TestLogger(t)
+	store := newTestReputationStore(t, time.Hour)
+	pids, err := createPeerIDs(3)
+	require.NoError(t, err)
+
+	idx := newTestIndex()
+	require.NoError(t, idx.LoadReputation(logger, store))
+	require.NoError(t, idx.Prune(pids[0]))
+	require.NoError(t, idx.Score(pids[1], &NodeScore{Name: SyncViolations, Value: MaxSyncViolations}))
+	subnets := records.AllSubnetsOf(128)
+	idx.UpdatePeerSubnets(pids[2], subnets)
+
+	// prune state is saved right away
+	rep, found, err := store.Get(pids[0].String())
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, rep.Pruned())
+
+	idx.SaveReputation(logger)
+
+	// simulate a restart
+	restored := newTestIndex()
+	require.NoError(t, restored.LoadReputation(logger, store))
+	require.True(t, restored.IsBad(logger, pids[0]))
+	require.True(t, restored.IsBad(logger, pids[1]))
+	require.False(t, restored.IsBad(logger, pids[2]))
+	require.Equal(t, subnets.String(), restored.GetPeerSubnets(pids[2]).String())
+
+	// evicted peers are saved right away
+	restored.EvictPruned(pids[0])
+	rep, found, err = store.Get(pids[0].String())
+	require.NoError(t, err)
+	require.True(t, found)
+	require.False(t, rep.Pruned())
+}
+
+func TestPeersIndex_ReputationExpiry(t *testing.T) {
+	logger := logging.TestLogger(t)
+	store := newTestReputationStore(t, time.Hour)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	pids, err := createPeerIDs(1)
+	require.NoError(t, err)
+
+	idx := newTestIndex()
+	require.NoError(t, idx.LoadReputation(logger, store))
+	require.NoError(t, idx.Score(pids[0], &NodeScore{Name: SyncViolations, Value: MaxSyncViolations}))
+	idx.SaveReputation(logger)
+
+	// restart, the record is loaded but the peer is not seen again
+	restored := newTestIndex()
+	require.NoError(t, restored.LoadReputation(logger, store))
+	require.True(t, restored.IsBad(logger, pids[0]))
+
+	// saving the unchanged record doesn't extend its expiry
+	now = now.Add(45 * time.Minute)
+	restored.SaveReputation(logger)
+	rep, found, err := store.Get(pids[0].String())
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, now.Add(-45*time.Minute).Add(time.Hour).Unix(), rep.ExpiresAt.Unix())
+
+	now = now.Add(30 * time.Minute)
+	restored.SaveReputation(logger)
+
+	// restart again, the stale record expired
+	restored = newTestIndex()
+	require.NoError(t, restored.LoadReputation(logger, store))
+	_, found, err = store.Get(pids[0].String())
+	require.NoError(t, err)
+	require.False(t, found)
+	require.False(t, restored.IsBad(logger, pids[0]))
+}
@@ -1,19 +1,47 @@
 package peers
 
 import (
+	"math"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
+const (
+	// GossipScore is the name of the overall gossipsub score of a peer
+	GossipScore = "PS_Score"
+	// GossipBehaviourPenalty is the name of the gossipsub behaviour penalty of a peer
+	GossipBehaviourPenalty = "PS_BehaviourPenalty"
+	// GossipIPColocationFactor is the name of the gossipsub IP colocation factor of a peer
+	GossipIPColocationFactor = "PS_IPColocationFactor"
+	// GossipInvalidMessageDeliveries is the name of the amount of invalid messages that were delivered by a peer,
+	// summed over all topics
+	GossipInvalidMessageDeliveries = "PS_InvalidMessageDeliveries"
+	// SyncViolations is the name of the amount of invalid or rate limited sync requests that were sent by a peer
+	SyncViolations = "SyncViolations"
+
+	// MaxSyncViolations is the amount of sync violations after which a peer is considered bad
+	MaxSyncViolations = 10
+	// syncViolationsUpdated is the name of the time (unix milliseconds) in which the sync violations of a peer were last updated
+	syncViolationsUpdated = "SyncViolationsUpdated"
+	// syncViolationDecay is the time after which a single sync violation is forgiven,
+	// so only peers that keep sending invalid requests reach MaxSyncViolations
+	syncViolationDecay = time.Minute
+
+	// badGossipScoreThreshold is the gossipsub score below which a peer is considered bad,
+	// aligned with the graylist threshold of pubsub (see topics/params.PeerScoreThresholds)
+	badGossipScoreThreshold = -16000.0
+)
+
 // scoresIndex implements ScoreIndex
 type scoresIndex struct {
 	scores map[peer.ID][]*NodeScore
 	lock   *sync.RWMutex
 }
 
-func newScoreIndex() ScoreIndex {
+func newScoreIndex() *scoresIndex {
 	return &scoresIndex{
 		scores: map[peer.ID][]*NodeScore{},
 		lock:   &sync.RWMutex{},
@@ -64,6 +92,102 @@ wantedScoresLoop:
 	return scores, nil
 }
 
+// all returns a copy of all the scores of the given peer
+func (s *scoresIndex) all(id peer.ID) []NodeScore {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var scores []NodeScore
+	for _, score := range s.scores[id] {
+		scores = append(scores, *score)
+	}
+	return scores
+}
+
+// peers returns the peers that have scores
+func (s *scoresIndex) peers() []peer.ID {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	peers := make([]peer.ID, 0, len(s.scores))
+	for id := range s.scores {
+		peers = append(peers, id)
+	}
+	return peers
+}
+
+// gossipScore returns the gossipsub score of the given peer, or false if it wasn't scored yet
+func gossipScore(idx ScoreIndex, id peer.ID) (float64, bool) {
+	scores, err := idx.GetScore(id, GossipScore)
+	if err != nil || len(scores) == 0 {
+		return 0, false
+	}
+	return scores[0].Value, true
+}
+
+// hasBadGossipScore returns true if the gossipsub score of the given peer is below the bad peers threshold
+func hasBadGossipScore(idx ScoreIndex, id peer.ID) bool {
+	score, ok := gossipScore(idx, id)
+	return ok && score < badGossipScoreThreshold
+}
+
+// AddSyncViolation increments the sync violations of the given peer and returns the updated amount,
+// partially decayed violations are still counted
+func AddSyncViolation(idx ScoreIndex, id peer.ID) (int, error) {
+	return addSyncViolation(idx, id, time.Now())
+}
+
+func addSyncViolation(idx ScoreIndex, id peer.ID, now time.Time) (int, error) {
+	violations, err := syncViolations(idx, id, now)
+	if err != nil {
+		return 0, err
+	}
+	violations++
+	err = idx.Score(id,
+		&NodeScore{Name: SyncViolations, Value: violations},
+		&NodeScore{Name: syncViolationsUpdated, Value: float64(now.UnixMilli())})
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Ceil(violations)), nil
+}
+
+// syncViolations returns the sync violations of the given peer, after the decay since they were last updated.
+// violations without an update time (e.g. of an older reputation record) are not decayed
+func syncViolations(idx ScoreIndex, id peer.ID, now time.Time) (float64, error) {
+	scores, err := idx.GetScore(id, SyncViolations, syncViolationsUpdated)
+	if err != nil {
+		return 0, err
+	}
+	var violations float64
+	var updated time.Time
+	for _, score := range scores {
+		switch score.Name {
+		case SyncViolations:
+			violations = score.Value
+		case syncViolationsUpdated:
+			updated = time.UnixMilli(int64(score.Value))
+		}
+	}
+	if !updated.IsZero() && now.After(updated) {
+		violations -= float64(now.Sub(updated)) / float64(syncViolationDecay)
+	}
+	if violations < 0 {
+		violations = 0
+	}
+	return violations, nil
+}
+
+// hasTooManySyncViolations returns true if the given peer reached the max amount of sync violations
+func hasTooManySyncViolations(idx ScoreIndex, id peer.ID) bool {
+	violations, err := syncViolations(idx, id, time.Now())
+	if err != nil {
+		return false
+	}
+	// partially decayed violations are still counted
+	return math.Ceil(violations) >= MaxSyncViolations
+}
+
 // GetTopScores accepts a map of scores and returns the best n peers
 func GetTopScores(peerScores map[peer.ID]PeerScore, n int) map[peer.ID]PeerScore {
 	pl := make(peerScoresList, len(peerScores))
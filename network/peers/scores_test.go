@@ -3,6 +3,7 @@ package peers
 import (
 	crand "crypto/rand"
 	"testing"
+	"time"
 
 	"github.com/bloxapp/ssv/network/commons"
 	nettesting "github.com/bloxapp/ssv/network/testing"
@@ -35,6 +36,48 @@ func TestScoresIndex(t *testing.T) {
 	require.Len(t, scores, 2)
 }
 
+func TestSyncViolations(t *testing.T) {
+	pids, err := createPeerIDs(2)
+	require.NoError(t, err)
+
+	si := newScoreIndex()
+
+	for i := 1; i < MaxSyncViolations; i++ {
+		violations, err := AddSyncViolation(si, pids[0])
+		require.NoError(t, err)
+		require.Equal(t, i, violations)
+		require.False(t, hasTooManySyncViolations(si, pids[0]))
+	}
+	violations, err := AddSyncViolation(si, pids[0])
+	require.NoError(t, err)
+	require.Equal(t, MaxSyncViolations, violations)
+	require.True(t, hasTooManySyncViolations(si, pids[0]))
+	require.False(t, hasTooManySyncViolations(si, pids[1]))
+}
+
+func TestSyncViolationsDecay(t *testing.T) {
+	pids, err := createPeerIDs(1)
+	require.NoError(t, err)
+
+	si := newScoreIndex()
+	now := time.Now()
+	for i := 0; i < MaxSyncViolations-1; i++ {
+		_, err := addSyncViolation(si, pids[0], now)
+		require.NoError(t, err)
+	}
+
+	// violations that are spread over time don't add up to MaxSyncViolations
+	now = now.Add(5 * syncViolationDecay)
+	violations, err := addSyncViolation(si, pids[0], now)
+	require.NoError(t, err)
+	require.Equal(t, MaxSyncViolations-5, violations)
+
+	now = now.Add(time.Hour)
+	current, err := syncViolations(si, pids[0], now)
+	require.NoError(t, err)
+	require.Zero(t, current)
+}
+
 func TestPeersTopScores(t *testing.T) {
 	pids, err := createPeerIDs(50)
 	require.NoError(t, err)
@@ -0,0 +1,100 @@
+package peers
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// StaticPeerTag is the tag used to protect static peers in the connection manager
+	StaticPeerTag = "ssv/static"
+)
+
+// StaticPeers keeps the peers that the node should always be connected to.
+// trusted peers are static peers that are also exempt from permissioned handshake filtering.
+// all methods are safe to use on a nil instance (i.e. no static peers).
+type StaticPeers struct {
+	lock  sync.RWMutex
+	peers map[peer.ID]peer.AddrInfo
+	// trusted holds the static peers that are explicitly trusted
+	trusted map[peer.ID]struct{}
+}
+
+// NewStaticPeers creates a new instance of StaticPeers
+func NewStaticPeers() *StaticPeers {
+	return &StaticPeers{
+		peers:   make(map[peer.ID]peer.AddrInfo),
+		trusted: make(map[peer.ID]struct{}),
+	}
+}
+
+// Add adds the given static peer, or updates its addresses if it was already added
+func (sp *StaticPeers) Add(info peer.AddrInfo, trusted bool) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	if existing, ok := sp.peers[info.ID]; ok {
+		info.Addrs = append(existing.Addrs, info.Addrs...)
+	}
+	sp.peers[info.ID] = info
+	if trusted {
+		sp.trusted[info.ID] = struct{}{}
+	}
+}
+
+// IsStatic returns whether the given peer is a static peer
+func (sp *StaticPeers) IsStatic(id peer.ID) bool {
+	if sp == nil {
+		return false
+	}
+	sp.lock.RLock()
+	defer sp.lock.RUnlock()
+
+	_, ok := sp.peers[id]
+	return ok
+}
+
+// IsTrusted returns whether the given peer is a trusted static peer
+func (sp *StaticPeers) IsTrusted(id peer.ID) bool {
+	if sp == nil {
+		return false
+	}
+	sp.lock.RLock()
+	defer sp.lock.RUnlock()
+
+	_, ok := sp.trusted[id]
+	return ok
+}
+
+// Peers returns the static peers
+func (sp *StaticPeers) Peers() []peer.AddrInfo {
+	if sp == nil {
+		return nil
+	}
+	sp.lock.RLock()
+	defer sp.lock.RUnlock()
+
+	infos := make([]peer.AddrInfo, 0, len(sp.peers))
+	for _, info := range sp.peers {
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Filter returns the given peers without the static peers
+func (sp *StaticPeers) Filter(ids []peer.ID) []peer.ID {
+	if sp == nil {
+		return ids
+	}
+	sp.lock.RLock()
+	defer sp.lock.RUnlock()
+
+	filtered := make([]peer.ID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := sp.peers[id]; !ok {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
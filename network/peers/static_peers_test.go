@@ -0,0 +1,54 @@
+package peers
+
+import (
+	"testing"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticPeers(t *testing.T) {
+	pids, err := createPeerIDs(3)
+	require.NoError(t, err)
+
+	sp := NewStaticPeers()
+	sp.Add(peer.AddrInfo{ID: pids[0]}, false)
+	sp.Add(peer.AddrInfo{ID: pids[1]}, true)
+
+	require.True(t, sp.IsStatic(pids[0]))
+	require.False(t, sp.IsTrusted(pids[0]))
+	require.True(t, sp.IsStatic(pids[1]))
+	require.True(t, sp.IsTrusted(pids[1]))
+	require.False(t, sp.IsStatic(pids[2]))
+	require.Len(t, sp.Peers(), 2)
+	require.Equal(t, []peer.ID{pids[2]}, sp.Filter(pids))
+
+	t.Run("nil", func(t *testing.T) {
+		var sp *StaticPeers
+		require.False(t, sp.IsStatic(pids[0]))
+		require.False(t, sp.IsTrusted(pids[0]))
+		require.Len(t, sp.Peers(), 0)
+		require.Equal(t, pids, sp.Filter(pids))
+	})
+}
+
+func TestPeersIndex_StaticPeers(t *testing.T) {
+	logger := logging.TestLogger(t)
+	pids, err := createPeerIDs(2)
+	require.NoError(t, err)
+
+	sp := NewStaticPeers()
+	sp.Add(peer.AddrInfo{ID: pids[0]}, false)
+	idx := newTestIndex()
+	idx.staticPeers = sp
+
+	require.NoError(t, idx.Prune(pids[0]))
+	require.NoError(t, idx.Prune(pids[1]))
+	require.NotEqual(t, StatePruned, idx.State(pids[0]))
+	require.Equal(t, StatePruned, idx.State(pids[1]))
+
+	require.NoError(t, idx.Score(pids[0], &NodeScore{Name: SyncViolations, Value: MaxSyncViolations}))
+	require.False(t, idx.IsBad(logger, pids[0]))
+	require.True(t, idx.IsBad(logger, pids[1]))
+}
@@ -15,7 +15,7 @@ type subnetsIndex struct {
 	lock *sync.RWMutex
 }
 
-func newSubnetsIndex(count int) SubnetsIndex {
+func newSubnetsIndex(count int) *subnetsIndex {
 	return &subnetsIndex{
 		subnets:     make([][]peer.ID, count),
 		peerSubnets: map[peer.ID]records.Subnets{},
@@ -113,12 +113,45 @@ func (si *subnetsIndex) GetPeerSubnets(id peer.ID) records.Subnets {
 	return cp
 }
 
+// peers returns the peers that have known subnets
+func (si *subnetsIndex) peers() []peer.ID {
+	si.lock.RLock()
+	defer si.lock.RUnlock()
+
+	peers := make([]peer.ID, 0, len(si.peerSubnets))
+	for id := range si.peerSubnets {
+		peers = append(peers, id)
+	}
+	return peers
+}
+
+// WeakSubnets returns the subnets of this node that have less than minPerSubnet connected peers
+func WeakSubnets(stats *SubnetsStats, minPerSubnet int, mySubnets records.Subnets) []int {
+	if stats == nil || len(stats.Connected) == 0 {
+		return nil
+	}
+	var weak []int
+	for subnet, val := range mySubnets {
+		if val == 0 {
+			continue
+		}
+		var connected int
+		if subnet < len(stats.Connected) {
+			connected = stats.Connected[subnet]
+		}
+		if connected < minPerSubnet {
+			weak = append(weak, subnet)
+		}
+	}
+	return weak
+}
+
 // GetSubnetsDistributionScores returns current subnets scores based on peers distribution.
 // subnets with low peer count would get higher score, and overloaded subnets gets a lower score.
 func GetSubnetsDistributionScores(stats *SubnetsStats, minPerSubnet int, mySubnets records.Subnets, topicMaxPeers int) []float64 {
 	const activeSubnetBoost = 0.2
 
-	allSubs, _ := records.Subnets{}.FromString(records.AllSubnets)
+	allSubs := records.AllSubnetsOf(len(mySubnets))
 	activeSubnets := records.SharedSubnets(allSubs, mySubnets, 0)
 
 	scores := make([]float64, len(allSubs))
@@ -92,6 +92,26 @@ func TestSubnetsDistributionScores(t *testing.T) {
 	require.Equal(t, float64(-6.05), distScores[5])
 }
 
+func TestWeakSubnets(t *testing.T) {
+	mySubnets := make(records.Subnets, 128)
+	mySubnets[1] = 1
+	mySubnets[3] = 1
+	mySubnets[5] = 1
+	stats := &SubnetsStats{
+		PeersCount: make([]int, 128),
+		Connected:  make([]int, 128),
+	}
+	stats.Connected[0] = 0 // not our subnet
+	stats.Connected[1] = 0
+	stats.Connected[3] = 3
+	stats.Connected[5] = 4
+
+	require.Equal(t, []int{1, 3}, WeakSubnets(stats, 4, mySubnets))
+	require.Equal(t, []int{1}, WeakSubnets(stats, 1, mySubnets))
+	require.Empty(t, WeakSubnets(&SubnetsStats{}, 4, mySubnets))
+	require.Empty(t, WeakSubnets(nil, 4, mySubnets))
+}
+
 func TestSubnetScore(t *testing.T) {
 	testCases := []struct {
 		connected int
@@ -0,0 +1,31 @@
+package records
+
+import (
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+// QUICEntry holds the UDP port of the QUIC transport of the node
+type QUICEntry uint16
+
+// ENRKey implements enr.Entry, returns the entry key
+func (q QUICEntry) ENRKey() string { return "quic" }
+
+// SetQUICEntry adds quic entry ('quic') to the node
+func SetQUICEntry(node *enode.LocalNode, port int) error {
+	node.Set(QUICEntry(port))
+	return nil
+}
+
+// GetQUICEntry extracts the value of quic entry ('quic'),
+// 0 is returned for nodes that don't support QUIC
+func GetQUICEntry(record *enr.Record) (int, error) {
+	port := new(QUICEntry)
+	if err := record.Load(port); err != nil {
+		if enr.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return int(*port), nil
+}
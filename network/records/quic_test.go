@@ -0,0 +1,32 @@
+package records
+
+import (
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/bloxapp/ssv/network/commons"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_QUICEntry(t *testing.T) {
+	priv, _, err := crypto.GenerateSecp256k1Key(crand.Reader)
+	require.NoError(t, err)
+	sk, err := commons.ConvertFromInterfacePrivKey(priv)
+	require.NoError(t, err)
+	ip, err := commons.IPAddr()
+	require.NoError(t, err)
+	node, err := CreateLocalNode(sk, "", ip, commons.DefaultUDP, commons.DefaultTCP)
+	require.NoError(t, err)
+
+	port, err := GetQUICEntry(node.Node().Record())
+	require.NoError(t, err)
+	require.Equal(t, 0, port)
+
+	require.NoError(t, SetQUICEntry(node, 14001))
+	t.Log("ENR with quic:", node.Node().String())
+
+	port, err = GetQUICEntry(node.Node().Record())
+	require.NoError(t, err)
+	require.Equal(t, 14001, port)
+}
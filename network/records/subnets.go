@@ -20,24 +20,36 @@ const (
 	AllSubnets = "ffffffffffffffffffffffffffffffff"
 )
 
+// AllSubnetsOf returns subnets with all of the given amount of subnets set
+func AllSubnetsOf(count int) Subnets {
+	subnets := make(Subnets, count)
+	for i := range subnets {
+		subnets[i] = 1
+	}
+	return subnets
+}
+
 // UpdateSubnets updates subnets entry according to the given changes.
-// count is the amount of subnets, in case that the entry doesn't exist as we want to initialize it
+// count is the amount of subnets, in case that the entry doesn't exist (or smaller) as we want to initialize it
 func UpdateSubnets(node *enode.LocalNode, count int, added []int, removed []int) ([]byte, error) {
 	subnets, err := GetSubnetsEntry(node.Node().Record())
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read subnets entry")
 	}
 	orig := make([]byte, len(subnets))
-	if len(subnets) == 0 { // not exist, creating slice
-		subnets = make([]byte, count)
-	} else {
-		copy(orig, subnets)
+	copy(orig, subnets)
+	if len(subnets) < count { // not exist or created with a smaller subnets count, extending slice
+		subnets = append(subnets, make([]byte, count-len(subnets))...)
 	}
 	for _, i := range added {
-		subnets[i] = 1
+		if i < len(subnets) {
+			subnets[i] = 1
+		}
 	}
 	for _, i := range removed {
-		subnets[i] = 0
+		if i < len(subnets) {
+			subnets[i] = 0
+		}
 	}
 	if bytes.Equal(orig, subnets) {
 		return nil, nil
@@ -48,34 +60,49 @@ func UpdateSubnets(node *enode.LocalNode, count int, added []int, removed []int)
 	return subnets, nil
 }
 
-// SetSubnetsEntry adds subnets entry to our enode.LocalNode
+// SetSubnetsEntry adds subnets entry to our enode.LocalNode.
+// the entry is a bitvector with one bit per subnet, so its size follows the subnets count of the fork.
 func SetSubnetsEntry(node *enode.LocalNode, subnets []byte) error {
-	subnetsVec := bitfield.NewBitvector128()
-	for i, subnet := range subnets {
-		subnetsVec.SetBitAt(uint64(i), subnet > 0)
-	}
+	subnetsVec := toBitvector(subnets)
 	node.Set(enr.WithEntry("subnets", &subnetsVec))
 	return nil
 }
 
 // GetSubnetsEntry extracts the value of subnets entry from some record
 func GetSubnetsEntry(record *enr.Record) ([]byte, error) {
-	subnetsVec := bitfield.NewBitvector128()
+	var subnetsVec []byte
 	if err := record.Load(enr.WithEntry("subnets", &subnetsVec)); err != nil {
 		if enr.IsNotFound(err) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	res := make([]byte, 0, subnetsVec.Len())
-	for i := uint64(0); i < subnetsVec.Len(); i++ {
-		val := byte(0)
-		if subnetsVec.BitAt(i) {
-			val = 1
+	return fromBitvector(subnetsVec), nil
+}
+
+// toBitvector packs the given subnets into a bitvector, using the bits order of bitfield.Bitvector128
+func toBitvector(subnets []byte) []byte {
+	if len(subnets) == 0 {
+		return bitfield.NewBitvector128()
+	}
+	vec := make([]byte, (len(subnets)+7)/8)
+	for i, subnet := range subnets {
+		if subnet > 0 {
+			vec[i/8] |= 1 << (i % 8)
 		}
-		res = append(res, val)
 	}
-	return res, nil
+	return vec
+}
+
+// fromBitvector unpacks the given bitvector into subnets
+func fromBitvector(vec []byte) []byte {
+	res := make([]byte, len(vec)*8)
+	for i := range res {
+		if vec[i/8]&(1<<(i%8)) > 0 {
+			res[i] = 1
+		}
+	}
+	return res
 }
 
 // Subnets holds all the subscribed subnets of a specific node
@@ -89,11 +116,7 @@ func (s Subnets) Clone() Subnets {
 }
 
 func (s Subnets) String() string {
-	subnetsVec := bitfield.NewBitvector128()
-	for subnet, val := range s {
-		subnetsVec.SetBitAt(uint64(subnet), val > uint8(0))
-	}
-	return hex.EncodeToString(subnetsVec.Bytes())
+	return hex.EncodeToString(toBitvector(s))
 }
 
 // FromString parses a given subnet string
@@ -138,7 +161,7 @@ func SharedSubnets(a, b []byte, maxLen int) []int {
 		if aval == 0 {
 			continue
 		}
-		if b[subnet] == 0 {
+		if subnet >= len(b) || b[subnet] == 0 {
 			continue
 		}
 		shared = append(shared, subnet)
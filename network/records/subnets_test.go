@@ -45,6 +45,31 @@ func Test_SubnetsEntry(t *testing.T) {
 	}
 }
 
+func Test_SubnetsEntryDynamicCount(t *testing.T) {
+	priv, _, err := crypto.GenerateSecp256k1Key(crand.Reader)
+	require.NoError(t, err)
+	sk, err := commons.ConvertFromInterfacePrivKey(priv)
+	require.NoError(t, err)
+	ip, err := commons.IPAddr()
+	require.NoError(t, err)
+	node, err := CreateLocalNode(sk, "", ip, commons.DefaultUDP, commons.DefaultTCP)
+	require.NoError(t, err)
+
+	subnets := make([]byte, 256)
+	subnets[3] = 1
+	subnets[200] = 1
+	require.NoError(t, SetSubnetsEntry(node, subnets))
+
+	subnetsFromEnr, err := GetSubnetsEntry(node.Node().Record())
+	require.NoError(t, err)
+	require.Equal(t, subnets, subnetsFromEnr)
+
+	parsed, err := Subnets{}.FromString(Subnets(subnets).String())
+	require.NoError(t, err)
+	require.Equal(t, Subnets(subnets), parsed)
+	require.Equal(t, []int{3}, SharedSubnets(subnets, subnets[:128], 0))
+}
+
 func TestSubnetsParsing(t *testing.T) {
 	subtests := []struct {
 		name        string
@@ -0,0 +1,90 @@
+package streams
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// RateLimiter limits the amount of requests that each peer can make on each protocol,
+// using a token bucket per peer and protocol.
+type RateLimiter struct {
+	// limit is the amount of requests allowed per interval, which is also the bucket capacity
+	limit    float64
+	interval time.Duration
+
+	buckets map[rateLimiterKey]*tokenBucket
+	lock    sync.Mutex
+
+	now func() time.Time
+}
+
+type rateLimiterKey struct {
+	peer     peer.ID
+	protocol protocol.ID
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter that allows limit requests per interval for each peer and protocol
+func NewRateLimiter(limit int, interval time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:    float64(limit),
+		interval: interval,
+		buckets:  make(map[rateLimiterKey]*tokenBucket),
+		now:      time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of the given peer and protocol,
+// it returns false if the bucket is empty and the request should be rejected.
+func (rl *RateLimiter) Allow(id peer.ID, protocolID protocol.ID) bool {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	now := rl.now()
+	key := rateLimiterKey{peer: id, protocol: protocolID}
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: rl.limit, lastSeen: now}
+		rl.buckets[key] = bucket
+	}
+	bucket.refill(now, rl.limit, rl.interval)
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// GC removes the buckets that were refilled to their full capacity, as they are equivalent to new buckets
+func (rl *RateLimiter) GC() {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	now := rl.now()
+	for key, bucket := range rl.buckets {
+		bucket.refill(now, rl.limit, rl.interval)
+		if bucket.tokens >= rl.limit {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+// refill adds the tokens that were accumulated since the bucket was last seen, up to the given capacity
+func (b *tokenBucket) refill(now time.Time, capacity float64, interval time.Duration) {
+	elapsed := now.Sub(b.lastSeen)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens += capacity * float64(elapsed) / float64(interval)
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.lastSeen = now
+}
@@ -0,0 +1,53 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	rl := NewRateLimiter(3, time.Minute)
+	rl.now = func() time.Time {
+		return now
+	}
+
+	p1, p2 := peer.ID("peer1"), peer.ID("peer2")
+	prot1, prot2 := protocol.ID("/test/protocol/1"), protocol.ID("/test/protocol/2")
+
+	t.Run("limit per peer and protocol", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.True(t, rl.Allow(p1, prot1))
+		}
+		require.False(t, rl.Allow(p1, prot1))
+		require.True(t, rl.Allow(p1, prot2))
+		require.True(t, rl.Allow(p2, prot1))
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		now = now.Add(20 * time.Second)
+		require.True(t, rl.Allow(p1, prot1))
+		require.False(t, rl.Allow(p1, prot1))
+
+		now = now.Add(time.Hour)
+		for i := 0; i < 3; i++ {
+			require.True(t, rl.Allow(p1, prot1))
+		}
+		require.False(t, rl.Allow(p1, prot1))
+	})
+
+	t.Run("gc", func(t *testing.T) {
+		require.Len(t, rl.buckets, 3)
+		now = now.Add(30 * time.Second)
+		rl.GC()
+		// the buckets of p1/prot2 and p2/prot1 are full again
+		require.Len(t, rl.buckets, 1)
+		now = now.Add(30 * time.Second)
+		rl.GC()
+		require.Len(t, rl.buckets, 0)
+	})
+}
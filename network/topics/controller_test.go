@@ -233,7 +233,7 @@ func newPeer(ctx context.Context, logger *zap.Logger, t *testing.T, msgValidator
 	//
 	if msgValidator {
 		cfg.MsgValidatorFactory = func(s string) MsgValidatorFunc {
-			return NewSSVMsgValidator(fork)
+			return NewSSVMsgValidator(logger, fork, nil, nil)
 		}
 	}
 	ps, tm, err := NewPubsub(ctx, logger, cfg, fork)
@@ -58,8 +58,15 @@ func init() {
 type msgValidationResult string
 
 var (
+	validationResultValid    msgValidationResult = "valid"
 	validationResultNoData   msgValidationResult = "no_data"
 	validationResultEncoding msgValidationResult = "encoding"
+	validationResultTopic    msgValidationResult = "topic"
+	// validationResultUnresolved is reported for messages of validators whose topic can't be resolved yet
+	validationResultUnresolved msgValidationResult = "unresolved"
+	// validationResultValidator is reported for messages of unknown or liquidated validators
+	validationResultValidator msgValidationResult = "validator"
+	validationResultUnknown   msgValidationResult = "unknown"
 )
 
 func reportValidationResult(result msgValidationResult) {
@@ -3,18 +3,28 @@ package topics
 import (
 	"context"
 
-	"github.com/bloxapp/ssv/network/forks"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/message/validation"
+	"github.com/bloxapp/ssv/network/forks"
+	"github.com/bloxapp/ssv/network/forks/genesis"
 )
 
 // MsgValidatorFunc represents a message validator
 type MsgValidatorFunc = func(ctx context.Context, p peer.ID, msg *pubsub.Message) pubsub.ValidationResult
 
 // NewSSVMsgValidator creates a new msg validator that validates message structure,
-// and checks that the message was sent on the right topic.
-// TODO: enable post SSZ change, remove logs, break into smaller validators?
-func NewSSVMsgValidator(fork forks.Fork) func(ctx context.Context, p peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+// checks that the message was sent on the right topic and validates its content with the given message validator.
+// messages that fail the content validation are rejected or ignored according to the returned error,
+// rejected messages are penalized by pubsub peer scoring.
+// messages of validators that are not allowed by validatorsFilter are ignored, as the registry might be out of sync.
+// if msgValidator is in log-only mode, content validation failures are only logged and reported.
+// if msgValidator or validatorsFilter are nil, they are not used.
+func NewSSVMsgValidator(logger *zap.Logger, fork forks.Fork, validatorsFilter *ValidatorsFilter, msgValidator *validation.MessageValidator) func(ctx context.Context, p peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
 	return func(ctx context.Context, p peer.ID, pmsg *pubsub.Message) pubsub.ValidationResult {
 		topic := pmsg.GetTopic()
 		metricPubsubActiveMsgValidation.WithLabelValues(topic).Inc()
@@ -26,7 +36,6 @@ func NewSSVMsgValidator(fork forks.Fork) func(ctx context.Context, p peer.ID, ms
 		msg, err := fork.DecodeNetworkMsg(pmsg.GetData())
 		if err != nil {
 			// can't decode message
-			// logger.Debug("invalid: can't decode message", zap.Error(err))
 			reportValidationResult(validationResultEncoding)
 			return pubsub.ValidationReject
 		}
@@ -34,33 +43,57 @@ func NewSSVMsgValidator(fork forks.Fork) func(ctx context.Context, p peer.ID, ms
 			reportValidationResult(validationResultEncoding)
 			return pubsub.ValidationReject
 		}
-		pmsg.ValidatorData = *msg
-		return pubsub.ValidationAccept
 
 		// Check if the message was sent on the right topic.
-		// currentTopic := pmsg.GetTopic()
-		// currentTopicBaseName := fork.GetTopicBaseName(currentTopic)
-		// topics := fork.ValidatorTopicID(msg.GetID().GetPubKey())
-		// for _, tp := range topics {
-		//	if tp == currentTopicBaseName {
-		//		reportValidationResult(validationResultValid)
-		//		return pubsub.ValidationAccept
-		//	}
-		//}
-		// reportValidationResult(validationResultTopic)
-		// return pubsub.ValidationReject
+		// the topic of validators whose committee isn't known yet can't be checked, as the registry might be out of sync
+		validatorTopics := fork.ValidatorTopicID(msg.GetID().GetPubKey())
+		if len(validatorTopics) == 1 && validatorTopics[0] == genesis.UnresolvedSubnet {
+			reportValidationResult(validationResultUnresolved)
+			return pubsub.ValidationIgnore
+		}
+		if !isValidatorTopic(fork, validatorTopics, topic) {
+			reportValidationResult(validationResultTopic)
+			return pubsub.ValidationReject
+		}
+
+		if validatorsFilter != nil && !validatorsFilter.Allow(msg.GetID().GetPubKey()) {
+			reportValidationResult(validationResultValidator)
+			return pubsub.ValidationIgnore
+		}
+
+		if msgValidator != nil {
+			if err := msgValidator.Validate(msg); err != nil {
+				var validationErr *validation.Error
+				if !errors.As(err, &validationErr) {
+					reportValidationResult(validationResultUnknown)
+					return pubsub.ValidationIgnore
+				}
+				reportValidationResult(msgValidationResult(validationErr.Reason()))
+				if msgValidator.LogOnly() {
+					logger.Debug("accepting message that failed validation", fields.Topic(topic), fields.PeerID(p), zap.Error(err))
+					pmsg.ValidatorData = *msg
+					return pubsub.ValidationAccept
+				}
+				if validationErr.Reject() {
+					return pubsub.ValidationReject
+				}
+				return pubsub.ValidationIgnore
+			}
+		}
+
+		pmsg.ValidatorData = *msg
+		reportValidationResult(validationResultValid)
+		return pubsub.ValidationAccept
 	}
 }
 
-//// CombineMsgValidators executes multiple validators
-// func CombineMsgValidators(validators ...MsgValidatorFunc) MsgValidatorFunc {
-//	return func(ctx context.Context, p peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
-//		res := pubsub.ValidationAccept
-//		for _, v := range validators {
-//			if res = v(ctx, p, msg); res == pubsub.ValidationReject {
-//				break
-//			}
-//		}
-//		return res
-//	}
-//}
+// isValidatorTopic returns true if the given topic is one of the given validator topics
+func isValidatorTopic(fork forks.Fork, validatorTopics []string, topic string) bool {
+	baseName := fork.GetTopicBaseName(topic)
+	for _, tp := range validatorTopics {
+		if tp == baseName {
+			return true
+		}
+	}
+	return false
+}
@@ -12,7 +12,9 @@ import (
 	ps_pb "github.com/libp2p/go-libp2p-pubsub/pb"
 	"github.com/stretchr/testify/require"
 
+	"github.com/bloxapp/ssv/logging"
 	"github.com/bloxapp/ssv/network/forks/genesis"
+	forksv2 "github.com/bloxapp/ssv/network/forks/v2"
 	"github.com/bloxapp/ssv/protocol/v2/types"
 	"github.com/bloxapp/ssv/utils/threshold"
 )
@@ -20,7 +22,7 @@ import (
 func TestMsgValidator(t *testing.T) {
 	pks := createSharePublicKeys(4)
 	f := genesis.ForkGenesis{}
-	mv := NewSSVMsgValidator(&f)
+	mv := NewSSVMsgValidator(logging.TestLogger(t), &f, nil, nil)
 	require.NotNil(t, mv)
 
 	t.Run("valid consensus msg", func(t *testing.T) {
@@ -37,20 +39,19 @@ func TestMsgValidator(t *testing.T) {
 		require.Equal(t, res, pubsub.ValidationAccept)
 	})
 
-	// TODO: enable once topic validation is in place
-	// t.Run("wrong topic", func(t *testing.T) {
-	//	pkHex := "b5de683dbcb3febe8320cc741948b9282d59b75a6970ed55d6f389da59f26325331b7ea0e71a2552373d0debb6048b8a"
-	//	msg, err := dummySSVConsensusMsg(pkHex, 15160)
-	//	require.NoError(t, err)
-	//	raw, err := msg.Encode()
-	//	require.NoError(t, err)
-	//	pk, err := hex.DecodeString("a297599ccf617c3b6118bbd248494d7072bb8c6c1cc342ea442a289415987d306bad34415f89469221450a2501a832ec")
-	//	require.NoError(t, err)
-	//	topics := f.ValidatorTopicID(pk)
-	//	pmsg := newPBMsg(raw, topics[0], []byte("16Uiu2HAkyWQyCb6reWXGQeBUt9EXArk6h3aq3PsFMwLNq3pPGH1r"))
-	//	res := mv(context.Background(), "16Uiu2HAkyWQyCb6reWXGQeBUt9EXArk6h3aq3PsFMwLNq3pPGH1r", pmsg)
-	//	require.Equal(t, res, pubsub.ValidationReject)
-	// })
+	t.Run("wrong topic", func(t *testing.T) {
+		pkHex := "b5de683dbcb3febe8320cc741948b9282d59b75a6970ed55d6f389da59f26325331b7ea0e71a2552373d0debb6048b8a"
+		msg, err := dummySSVConsensusMsg(pkHex, 15160)
+		require.NoError(t, err)
+		raw, err := msg.Encode()
+		require.NoError(t, err)
+		pk, err := hex.DecodeString("a297599ccf617c3b6118bbd248494d7072bb8c6c1cc342ea442a289415987d306bad34415f89469221450a2501a832ec")
+		require.NoError(t, err)
+		topics := f.ValidatorTopicID(pk)
+		pmsg := newPBMsg(raw, f.GetTopicFullName(topics[0]), []byte("16Uiu2HAkyWQyCb6reWXGQeBUt9EXArk6h3aq3PsFMwLNq3pPGH1r"))
+		res := mv(context.Background(), "16Uiu2HAkyWQyCb6reWXGQeBUt9EXArk6h3aq3PsFMwLNq3pPGH1r", pmsg)
+		require.Equal(t, res, pubsub.ValidationReject)
+	})
 
 	t.Run("empty message", func(t *testing.T) {
 		pmsg := newPBMsg([]byte{}, "xxx", []byte{})
@@ -58,16 +59,27 @@ func TestMsgValidator(t *testing.T) {
 		require.Equal(t, res, pubsub.ValidationReject)
 	})
 
-	// TODO: enable once topic validation is in place
-	// t.Run("invalid validator public key", func(t *testing.T) {
-	//	msg, err := dummySSVConsensusMsg("10101011", 1)
-	//	require.NoError(t, err)
-	//	raw, err := msg.Encode()
-	//	require.NoError(t, err)
-	//	pmsg := newPBMsg(raw, "xxx", []byte{})
-	//	res := mv(context.Background(), "xxxx", pmsg)
-	//	require.Equal(t, res, pubsub.ValidationReject)
-	// })
+	t.Run("unresolved committee", func(t *testing.T) {
+		v2Fork := forksv2.New()
+		v2Validator := NewSSVMsgValidator(logging.TestLogger(t), v2Fork, nil, nil)
+		msg, err := dummySSVConsensusMsg(pks[1], 15160)
+		require.NoError(t, err)
+		raw, err := msg.Encode()
+		require.NoError(t, err)
+		pmsg := newPBMsg(raw, v2Fork.GetTopicFullName("12"), []byte("16Uiu2HAkyWQyCb6reWXGQeBUt9EXArk6h3aq3PsFMwLNq3pPGH1r"))
+		res := v2Validator(context.Background(), "16Uiu2HAkyWQyCb6reWXGQeBUt9EXArk6h3aq3PsFMwLNq3pPGH1r", pmsg)
+		require.Equal(t, res, pubsub.ValidationIgnore)
+	})
+
+	t.Run("invalid validator public key", func(t *testing.T) {
+		msg, err := dummySSVConsensusMsg("10101011", 1)
+		require.NoError(t, err)
+		raw, err := msg.Encode()
+		require.NoError(t, err)
+		pmsg := newPBMsg(raw, "xxx", []byte{})
+		res := mv(context.Background(), "xxxx", pmsg)
+		require.Equal(t, res, pubsub.ValidationReject)
+	})
 
 }
 
@@ -22,32 +22,35 @@ func DefaultScoringConfig() *ScoringConfig {
 }
 
 // scoreInspector inspects scores and updates the score index accordingly
-// TODO: finalize once validation is in place
 func scoreInspector(logger *zap.Logger, scoreIdx peers.ScoreIndex) pubsub.ExtendedPeerScoreInspectFn {
 	return func(scores map[peer.ID]*pubsub.PeerScoreSnapshot) {
 		for pid, peerScores := range scores {
-			// scores := []*peers.NodeScore{
-			//	{
-			//		Name:  "PS_Score",
-			//		Value: peerScores.Score,
-			//	}, {
-			//		Name:  "PS_BehaviourPenalty",
-			//		Value: peerScores.BehaviourPenalty,
-			//	}, {
-			//		Name:  "PS_IPColocationFactor",
-			//		Value: peerScores.IPColocationFactor,
-			//	},
-			//}
-			logger.Debug("peer scores", fields.PeerID(pid),
-				zap.Any("peerScores", peerScores))
+			var invalidMessageDeliveries float64
+			for _, topicScores := range peerScores.Topics {
+				invalidMessageDeliveries += topicScores.InvalidMessageDeliveries
+			}
+			nodeScores := []*peers.NodeScore{
+				{
+					Name:  peers.GossipScore,
+					Value: peerScores.Score,
+				}, {
+					Name:  peers.GossipBehaviourPenalty,
+					Value: peerScores.BehaviourPenalty,
+				}, {
+					Name:  peers.GossipIPColocationFactor,
+					Value: peerScores.IPColocationFactor,
+				}, {
+					Name:  peers.GossipInvalidMessageDeliveries,
+					Value: invalidMessageDeliveries,
+				},
+			}
 			metricPubsubPeerScoreInspect.WithLabelValues(pid.String()).Set(peerScores.Score)
-			// err := scoreIdx.Score(pid, scores...)
-			// if err != nil {
-			//	logger.Warn("could not score peer", zap.String("peer", pid.String()), zap.Error(err))
-			// } else {
-			//	logger.Debug("peer scores were updated", zap.String("peer", pid.String()),
-			//		zap.Any("scores", scores), zap.Any("topicScores", peerScores.Topics))
-			//}
+			if err := scoreIdx.Score(pid, nodeScores...); err != nil {
+				logger.Warn("could not score peer", fields.PeerID(pid), zap.Error(err))
+				continue
+			}
+			logger.Debug("peer scores were updated", fields.PeerID(pid),
+				zap.Any("scores", nodeScores), zap.Any("topicScores", peerScores.Topics))
 		}
 	}
 }
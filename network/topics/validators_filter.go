@@ -0,0 +1,71 @@
+package topics
+
+import (
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/pkg/errors"
+
+	"github.com/bloxapp/ssv/message/validation"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+// validatorsCacheTTL is the time that a validator share is kept in the cache of ValidatorsFilter,
+// it bounds the time it takes for registry changes (e.g. liquidation) to be reflected.
+// kept in a var to allow flexibility (e.g. in tests)
+var validatorsCacheTTL = time.Minute
+
+// ValidatorsFilter filters pubsub messages of validators that don't exist in the registry or were liquidated.
+// shares are kept in a bounded cache, so most lookups don't read from the storage.
+// it also implements validation.SharesStorage, so the message validator could use the same cache.
+type ValidatorsFilter struct {
+	shares validation.SharesStorage
+	cache  *lru.Cache[string, cachedShare]
+	now    func() time.Time
+}
+
+// cachedShare holds the result of a share lookup, a nil share means that the validator was not found
+type cachedShare struct {
+	share   *types.SSVShare
+	expires time.Time
+}
+
+// NewValidatorsFilter creates a new ValidatorsFilter that caches up to cacheSize validators
+func NewValidatorsFilter(shares validation.SharesStorage, cacheSize int) (*ValidatorsFilter, error) {
+	cache, err := lru.New[string, cachedShare](cacheSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create validators cache")
+	}
+	return &ValidatorsFilter{
+		shares: shares,
+		cache:  cache,
+		now:    time.Now,
+	}, nil
+}
+
+// Allow returns true if the given validator exists in the registry and is not liquidated
+func (f *ValidatorsFilter) Allow(pk []byte) bool {
+	share, found, err := f.GetShare(pk)
+	if err != nil || !found {
+		return false
+	}
+	return !share.Liquidated
+}
+
+// GetShare returns the share of the given validator, from the cache if possible
+func (f *ValidatorsFilter) GetShare(pk []byte) (*types.SSVShare, bool, error) {
+	key := string(pk)
+	now := f.now()
+	if cached, ok := f.cache.Get(key); ok && now.Before(cached.expires) {
+		return cached.share, cached.share != nil, nil
+	}
+	share, found, err := f.shares.GetShare(pk)
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		share = nil
+	}
+	f.cache.Add(key, cachedShare{share: share, expires: now.Add(validatorsCacheTTL)})
+	return share, share != nil, nil
+}
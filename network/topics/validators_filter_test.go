@@ -0,0 +1,77 @@
+package topics
+
+import (
+	"testing"
+	"time"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+type mockShares struct {
+	shares map[string]*types.SSVShare
+	reads  int
+	err    error
+}
+
+func (m *mockShares) GetShare(key []byte) (*types.SSVShare, bool, error) {
+	m.reads++
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	share, ok := m.shares[string(key)]
+	return share, ok, nil
+}
+
+func TestValidatorsFilter(t *testing.T) {
+	active := &types.SSVShare{Share: spectypes.Share{ValidatorPubKey: []byte("active")}}
+	liquidated := &types.SSVShare{Share: spectypes.Share{ValidatorPubKey: []byte("liquidated")}}
+	liquidated.Liquidated = true
+	shares := &mockShares{shares: map[string]*types.SSVShare{
+		"active":     active,
+		"liquidated": liquidated,
+	}}
+
+	now := time.Now()
+	vf, err := NewValidatorsFilter(shares, 2)
+	require.NoError(t, err)
+	vf.now = func() time.Time {
+		return now
+	}
+
+	t.Run("allow known validators", func(t *testing.T) {
+		require.True(t, vf.Allow([]byte("active")))
+		require.False(t, vf.Allow([]byte("liquidated")))
+		require.Equal(t, 2, shares.reads)
+		// cached
+		require.True(t, vf.Allow([]byte("active")))
+		require.False(t, vf.Allow([]byte("liquidated")))
+		require.Equal(t, 2, shares.reads)
+	})
+
+	t.Run("unknown validator", func(t *testing.T) {
+		require.False(t, vf.Allow([]byte("unknown")))
+		require.False(t, vf.Allow([]byte("unknown")))
+		require.Equal(t, 3, shares.reads)
+		// the cache is bounded, so the oldest entry was evicted
+		require.True(t, vf.Allow([]byte("active")))
+		require.Equal(t, 4, shares.reads)
+	})
+
+	t.Run("expired entries", func(t *testing.T) {
+		shares.shares["unknown"] = active
+		require.False(t, vf.Allow([]byte("unknown")))
+		now = now.Add(validatorsCacheTTL)
+		require.True(t, vf.Allow([]byte("unknown")))
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		shares.err = errors.New("test")
+		require.False(t, vf.Allow([]byte("other")))
+		_, _, err := vf.GetShare([]byte("other"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,362 @@
+package nodeapi
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/eth1"
+	exporterapi "github.com/bloxapp/ssv/exporter/api"
+	qbftstorage "github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/network/records"
+	"github.com/bloxapp/ssv/protocol/v2/message"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/runner"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/validator"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	// maxDecidedRange is the widest range of heights that can be requested at once, as all of them are read into memory
+	maxDecidedRange = 1000
+)
+
+// ValidatorsController is the subset of the validators controller that is served by the API
+type ValidatorsController interface {
+	GetOperatorShares(logger *zap.Logger) ([]*types.SSVShare, error)
+	GetValidator(pubKey string) (*validator.Validator, bool)
+	GetRunningValidators() []*validator.Validator
+	ExitValidator(logger *zap.Logger, pubKey []byte, epoch phase0.Epoch) error
+	DoppelgangerStatus(pubKey string) string
+}
+
+// PeersProvider is the subset of the p2p network that is served by the API
+type PeersProvider interface {
+	ConnectedPeers() []peer.ID
+	PeerSubnets(id peer.ID) records.Subnets
+	ActiveSubnets() records.Subnets
+}
+
+// Options contains the stores and components that are served by the API
+type Options struct {
+	Validators ValidatorsController
+	Peers      PeersProvider
+	SyncOffset eth1.SyncOffsetStorage
+	QBFTStores *qbftstorage.QBFTStores
+	// ExitToken is the bearer token that authorizes voluntary exits, exits are disabled if empty
+	ExitToken string
+}
+
+// Server is a versioned REST API that exposes the state of the node
+type Server struct {
+	validators ValidatorsController
+	peers      PeersProvider
+	syncOffset eth1.SyncOffsetStorage
+	qbftStores *qbftstorage.QBFTStores
+	exitToken  string
+}
+
+// New creates a new instance of Server
+func New(opts Options) *Server {
+	return &Server{
+		validators: opts.Validators,
+		peers:      opts.Peers,
+		syncOffset: opts.SyncOffset,
+		qbftStores: opts.QBFTStores,
+		exitToken:  opts.ExitToken,
+	}
+}
+
+// Handler returns the http handler of all the API end-points
+func (s *Server) Handler(logger *zap.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/node/shares", s.get(logger, s.handleShares))
+	mux.HandleFunc("/v1/node/validators", s.get(logger, s.handleValidators))
+	mux.HandleFunc("/v1/node/peers", s.get(logger, s.handlePeers))
+	mux.HandleFunc("/v1/node/subnets", s.get(logger, s.handleSubnets))
+	mux.HandleFunc("/v1/eth1/sync-offset", s.get(logger, s.handleSyncOffset))
+	mux.HandleFunc("/v1/decided", s.get(logger, s.handleDecided))
+	mux.HandleFunc("/v1/node/validators/exit", s.post(logger, s.handleExit))
+	return mux
+}
+
+// Start starts an http server that serves the API on the given address
+func (s *Server) Start(logger *zap.Logger, addr string) error {
+	logger.Info("starting node API", fields.Address(addr))
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(logger),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "failed to serve node API")
+	}
+	return nil
+}
+
+// apiError is an error with the corresponding http status
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+func badRequest(err error) error {
+	return &apiError{status: http.StatusBadRequest, err: err}
+}
+
+func notFound(err error) error {
+	return &apiError{status: http.StatusNotFound, err: err}
+}
+
+func unauthorized(err error) error {
+	return &apiError{status: http.StatusUnauthorized, err: err}
+}
+
+func forbidden(err error) error {
+	return &apiError{status: http.StatusForbidden, err: err}
+}
+
+func unavailable(err error) error {
+	return &apiError{status: http.StatusServiceUnavailable, err: err}
+}
+
+// handlerFunc handles a request and returns the response object, that is encoded as JSON
+type handlerFunc func(logger *zap.Logger, r *http.Request) (interface{}, error)
+
+// get wraps the given handler, accepting only GET requests and writing JSON responses
+func (s *Server) get(logger *zap.Logger, h handlerFunc) http.HandlerFunc {
+	return s.handle(logger, http.MethodGet, h)
+}
+
+// post wraps the given handler, accepting only POST requests and writing JSON responses
+func (s *Server) post(logger *zap.Logger, h handlerFunc) http.HandlerFunc {
+	return s.handle(logger, http.MethodPost, h)
+}
+
+func (s *Server) handle(logger *zap.Logger, method string, h handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeJSON(logger, w, http.StatusMethodNotAllowed, &ErrorResponse{Error: "method not allowed"})
+			return
+		}
+		res, err := h(logger, r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var apiErr *apiError
+			if errors.As(err, &apiErr) {
+				status = apiErr.status
+			} else {
+				logger.Warn("failed to handle API request", zap.String("path", r.URL.Path), zap.Error(err))
+			}
+			writeJSON(logger, w, status, &ErrorResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(logger, w, http.StatusOK, res)
+	}
+}
+
+func writeJSON(logger *zap.Logger, w http.ResponseWriter, status int, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Debug("failed to write API response", zap.Error(err))
+	}
+}
+
+func (s *Server) handleShares(logger *zap.Logger, r *http.Request) (interface{}, error) {
+	shares, err := s.validators.GetOperatorShares(logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get operator shares")
+	}
+	res := &SharesResponse{Data: make([]ShareResponse, 0, len(shares))}
+	for _, share := range shares {
+		pk := hex.EncodeToString(share.ValidatorPubKey)
+		item := ShareResponse{
+			PublicKey:    pk,
+			Status:       "unknown",
+			OwnerAddress: share.OwnerAddress.String(),
+			Committee:    make([]spectypes.OperatorID, 0, len(share.Committee)),
+			Liquidated:   share.Liquidated,
+		}
+		if share.HasBeaconMetadata() {
+			item.Index = uint64(share.BeaconMetadata.Index)
+			item.Status = share.BeaconMetadata.Status.String()
+			item.Balance = uint64(share.BeaconMetadata.Balance)
+		}
+		for _, op := range share.Committee {
+			item.Committee = append(item.Committee, op.OperatorID)
+		}
+		_, item.Running = s.validators.GetValidator(pk)
+		res.Data = append(res.Data, item)
+	}
+	return res, nil
+}
+
+func (s *Server) handleValidators(logger *zap.Logger, r *http.Request) (interface{}, error) {
+	validators := s.validators.GetRunningValidators()
+	res := &ValidatorsResponse{Data: make([]ValidatorResponse, 0, len(validators))}
+	for _, v := range validators {
+		queues := make(map[string]int)
+		for role, n := range v.QueueLengths() {
+			queues[message.BeaconRoleToString(role)] = n
+		}
+		pk := hex.EncodeToString(v.Share.ValidatorPubKey)
+		res.Data = append(res.Data, ValidatorResponse{
+			PublicKey:    pk,
+			Queues:       queues,
+			Doppelganger: s.validators.DoppelgangerStatus(pk),
+		})
+	}
+	sort.Slice(res.Data, func(i, j int) bool {
+		return res.Data[i].PublicKey < res.Data[j].PublicKey
+	})
+	return res, nil
+}
+
+func (s *Server) handlePeers(logger *zap.Logger, r *http.Request) (interface{}, error) {
+	pids := s.peers.ConnectedPeers()
+	res := &PeersResponse{Count: len(pids), Data: make([]PeerResponse, 0, len(pids))}
+	for _, pid := range pids {
+		res.Data = append(res.Data, PeerResponse{
+			ID:      pid.String(),
+			Subnets: s.peers.PeerSubnets(pid).String(),
+		})
+	}
+	return res, nil
+}
+
+func (s *Server) handleSubnets(logger *zap.Logger, r *http.Request) (interface{}, error) {
+	subnets := s.peers.ActiveSubnets()
+	res := &SubnetsResponse{Subnets: subnets.String(), Active: make([]int, 0)}
+	for i, b := range subnets {
+		if b > 0 {
+			res.Active = append(res.Active, i)
+		}
+	}
+	return res, nil
+}
+
+func (s *Server) handleSyncOffset(logger *zap.Logger, r *http.Request) (interface{}, error) {
+	offset, found, err := s.syncOffset.GetSyncOffset()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get sync offset")
+	}
+	if !found || offset == nil {
+		return nil, notFound(errors.New("sync offset not found"))
+	}
+	return &SyncOffsetResponse{BlockNumber: offset.Uint64()}, nil
+}
+
+// handleDecided returns the decided instances of the given validator and role in the range of heights [from, to]
+func (s *Server) handleDecided(logger *zap.Logger, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	pk, err := hex.DecodeString(query.Get("publicKey"))
+	if err != nil || len(pk) == 0 {
+		return nil, badRequest(errors.New("invalid validator public key"))
+	}
+	role, err := message.BeaconRoleFromString(query.Get("role"))
+	if err != nil {
+		return nil, badRequest(errors.New("invalid role"))
+	}
+	from, err := strconv.ParseUint(query.Get("from"), 10, 64)
+	if err != nil {
+		return nil, badRequest(errors.New("invalid 'from' height"))
+	}
+	to, err := strconv.ParseUint(query.Get("to"), 10, 64)
+	if err != nil || to < from {
+		return nil, badRequest(errors.New("invalid 'to' height"))
+	}
+	if to-from >= maxDecidedRange {
+		return nil, badRequest(errors.Errorf("range of heights is above the limit of %d", maxDecidedRange))
+	}
+
+	store := s.qbftStores.Get(role)
+	if store == nil {
+		return nil, notFound(errors.Errorf("no decided storage for role %s", role.String()))
+	}
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), pk, role)
+	instances, err := store.GetInstancesInRange(msgID[:], specqbft.Height(from), specqbft.Height(to))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get decided instances")
+	}
+
+	res := &DecidedResponse{Data: make([]*exporterapi.SignedMessageAPI, 0, len(instances))}
+	if len(instances) == 0 {
+		return res, nil
+	}
+	msgs := make([]*specqbft.SignedMessage, 0, len(instances))
+	for _, instance := range instances {
+		msgs = append(msgs, instance.DecidedMessage)
+	}
+	data, err := exporterapi.DecidedAPIData(msgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert decided messages")
+	}
+	res.Data = data.([]*exporterapi.SignedMessageAPI)
+	return res, nil
+}
+
+// handleExit starts a voluntary exit of a validator of this node's operator,
+// the exit is submitted once a quorum of the committee operators requested an exit of the same epoch.
+// exits are irreversible, so requests must be authorized by the exit token.
+func (s *Server) handleExit(logger *zap.Logger, r *http.Request) (interface{}, error) {
+	if len(s.exitToken) == 0 {
+		return nil, forbidden(runner.ErrVoluntaryExitsDisabled)
+	}
+	if !s.authorizedExit(r) {
+		return nil, unauthorized(errors.New("invalid exit token"))
+	}
+	req := &ExitRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, badRequest(errors.New("invalid request body"))
+	}
+	pk, err := hex.DecodeString(req.PublicKey)
+	if err != nil || len(pk) == 0 {
+		return nil, badRequest(errors.New("invalid validator public key"))
+	}
+	if _, ok := s.validators.GetValidator(hex.EncodeToString(pk)); !ok {
+		return nil, notFound(errors.New("validator not found"))
+	}
+	if err := s.validators.ExitValidator(logger, pk, phase0.Epoch(req.Epoch)); err != nil {
+		err = errors.Wrap(err, "could not exit validator")
+		switch {
+		case errors.Is(err, runner.ErrInvalidVoluntaryExit):
+			return nil, badRequest(err)
+		case errors.Is(err, runner.ErrVoluntaryExitsDisabled):
+			return nil, forbidden(err)
+		case errors.Is(err, runner.ErrVoluntaryExitBusy):
+			return nil, unavailable(err)
+		default:
+			return nil, err
+		}
+	}
+	return &ExitResponse{PublicKey: hex.EncodeToString(pk), Epoch: req.Epoch}, nil
+}
+
+// authorizedExit returns true if the request carries the exit token as a bearer token
+func (s *Server) authorizedExit(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.exitToken)) == 1
+}
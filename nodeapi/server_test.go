@@ -0,0 +1,321 @@
+package nodeapi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	eth2apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	spectestingutils "github.com/bloxapp/ssv-spec/types/testingutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/network/records"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	qbfttesting "github.com/bloxapp/ssv/protocol/v2/qbft/testing"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/queue"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/runner"
+	ssvtesting "github.com/bloxapp/ssv/protocol/v2/ssv/testing"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/validator"
+	protocoltesting "github.com/bloxapp/ssv/protocol/v2/testing"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+type mockValidators struct {
+	shares     []*types.SSVShare
+	validators []*validator.Validator
+	exits      map[string]phase0.Epoch
+}
+
+func (m *mockValidators) GetOperatorShares(logger *zap.Logger) ([]*types.SSVShare, error) {
+	return m.shares, nil
+}
+
+func (m *mockValidators) GetValidator(pubKey string) (*validator.Validator, bool) {
+	for _, v := range m.validators {
+		if hex.EncodeToString(v.Share.ValidatorPubKey) == pubKey {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (m *mockValidators) GetRunningValidators() []*validator.Validator {
+	return m.validators
+}
+
+func (m *mockValidators) DoppelgangerStatus(pubKey string) string {
+	return "safe"
+}
+
+func (m *mockValidators) ExitValidator(logger *zap.Logger, pubKey []byte, epoch phase0.Epoch) error {
+	switch {
+	case epoch == 200:
+		return errors.Wrap(runner.ErrVoluntaryExitBusy, "validator queue is full")
+	case epoch == 300:
+		return errors.New("could not encode event msg")
+	case epoch > 100:
+		return errors.Wrap(runner.ErrInvalidVoluntaryExit, "exit epoch is in the future")
+	}
+	m.exits[hex.EncodeToString(pubKey)] = epoch
+	return nil
+}
+
+type mockPeers struct {
+	peers   map[peer.ID]records.Subnets
+	subnets records.Subnets
+}
+
+func (m *mockPeers) ConnectedPeers() []peer.ID {
+	var pids []peer.ID
+	for pid := range m.peers {
+		pids = append(pids, pid)
+	}
+	return pids
+}
+
+func (m *mockPeers) PeerSubnets(id peer.ID) records.Subnets {
+	return m.peers[id]
+}
+
+func (m *mockPeers) ActiveSubnets() records.Subnets {
+	return m.subnets
+}
+
+type mockSyncOffset struct {
+	offset *eth1.SyncOffset
+}
+
+func (m *mockSyncOffset) SaveSyncOffset(offset *eth1.SyncOffset) error {
+	m.offset = offset
+	return nil
+}
+
+func (m *mockSyncOffset) GetSyncOffset() (*eth1.SyncOffset, bool, error) {
+	return m.offset, m.offset != nil, nil
+}
+
+func TestServer(t *testing.T) {
+	logger := logging.TestLogger(t)
+	keySet := spectestingutils.Testing4SharesSet()
+
+	running := ssvtesting.BaseValidator(logger, keySet)
+	running.Queues[spectypes.BNRoleAttester].Q.Push(&queue.DecodedSSVMessage{SSVMessage: &spectypes.SSVMessage{}})
+
+	stopped := &types.SSVShare{
+		Share: spectypes.Share{
+			ValidatorPubKey: []byte{1, 2, 3},
+			Committee:       []*spectypes.Operator{{OperatorID: 1}, {OperatorID: 2}},
+		},
+		Metadata: types.Metadata{
+			BeaconMetadata: &beaconprotocol.ValidatorMetadata{Index: 7, Status: eth2apiv1.ValidatorStateActiveOngoing, Balance: 32},
+			OwnerAddress:   common.HexToAddress("0x1"),
+			Liquidated:     true,
+		},
+	}
+
+	stores := qbfttesting.TestingStores(logger)
+	pk := keySet.ValidatorPK.Serialize()
+	instances, err := protocoltesting.CreateMultipleStoredInstances(keySet.Shares, 0, 10, func(height specqbft.Height) ([]spectypes.OperatorID, *specqbft.Message) {
+		id := spectypes.NewMsgID(types.GetDefaultDomain(), pk, spectypes.BNRoleAttester)
+		return []spectypes.OperatorID{1, 2, 3}, &specqbft.Message{
+			MsgType:    specqbft.CommitMsgType,
+			Height:     height,
+			Round:      1,
+			Identifier: id[:],
+			Root:       [32]byte{0x1, 0x2, 0x3},
+		}
+	})
+	require.NoError(t, err)
+	for _, instance := range instances {
+		require.NoError(t, stores.Get(spectypes.BNRoleAttester).SaveInstance(instance))
+	}
+
+	syncOffset := &mockSyncOffset{}
+	validators := &mockValidators{
+		shares:     []*types.SSVShare{running.Share, stopped},
+		validators: []*validator.Validator{running},
+		exits:      make(map[string]phase0.Epoch),
+	}
+	server := New(Options{
+		Validators: validators,
+		Peers: &mockPeers{
+			peers:   map[peer.ID]records.Subnets{"peer-1": {1, 0, 1}},
+			subnets: records.Subnets{0, 1, 1},
+		},
+		SyncOffset: syncOffset,
+		QBFTStores: stores,
+		ExitToken:  "exit-token",
+	})
+	ts := httptest.NewServer(server.Handler(logger))
+	defer ts.Close()
+
+	get := func(t *testing.T, path string, expectedStatus int, res interface{}) {
+		resp, err := http.Get(ts.URL + path)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, expectedStatus, resp.StatusCode)
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+
+	t.Run("shares", func(t *testing.T) {
+		var res SharesResponse
+		get(t, "/v1/node/shares", http.StatusOK, &res)
+		require.Len(t, res.Data, 2)
+		require.Equal(t, hex.EncodeToString(pk), res.Data[0].PublicKey)
+		require.True(t, res.Data[0].Running)
+		require.Equal(t, "unknown", res.Data[0].Status)
+		require.Equal(t, ShareResponse{
+			PublicKey:    "010203",
+			Index:        7,
+			Status:       eth2apiv1.ValidatorStateActiveOngoing.String(),
+			Balance:      32,
+			OwnerAddress: common.HexToAddress("0x1").String(),
+			Committee:    []spectypes.OperatorID{1, 2},
+			Liquidated:   true,
+		}, res.Data[1])
+	})
+
+	t.Run("validators", func(t *testing.T) {
+		var res ValidatorsResponse
+		get(t, "/v1/node/validators", http.StatusOK, &res)
+		require.Len(t, res.Data, 1)
+		require.Equal(t, hex.EncodeToString(pk), res.Data[0].PublicKey)
+		require.Equal(t, 1, res.Data[0].Queues[spectypes.BNRoleAttester.String()])
+		require.Equal(t, 0, res.Data[0].Queues[spectypes.BNRoleProposer.String()])
+		require.Equal(t, "safe", res.Data[0].Doppelganger)
+	})
+
+	t.Run("peers", func(t *testing.T) {
+		var res PeersResponse
+		get(t, "/v1/node/peers", http.StatusOK, &res)
+		require.Equal(t, 1, res.Count)
+		require.Equal(t, []PeerResponse{{ID: peer.ID("peer-1").String(), Subnets: records.Subnets{1, 0, 1}.String()}}, res.Data)
+	})
+
+	t.Run("subnets", func(t *testing.T) {
+		var res SubnetsResponse
+		get(t, "/v1/node/subnets", http.StatusOK, &res)
+		require.Equal(t, records.Subnets{0, 1, 1}.String(), res.Subnets)
+		require.Equal(t, []int{1, 2}, res.Active)
+	})
+
+	t.Run("sync offset", func(t *testing.T) {
+		var errRes ErrorResponse
+		get(t, "/v1/eth1/sync-offset", http.StatusNotFound, &errRes)
+		require.Equal(t, "sync offset not found", errRes.Error)
+
+		require.NoError(t, syncOffset.SaveSyncOffset(big.NewInt(1234)))
+		var res SyncOffsetResponse
+		get(t, "/v1/eth1/sync-offset", http.StatusOK, &res)
+		require.Equal(t, uint64(1234), res.BlockNumber)
+	})
+
+	t.Run("decided", func(t *testing.T) {
+		var res DecidedResponse
+		get(t, fmt.Sprintf("/v1/decided?publicKey=%x&role=ATTESTER&from=2&to=4", pk), http.StatusOK, &res)
+		require.Len(t, res.Data, 3)
+		require.Equal(t, specqbft.Height(2), res.Data[0].Message.Height)
+
+		get(t, fmt.Sprintf("/v1/decided?publicKey=%x&role=PROPOSER&from=2&to=4", pk), http.StatusOK, &res)
+		require.Len(t, res.Data, 0)
+
+		get(t, fmt.Sprintf("/v1/decided?publicKey=%x&role=ATTESTER&from=0&to=999", pk), http.StatusOK, &res)
+		require.NotEmpty(t, res.Data)
+	})
+
+	t.Run("bad decided request", func(t *testing.T) {
+		var res ErrorResponse
+		get(t, fmt.Sprintf("/v1/decided?publicKey=%x&role=UNKNOWN&from=2&to=4", pk), http.StatusBadRequest, &res)
+		require.Equal(t, "invalid role", res.Error)
+
+		get(t, fmt.Sprintf("/v1/decided?publicKey=%x&role=ATTESTER&from=4&to=2", pk), http.StatusBadRequest, &res)
+		require.Equal(t, "invalid 'to' height", res.Error)
+
+		get(t, fmt.Sprintf("/v1/decided?publicKey=%x&role=ATTESTER&from=0&to=1000", pk), http.StatusBadRequest, &res)
+		require.Equal(t, "range of heights is above the limit of 1000", res.Error)
+
+		get(t, fmt.Sprintf("/v1/decided?publicKey=%x&role=ATTESTER&from=0&to=18446744073709551615", pk), http.StatusBadRequest, &res)
+		require.Equal(t, "range of heights is above the limit of 1000", res.Error)
+
+		get(t, "/v1/decided?publicKey=xyz&role=ATTESTER&from=2&to=4", http.StatusBadRequest, &res)
+		require.Equal(t, "invalid validator public key", res.Error)
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		postWithToken := func(t *testing.T, url, token, body string, expectedStatus int, res interface{}) {
+			req, err := http.NewRequest(http.MethodPost, url+"/v1/node/validators/exit", strings.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+			require.Equal(t, expectedStatus, resp.StatusCode)
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+		}
+		post := func(t *testing.T, body string, expectedStatus int, res interface{}) {
+			postWithToken(t, ts.URL, "exit-token", body, expectedStatus, res)
+		}
+
+		var res ExitResponse
+		post(t, fmt.Sprintf(`{"publicKey":"%x","epoch":12}`, pk), http.StatusOK, &res)
+		require.Equal(t, ExitResponse{PublicKey: hex.EncodeToString(pk), Epoch: 12}, res)
+		require.Equal(t, map[string]phase0.Epoch{hex.EncodeToString(pk): 12}, validators.exits)
+
+		var errRes ErrorResponse
+		post(t, `{"publicKey":"010203","epoch":12}`, http.StatusNotFound, &errRes)
+		require.Equal(t, "validator not found", errRes.Error)
+
+		post(t, fmt.Sprintf(`{"publicKey":"%x","epoch":101}`, pk), http.StatusBadRequest, &errRes)
+		require.Equal(t, "could not exit validator: exit epoch is in the future: invalid voluntary exit", errRes.Error)
+
+		post(t, fmt.Sprintf(`{"publicKey":"%x","epoch":200}`, pk), http.StatusServiceUnavailable, &errRes)
+		require.Equal(t, "could not exit validator: validator queue is full: validator is busy", errRes.Error)
+
+		post(t, fmt.Sprintf(`{"publicKey":"%x","epoch":300}`, pk), http.StatusInternalServerError, &errRes)
+		require.Equal(t, "could not exit validator: could not encode event msg", errRes.Error)
+
+		// exits are irreversible, so they are only started with the exit token
+		postWithToken(t, ts.URL, "", fmt.Sprintf(`{"publicKey":"%x","epoch":12}`, pk), http.StatusUnauthorized, &errRes)
+		require.Equal(t, "invalid exit token", errRes.Error)
+		postWithToken(t, ts.URL, "wrong-token", fmt.Sprintf(`{"publicKey":"%x","epoch":12}`, pk), http.StatusUnauthorized, &errRes)
+		require.Equal(t, "invalid exit token", errRes.Error)
+
+		disabled := httptest.NewServer(New(Options{Validators: validators}).Handler(logger))
+		defer disabled.Close()
+		postWithToken(t, disabled.URL, "", fmt.Sprintf(`{"publicKey":"%x","epoch":12}`, pk), http.StatusForbidden, &errRes)
+		require.Equal(t, "voluntary exits are disabled", errRes.Error)
+
+		post(t, `{"publicKey":"xyz"}`, http.StatusBadRequest, &errRes)
+		require.Equal(t, "invalid validator public key", errRes.Error)
+
+		resp, err := http.Get(ts.URL + "/v1/node/validators/exit")
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/v1/node/peers", "application/json", nil)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
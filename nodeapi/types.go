@@ -0,0 +1,84 @@
+package nodeapi
+
+import (
+	spectypes "github.com/bloxapp/ssv-spec/types"
+
+	exporterapi "github.com/bloxapp/ssv/exporter/api"
+)
+
+// ErrorResponse is returned by all end-points in case of failure
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ShareResponse is the information of a validator share that belongs to this node's operator
+type ShareResponse struct {
+	PublicKey    string                 `json:"publicKey"`
+	Index        uint64                 `json:"index"`
+	Status       string                 `json:"status"`
+	Balance      uint64                 `json:"balance"`
+	OwnerAddress string                 `json:"ownerAddress"`
+	Committee    []spectypes.OperatorID `json:"committee"`
+	Liquidated   bool                   `json:"liquidated"`
+	Running      bool                   `json:"running"`
+}
+
+// SharesResponse is the response of GET /v1/node/shares
+type SharesResponse struct {
+	Data []ShareResponse `json:"data"`
+}
+
+// ValidatorResponse is the information of a running validator
+type ValidatorResponse struct {
+	PublicKey string `json:"publicKey"`
+	// Queues holds the amount of pending messages by duty role
+	Queues map[string]int `json:"queues"`
+	// Doppelganger is the doppelganger protection status, omitted when the protection is disabled
+	Doppelganger string `json:"doppelganger,omitempty"`
+}
+
+// ValidatorsResponse is the response of GET /v1/node/validators
+type ValidatorsResponse struct {
+	Data []ValidatorResponse `json:"data"`
+}
+
+// PeerResponse is the information of a connected peer
+type PeerResponse struct {
+	ID      string `json:"id"`
+	Subnets string `json:"subnets"`
+}
+
+// PeersResponse is the response of GET /v1/node/peers
+type PeersResponse struct {
+	Count int            `json:"count"`
+	Data  []PeerResponse `json:"data"`
+}
+
+// SubnetsResponse is the response of GET /v1/node/subnets
+type SubnetsResponse struct {
+	Subnets string `json:"subnets"`
+	Active  []int  `json:"active"`
+}
+
+// SyncOffsetResponse is the response of GET /v1/eth1/sync-offset
+type SyncOffsetResponse struct {
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+// DecidedResponse is the response of GET /v1/decided
+type DecidedResponse struct {
+	Data []*exporterapi.SignedMessageAPI `json:"data"`
+}
+
+// ExitRequest is the body of POST /v1/node/validators/exit
+type ExitRequest struct {
+	PublicKey string `json:"publicKey"`
+	// Epoch of the voluntary exit, all the committee operators must request the same epoch
+	Epoch uint64 `json:"epoch"`
+}
+
+// ExitResponse is the response of POST /v1/node/validators/exit
+type ExitResponse struct {
+	PublicKey string `json:"publicKey"`
+	Epoch     uint64 `json:"epoch"`
+}
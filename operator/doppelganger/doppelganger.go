@@ -0,0 +1,301 @@
+package doppelganger
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/operator/slot_ticker"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+// Status is the doppelganger status of a validator
+type Status int32
+
+const (
+	// StatusUnknown means that the validator is not watched
+	StatusUnknown Status = iota
+	// StatusChecking means that the validator is watched and can't sign yet
+	StatusChecking
+	// StatusSafe means that no doppelganger was detected and the validator can sign
+	StatusSafe
+	// StatusDetected means that a doppelganger was detected and the validator must not sign
+	StatusDetected
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusChecking:
+		return "checking"
+	case StatusSafe:
+		return "safe"
+	case StatusDetected:
+		return "detected"
+	default:
+		return "unknown"
+	}
+}
+
+// SafeHandler is called once a validator is safe to start
+type SafeHandler func(logger *zap.Logger, pubKey string)
+
+// Options holds the needed dependencies
+type Options struct {
+	Ctx        context.Context
+	Beacon     beaconprotocol.Beacon
+	Network    beaconprotocol.Network
+	Ticker     slot_ticker.Ticker
+	OperatorID spectypes.OperatorID
+	// Epochs is the number of epochs a validator is watched before it is safe to start
+	Epochs uint64
+	OnSafe SafeHandler
+}
+
+// Handler delays the start of validators until their shares are known not to be used by another instance of this operator
+type Handler interface {
+	// Start checks the watched validators on every epoch until the context is done
+	Start(logger *zap.Logger)
+	// Watch starts to watch the given validator, it does nothing if the validator is already watched
+	Watch(logger *zap.Logger, share *types.SSVShare)
+	// Remove stops watching the given validator and forgets its status
+	Remove(pubKey string)
+	// ObserveMessage checks whether the given network message was signed by this operator for a watched validator
+	ObserveMessage(logger *zap.Logger, msg *spectypes.SSVMessage)
+	// Status returns the status of the given validator
+	Status(pubKey string) Status
+	// CanSign returns true if the given validator is safe to sign
+	CanSign(pubKey string) bool
+}
+
+// validatorState is the doppelganger state of a single validator
+type validatorState struct {
+	index  phase0.ValidatorIndex
+	status Status
+	// startEpoch is the first watched epoch, the epoch in which the watch started is skipped
+	// as it might include signatures of a previous run of this node
+	startEpoch phase0.Epoch
+	// remainingEpochs is the number of epochs that are yet to be checked
+	remainingEpochs uint64
+	// liveWithoutOperator is true if the rest of the committee can reach quorum without this operator,
+	// in which case beacon liveness doesn't imply that the share of this operator is used
+	liveWithoutOperator bool
+}
+
+// handler implements Handler
+type handler struct {
+	ctx        context.Context
+	beacon     beaconprotocol.Beacon
+	network    beaconprotocol.Network
+	ticker     slot_ticker.Ticker
+	operatorID spectypes.OperatorID
+	epochs     uint64
+	onSafe     SafeHandler
+
+	mu           sync.RWMutex
+	currentEpoch phase0.Epoch
+	validators   map[string]*validatorState
+}
+
+// New creates a new doppelganger handler
+func New(opts *Options) Handler {
+	return &handler{
+		ctx:          opts.Ctx,
+		beacon:       opts.Beacon,
+		network:      opts.Network,
+		ticker:       opts.Ticker,
+		operatorID:   opts.OperatorID,
+		epochs:       opts.Epochs,
+		onSafe:       opts.OnSafe,
+		currentEpoch: opts.Network.EstimatedCurrentEpoch(),
+		validators:   make(map[string]*validatorState),
+	}
+}
+
+func (h *handler) Start(logger *zap.Logger) {
+	logger = logger.Named("doppelganger")
+	tickerChan := make(chan phase0.Slot, 32)
+	sub := h.ticker.Subscribe(tickerChan)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case slot := <-tickerChan:
+			h.onSlot(logger, slot)
+		}
+	}
+}
+
+// onSlot checks the previous epoch once a new epoch starts
+func (h *handler) onSlot(logger *zap.Logger, slot phase0.Slot) {
+	epoch := h.network.EstimatedEpochAtSlot(slot)
+	h.mu.Lock()
+	if epoch <= h.currentEpoch {
+		h.mu.Unlock()
+		return
+	}
+	h.currentEpoch = epoch
+	h.mu.Unlock()
+
+	h.checkEpoch(logger, epoch-1)
+}
+
+// checkEpoch checks the beacon liveness of the watched validators in the given epoch,
+// validators without a doppelganger in the configured number of epochs are marked as safe
+func (h *handler) checkEpoch(logger *zap.Logger, epoch phase0.Epoch) {
+	var indices []phase0.ValidatorIndex
+	h.mu.RLock()
+	for _, state := range h.validators {
+		if state.status == StatusChecking && state.startEpoch <= epoch {
+			indices = append(indices, state.index)
+		}
+	}
+	h.mu.RUnlock()
+	if len(indices) == 0 {
+		return
+	}
+
+	liveness, err := h.beacon.ValidatorLiveness(epoch, indices)
+	if err != nil {
+		// the epoch doesn't count, so validators are watched for an extra epoch
+		logger.Warn("could not get validators liveness", zap.Uint64("epoch", uint64(epoch)), zap.Error(err))
+		return
+	}
+
+	var safe []string
+	h.mu.Lock()
+	for pk, state := range h.validators {
+		if state.status != StatusChecking || state.startEpoch > epoch {
+			continue
+		}
+		if liveness[state.index] && !state.liveWithoutOperator {
+			h.setStatus(pk, state, StatusDetected)
+			logger.Error("doppelganger detected: validator is live on the beacon chain without this node",
+				zap.String(fields.FieldPubKey, pk), zap.Uint64("epoch", uint64(epoch)))
+			continue
+		}
+		if state.remainingEpochs > 0 {
+			state.remainingEpochs--
+		}
+		if state.remainingEpochs == 0 {
+			h.setStatus(pk, state, StatusSafe)
+			safe = append(safe, pk)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, pk := range safe {
+		logger.Info("no doppelganger detected, starting validator", zap.String(fields.FieldPubKey, pk))
+		if h.onSafe != nil {
+			h.onSafe(logger, pk)
+		}
+	}
+}
+
+func (h *handler) Watch(logger *zap.Logger, share *types.SSVShare) {
+	pk := hex.EncodeToString(share.ValidatorPubKey)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.validators[pk]; ok {
+		return
+	}
+	state := &validatorState{
+		index:               share.BeaconMetadata.Index,
+		startEpoch:          h.currentEpoch + 1,
+		remainingEpochs:     h.epochs,
+		liveWithoutOperator: uint64(len(share.Committee)-1) >= share.Quorum,
+	}
+	h.validators[pk] = state
+	h.setStatus(pk, state, StatusChecking)
+	logger.Info("watching validator for doppelgangers", fields.PubKey(share.ValidatorPubKey),
+		zap.Uint64("start_epoch", uint64(state.startEpoch)), zap.Uint64("epochs", h.epochs))
+}
+
+func (h *handler) Remove(pubKey string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.validators, pubKey)
+	metricsDoppelgangerStatus.DeleteLabelValues(pubKey)
+}
+
+func (h *handler) ObserveMessage(logger *zap.Logger, msg *spectypes.SSVMessage) {
+	pk := hex.EncodeToString(msg.MsgID.GetPubKey())
+
+	if !h.watching(pk) || !h.signedByOperator(msg) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.validators[pk]
+	if !ok || state.status != StatusChecking {
+		return
+	}
+	h.setStatus(pk, state, StatusDetected)
+	logger.Error("doppelganger detected: message of this operator was received from the network",
+		fields.PubKey(msg.MsgID.GetPubKey()), fields.Role(msg.MsgID.GetRoleType()))
+}
+
+// watching returns true if messages of the given validator are currently watched
+func (h *handler) watching(pubKey string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	state, ok := h.validators[pubKey]
+	return ok && state.status == StatusChecking && state.startEpoch <= h.currentEpoch
+}
+
+// signedByOperator returns true if this operator is one of the signers of the given message
+func (h *handler) signedByOperator(msg *spectypes.SSVMessage) bool {
+	switch msg.MsgType {
+	case spectypes.SSVConsensusMsgType:
+		signedMsg := &specqbft.SignedMessage{}
+		if err := signedMsg.Decode(msg.Data); err != nil {
+			return false
+		}
+		for _, signer := range signedMsg.Signers {
+			if signer == h.operatorID {
+				return true
+			}
+		}
+	case spectypes.SSVPartialSignatureMsgType:
+		signedMsg := &spectypes.SignedPartialSignatureMessage{}
+		if err := signedMsg.Decode(msg.Data); err != nil {
+			return false
+		}
+		return signedMsg.Signer == h.operatorID
+	}
+	return false
+}
+
+func (h *handler) Status(pubKey string) Status {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if state, ok := h.validators[pubKey]; ok {
+		return state.status
+	}
+	return StatusUnknown
+}
+
+func (h *handler) CanSign(pubKey string) bool {
+	return h.Status(pubKey) == StatusSafe
+}
+
+// setStatus updates the status of the given validator, the caller must hold the lock
+func (h *handler) setStatus(pubKey string, state *validatorState, status Status) {
+	state.status = status
+	metricsDoppelgangerStatus.WithLabelValues(pubKey).Set(float64(status))
+}
@@ -0,0 +1,222 @@
+package doppelganger
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	spectestingutils "github.com/bloxapp/ssv-spec/types/testingutils"
+	"github.com/golang/mock/gomock"
+	"github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+const testOperatorID = spectypes.OperatorID(1)
+
+func TestHandler_Safe(t *testing.T) {
+	logger := logging.TestLogger(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := beacon.NewMockBeacon(ctrl)
+	var started []string
+	h := newTestHandler(client, func(logger *zap.Logger, pubKey string) {
+		started = append(started, pubKey)
+	})
+
+	share := testShare(1, 4)
+	pk := hex.EncodeToString(share.ValidatorPubKey)
+	h.Watch(logger, share)
+	require.Equal(t, StatusChecking, h.Status(pk))
+	require.False(t, h.CanSign(pk))
+
+	// the epoch of the watch start is skipped
+	h.onSlot(logger, slotOf(11))
+	require.Equal(t, StatusChecking, h.Status(pk))
+
+	// the rest of the committee keeps the validator live, which isn't a doppelganger
+	client.EXPECT().ValidatorLiveness(phase0.Epoch(11), []phase0.ValidatorIndex{1}).Return(map[phase0.ValidatorIndex]bool{1: true}, nil)
+	h.onSlot(logger, slotOf(12))
+	require.Equal(t, StatusChecking, h.Status(pk))
+	require.Empty(t, started)
+
+	// a later slot of the same epoch doesn't trigger another check
+	h.onSlot(logger, slotOf(12)+1)
+
+	client.EXPECT().ValidatorLiveness(phase0.Epoch(12), []phase0.ValidatorIndex{1}).Return(map[phase0.ValidatorIndex]bool{1: false}, nil)
+	h.onSlot(logger, slotOf(13))
+	require.Equal(t, StatusSafe, h.Status(pk))
+	require.True(t, h.CanSign(pk))
+	require.Equal(t, []string{pk}, started)
+
+	// watching a safe validator doesn't reset its status
+	h.Watch(logger, share)
+	require.Equal(t, StatusSafe, h.Status(pk))
+
+	h.Remove(pk)
+	require.Equal(t, StatusUnknown, h.Status(pk))
+}
+
+func TestHandler_LivenessError(t *testing.T) {
+	logger := logging.TestLogger(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := beacon.NewMockBeacon(ctrl)
+	h := newTestHandler(client, nil)
+
+	share := testShare(1, 4)
+	pk := hex.EncodeToString(share.ValidatorPubKey)
+	h.Watch(logger, share)
+	h.onSlot(logger, slotOf(11))
+
+	// epochs that couldn't be checked don't count
+	client.EXPECT().ValidatorLiveness(phase0.Epoch(11), gomock.Any()).Return(nil, errors.New("test error"))
+	h.onSlot(logger, slotOf(12))
+	client.EXPECT().ValidatorLiveness(phase0.Epoch(12), gomock.Any()).Return(map[phase0.ValidatorIndex]bool{}, nil)
+	h.onSlot(logger, slotOf(13))
+	require.Equal(t, StatusChecking, h.Status(pk))
+
+	client.EXPECT().ValidatorLiveness(phase0.Epoch(13), gomock.Any()).Return(map[phase0.ValidatorIndex]bool{}, nil)
+	h.onSlot(logger, slotOf(14))
+	require.Equal(t, StatusSafe, h.Status(pk))
+}
+
+func TestHandler_DetectedByLiveness(t *testing.T) {
+	logger := logging.TestLogger(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := beacon.NewMockBeacon(ctrl)
+	h := newTestHandler(client, func(logger *zap.Logger, pubKey string) {
+		require.Fail(t, "validator with a doppelganger was started")
+	})
+
+	// a validator that can't be live without this operator
+	share := testShare(1, 1)
+	pk := hex.EncodeToString(share.ValidatorPubKey)
+	h.Watch(logger, share)
+	h.onSlot(logger, slotOf(11))
+
+	client.EXPECT().ValidatorLiveness(phase0.Epoch(11), []phase0.ValidatorIndex{1}).Return(map[phase0.ValidatorIndex]bool{1: true}, nil)
+	h.onSlot(logger, slotOf(12))
+	require.Equal(t, StatusDetected, h.Status(pk))
+	require.False(t, h.CanSign(pk))
+
+	// detected validators aren't checked anymore
+	h.onSlot(logger, slotOf(13))
+	h.onSlot(logger, slotOf(14))
+	require.Equal(t, StatusDetected, h.Status(pk))
+}
+
+func TestHandler_DetectedByNetwork(t *testing.T) {
+	logger := logging.TestLogger(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ks := spectestingutils.Testing4SharesSet()
+	client := beacon.NewMockBeacon(ctrl)
+	h := newTestHandler(client, nil)
+
+	partialSigShare := testShare(1, 4)
+	partialSigPK := hex.EncodeToString(partialSigShare.ValidatorPubKey)
+	h.Watch(logger, partialSigShare)
+	consensusShare := testShare(2, 4)
+	consensusPK := hex.EncodeToString(consensusShare.ValidatorPubKey)
+	h.Watch(logger, consensusShare)
+
+	partialSigMsg := func(pk []byte, signer spectypes.OperatorID) *spectypes.SSVMessage {
+		data, err := spectestingutils.PostConsensusAttestationMsg(ks.Shares[signer], signer, specqbft.FirstHeight).Encode()
+		require.NoError(t, err)
+		return &spectypes.SSVMessage{
+			MsgType: spectypes.SSVPartialSignatureMsgType,
+			MsgID:   spectypes.NewMsgID(types.GetDefaultDomain(), pk, spectypes.BNRoleAttester),
+			Data:    data,
+		}
+	}
+	consensusMsg := func(pk []byte, signers ...spectypes.OperatorID) *spectypes.SSVMessage {
+		sks := make([]*bls.SecretKey, 0, len(signers))
+		for _, signer := range signers {
+			sks = append(sks, ks.Shares[signer])
+		}
+		data, err := spectestingutils.TestingCommitMultiSignerMessage(sks, signers).Encode()
+		require.NoError(t, err)
+		return &spectypes.SSVMessage{
+			MsgType: spectypes.SSVConsensusMsgType,
+			MsgID:   spectypes.NewMsgID(types.GetDefaultDomain(), pk, spectypes.BNRoleAttester),
+			Data:    data,
+		}
+	}
+
+	// messages of the epoch of the watch start might come from a previous run of this node
+	h.ObserveMessage(logger, partialSigMsg(partialSigShare.ValidatorPubKey, testOperatorID))
+	require.Equal(t, StatusChecking, h.Status(partialSigPK))
+
+	h.onSlot(logger, slotOf(11))
+
+	// messages of other operators are expected
+	h.ObserveMessage(logger, partialSigMsg(partialSigShare.ValidatorPubKey, 2))
+	h.ObserveMessage(logger, consensusMsg(consensusShare.ValidatorPubKey, 2, 3, 4))
+	require.Equal(t, StatusChecking, h.Status(partialSigPK))
+	require.Equal(t, StatusChecking, h.Status(consensusPK))
+
+	h.ObserveMessage(logger, partialSigMsg(partialSigShare.ValidatorPubKey, testOperatorID))
+	require.Equal(t, StatusDetected, h.Status(partialSigPK))
+	require.Equal(t, StatusChecking, h.Status(consensusPK))
+
+	h.ObserveMessage(logger, consensusMsg(consensusShare.ValidatorPubKey, 1, 2, 3))
+	require.Equal(t, StatusDetected, h.Status(consensusPK))
+
+	// messages of validators that aren't watched are ignored
+	h.ObserveMessage(logger, partialSigMsg(testShare(3, 4).ValidatorPubKey, testOperatorID))
+	require.Equal(t, StatusUnknown, h.Status(hex.EncodeToString(testShare(3, 4).ValidatorPubKey)))
+}
+
+func newTestHandler(client beacon.Beacon, onSafe SafeHandler) *handler {
+	h := New(&Options{
+		Ctx:        context.Background(),
+		Beacon:     client,
+		Network:    beacon.NewNetwork(core.PraterNetwork, 0),
+		OperatorID: testOperatorID,
+		Epochs:     2,
+		OnSafe:     onSafe,
+	}).(*handler)
+	h.currentEpoch = 10
+	return h
+}
+
+// testShare returns a share of this operator with the given validator index and committee size
+func testShare(index phase0.ValidatorIndex, committeeSize int) *types.SSVShare {
+	committee := make([]*spectypes.Operator, committeeSize)
+	for i := range committee {
+		committee[i] = &spectypes.Operator{OperatorID: spectypes.OperatorID(i + 1)}
+	}
+	quorum, partialQuorum := types.ComputeQuorumAndPartialQuorum(committeeSize)
+	return &types.SSVShare{
+		Share: spectypes.Share{
+			OperatorID:      testOperatorID,
+			ValidatorPubKey: bytes.Repeat([]byte{byte(index)}, 48),
+			Committee:       committee,
+			Quorum:          quorum,
+			PartialQuorum:   partialQuorum,
+		},
+		Metadata: types.Metadata{
+			BeaconMetadata: &beacon.ValidatorMetadata{Index: index},
+		},
+	}
+}
+
+func slotOf(epoch phase0.Epoch) phase0.Slot {
+	return phase0.Slot(uint64(epoch) * 32)
+}
@@ -0,0 +1,21 @@
+package doppelganger
+
+import (
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricsDoppelgangerStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ssv:validator:v2:doppelganger_status",
+		Help: "Doppelganger status of validator (0 - unknown, 1 - checking, 2 - safe, 3 - detected)",
+	}, []string{"pubKey"})
+)
+
+func init() {
+	if err := prometheus.Register(metricsDoppelgangerStatus); err != nil {
+		log.Println("could not register prometheus collector")
+	}
+}
@@ -10,7 +10,7 @@ import (
 // getForkVersion returns the fork version of the given slot
 func (n *operatorNode) getForkVersion(slot phase0.Slot) forksprotocol.ForkVersion {
 	epoch := n.ethNetwork.EstimatedEpochAtSlot(slot)
-	return forksprotocol.GetCurrentForkVersion(epoch)
+	return n.forkEpochs.GetCurrentForkVersion(epoch)
 }
 
 // listenForCurrentSlot updates forkVersion and checks if a fork is needed
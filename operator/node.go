@@ -17,6 +17,7 @@ import (
 	qbftstorage "github.com/bloxapp/ssv/ibft/storage"
 	"github.com/bloxapp/ssv/monitoring/metrics"
 	"github.com/bloxapp/ssv/network"
+	"github.com/bloxapp/ssv/nodeapi"
 	"github.com/bloxapp/ssv/operator/duties"
 	"github.com/bloxapp/ssv/operator/fee_recipient"
 	"github.com/bloxapp/ssv/operator/slot_ticker"
@@ -45,6 +46,8 @@ type Options struct {
 	DutyExec            duties.DutyExecutor
 	// genesis epoch
 	GenesisEpoch uint64 `yaml:"GenesisEpoch" env:"GENESIS_EPOCH" env-default:"156113" env-description:"Genesis Epoch SSV node will start"`
+	V1ForkEpoch  uint64 `yaml:"V1ForkEpoch" env:"V1_FORK_EPOCH" env-description:"Epoch of the v1 network fork (snappy compression of network messages), 0 to disable"`
+	V2ForkEpoch  uint64 `yaml:"V2ForkEpoch" env:"V2_FORK_EPOCH" env-description:"Epoch of the v2 network fork (subnets by committee), 0 to disable"`
 	// max slots for duty to wait
 	DutyLimit        uint64                      `yaml:"DutyLimit" env:"DUTY_LIMIT" env-default:"32" env-description:"max slots to wait for duty to start"`
 	ValidatorOptions validator.ControllerOptions `yaml:"ValidatorOptions"`
@@ -53,6 +56,20 @@ type Options struct {
 
 	WS        api.WebSocketServer
 	WsAPIPort int
+	// APIPort is the port of the node REST API, zero disables the API
+	APIPort int
+	// APIExitToken authorizes voluntary exits through the node REST API, exits are disabled in the API if empty
+	APIExitToken string
+	// DecidedRetention is the retention policy of historical decided instances
+	DecidedRetention qbftstorage.RetentionOptions `yaml:"DecidedRetention"`
+}
+
+// ForkEpochs returns the configured activation epochs of the fork versions
+func (opts Options) ForkEpochs() forksprotocol.ForkEpochs {
+	return forksprotocol.ForkEpochs{
+		V1: phase0.Epoch(opts.V1ForkEpoch),
+		V2: phase0.Epoch(opts.V2ForkEpoch),
+	}
 }
 
 // operatorNode implements Node interface
@@ -71,14 +88,19 @@ type operatorNode struct {
 	// fork           *forks.Forker
 
 	forkVersion forksprotocol.ForkVersion
+	forkEpochs  forksprotocol.ForkEpochs
 
-	ws        api.WebSocketServer
-	wsAPIPort int
+	ws           api.WebSocketServer
+	wsAPIPort    int
+	apiPort      int
+	apiExitToken string
+
+	decidedPruner *qbftstorage.Pruner
 }
 
 // New is the constructor of operatorNode
 func New(logger *zap.Logger, opts Options, slotTicker slot_ticker.Ticker) Node {
-	storageMap := qbftstorage.NewStores()
+	storageMap := qbftstorage.NewStores(opts.DB)
 
 	roles := []spectypes.BeaconRole{
 		spectypes.BNRoleAttester,
@@ -123,9 +145,17 @@ func New(logger *zap.Logger, opts Options, slotTicker slot_ticker.Ticker) Node {
 			OperatorData:     opts.ValidatorOptions.OperatorData,
 		}),
 		forkVersion: opts.ForkVersion,
+		forkEpochs:  opts.ForkEpochs(),
+
+		ws:           opts.WS,
+		wsAPIPort:    opts.WsAPIPort,
+		apiPort:      opts.APIPort,
+		apiExitToken: opts.APIExitToken,
+	}
 
-		ws:        opts.WS,
-		wsAPIPort: opts.WsAPIPort,
+	if opts.DecidedRetention.Enabled() {
+		node.decidedPruner = qbftstorage.NewPruner(opts.Context, opts.DB, storageMap, opts.ETHNetwork,
+			node.decidedValidators, opts.DecidedRetention)
 	}
 
 	if err := node.init(opts); err != nil {
@@ -158,6 +188,10 @@ func (n *operatorNode) Start(logger *zap.Logger) error {
 		}
 	}()
 
+	if n.apiPort != 0 {
+		go n.startAPIServer(logger)
+	}
+
 	// slot ticker init
 	go n.ticker.Start(logger)
 
@@ -168,12 +202,29 @@ func (n *operatorNode) Start(logger *zap.Logger) error {
 	go n.listenForCurrentSlot(logger)
 	go n.reportOperators(logger)
 
+	if n.decidedPruner != nil {
+		go n.decidedPruner.Start(logger)
+	}
+
 	go n.feeRecipientCtrl.Start(logger)
 	n.dutyCtrl.Start(logger)
 
 	return nil
 }
 
+// decidedValidators returns the public keys of all the validators, which decided history might be stored
+func (n *operatorNode) decidedValidators(logger *zap.Logger) ([][]byte, error) {
+	shares, err := n.storage.GetAllShares(logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get validator shares")
+	}
+	pks := make([][]byte, 0, len(shares))
+	for _, share := range shares {
+		pks = append(pks, share.ValidatorPubKey)
+	}
+	return pks, nil
+}
+
 // listenForCurrentSlot listens to current slot and trigger relevant components if needed
 func (n *operatorNode) listenForCurrentSlot(logger *zap.Logger) {
 	tickerChan := make(chan phase0.Slot, 32)
@@ -256,6 +307,10 @@ func (n *operatorNode) handleQueryRequests(logger *zap.Logger, nm *api.NetworkMe
 	switch nm.Msg.Type {
 	case api.TypeDecided:
 		api.HandleDecidedQuery(logger, n.qbftStorage, nm)
+	case api.TypeValidator:
+		api.HandleValidatorQuery(logger, n.storage, nm)
+	case api.TypeOperator:
+		api.HandleOperatorQuery(logger, n.storage, nm)
 	case api.TypeError:
 		api.HandleErrorQuery(logger, nm)
 	default:
@@ -277,6 +332,19 @@ func (n *operatorNode) startWSServer(logger *zap.Logger) error {
 	return nil
 }
 
+func (n *operatorNode) startAPIServer(logger *zap.Logger) {
+	server := nodeapi.New(nodeapi.Options{
+		Validators: n.validatorsCtrl,
+		Peers:      n.net,
+		SyncOffset: n.storage,
+		QBFTStores: n.qbftStorage,
+		ExitToken:  n.apiExitToken,
+	})
+	if err := server.Start(logger, fmt.Sprintf(":%d", n.apiPort)); err != nil {
+		logger.Error("failed to start node API", zap.Error(err))
+	}
+}
+
 func (n *operatorNode) reportOperators(logger *zap.Logger) {
 	operators, err := n.storage.ListOperators(logger, 0, 1000) // TODO more than 1000?
 	if err != nil {
@@ -3,11 +3,14 @@ package storage
 import (
 	"crypto/rsa"
 	"encoding/base64"
+	"encoding/json"
 	"math/big"
+	"sync"
 
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
@@ -21,13 +24,38 @@ import (
 )
 
 var (
-	storagePrefix = []byte("operator/")
-	syncOffsetKey = []byte("syncOffset")
+	storagePrefix   = []byte("operator/")
+	syncOffsetKey   = []byte("syncOffset")
+	recentLogsKey   = []byte("recentLogs")
+	eventJournalKey = []byte("eventJournal")
 )
 
+// EventJournalEntry holds the registry state that was overridden by a contract event,
+// a nil value means that the entry didn't exist before the event
+type EventJournalEntry struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	TxHash      common.Hash `json:"txHash"`
+	LogIndex    uint        `json:"logIndex"`
+
+	Operators  map[uint64]*registrystorage.OperatorData          `json:"operators"`
+	Shares     map[string]*types.SSVShare                        `json:"shares"`
+	Recipients map[common.Address]*registrystorage.RecipientData `json:"recipients"`
+	// RemovedSecrets are the validators whose share secret is removed once the event is out of the reorg window
+	RemovedSecrets []string `json:"removedSecrets,omitempty"`
+}
+
+// EventJournalStorage represents the interface for persisting the event journal
+type EventJournalStorage interface {
+	SaveEventJournal(entries []*EventJournalEntry) error
+	GetEventJournal() ([]*EventJournalEntry, error)
+}
+
 // Storage represents the interface for ssv node storage
 type Storage interface {
 	eth1.SyncOffsetStorage
+	eth1.RecentLogsStorage
+	EventJournalStorage
 	registry.RegistryStore
 	registrystorage.Operators
 	registrystorage.Recipients
@@ -35,11 +63,17 @@ type Storage interface {
 
 	GetPrivateKey() (*rsa.PrivateKey, bool, error)
 	SetupPrivateKey(logger *zap.Logger, operatorKeyBase64 string, generateIfNone bool) ([]byte, error)
+	SetupKeystorePrivateKey(logger *zap.Logger, sk *rsa.PrivateKey) ([]byte, error)
+	DeletePrivateKey() error
 }
 
 type storage struct {
 	db basedb.IDb
 
+	// keystoreKey is the operator private key that was decrypted from a keystore, it is never saved to the db
+	keystoreKey     *rsa.PrivateKey
+	keystoreKeyLock sync.RWMutex
+
 	operatorStore  registrystorage.Operators
 	recipientStore registrystorage.Recipients
 	shareStore     registrystorage.Shares
@@ -129,6 +163,11 @@ func (s *storage) CleanRegistryData() error {
 		return errors.Wrap(err, "could not clean sync offset")
 	}
 
+	err = s.cleanReorgState()
+	if err != nil {
+		return errors.Wrap(err, "could not clean reorg state")
+	}
+
 	err = s.cleanOperators()
 	if err != nil {
 		return errors.Wrap(err, "could not clean operators")
@@ -150,6 +189,13 @@ func (s *storage) cleanSyncOffset() error {
 	return s.db.RemoveAllByCollection(append(storagePrefix, syncOffsetKey...))
 }
 
+func (s *storage) cleanReorgState() error {
+	if err := s.db.RemoveAllByCollection(append(storagePrefix, recentLogsKey...)); err != nil {
+		return err
+	}
+	return s.db.RemoveAllByCollection(append(storagePrefix, eventJournalKey...))
+}
+
 func (s *storage) cleanOperators() error {
 	operatorsPrefix := s.GetOperatorsPrefix()
 	return s.db.RemoveAllByCollection(append(storagePrefix, operatorsPrefix...))
@@ -174,8 +220,65 @@ func (s *storage) GetSyncOffset() (*eth1.SyncOffset, bool, error) {
 	return offset, found, nil
 }
 
+// SaveRecentLogs saves the logs that were handled within the reorg window
+func (s *storage) SaveRecentLogs(logs []ethtypes.Log) error {
+	raw, err := json.Marshal(logs)
+	if err != nil {
+		return errors.Wrap(err, "could not encode recent logs")
+	}
+	return s.db.Set(storagePrefix, recentLogsKey, raw)
+}
+
+// GetRecentLogs returns the logs that were handled within the reorg window
+func (s *storage) GetRecentLogs() ([]ethtypes.Log, error) {
+	obj, found, err := s.db.Get(storagePrefix, recentLogsKey)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	var logs []ethtypes.Log
+	if err := json.Unmarshal(obj.Value, &logs); err != nil {
+		return nil, errors.Wrap(err, "could not decode recent logs")
+	}
+	return logs, nil
+}
+
+// SaveEventJournal saves the journal of the events that were handled within the reorg window
+func (s *storage) SaveEventJournal(entries []*EventJournalEntry) error {
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return errors.Wrap(err, "could not encode event journal")
+	}
+	return s.db.Set(storagePrefix, eventJournalKey, raw)
+}
+
+// GetEventJournal returns the journal of the events that were handled within the reorg window
+func (s *storage) GetEventJournal() ([]*EventJournalEntry, error) {
+	obj, found, err := s.db.Get(storagePrefix, eventJournalKey)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	var entries []*EventJournalEntry
+	if err := json.Unmarshal(obj.Value, &entries); err != nil {
+		return nil, errors.Wrap(err, "could not decode event journal")
+	}
+	return entries, nil
+}
+
 // GetPrivateKey return rsa private key
 func (s *storage) GetPrivateKey() (*rsa.PrivateKey, bool, error) {
+	s.keystoreKeyLock.RLock()
+	keystoreKey := s.keystoreKey
+	s.keystoreKeyLock.RUnlock()
+	if keystoreKey != nil {
+		return keystoreKey, true, nil
+	}
+
 	obj, found, err := s.db.Get(storagePrefix, []byte("private-key"))
 	if err != nil {
 		return nil, false, err
@@ -218,6 +321,21 @@ func (s *storage) SetupPrivateKey(logger *zap.Logger, operatorKeyBase64 string,
 	return []byte(operatorPublicKey), nil
 }
 
+// SetupKeystorePrivateKey sets the operator private key that was decrypted from a keystore.
+// the key is kept in memory only, and takes precedence over a key in the db.
+func (s *storage) SetupKeystorePrivateKey(logger *zap.Logger, sk *rsa.PrivateKey) ([]byte, error) {
+	operatorPublicKey, err := rsaencryption.ExtractPublicKey(sk)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract operator public key")
+	}
+	s.keystoreKeyLock.Lock()
+	s.keystoreKey = sk
+	s.keystoreKeyLock.Unlock()
+
+	logger.Info("loaded operator private key from keystore", zap.Any("public-key", operatorPublicKey))
+	return []byte(operatorPublicKey), nil
+}
+
 // validateKey validate provided and exist key. save if needed.
 func (s *storage) validateKey(generateIfNone bool, operatorKey string) error {
 	// check if passed new key. if so, save new key (force to always save key when provided)
@@ -253,6 +371,11 @@ func (s *storage) savePrivateKey(operatorKey string) error {
 	return nil
 }
 
+// DeletePrivateKey removes the operator private key from the db
+func (s *storage) DeletePrivateKey() error {
+	return s.db.Delete(storagePrefix, []byte("private-key"))
+}
+
 func (s *storage) UpdateValidatorMetadata(logger *zap.Logger, pk string, metadata *beacon.ValidatorMetadata) error {
 	return s.shareStore.(beacon.ValidatorMetadataStorage).UpdateValidatorMetadata(logger, pk, metadata)
 }
@@ -4,10 +4,15 @@ import (
 	"encoding/base64"
 	"testing"
 
+	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/bloxapp/ssv/logging"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/require"
 
 	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
 	ssvstorage "github.com/bloxapp/ssv/storage"
 	"github.com/bloxapp/ssv/storage/basedb"
 	"github.com/bloxapp/ssv/utils/rsaencryption"
@@ -185,3 +190,91 @@ func TestStorage_SaveAndGetSyncOffset(t *testing.T) {
 	require.NoError(t, err)
 	require.Zero(t, offset.Cmp(o))
 }
+
+func TestStorage_SaveAndGetEventJournal(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := ssvstorage.GetStorageFactory(logger, basedb.Options{
+		Type: "badger-memory",
+		Path: "",
+	})
+	require.NoError(t, err)
+	s := NewNodeStorage(db)
+
+	entries, err := s.GetEventJournal()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	owner := common.HexToAddress("0x1")
+	share := &types.SSVShare{
+		Share:    spectypes.Share{ValidatorPubKey: []byte("pk"), Committee: []*spectypes.Operator{{OperatorID: 1}}, Quorum: 3, PartialQuorum: 2},
+		Metadata: types.Metadata{OwnerAddress: owner},
+	}
+	entry := &EventJournalEntry{
+		BlockNumber:    10,
+		BlockHash:      common.HexToHash("0x2"),
+		TxHash:         common.HexToHash("0x3"),
+		LogIndex:       1,
+		Operators:      map[uint64]*registrystorage.OperatorData{1: {ID: 1, PublicKey: []byte("operator-pk")}, 2: nil},
+		Shares:         map[string]*types.SSVShare{"pk": share, "removed": nil},
+		Recipients:     map[common.Address]*registrystorage.RecipientData{owner: nil},
+		RemovedSecrets: []string{"pk"},
+	}
+	require.NoError(t, s.SaveEventJournal([]*EventJournalEntry{entry}))
+	entries, err = s.GetEventJournal()
+	require.NoError(t, err)
+	require.Equal(t, []*EventJournalEntry{entry}, entries)
+
+	logs := []ethtypes.Log{{
+		Address:     owner,
+		Topics:      []common.Hash{common.HexToHash("0x4")},
+		Data:        []byte{},
+		BlockNumber: 10,
+		TxHash:      common.HexToHash("0x3"),
+		BlockHash:   common.HexToHash("0x2"),
+		Index:       1,
+	}}
+	require.NoError(t, s.SaveRecentLogs(logs))
+	savedLogs, err := s.GetRecentLogs()
+	require.NoError(t, err)
+	require.Equal(t, logs, savedLogs)
+
+	require.NoError(t, s.CleanRegistryData())
+	entries, err = s.GetEventJournal()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	savedLogs, err = s.GetRecentLogs()
+	require.NoError(t, err)
+	require.Empty(t, savedLogs)
+}
+
+func TestSetupKeystorePrivateKey(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := ssvstorage.GetStorageFactory(logger, basedb.Options{
+		Type: "badger-memory",
+		Path: "",
+	})
+	require.NoError(t, err)
+	defer db.Close(logger)
+
+	operatorStorage := storage{
+		db: db,
+	}
+
+	KeyByte, err := base64.StdEncoding.DecodeString(skPem)
+	require.NoError(t, err)
+	sk, err := rsaencryption.ConvertPemToPrivateKey(string(KeyByte))
+	require.NoError(t, err)
+
+	pk, err := operatorStorage.SetupKeystorePrivateKey(logger, sk)
+	require.NoError(t, err)
+	require.Equal(t, pkPem, string(pk))
+
+	// the key is available but is not saved to the db
+	got, found, err := operatorStorage.GetPrivateKey()
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, sk.Equal(got))
+	_, found, err = db.Get(storagePrefix, []byte("private-key"))
+	require.NoError(t, err)
+	require.False(t, found)
+}
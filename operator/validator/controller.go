@@ -24,6 +24,8 @@ import (
 	"github.com/bloxapp/ssv/ibft/storage"
 	"github.com/bloxapp/ssv/network"
 	forksfactory "github.com/bloxapp/ssv/network/forks/factory"
+	"github.com/bloxapp/ssv/operator/doppelganger"
+	"github.com/bloxapp/ssv/operator/slot_ticker"
 	nodestorage "github.com/bloxapp/ssv/operator/storage"
 	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
@@ -58,6 +60,12 @@ type GetRecipientDataFunc func(owner common.Address) (*registrystorage.Recipient
 // ShareEventHandlerFunc is a function that handles event in an extended mode
 type ShareEventHandlerFunc func(share *types.SSVShare)
 
+// SharesCache is a cached lookup of validator shares,
+// it is used for the shares of non-committee messages instead of reading RegistryStorage per message
+type SharesCache interface {
+	GetShare(key []byte) (*types.SSVShare, bool, error)
+}
+
 // ControllerOptions for creating a validator controller
 type ControllerOptions struct {
 	Context                    context.Context
@@ -74,12 +82,18 @@ type ControllerOptions struct {
 	FullNode                   bool `yaml:"FullNode" env:"FULLNODE" env-default:"false" env-description:"Save decided history rather than just highest messages"`
 	Exporter                   bool `yaml:"Exporter" env:"EXPORTER" env-default:"false" env-description:""`
 	BuilderProposals           bool `yaml:"BuilderProposals" env:"BUILDER_PROPOSALS" env-default:"false" env-description:"Use external builders to produce blocks"`
+	CleanDecidedOnLiquidation  bool `yaml:"CleanDecidedOnLiquidation" env:"CLEAN_DECIDED_ON_LIQUIDATION" env-default:"false" env-description:"Remove the decided history of validators when their cluster is liquidated"`
 	KeyManager                 spectypes.KeyManager
 	OperatorData               *registrystorage.OperatorData
 	RegistryStorage            nodestorage.Storage
+	SharesCache                SharesCache
 	ForkVersion                forksprotocol.ForkVersion
 	NewDecidedHandler          qbftcontroller.NewDecidedHandler
 	DutyRoles                  []spectypes.BeaconRole
+	SlotTicker                 slot_ticker.Ticker
+	DoppelgangerProtection     bool   `yaml:"DoppelgangerProtection" env:"DOPPELGANGER_PROTECTION" env-default:"false" env-description:"Delay the start of validators until no other instance of this operator was seen signing for them"`
+	DoppelgangerEpochs         uint64 `yaml:"DoppelgangerEpochs" env:"DOPPELGANGER_EPOCHS" env-default:"2" env-description:"Number of epochs to watch for doppelgangers before a validator starts"`
+	VoluntaryExits             bool   `yaml:"VoluntaryExits" env:"VOLUNTARY_EXITS" env-default:"false" env-description:"Enable voluntary exits of validators, which use message types that are not part of the SSV spec and must be enabled by all the operators of a committee"`
 
 	// worker flags
 	WorkersCount    int `yaml:"MsgWorkersCount" env:"MSG_WORKERS_COUNT" env-default:"256" env-description:"Number of goroutines to use for message workers"`
@@ -94,6 +108,7 @@ type Controller interface {
 	StartValidators(logger *zap.Logger)
 	GetValidatorsIndices(logger *zap.Logger) []phase0.ValidatorIndex
 	GetValidator(pubKey string) (*validator.Validator, bool)
+	GetRunningValidators() []*validator.Validator
 	UpdateValidatorMetaDataLoop(logger *zap.Logger)
 	StartNetworkHandlers(logger *zap.Logger)
 	Eth1EventHandler(logger *zap.Logger, ongoingSync bool) eth1.SyncEventHandler
@@ -104,7 +119,12 @@ type Controller interface {
 	//  - the amount of validators assigned to this operator
 	GetValidatorStats(logger *zap.Logger) (uint64, uint64, uint64, error)
 	GetOperatorData() *registrystorage.OperatorData
-	//OnFork(forkVersion forksprotocol.ForkVersion) error
+	// ExitValidator starts a voluntary exit of the given validator at the given epoch
+	ExitValidator(logger *zap.Logger, pubKey []byte, epoch phase0.Epoch) error
+	// DoppelgangerStatus returns the doppelganger status of the given validator,
+	// or an empty string if doppelganger protection is disabled
+	DoppelgangerStatus(pubKey string) string
+	forksprotocol.ForkHandler
 }
 
 // controller implements Controller
@@ -112,6 +132,7 @@ type controller struct {
 	context context.Context
 
 	sharesStorage     registrystorage.Shares
+	sharesCache       SharesCache
 	operatorsStorage  registrystorage.Operators
 	recipientsStorage registrystorage.Recipients
 	ibftStorageMap    *storage.QBFTStores
@@ -125,6 +146,14 @@ type controller struct {
 	validatorsMap    *validatorsMap
 	validatorOptions *validator.Options
 
+	// eventJournal is used to revert contract events that are removed by chain reorgs
+	eventJournal *eventJournal
+
+	// doppelganger delays the start of validators, nil if doppelganger protection is disabled
+	doppelganger doppelganger.Handler
+
+	cleanDecidedOnLiquidation bool
+
 	metadataUpdateQueue    utilsprotocol.Queue
 	metadataUpdateInterval time.Duration
 
@@ -144,7 +173,7 @@ type controller struct {
 // NewController creates a new validator controller instance
 func NewController(logger *zap.Logger, options ControllerOptions) Controller {
 	logger.Debug("CreatingController", zap.Bool("full_node", options.FullNode), fields.BuilderProposals(options.BuilderProposals))
-	storageMap := storage.NewStores()
+	storageMap := storage.NewStores(options.DB)
 	storageMap.Add(spectypes.BNRoleAttester, storage.New(options.DB, spectypes.BNRoleAttester.String(), options.ForkVersion))
 	storageMap.Add(spectypes.BNRoleProposer, storage.New(options.DB, spectypes.BNRoleProposer.String(), options.ForkVersion))
 	storageMap.Add(spectypes.BNRoleAggregator, storage.New(options.DB, spectypes.BNRoleAggregator.String(), options.ForkVersion))
@@ -164,9 +193,10 @@ func NewController(logger *zap.Logger, options ControllerOptions) Controller {
 	}
 
 	validatorOptions := &validator.Options{ //TODO add vars
-		Network: options.Network,
-		Beacon:  options.Beacon,
-		Storage: storageMap,
+		Network:       options.Network,
+		BeaconNetwork: options.ETHNetwork,
+		Beacon:        options.Beacon,
+		Storage:       storageMap,
 		//Share:   nil,  // set per validator
 		Signer: options.KeyManager,
 		//Mode: validator.ModeRW // set per validator
@@ -176,6 +206,7 @@ func NewController(logger *zap.Logger, options ControllerOptions) Controller {
 		Exporter:          options.Exporter,
 		BuilderProposals:  options.BuilderProposals,
 		GasLimit:          options.GasLimit,
+		VoluntaryExits:    options.VoluntaryExits,
 	}
 
 	// If full node, increase queue size to make enough room
@@ -189,6 +220,7 @@ func NewController(logger *zap.Logger, options ControllerOptions) Controller {
 
 	ctrl := controller{
 		sharesStorage:              options.RegistryStorage,
+		sharesCache:                options.RegistryStorage,
 		operatorsStorage:           options.RegistryStorage,
 		recipientsStorage:          options.RegistryStorage,
 		ibftStorageMap:             storageMap,
@@ -202,6 +234,9 @@ func NewController(logger *zap.Logger, options ControllerOptions) Controller {
 
 		validatorsMap:    newValidatorsMap(options.Context, validatorOptions),
 		validatorOptions: validatorOptions,
+		eventJournal:     newEventJournal(logger, options.RegistryStorage),
+
+		cleanDecidedOnLiquidation: options.CleanDecidedOnLiquidation,
 
 		metadataUpdateQueue:    tasks.NewExecutionQueue(10 * time.Millisecond),
 		metadataUpdateInterval: options.MetadataUpdateInterval,
@@ -215,6 +250,22 @@ func NewController(logger *zap.Logger, options ControllerOptions) Controller {
 		nonCommitteeLocks: make(map[spectypes.MessageID]*sync.Mutex),
 	}
 
+	if options.SharesCache != nil {
+		ctrl.sharesCache = options.SharesCache
+	}
+
+	if options.DoppelgangerProtection && !options.Exporter {
+		ctrl.doppelganger = doppelganger.New(&doppelganger.Options{
+			Ctx:        options.Context,
+			Beacon:     options.Beacon,
+			Network:    options.ETHNetwork,
+			Ticker:     options.SlotTicker,
+			OperatorID: options.OperatorData.ID,
+			Epochs:     options.DoppelgangerEpochs,
+			OnSafe:     ctrl.onDoppelgangerSafe,
+		})
+	}
+
 	if err := ctrl.initShares(logger, options); err != nil {
 		logger.Panic("could not initialize shares", zap.Error(err))
 	}
@@ -257,6 +308,13 @@ func (c *controller) GetOperatorData() *registrystorage.OperatorData {
 	return c.operatorData
 }
 
+// OnFork is called upon a fork version change
+func (c *controller) OnFork(logger *zap.Logger, forkVersion forksprotocol.ForkVersion) error {
+	logger.Info("forking validators controller", fields.Fork(forkVersion))
+	c.forkVersion = forkVersion
+	return nil
+}
+
 func (c *controller) GetValidatorStats(logger *zap.Logger) (uint64, uint64, uint64, error) {
 	allShares, err := c.sharesStorage.GetAllShares(logger)
 	if err != nil {
@@ -294,6 +352,9 @@ func (c *controller) handleRouterMessages(logger *zap.Logger) {
 			pk := msg.GetID().GetPubKey()
 			hexPK := hex.EncodeToString(pk)
 			if v, ok := c.validatorsMap.GetValidator(hexPK); ok {
+				if c.doppelganger != nil {
+					c.doppelganger.ObserveMessage(logger, &msg)
+				}
 				v.HandleMessage(logger, &msg)
 			} else {
 				if msg.MsgType != spectypes.SSVConsensusMsgType {
@@ -321,17 +382,22 @@ func (c *controller) getShare(pk spectypes.ValidatorPK) (*types.SSVShare, error)
 }
 
 func (c *controller) handleWorkerMessages(logger *zap.Logger, msg *spectypes.SSVMessage) error {
-	share, err := c.getShare(msg.GetID().GetPubKey())
+	share, found, err := c.sharesCache.GetShare(msg.GetID().GetPubKey())
 	if err != nil {
-		return err
+		return errors.Wrapf(err, "could not read validator share [%s]", hex.EncodeToString(msg.GetID().GetPubKey()))
 	}
-	if share == nil {
+	if !found {
 		return errors.Errorf("could not find validator [%s]", hex.EncodeToString(msg.GetID().GetPubKey()))
 	}
 
 	opts := *c.validatorOptions
 	opts.SSVShare = share
 
+	// roles without consensus (e.g. voluntary exit) have no decided messages to process
+	if opts.Storage.Get(msg.GetID().GetRoleType()) == nil {
+		return nil
+	}
+
 	// Lock this message ID.
 	lock := func() *sync.Mutex {
 		c.nonCommitteeMutex.Lock()
@@ -377,10 +443,17 @@ func (c *controller) ListenToEth1Events(logger *zap.Logger, feed *event.Feed) {
 func (c *controller) StartValidators(logger *zap.Logger) {
 	logger = logger.Named(logging.NameController)
 
+	if err := c.ibftStorageMap.ResumeCleanups(logger); err != nil {
+		logger.Error("could not resume decided cleanups", zap.Error(err))
+	}
+
 	if c.validatorOptions.Exporter {
 		c.setupNonCommitteeValidators(logger)
 		return
 	}
+	if c.doppelganger != nil {
+		go c.doppelganger.Start(logger)
+	}
 
 	shares, err := c.sharesStorage.GetFilteredShares(logger, registrystorage.ByOperatorIDAndNotLiquidated(c.operatorData.ID))
 	if err != nil {
@@ -406,7 +479,7 @@ func (c *controller) setupValidators(logger *zap.Logger, shares []*types.SSVShar
 			logger.Warn("could not start validator", fields.PubKey(validatorShare.ValidatorPubKey), zap.Error(err))
 			errs = append(errs, err)
 		}
-		if !isStarted && err == nil {
+		if !isStarted && err == nil && !validatorShare.HasBeaconMetadata() {
 			// Fetch metadata, if needed.
 			fetchMetadata = append(fetchMetadata, validatorShare.ValidatorPubKey)
 		}
@@ -528,6 +601,16 @@ func (c *controller) GetValidator(pubKey string) (*validator.Validator, bool) {
 	return c.validatorsMap.GetValidator(pubKey)
 }
 
+// GetRunningValidators returns the validators that were started by this node
+func (c *controller) GetRunningValidators() []*validator.Validator {
+	var validators []*validator.Validator
+	_ = c.validatorsMap.ForEach(func(v *validator.Validator) error {
+		validators = append(validators, v)
+		return nil
+	})
+	return validators
+}
+
 // GetValidatorsIndices returns a list of all the active validators indices
 // and fetch indices for missing once (could be first time attesting or non active once)
 func (c *controller) GetValidatorsIndices(logger *zap.Logger) []phase0.ValidatorIndex {
@@ -536,7 +619,8 @@ func (c *controller) GetValidatorsIndices(logger *zap.Logger) []phase0.Validator
 	indices := make([]phase0.ValidatorIndex, 0, len(c.validatorsMap.validatorsMap))
 	err := c.validatorsMap.ForEach(func(v *validator.Validator) error {
 		// Beacon node throws error when trying to fetch duties for non-existing validators.
-		if v.Share.BeaconMetadata.IsActive() {
+		// Validators that wait for doppelganger protection don't perform duties.
+		if v.Share.BeaconMetadata.IsActive() && c.canSign(v) {
 			indices = append(indices, v.Share.BeaconMetadata.Index)
 		}
 		return nil
@@ -616,6 +700,9 @@ func (c *controller) onShareCreate(logger *zap.Logger, validatorEvent abiparser.
 func (c *controller) onShareRemove(pk string, removeSecret bool) error {
 	// remove from validatorsMap
 	v := c.validatorsMap.RemoveValidator(pk)
+	if c.doppelganger != nil {
+		c.doppelganger.Remove(pk)
+	}
 
 	// stop instance
 	if v != nil {
@@ -655,6 +742,14 @@ func (c *controller) startValidator(logger *zap.Logger, v *validator.Validator)
 	if v.Share.BeaconMetadata.Index == 0 {
 		return false, errors.New("could not start validator: index not found")
 	}
+	if !c.canSign(v) {
+		// the validator is started by onDoppelgangerSafe, in the meantime its topic is watched for doppelgangers
+		if err := c.network.Subscribe(v.Share.ValidatorPubKey); err != nil {
+			return false, errors.Wrap(err, "could not subscribe to validator topic")
+		}
+		c.doppelganger.Watch(logger, v.Share)
+		return false, nil
+	}
 	if err := v.Start(logger); err != nil {
 		metricsValidatorStatus.WithLabelValues(hex.EncodeToString(v.Share.ValidatorPubKey)).Set(float64(validatorStatusError))
 		return false, errors.Wrap(err, "could not start validator")
@@ -662,6 +757,30 @@ func (c *controller) startValidator(logger *zap.Logger, v *validator.Validator)
 	return true, nil
 }
 
+// canSign returns true if the given validator isn't waiting for doppelganger protection
+func (c *controller) canSign(v *validator.Validator) bool {
+	return c.doppelganger == nil || c.doppelganger.CanSign(hex.EncodeToString(v.Share.ValidatorPubKey))
+}
+
+// onDoppelgangerSafe starts a validator once no doppelganger was detected for it
+func (c *controller) onDoppelgangerSafe(logger *zap.Logger, pk string) {
+	v, ok := c.validatorsMap.GetValidator(pk)
+	if !ok {
+		return
+	}
+	if _, err := c.startValidator(logger, v); err != nil {
+		logger.Warn("could not start validator after doppelganger check", zap.String("pk", pk), zap.Error(err))
+	}
+}
+
+// DoppelgangerStatus returns the doppelganger status of the given validator
+func (c *controller) DoppelgangerStatus(pubKey string) string {
+	if c.doppelganger == nil {
+		return ""
+	}
+	return c.doppelganger.Status(pubKey).String()
+}
+
 // UpdateValidatorMetaDataLoop updates metadata of validators in an interval
 func (c *controller) UpdateValidatorMetaDataLoop(logger *zap.Logger) {
 	logger = logger.Named(logging.NameController)
@@ -701,6 +820,10 @@ func SetupRunners(ctx context.Context, logger *zap.Logger, options validator.Opt
 		spectypes.BNRoleSyncCommitteeContribution,
 		spectypes.BNRoleValidatorRegistration,
 	}
+	// without a runner, messages of voluntary exits are neither sent nor processed
+	if options.VoluntaryExits {
+		runnersType = append(runnersType, message.BNRoleVoluntaryExit)
+	}
 
 	domainType := types.GetDefaultDomain()
 	buildController := func(role spectypes.BeaconRole, valueCheckF specqbft.ProposedValueCheckF) *qbftcontroller.Controller {
@@ -726,33 +849,44 @@ func SetupRunners(ctx context.Context, logger *zap.Logger, options validator.Opt
 		return qbftCtrl
 	}
 
+	specNetwork, accurate := specValueCheckNetwork(options.BeaconNetwork)
+	valueCheck := func(check specqbft.ProposedValueCheckF) specqbft.ProposedValueCheckF {
+		if accurate {
+			return check
+		}
+		return withEpochCheck(options.BeaconNetwork, check)
+	}
+
 	runners := runner.DutyRunners{}
 	for _, role := range runnersType {
 		switch role {
 		case spectypes.BNRoleAttester:
-			valCheck := specssv.AttesterValueCheckF(options.Signer, spectypes.PraterNetwork, options.SSVShare.Share.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index, options.SSVShare.SharePubKey)
+			valCheck := valueCheck(specssv.AttesterValueCheckF(options.Signer, specNetwork, options.SSVShare.Share.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index, options.SSVShare.SharePubKey))
 			qbftCtrl := buildController(spectypes.BNRoleAttester, valCheck)
-			runners[role] = runner.NewAttesterRunnner(spectypes.PraterNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, valCheck, 0)
+			runners[role] = runner.NewAttesterRunnner(options.BeaconNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, valCheck, 0)
 		case spectypes.BNRoleProposer:
-			proposedValueCheck := specssv.ProposerValueCheckF(options.Signer, spectypes.PraterNetwork, options.SSVShare.Share.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index, options.SSVShare.SharePubKey, options.BuilderProposals)
+			proposedValueCheck := valueCheck(specssv.ProposerValueCheckF(options.Signer, specNetwork, options.SSVShare.Share.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index, options.SSVShare.SharePubKey, options.BuilderProposals))
 			qbftCtrl := buildController(spectypes.BNRoleProposer, proposedValueCheck)
-			runners[role] = runner.NewProposerRunner(spectypes.PraterNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, proposedValueCheck, 0)
+			runners[role] = runner.NewProposerRunner(options.BeaconNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, proposedValueCheck, 0)
 			runners[role].(*runner.ProposerRunner).ProducesBlindedBlocks = options.BuilderProposals // apply blinded block flag
 		case spectypes.BNRoleAggregator:
-			aggregatorValueCheckF := specssv.AggregatorValueCheckF(options.Signer, spectypes.PraterNetwork, options.SSVShare.Share.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index)
+			aggregatorValueCheckF := valueCheck(specssv.AggregatorValueCheckF(options.Signer, specNetwork, options.SSVShare.Share.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index))
 			qbftCtrl := buildController(spectypes.BNRoleAggregator, aggregatorValueCheckF)
-			runners[role] = runner.NewAggregatorRunner(spectypes.PraterNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, aggregatorValueCheckF, 0)
+			runners[role] = runner.NewAggregatorRunner(options.BeaconNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, aggregatorValueCheckF, 0)
 		case spectypes.BNRoleSyncCommittee:
-			syncCommitteeValueCheckF := specssv.SyncCommitteeValueCheckF(options.Signer, spectypes.PraterNetwork, options.SSVShare.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index)
+			syncCommitteeValueCheckF := valueCheck(specssv.SyncCommitteeValueCheckF(options.Signer, specNetwork, options.SSVShare.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index))
 			qbftCtrl := buildController(spectypes.BNRoleSyncCommittee, syncCommitteeValueCheckF)
-			runners[role] = runner.NewSyncCommitteeRunner(spectypes.PraterNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, syncCommitteeValueCheckF, 0)
+			runners[role] = runner.NewSyncCommitteeRunner(options.BeaconNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, syncCommitteeValueCheckF, 0)
 		case spectypes.BNRoleSyncCommitteeContribution:
-			syncCommitteeContributionValueCheckF := specssv.SyncCommitteeContributionValueCheckF(options.Signer, spectypes.PraterNetwork, options.SSVShare.Share.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index)
+			syncCommitteeContributionValueCheckF := valueCheck(specssv.SyncCommitteeContributionValueCheckF(options.Signer, specNetwork, options.SSVShare.Share.ValidatorPubKey, options.SSVShare.BeaconMetadata.Index))
 			qbftCtrl := buildController(spectypes.BNRoleSyncCommitteeContribution, syncCommitteeContributionValueCheckF)
-			runners[role] = runner.NewSyncCommitteeAggregatorRunner(spectypes.PraterNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, syncCommitteeContributionValueCheckF, 0)
+			runners[role] = runner.NewSyncCommitteeAggregatorRunner(options.BeaconNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer, syncCommitteeContributionValueCheckF, 0)
 		case spectypes.BNRoleValidatorRegistration:
 			qbftCtrl := buildController(spectypes.BNRoleValidatorRegistration, nil)
-			runners[role] = runner.NewValidatorRegistrationRunner(spectypes.PraterNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer)
+			runners[role] = runner.NewValidatorRegistrationRunner(options.BeaconNetwork, &options.SSVShare.Share, qbftCtrl, options.Beacon, options.Network, options.Signer)
+		case message.BNRoleVoluntaryExit:
+			// voluntary exit has no consensus phase, hence no controller
+			runners[role] = runner.NewVoluntaryExitRunner(options.BeaconNetwork, &options.SSVShare.Share, options.Beacon, options.Network, options.Signer)
 		}
 	}
 	return runners
@@ -6,19 +6,25 @@ import (
 	"testing"
 	"time"
 
-	"github.com/bloxapp/ssv/logging"
-
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
 	spectypes "github.com/bloxapp/ssv-spec/types"
+	spectestingutils "github.com/bloxapp/ssv-spec/types/testingutils"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	qbftstorage "github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/logging"
 	"github.com/bloxapp/ssv/network/forks/genesis"
+	forksprotocol "github.com/bloxapp/ssv/protocol/forks"
 	"github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	"github.com/bloxapp/ssv/protocol/v2/message"
 	"github.com/bloxapp/ssv/protocol/v2/queue/worker"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/validator"
 	"github.com/bloxapp/ssv/protocol/v2/types"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
 )
 
 // TODO: increase test coverage, add more tests, e.g.:
@@ -135,6 +141,75 @@ func TestGetIndices(t *testing.T) {
 	require.Equal(t, 1, len(indices)) // should return only active indices
 }
 
+func TestSetupRunners(t *testing.T) {
+	goerli, err := beacon.NetworkFromOptions(beacon.Options{Network: "goerli"})
+	require.NoError(t, err)
+	networks := map[string]beacon.Network{
+		"mainnet": beacon.NewNetwork(core.MainNetwork, 0),
+		"prater":  beacon.NewNetwork(core.PraterNetwork, 0),
+		"goerli":  goerli,
+		"devnet":  beacon.NewCustomNetwork("devnet", 1680000000, phase0.Version{0x00, 0x00, 0x00, 0x69}),
+	}
+
+	logger := logging.TestLogger(t)
+	keySet := spectestingutils.Testing4SharesSet()
+
+	for name, network := range networks {
+		network := network
+		t.Run(name, func(t *testing.T) {
+			options := validator.Options{
+				Network:       spectestingutils.NewTestingNetwork(),
+				BeaconNetwork: network,
+				Beacon:        spectestingutils.NewTestingBeaconNode(),
+				Storage:       newTestStores(t, logger),
+				Signer:        spectestingutils.NewTestingKeyManager(),
+				SSVShare: &types.SSVShare{
+					Share: *spectestingutils.TestingShare(keySet),
+					Metadata: types.Metadata{
+						BeaconMetadata: &beacon.ValidatorMetadata{
+							Index: 1,
+						},
+					},
+				},
+			}
+
+			runners := SetupRunners(context.Background(), logger, options)
+			require.Len(t, runners, 6)
+			for role, r := range runners {
+				require.Equal(t, network, r.GetBaseRunner().BeaconNetwork, "role %s", role.String())
+			}
+
+			// duties too far into the future are rejected with the genesis of the network
+			cd := *spectestingutils.TestSyncCommitteeConsensusData
+			cd.Duty.Slot = network.FirstSlotAtEpoch(network.EstimatedCurrentEpoch() + 2)
+			data, err := cd.Encode()
+			require.NoError(t, err)
+			require.EqualError(t, runners[spectypes.BNRoleSyncCommittee].GetValCheckF()(data), "duty epoch is into far future")
+		})
+	}
+}
+
+func newTestStores(t *testing.T, logger *zap.Logger) *qbftstorage.QBFTStores {
+	db, err := storage.GetStorageFactory(logger, basedb.Options{
+		Type: "badger-memory",
+		Path: "",
+	})
+	require.NoError(t, err)
+
+	stores := qbftstorage.NewStores(db)
+	for _, role := range []spectypes.BeaconRole{
+		spectypes.BNRoleAttester,
+		spectypes.BNRoleProposer,
+		spectypes.BNRoleAggregator,
+		spectypes.BNRoleSyncCommittee,
+		spectypes.BNRoleSyncCommitteeContribution,
+		spectypes.BNRoleValidatorRegistration,
+	} {
+		stores.Add(role, qbftstorage.New(db, role.String(), forksprotocol.GenesisForkVersion))
+	}
+	return stores
+}
+
 func setupController(logger *zap.Logger, validators map[string]*validator.Validator) controller {
 	return controller{
 		context:                    context.Background(),
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/hex"
 
-	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -17,9 +16,16 @@ import (
 	registrystorage "github.com/bloxapp/ssv/registry/storage"
 )
 
-// Eth1EventHandler is a factory function for creating eth1 event handler
+// Eth1EventHandler is a factory function for creating eth1 event handler.
+// Events that were removed by a chain reorg are reverted, using the state that was saved before handling them.
 func (c *controller) Eth1EventHandler(logger *zap.Logger, ongoingSync bool) eth1.SyncEventHandler {
 	return func(e eth1.Event) ([]zap.Field, error) {
+		if e.Log.Removed {
+			return c.revertEvent(logger, e, ongoingSync)
+		}
+		if err := c.journalEvent(logger, e); err != nil {
+			return nil, errors.Wrap(err, "could not journal event")
+		}
 		switch e.Name {
 		case abiparser.OperatorAdded:
 			ev := e.Data.(abiparser.OperatorAddedEvent)
@@ -91,7 +97,8 @@ func (c *controller) handleOperatorAddedEvent(logger *zap.Logger, event abiparse
 	return logFields, nil
 }
 
-// handleOperatorRemovedEvent parses the given event and removing operator data
+// handleOperatorRemovedEvent parses the given event and removes operator data,
+// in case the removed operator is this node's operator, all of its validators are stopped
 func (c *controller) handleOperatorRemovedEvent(
 	logger *zap.Logger,
 	event abiparser.OperatorRemovedEvent,
@@ -107,12 +114,48 @@ func (c *controller) handleOperatorRemovedEvent(
 		}
 	}
 
+	isOperatorEvent := od.ID == c.operatorData.ID
+	var stoppedPubKeys []string
+	if isOperatorEvent {
+		shares, err := c.sharesStorage.GetFilteredShares(logger, registrystorage.ByOperatorID(od.ID))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get validator shares by operator id")
+		}
+		for _, share := range shares {
+			pubKey := hex.EncodeToString(share.ValidatorPubKey)
+			metricsValidatorStatus.WithLabelValues(pubKey).Set(float64(validatorStatusRemoved))
+			stoppedPubKeys = append(stoppedPubKeys, pubKey)
+			if ongoingSync {
+				// the share secret is kept, so the validator could be restarted in case the event is reverted
+				if err := c.onShareRemove(pubKey, false); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := c.operatorsStorage.DeleteOperatorData(od.ID); err != nil {
+		return nil, errors.Wrap(err, "could not delete operator data")
+	}
+	if isOperatorEvent {
+		c.operatorData = &registrystorage.OperatorData{PublicKey: c.operatorData.PublicKey}
+	}
+	if c.network != nil {
+		c.network.RevokeOperator(logger, od.PublicKey)
+	}
+	exporter.ReportOperatorRemoved(logger, od)
+
 	logFields := make([]zap.Field, 0)
-	logFields = append(logFields,
-		zap.Uint64("operatorId", od.ID),
-		zap.String("operatorPubKey", string(od.PublicKey)),
-		zap.String("ownerAddress", od.OwnerAddress.String()),
-	)
+	if isOperatorEvent || c.validatorOptions.FullNode {
+		logFields = append(logFields,
+			zap.Uint64("operatorId", od.ID),
+			zap.String("operatorPubKey", string(od.PublicKey)),
+			zap.String("ownerAddress", od.OwnerAddress.String()),
+		)
+	}
+	if len(stoppedPubKeys) > 0 {
+		logFields = append(logFields, zap.Strings("stoppedValidators", stoppedPubKeys))
+	}
 
 	return logFields, nil
 }
@@ -184,13 +227,9 @@ func (c *controller) handleValidatorRemovedEvent(
 		}
 	}
 
-	// remove decided messages
-	messageID := spectypes.NewMsgID(types.GetDefaultDomain(), share.ValidatorPubKey, spectypes.BNRoleAttester)
-	store := c.ibftStorageMap.Get(messageID.GetRoleType())
-	if store != nil {
-		if err := store.CleanAllInstances(logger, messageID[:]); err != nil { // TODO need to delete for multi duty as well
-			return nil, errors.Wrap(err, "could not clean all decided messages")
-		}
+	// remove decided messages of all roles
+	if err := c.ibftStorageMap.CleanAllInstances(logger, share.ValidatorPubKey); err != nil {
+		return nil, errors.Wrap(err, "could not clean all decided messages")
 	}
 
 	// remove from storage
@@ -203,7 +242,8 @@ func (c *controller) handleValidatorRemovedEvent(
 		pubKey := hex.EncodeToString(event.PublicKey)
 		metricsValidatorStatus.WithLabelValues(pubKey).Set(float64(validatorStatusRemoved))
 		if ongoingSync {
-			if err := c.onShareRemove(hex.EncodeToString(share.ValidatorPubKey), true); err != nil {
+			// the share secret is removed once the event is out of the reorg window, see journalEvent
+			if err := c.onShareRemove(hex.EncodeToString(share.ValidatorPubKey), false); err != nil {
 				return nil, err
 			}
 		}
@@ -242,6 +282,18 @@ func (c *controller) handleClusterLiquidatedEvent(
 		}
 	}
 
+	if c.cleanDecidedOnLiquidation {
+		for _, pubKey := range liquidatedPubKeys {
+			rawPubKey, err := hex.DecodeString(pubKey)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not decode validator public key")
+			}
+			if err := c.ibftStorageMap.CleanAllInstances(logger, rawPubKey); err != nil {
+				return nil, errors.Wrap(err, "could not clean all decided messages")
+			}
+		}
+	}
+
 	logFields := make([]zap.Field, 0)
 	if len(liquidatedPubKeys) > 0 {
 		logFields = append(logFields,
@@ -0,0 +1,350 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/eth1/abiparser"
+	"github.com/bloxapp/ssv/logging/fields"
+	nodestorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/validator"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
+)
+
+// eventJournal keeps the registry state from before the recently handled events,
+// so the events could be reverted in case they are removed by a chain reorg.
+// The journal is persisted next to the sync offset, so reorgs that happen while the node is down are reverted as well.
+type eventJournal struct {
+	mu      sync.Mutex
+	entries []*nodestorage.EventJournalEntry
+	storage nodestorage.EventJournalStorage
+}
+
+// newEventJournal creates a journal that is persisted in the given storage (optional), and loads the saved entries
+func newEventJournal(logger *zap.Logger, storage nodestorage.EventJournalStorage) *eventJournal {
+	j := &eventJournal{storage: storage}
+	if storage != nil {
+		entries, err := storage.GetEventJournal()
+		if err != nil {
+			logger.Warn("could not load event journal, events that were removed while the node was down won't be reverted", zap.Error(err))
+		}
+		j.entries = entries
+	}
+	return j
+}
+
+// add adds an entry and returns the entries that were dropped for being out of the reorg window.
+// An entry of a log that is already journaled is ignored, e.g. when the history is synced again after a restart,
+// since only the first one holds the state from before the event.
+func (j *eventJournal) add(logger *zap.Logger, entry *nodestorage.EventJournalEntry) []*nodestorage.EventJournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.find(entry.BlockHash, entry.TxHash, entry.LogIndex) >= 0 {
+		return nil
+	}
+	i := 0
+	for i < len(j.entries) && j.entries[i].BlockNumber+eth1.ReorgWindow < entry.BlockNumber {
+		i++
+	}
+	dropped := append([]*nodestorage.EventJournalEntry{}, j.entries[:i]...)
+	j.entries = append(j.entries[i:], entry)
+	j.save(logger)
+	return dropped
+}
+
+// pop removes and returns the entry of the given log, or nil if not found
+func (j *eventJournal) pop(logger *zap.Logger, vLog ethtypes.Log) *nodestorage.EventJournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	i := j.find(vLog.BlockHash, vLog.TxHash, vLog.Index)
+	if i < 0 {
+		return nil
+	}
+	entry := j.entries[i]
+	j.entries = append(j.entries[:i], j.entries[i+1:]...)
+	j.save(logger)
+	return entry
+}
+
+// find returns the index of the entry of the given log, or -1 if not found. The caller must hold mu.
+func (j *eventJournal) find(blockHash, txHash common.Hash, index uint) int {
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		entry := j.entries[i]
+		if entry.BlockHash == blockHash && entry.TxHash == txHash && entry.LogIndex == index {
+			return i
+		}
+	}
+	return -1
+}
+
+// save persists the journal. The caller must hold mu.
+func (j *eventJournal) save(logger *zap.Logger) {
+	if j.storage == nil {
+		return
+	}
+	if err := j.storage.SaveEventJournal(j.entries); err != nil {
+		logger.Warn("could not save event journal", zap.Error(err))
+	}
+}
+
+// journalEvent saves the registry state that is about to be changed by the given event
+func (c *controller) journalEvent(logger *zap.Logger, e eth1.Event) error {
+	entry := &nodestorage.EventJournalEntry{
+		BlockNumber: e.Log.BlockNumber,
+		BlockHash:   e.Log.BlockHash,
+		TxHash:      e.Log.TxHash,
+		LogIndex:    e.Log.Index,
+		Operators:   make(map[uint64]*registrystorage.OperatorData),
+		Shares:      make(map[string]*types.SSVShare),
+		Recipients:  make(map[common.Address]*registrystorage.RecipientData),
+	}
+
+	switch ev := e.Data.(type) {
+	case abiparser.OperatorAddedEvent:
+		if err := c.journalOperator(entry, ev.OperatorId); err != nil {
+			return err
+		}
+	case abiparser.OperatorRemovedEvent:
+		if err := c.journalOperator(entry, ev.OperatorId); err != nil {
+			return err
+		}
+	case abiparser.ValidatorAddedEvent:
+		if err := c.journalShare(entry, ev.PublicKey); err != nil {
+			return err
+		}
+	case abiparser.ValidatorRemovedEvent:
+		if err := c.journalShare(entry, ev.PublicKey); err != nil {
+			return err
+		}
+		// the share secret is kept while the event could be reverted, so the validator could be restarted
+		pubKey := hex.EncodeToString(ev.PublicKey)
+		if share := entry.Shares[pubKey]; share != nil && share.BelongsToOperator(c.operatorData.ID) {
+			entry.RemovedSecrets = append(entry.RemovedSecrets, pubKey)
+		}
+	case abiparser.ClusterLiquidatedEvent:
+		if err := c.journalCluster(logger, entry, ev.Owner, ev.OperatorIds); err != nil {
+			return err
+		}
+	case abiparser.ClusterReactivatedEvent:
+		if err := c.journalCluster(logger, entry, ev.Owner, ev.OperatorIds); err != nil {
+			return err
+		}
+	case abiparser.FeeRecipientAddressUpdatedEvent:
+		r, found, err := c.recipientsStorage.GetRecipientData(ev.Owner)
+		if err != nil {
+			return errors.Wrap(err, "could not get recipient data")
+		}
+		if !found {
+			r = nil
+		}
+		entry.Recipients[ev.Owner] = r
+	default:
+		return nil
+	}
+
+	for _, dropped := range c.eventJournal.add(logger, entry) {
+		c.removeSecrets(logger, dropped.RemovedSecrets)
+	}
+	return nil
+}
+
+// removeSecrets removes the share secrets of removed validators, once their removal can't be reverted anymore
+func (c *controller) removeSecrets(logger *zap.Logger, pubKeys []string) {
+	for _, pubKey := range pubKeys {
+		rawPubKey, err := hex.DecodeString(pubKey)
+		if err != nil {
+			logger.Warn("could not decode validator public key", zap.String("pubKey", pubKey), zap.Error(err))
+			continue
+		}
+		// the validator might have been added again in the meantime
+		if _, found, err := c.sharesStorage.GetShare(rawPubKey); err != nil || found {
+			continue
+		}
+		if err := c.keyManager.RemoveShare(pubKey); err != nil {
+			logger.Warn("could not remove share secret from key manager", fields.PubKey(rawPubKey), zap.Error(err))
+		}
+	}
+}
+
+func (c *controller) journalOperator(entry *nodestorage.EventJournalEntry, id uint64) error {
+	od, found, err := c.operatorsStorage.GetOperatorData(id)
+	if err != nil {
+		return errors.Wrap(err, "could not get operator data")
+	}
+	if !found {
+		od = nil
+	}
+	entry.Operators[id] = od
+	return nil
+}
+
+func (c *controller) journalShare(entry *nodestorage.EventJournalEntry, pubKey []byte) error {
+	share, found, err := c.sharesStorage.GetShare(pubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not get validator share")
+	}
+	if !found {
+		share = nil
+	}
+	entry.Shares[hex.EncodeToString(pubKey)] = share
+	return nil
+}
+
+func (c *controller) journalCluster(logger *zap.Logger, entry *nodestorage.EventJournalEntry, owner common.Address, operatorIDs []uint64) error {
+	clusterID, err := types.ComputeClusterIDHash(owner.Bytes(), operatorIDs)
+	if err != nil {
+		return errors.Wrap(err, "could not compute share cluster id")
+	}
+	shares, err := c.sharesStorage.GetFilteredShares(logger, registrystorage.ByClusterID(clusterID))
+	if err != nil {
+		return errors.Wrap(err, "could not get validator shares by cluster id")
+	}
+	for _, share := range shares {
+		entry.Shares[hex.EncodeToString(share.ValidatorPubKey)] = share
+	}
+	return nil
+}
+
+// revertEvent restores the registry state from before an event that was removed by a chain reorg,
+// and starts or stops the affected validators accordingly during ongoing sync
+// (during history sync the validators are started later on, according to the restored state).
+func (c *controller) revertEvent(logger *zap.Logger, e eth1.Event, ongoingSync bool) ([]zap.Field, error) {
+	entry := c.eventJournal.pop(logger, e.Log)
+	if entry == nil {
+		return nil, errors.New("could not find the state before the removed event, resync is required")
+	}
+
+	for id, od := range entry.Operators {
+		if err := c.restoreOperator(logger, id, od, ongoingSync); err != nil {
+			return nil, err
+		}
+	}
+	for owner, r := range entry.Recipients {
+		if err := c.restoreRecipient(owner, r); err != nil {
+			return nil, err
+		}
+	}
+	var revertedPubKeys []string
+	for pubKey, share := range entry.Shares {
+		reverted, err := c.restoreShare(logger, pubKey, share, ongoingSync)
+		if err != nil {
+			return nil, err
+		}
+		if reverted {
+			revertedPubKeys = append(revertedPubKeys, pubKey)
+		}
+	}
+
+	logFields := []zap.Field{zap.Bool("reverted", true)}
+	if len(revertedPubKeys) > 0 {
+		logFields = append(logFields, zap.Strings("validators", revertedPubKeys))
+	}
+	return logFields, nil
+}
+
+func (c *controller) restoreOperator(logger *zap.Logger, id uint64, od *registrystorage.OperatorData, ongoingSync bool) error {
+	if od == nil {
+		if err := c.operatorsStorage.DeleteOperatorData(id); err != nil {
+			return errors.Wrap(err, "could not delete operator data")
+		}
+		if c.operatorData.ID == id {
+			c.operatorData = &registrystorage.OperatorData{PublicKey: c.operatorData.PublicKey}
+		}
+		return nil
+	}
+	if _, err := c.operatorsStorage.SaveOperatorData(logger, od); err != nil {
+		return errors.Wrap(err, "could not save operator data")
+	}
+	if c.operatorData.ID == od.ID || !bytes.Equal(c.operatorData.PublicKey, od.PublicKey) {
+		return nil
+	}
+	// the removal of this node's operator was reverted
+	c.operatorData = od
+	if !ongoingSync {
+		return nil
+	}
+	shares, err := c.sharesStorage.GetFilteredShares(logger, registrystorage.ByOperatorIDAndNotLiquidated(od.ID))
+	if err != nil {
+		return errors.Wrap(err, "could not get validator shares by operator id")
+	}
+	for _, share := range shares {
+		if _, err := c.onShareStart(logger, share); err != nil {
+			logger.Warn("could not start restored validator", fields.PubKey(share.ValidatorPubKey), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (c *controller) restoreRecipient(owner common.Address, r *registrystorage.RecipientData) error {
+	if r == nil {
+		if err := c.recipientsStorage.DeleteRecipientData(owner); err != nil {
+			return errors.Wrap(err, "could not delete recipient data")
+		}
+		r = &registrystorage.RecipientData{Owner: owner}
+		copy(r.FeeRecipient[:], owner.Bytes())
+	} else if _, err := c.recipientsStorage.SaveRecipientData(r); err != nil {
+		return errors.Wrap(err, "could not save recipient data")
+	}
+
+	_ = c.validatorsMap.ForEach(func(v *validator.Validator) error {
+		if v.Share.OwnerAddress == owner {
+			v.Share.FeeRecipientAddress = r.FeeRecipient
+		}
+		return nil
+	})
+	return nil
+}
+
+// restoreShare restores the given share, returns true if the share belongs to the operator
+func (c *controller) restoreShare(logger *zap.Logger, pubKey string, share *types.SSVShare, ongoingSync bool) (bool, error) {
+	rawPubKey, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return false, errors.Wrap(err, "could not decode validator public key")
+	}
+	current, found, err := c.sharesStorage.GetShare(rawPubKey)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get validator share")
+	}
+	isOperatorShare := (found && current.BelongsToOperator(c.operatorData.ID)) ||
+		(share != nil && share.BelongsToOperator(c.operatorData.ID))
+
+	if share == nil {
+		if found {
+			if err := c.sharesStorage.DeleteShare(rawPubKey); err != nil {
+				return false, errors.Wrap(err, "could not remove validator share")
+			}
+		}
+		if isOperatorShare {
+			metricsValidatorStatus.WithLabelValues(pubKey).Set(float64(validatorStatusRemoved))
+			if err := c.onShareRemove(pubKey, true); err != nil {
+				return false, err
+			}
+		}
+		return isOperatorShare, nil
+	}
+
+	if err := c.sharesStorage.SaveShare(logger, share); err != nil {
+		return false, errors.Wrap(err, "could not save validator share")
+	}
+	if !isOperatorShare || !ongoingSync {
+		return isOperatorShare, nil
+	}
+	if share.Liquidated {
+		return true, c.onShareRemove(pubKey, false)
+	}
+	// the share secret of a removed validator is kept until the removal is out of the reorg window
+	if _, err := c.onShareStart(logger, share); err != nil {
+		logger.Warn("could not start restored validator", fields.PubKey(share.ValidatorPubKey), zap.Error(err))
+	}
+	return true, nil
+}
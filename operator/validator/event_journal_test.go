@@ -0,0 +1,190 @@
+package validator
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/eth1"
+	"github.com/bloxapp/ssv/eth1/abiparser"
+	"github.com/bloxapp/ssv/logging"
+	operatorstorage "github.com/bloxapp/ssv/operator/storage"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/validator"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	registrystorage "github.com/bloxapp/ssv/registry/storage"
+	"github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+func TestEth1EventHandler_Revert(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := storage.GetStorageFactory(logger, basedb.Options{
+		Type: "badger-memory",
+		Path: "",
+	})
+	require.NoError(t, err)
+	registryStorage := operatorstorage.NewNodeStorage(db)
+
+	ctr := setupController(logger, map[string]*validator.Validator{})
+	ctr.sharesStorage = registryStorage
+	ctr.operatorsStorage = registryStorage
+	ctr.recipientsStorage = registryStorage
+	ctr.ibftStorageMap = newTestStores(t, logger)
+	ctr.operatorData = &registrystorage.OperatorData{ID: 1}
+	ctr.validatorOptions = &validator.Options{}
+	ctr.eventJournal = newEventJournal(logger, registryStorage)
+	keyManager := &recordingKeyManager{}
+	ctr.keyManager = keyManager
+	handler := ctr.Eth1EventHandler(logger, false)
+
+	owner := common.HexToAddress("0x1")
+	operatorIDs := []uint64{1, 2, 3, 4}
+	newShare := func(pubKey string, operatorIDs ...uint64) *types.SSVShare {
+		share := &types.SSVShare{
+			Share:    spectypes.Share{ValidatorPubKey: []byte(pubKey)},
+			Metadata: types.Metadata{OwnerAddress: owner},
+		}
+		for _, id := range operatorIDs {
+			share.Committee = append(share.Committee, &spectypes.Operator{OperatorID: id})
+		}
+		return share
+	}
+	require.NoError(t, registryStorage.SaveShare(logger, newShare("operator-share", operatorIDs...)))
+	require.NoError(t, registryStorage.SaveShare(logger, newShare("other-share", 5, 6, 7, 8)))
+
+	// handles the given event, and returns a function that reverts it
+	var block uint64
+	handle := func(name string, data interface{}) func() {
+		block++
+		vLog := ethtypes.Log{BlockNumber: block, BlockHash: common.BigToHash(new(big.Int).SetUint64(block))}
+		_, err := handler(eth1.Event{Log: vLog, Name: name, Data: data})
+		require.NoError(t, err)
+		return func() {
+			vLog.Removed = true
+			_, err := handler(eth1.Event{Log: vLog, Name: name, Data: data})
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("operator added", func(t *testing.T) {
+		revert := handle(abiparser.OperatorAdded, abiparser.OperatorAddedEvent{OperatorId: 10, Owner: owner, PublicKey: []byte("pk")})
+		_, found, err := registryStorage.GetOperatorData(10)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		revert()
+		_, found, err = registryStorage.GetOperatorData(10)
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("fee recipient updated", func(t *testing.T) {
+		revertFirst := handle(abiparser.FeeRecipientAddressUpdated, abiparser.FeeRecipientAddressUpdatedEvent{Owner: owner, RecipientAddress: common.HexToAddress("0x2")})
+		revertSecond := handle(abiparser.FeeRecipientAddressUpdated, abiparser.FeeRecipientAddressUpdatedEvent{Owner: owner, RecipientAddress: common.HexToAddress("0x3")})
+
+		revertSecond()
+		r, found, err := registryStorage.GetRecipientData(owner)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, common.HexToAddress("0x2").Bytes(), r.FeeRecipient[:])
+
+		revertFirst()
+		_, found, err = registryStorage.GetRecipientData(owner)
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("cluster liquidated", func(t *testing.T) {
+		revert := handle(abiparser.ClusterLiquidated, abiparser.ClusterLiquidatedEvent{Owner: owner, OperatorIds: operatorIDs})
+		share, _, err := registryStorage.GetShare([]byte("operator-share"))
+		require.NoError(t, err)
+		require.True(t, share.Liquidated)
+
+		revert()
+		share, _, err = registryStorage.GetShare([]byte("operator-share"))
+		require.NoError(t, err)
+		require.False(t, share.Liquidated)
+	})
+
+	t.Run("validator removed", func(t *testing.T) {
+		revert := handle(abiparser.ValidatorRemoved, abiparser.ValidatorRemovedEvent{Owner: owner, PublicKey: []byte("other-share"), OperatorIds: []uint64{5, 6, 7, 8}})
+		_, found, err := registryStorage.GetShare([]byte("other-share"))
+		require.NoError(t, err)
+		require.False(t, found)
+
+		revert()
+		share, found, err := registryStorage.GetShare([]byte("other-share"))
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, owner, share.OwnerAddress)
+	})
+
+	t.Run("validator removed before a restart", func(t *testing.T) {
+		revert := handle(abiparser.ValidatorRemoved, abiparser.ValidatorRemovedEvent{Owner: owner, PublicKey: []byte("operator-share"), OperatorIds: operatorIDs})
+		_, found, err := registryStorage.GetShare([]byte("operator-share"))
+		require.NoError(t, err)
+		require.False(t, found)
+
+		// the journal is loaded from the storage after a restart
+		ctr.eventJournal = newEventJournal(logger, registryStorage)
+		revert()
+		share, found, err := registryStorage.GetShare([]byte("operator-share"))
+		require.NoError(t, err)
+		require.True(t, found)
+		require.True(t, share.BelongsToOperator(1))
+		require.Empty(t, keyManager.removedShares)
+	})
+
+	t.Run("removed validator secret", func(t *testing.T) {
+		handle(abiparser.ValidatorRemoved, abiparser.ValidatorRemovedEvent{Owner: owner, PublicKey: []byte("operator-share"), OperatorIds: operatorIDs})
+		require.Empty(t, keyManager.removedShares)
+
+		// the share secret is removed once the removal can't be reverted anymore
+		block += eth1.ReorgWindow
+		handle(abiparser.FeeRecipientAddressUpdated, abiparser.FeeRecipientAddressUpdatedEvent{Owner: owner, RecipientAddress: common.HexToAddress("0x4")})
+		require.Equal(t, []string{hex.EncodeToString([]byte("operator-share"))}, keyManager.removedShares)
+
+		require.NoError(t, registryStorage.SaveShare(logger, newShare("operator-share", operatorIDs...)))
+	})
+
+	t.Run("operator removed", func(t *testing.T) {
+		_, err := registryStorage.SaveOperatorData(logger, &registrystorage.OperatorData{ID: 1, PublicKey: []byte("operator-pk"), OwnerAddress: owner})
+		require.NoError(t, err)
+		ctr.operatorData = &registrystorage.OperatorData{ID: 1, PublicKey: []byte("operator-pk")}
+
+		revert := handle(abiparser.OperatorRemoved, abiparser.OperatorRemovedEvent{OperatorId: 1})
+		_, found, err := registryStorage.GetOperatorData(1)
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Equal(t, uint64(0), ctr.operatorData.ID)
+		require.Equal(t, []byte("operator-pk"), ctr.operatorData.PublicKey)
+
+		revert()
+		_, found, err = registryStorage.GetOperatorData(1)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, uint64(1), ctr.operatorData.ID)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		vLog := ethtypes.Log{BlockNumber: 100, Removed: true}
+		_, err := handler(eth1.Event{Log: vLog, Name: abiparser.ValidatorRemoved, Data: abiparser.ValidatorRemovedEvent{}})
+		require.Error(t, err)
+	})
+}
+
+// recordingKeyManager records the share secrets that are removed
+type recordingKeyManager struct {
+	spectypes.KeyManager
+	removedShares []string
+}
+
+func (km *recordingKeyManager) RemoveShare(pubKey string) error {
+	km.removedShares = append(km.removedShares, pubKey)
+	return nil
+}
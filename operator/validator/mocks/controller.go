@@ -92,6 +92,20 @@ func (mr *MockControllerMockRecorder) GetValidator(pubKey interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidator", reflect.TypeOf((*MockController)(nil).GetValidator), pubKey)
 }
 
+// GetRunningValidators mocks base method
+func (m *MockController) GetRunningValidators() []*validator.Validator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunningValidators")
+	ret0, _ := ret[0].([]*validator.Validator)
+	return ret0
+}
+
+// GetRunningValidators indicates an expected call of GetRunningValidators
+func (mr *MockControllerMockRecorder) GetRunningValidators() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningValidators", reflect.TypeOf((*MockController)(nil).GetRunningValidators))
+}
+
 // UpdateValidatorMetaDataLoop mocks base method
 func (m *MockController) UpdateValidatorMetaDataLoop(logger *zap.Logger) {
 	m.ctrl.T.Helper()
@@ -175,3 +189,31 @@ func (mr *MockControllerMockRecorder) GetOperatorData() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperatorData", reflect.TypeOf((*MockController)(nil).GetOperatorData))
 }
+
+// ExitValidator mocks base method
+func (m *MockController) ExitValidator(logger *zap.Logger, pubKey []byte, epoch phase0.Epoch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExitValidator", logger, pubKey, epoch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExitValidator indicates an expected call of ExitValidator
+func (mr *MockControllerMockRecorder) ExitValidator(logger, pubKey, epoch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExitValidator", reflect.TypeOf((*MockController)(nil).ExitValidator), logger, pubKey, epoch)
+}
+
+// DoppelgangerStatus mocks base method
+func (m *MockController) DoppelgangerStatus(pubKey string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoppelgangerStatus", pubKey)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DoppelgangerStatus indicates an expected call of DoppelgangerStatus
+func (mr *MockControllerMockRecorder) DoppelgangerStatus(pubKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoppelgangerStatus", reflect.TypeOf((*MockController)(nil).DoppelgangerStatus), pubKey)
+}
@@ -0,0 +1,48 @@
+package validator
+
+import (
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
+)
+
+// specValueCheckNetwork returns the network to pass to the spec value checks, which resolve the genesis by the network name,
+// and whether their current epoch estimation matches the given network.
+func specValueCheckNetwork(network beaconprotocol.BeaconNetwork) (spectypes.BeaconNetwork, bool) {
+	switch n := network.(type) {
+	case spectypes.BeaconNetwork:
+		return n, true
+	case beaconprotocol.Network:
+		specNetwork := n.BeaconNetwork()
+		return specNetwork, specNetwork.MinGenesisTime() == n.MinGenesisTime()
+	default:
+		return "", false
+	}
+}
+
+// withEpochCheck checks that the duty and attestation target epochs aren't too far into the future with the given network,
+// before calling the spec value check which can't estimate the current epoch of networks it doesn't know.
+func withEpochCheck(network beaconprotocol.BeaconNetwork, check specqbft.ProposedValueCheckF) specqbft.ProposedValueCheckF {
+	return func(data []byte) error {
+		cd := &spectypes.ConsensusData{}
+		if err := cd.Decode(data); err != nil {
+			return errors.Wrap(err, "failed decoding consensus data")
+		}
+		currentEpoch := network.EstimatedCurrentEpoch()
+		if network.EstimatedEpochAtSlot(cd.Duty.Slot) > currentEpoch+1 {
+			return errors.New("duty epoch is into far future")
+		}
+		if cd.Duty.Type == spectypes.BNRoleAttester {
+			attestationData, err := cd.GetAttestationData()
+			if err != nil {
+				return errors.Wrap(err, "could not get attestation data")
+			}
+			if attestationData.Target.Epoch > currentEpoch+1 {
+				return errors.New("attestation data target epoch is into far future")
+			}
+		}
+		return check(data)
+	}
+}
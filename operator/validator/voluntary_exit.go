@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/logging/fields"
+	"github.com/bloxapp/ssv/protocol/v2/message"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/queue"
+	"github.com/bloxapp/ssv/protocol/v2/ssv/runner"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+// ExitValidator starts a voluntary exit duty of the given validator in the first slot of the given epoch.
+// the exit is submitted once a quorum of the committee operators started an exit of the same epoch.
+// returned errors wrap runner.ErrVoluntaryExitsDisabled, runner.ErrInvalidVoluntaryExit or runner.ErrVoluntaryExitBusy
+// when they are not internal failures.
+func (c *controller) ExitValidator(logger *zap.Logger, pubKey []byte, epoch phase0.Epoch) error {
+	logger = logger.Named(logging.NameController).With(fields.PubKey(pubKey))
+
+	if !c.validatorOptions.VoluntaryExits {
+		return runner.ErrVoluntaryExitsDisabled
+	}
+	v, ok := c.validatorsMap.GetValidator(hex.EncodeToString(pubKey))
+	if !ok {
+		return errors.Wrap(runner.ErrInvalidVoluntaryExit, "validator is not running")
+	}
+	if !c.canSign(v) {
+		return errors.Wrap(runner.ErrVoluntaryExitBusy, "validator is waiting for doppelganger protection")
+	}
+	if !v.Share.HasBeaconMetadata() || !v.Share.BeaconMetadata.IsActive() {
+		return errors.Wrap(runner.ErrInvalidVoluntaryExit, "validator is not active")
+	}
+	beaconNetwork := c.validatorOptions.BeaconNetwork
+	if currentEpoch := beaconNetwork.EstimatedCurrentEpoch(); epoch > currentEpoch {
+		return errors.Wrapf(runner.ErrInvalidVoluntaryExit, "exit epoch %d is after the current epoch %d", epoch, currentEpoch)
+	}
+
+	duty := &spectypes.Duty{
+		Type:           message.BNRoleVoluntaryExit,
+		Slot:           beaconNetwork.FirstSlotAtEpoch(epoch),
+		ValidatorIndex: v.Share.BeaconMetadata.Index,
+	}
+	copy(duty.PubKey[:], pubKey)
+
+	data, err := json.Marshal(types.ExecuteDutyData{Duty: duty})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal execute duty data")
+	}
+	eventData, err := (&types.EventMsg{Type: types.ExecuteDuty, Data: data}).Encode()
+	if err != nil {
+		return errors.Wrap(err, "failed to encode event msg")
+	}
+	dec, err := queue.DecodeSSVMessage(logger, &spectypes.SSVMessage{
+		MsgType: message.SSVEventMsgType,
+		MsgID:   spectypes.NewMsgID(types.GetDefaultDomain(), pubKey, duty.Type),
+		Data:    eventData,
+	})
+	if err != nil {
+		return err
+	}
+	q, ok := v.Queues[duty.Type]
+	if !ok {
+		return errors.New("validator has no voluntary exit runner")
+	}
+	if pushed := q.Q.TryPush(dec); !pushed {
+		return errors.Wrap(runner.ErrVoluntaryExitBusy, "validator queue is full")
+	}
+
+	logger.Info("voluntary exit requested", fields.Slot(duty.Slot), zap.Uint64("epoch", uint64(epoch)))
+	return nil
+}
@@ -17,15 +17,34 @@ const (
 	ForkVersionEmpty ForkVersion = ""
 	// GenesisForkVersion is the version for v0
 	GenesisForkVersion ForkVersion = "genesis"
+	// V1ForkVersion is the version that compresses network messages with snappy
+	V1ForkVersion ForkVersion = "v1"
+	// V2ForkVersion is the version that maps validators to subnets according to their committee
+	V2ForkVersion ForkVersion = "v2"
 )
 
+// ForkEpochs holds the epochs in which the fork versions are activated, a zero epoch means that the fork is disabled.
+// it is set upon startup according to the node's configuration.
+type ForkEpochs struct {
+	// V1 is the epoch in which V1ForkVersion is activated
+	V1 phase0.Epoch
+	// V2 is the epoch in which V2ForkVersion is activated
+	V2 phase0.Epoch
+}
+
 // ForkHandler handles a fork event
 type ForkHandler interface {
 	// OnFork is called upon a ForkVersion change
 	OnFork(logger *zap.Logger, forkVersion ForkVersion) error
 }
 
-// GetCurrentForkVersion returns the current fork version
-func GetCurrentForkVersion(currentEpoch phase0.Epoch) ForkVersion {
+// GetCurrentForkVersion returns the fork version of the given epoch
+func (fe ForkEpochs) GetCurrentForkVersion(currentEpoch phase0.Epoch) ForkVersion {
+	if fe.V2 > 0 && currentEpoch >= fe.V2 {
+		return V2ForkVersion
+	}
+	if fe.V1 > 0 && currentEpoch >= fe.V1 {
+		return V1ForkVersion
+	}
 	return GenesisForkVersion
 }
@@ -0,0 +1,20 @@
+package forksprotocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCurrentForkVersion(t *testing.T) {
+	require.Equal(t, GenesisForkVersion, ForkEpochs{}.GetCurrentForkVersion(100))
+
+	epochs := ForkEpochs{V1: 100}
+	require.Equal(t, GenesisForkVersion, epochs.GetCurrentForkVersion(99))
+	require.Equal(t, V1ForkVersion, epochs.GetCurrentForkVersion(100))
+	require.Equal(t, V1ForkVersion, epochs.GetCurrentForkVersion(101))
+
+	epochs.V2 = 200
+	require.Equal(t, V1ForkVersion, epochs.GetCurrentForkVersion(199))
+	require.Equal(t, V2ForkVersion, epochs.GetCurrentForkVersion(200))
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/bloxapp/ssv-spec/ssv"
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/utils/format"
 )
 
 // TODO: add missing tests
@@ -42,6 +44,21 @@ type proposer interface {
 	SubmitProposalPreparation(feeRecipients map[phase0.ValidatorIndex]bellatrix.ExecutionAddress) error
 }
 
+type voluntaryExitSubmitter interface {
+	// SubmitVoluntaryExit submits a signed voluntary exit of a validator
+	SubmitVoluntaryExit(voluntaryExit *phase0.SignedVoluntaryExit) error
+}
+
+type livenessProvider interface {
+	// ValidatorLiveness returns whether each of the given validators was seen performing duties in the given epoch
+	ValidatorLiveness(epoch phase0.Epoch, indices []phase0.ValidatorIndex) (map[phase0.ValidatorIndex]bool, error)
+}
+
+type forkInfoProvider interface {
+	// ForkInfo returns the fork that is active in the given epoch and the genesis validators root
+	ForkInfo(epoch phase0.Epoch) (*phase0.Fork, phase0.Root, error)
+}
+
 // TODO need to handle differently (by spec)
 type signer interface {
 	ComputeSigningRoot(object interface{}, domain phase0.Domain) ([32]byte, error)
@@ -55,14 +72,25 @@ type Beacon interface {
 	beaconValidator
 	signer // TODO need to handle differently
 	proposer
+	voluntaryExitSubmitter
+	livenessProvider
+	forkInfoProvider
 }
 
 // Options for controller struct creation
 type Options struct {
-	Context        context.Context
-	Network        string `yaml:"Network" env:"NETWORK" env-default:"prater"`
-	MinGenesisTime uint64 `yaml:"MinGenesisTime" env:"MinGenesisTime"`
-	BeaconNodeAddr string `yaml:"BeaconNodeAddr" env:"BEACON_NODE_ADDR" env-required:"true"`
-	Graffiti       []byte
-	GasLimit       uint64
+	Context            context.Context
+	Network            string   `yaml:"Network" env:"NETWORK" env-default:"prater"`
+	MinGenesisTime     uint64   `yaml:"MinGenesisTime" env:"MinGenesisTime"`
+	GenesisForkVersion string   `yaml:"GenesisForkVersion" env:"GENESIS_FORK_VERSION" env-description:"Genesis fork version (hex) of a custom beacon network"`
+	BeaconNodeAddr     string   `yaml:"BeaconNodeAddr" env:"BEACON_NODE_ADDR" env-description:"Beacon node address"`
+	BeaconNodeAddrs    []string `yaml:"BeaconNodeAddrs" env:"BEACON_NODE_ADDRS" env-separator:";" env-description:"Beacon node addresses, in order of preference, used for failover and fan-out"`
+	Graffiti           []byte
+	GasLimit           uint64
+}
+
+// BeaconNodeAddresses returns the configured beacon node addresses without duplicates,
+// BeaconNodeAddr comes first if set.
+func (o Options) BeaconNodeAddresses() []string {
+	return format.Addresses(append([]string{o.BeaconNodeAddr}, o.BeaconNodeAddrs...)...)
 }
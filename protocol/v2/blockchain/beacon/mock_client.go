@@ -214,6 +214,120 @@ func (mr *MockproposerMockRecorder) SubmitProposalPreparation(feeRecipients inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitProposalPreparation", reflect.TypeOf((*Mockproposer)(nil).SubmitProposalPreparation), feeRecipients)
 }
 
+// MockvoluntaryExitSubmitter is a mock of voluntaryExitSubmitter interface
+type MockvoluntaryExitSubmitter struct {
+	ctrl     *gomock.Controller
+	recorder *MockvoluntaryExitSubmitterMockRecorder
+}
+
+// MockvoluntaryExitSubmitterMockRecorder is the mock recorder for MockvoluntaryExitSubmitter
+type MockvoluntaryExitSubmitterMockRecorder struct {
+	mock *MockvoluntaryExitSubmitter
+}
+
+// NewMockvoluntaryExitSubmitter creates a new mock instance
+func NewMockvoluntaryExitSubmitter(ctrl *gomock.Controller) *MockvoluntaryExitSubmitter {
+	mock := &MockvoluntaryExitSubmitter{ctrl: ctrl}
+	mock.recorder = &MockvoluntaryExitSubmitterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockvoluntaryExitSubmitter) EXPECT() *MockvoluntaryExitSubmitterMockRecorder {
+	return m.recorder
+}
+
+// SubmitVoluntaryExit mocks base method
+func (m *MockvoluntaryExitSubmitter) SubmitVoluntaryExit(voluntaryExit *phase0.SignedVoluntaryExit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitVoluntaryExit", voluntaryExit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitVoluntaryExit indicates an expected call of SubmitVoluntaryExit
+func (mr *MockvoluntaryExitSubmitterMockRecorder) SubmitVoluntaryExit(voluntaryExit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitVoluntaryExit", reflect.TypeOf((*MockvoluntaryExitSubmitter)(nil).SubmitVoluntaryExit), voluntaryExit)
+}
+
+// MocklivenessProvider is a mock of livenessProvider interface
+type MocklivenessProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MocklivenessProviderMockRecorder
+}
+
+// MocklivenessProviderMockRecorder is the mock recorder for MocklivenessProvider
+type MocklivenessProviderMockRecorder struct {
+	mock *MocklivenessProvider
+}
+
+// NewMocklivenessProvider creates a new mock instance
+func NewMocklivenessProvider(ctrl *gomock.Controller) *MocklivenessProvider {
+	mock := &MocklivenessProvider{ctrl: ctrl}
+	mock.recorder = &MocklivenessProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MocklivenessProvider) EXPECT() *MocklivenessProviderMockRecorder {
+	return m.recorder
+}
+
+// ValidatorLiveness mocks base method
+func (m *MocklivenessProvider) ValidatorLiveness(epoch phase0.Epoch, indices []phase0.ValidatorIndex) (map[phase0.ValidatorIndex]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatorLiveness", epoch, indices)
+	ret0, _ := ret[0].(map[phase0.ValidatorIndex]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidatorLiveness indicates an expected call of ValidatorLiveness
+func (mr *MocklivenessProviderMockRecorder) ValidatorLiveness(epoch, indices interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatorLiveness", reflect.TypeOf((*MocklivenessProvider)(nil).ValidatorLiveness), epoch, indices)
+}
+
+// MockforkInfoProvider is a mock of forkInfoProvider interface
+type MockforkInfoProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockforkInfoProviderMockRecorder
+}
+
+// MockforkInfoProviderMockRecorder is the mock recorder for MockforkInfoProvider
+type MockforkInfoProviderMockRecorder struct {
+	mock *MockforkInfoProvider
+}
+
+// NewMockforkInfoProvider creates a new mock instance
+func NewMockforkInfoProvider(ctrl *gomock.Controller) *MockforkInfoProvider {
+	mock := &MockforkInfoProvider{ctrl: ctrl}
+	mock.recorder = &MockforkInfoProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockforkInfoProvider) EXPECT() *MockforkInfoProviderMockRecorder {
+	return m.recorder
+}
+
+// ForkInfo mocks base method
+func (m *MockforkInfoProvider) ForkInfo(epoch phase0.Epoch) (*phase0.Fork, phase0.Root, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForkInfo", epoch)
+	ret0, _ := ret[0].(*phase0.Fork)
+	ret1, _ := ret[1].(phase0.Root)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ForkInfo indicates an expected call of ForkInfo
+func (mr *MockforkInfoProviderMockRecorder) ForkInfo(epoch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForkInfo", reflect.TypeOf((*MockforkInfoProvider)(nil).ForkInfo), epoch)
+}
+
 // Mocksigner is a mock of signer interface
 type Mocksigner struct {
 	ctrl     *gomock.Controller
@@ -643,3 +757,48 @@ func (mr *MockBeaconMockRecorder) SubmitProposalPreparation(feeRecipients interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitProposalPreparation", reflect.TypeOf((*MockBeacon)(nil).SubmitProposalPreparation), feeRecipients)
 }
+
+// SubmitVoluntaryExit mocks base method
+func (m *MockBeacon) SubmitVoluntaryExit(voluntaryExit *phase0.SignedVoluntaryExit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitVoluntaryExit", voluntaryExit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitVoluntaryExit indicates an expected call of SubmitVoluntaryExit
+func (mr *MockBeaconMockRecorder) SubmitVoluntaryExit(voluntaryExit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitVoluntaryExit", reflect.TypeOf((*MockBeacon)(nil).SubmitVoluntaryExit), voluntaryExit)
+}
+
+// ValidatorLiveness mocks base method
+func (m *MockBeacon) ValidatorLiveness(epoch phase0.Epoch, indices []phase0.ValidatorIndex) (map[phase0.ValidatorIndex]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatorLiveness", epoch, indices)
+	ret0, _ := ret[0].(map[phase0.ValidatorIndex]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidatorLiveness indicates an expected call of ValidatorLiveness
+func (mr *MockBeaconMockRecorder) ValidatorLiveness(epoch, indices interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatorLiveness", reflect.TypeOf((*MockBeacon)(nil).ValidatorLiveness), epoch, indices)
+}
+
+// ForkInfo mocks base method
+func (m *MockBeacon) ForkInfo(epoch phase0.Epoch) (*phase0.Fork, phase0.Root, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForkInfo", epoch)
+	ret0, _ := ret[0].(*phase0.Fork)
+	ret1, _ := ret[1].(phase0.Root)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ForkInfo indicates an expected call of ForkInfo
+func (mr *MockBeaconMockRecorder) ForkInfo(epoch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForkInfo", reflect.TypeOf((*MockBeacon)(nil).ForkInfo), epoch)
+}
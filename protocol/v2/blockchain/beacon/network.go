@@ -1,21 +1,137 @@
 package beacon
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/bloxapp/eth2-key-manager/core"
+	keymanagersigner "github.com/bloxapp/eth2-key-manager/signer"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
 )
 
+// BeaconNetwork is the slot, epoch and fork arithmetic used by runners and value checks,
+// it is implemented by both spectypes.BeaconNetwork and Network.
+type BeaconNetwork interface {
+	ForkVersion() [4]byte
+	MinGenesisTime() uint64
+	SlotDurationSec() time.Duration
+	SlotsPerEpoch() uint64
+	EstimatedCurrentSlot() phase0.Slot
+	EstimatedSlotAtTime(time int64) phase0.Slot
+	EstimatedTimeAtSlot(slot phase0.Slot) int64
+	EstimatedCurrentEpoch() phase0.Epoch
+	EstimatedEpochAtSlot(slot phase0.Slot) phase0.Epoch
+	FirstSlotAtEpoch(epoch phase0.Epoch) phase0.Slot
+	EpochStartTime(epoch phase0.Epoch) time.Time
+}
+
 // Network is a beacon chain network.
 type Network struct {
 	core.Network
-	minGenesisTime uint64
+	minGenesisTime     uint64
+	genesisForkVersion *phase0.Version
 }
 
 // NewNetwork creates a new beacon chain network.
 func NewNetwork(network core.Network, minGenesisTime uint64) Network {
-	return Network{network, minGenesisTime}
+	return Network{Network: network, minGenesisTime: minGenesisTime}
+}
+
+// NewCustomNetwork creates a beacon chain network that isn't known to core (e.g. a devnet),
+// with its own genesis time and genesis fork version.
+func NewCustomNetwork(network core.Network, minGenesisTime uint64, genesisForkVersion phase0.Version) Network {
+	return Network{Network: network, minGenesisTime: minGenesisTime, genesisForkVersion: &genesisForkVersion}
+}
+
+// goerliNetwork is the name of the execution layer network of prater, which is used interchangeably with it
+const goerliNetwork = "goerli"
+
+// NetworkFromOptions creates the beacon chain network described by the given options.
+// Known networks (mainnet, prater or goerli) are resolved by name, while any other name
+// requires both MinGenesisTime and GenesisForkVersion to be set.
+func NetworkFromOptions(opt Options) (Network, error) {
+	if opt.Network == goerliNetwork {
+		opt.Network = string(core.PraterNetwork)
+	}
+	if network := core.NetworkFromString(opt.Network); network != "" {
+		if len(opt.GenesisForkVersion) == 0 {
+			return NewNetwork(network, opt.MinGenesisTime), nil
+		}
+		forkVersion, err := parseForkVersion(opt.GenesisForkVersion)
+		if err != nil {
+			return Network{}, err
+		}
+		return NewCustomNetwork(network, opt.MinGenesisTime, forkVersion), nil
+	}
+	if len(opt.Network) == 0 {
+		return Network{}, errors.New("network is not set")
+	}
+	if opt.MinGenesisTime == 0 || len(opt.GenesisForkVersion) == 0 {
+		return Network{}, errors.Errorf("custom network %s requires MinGenesisTime and GenesisForkVersion", opt.Network)
+	}
+	forkVersion, err := parseForkVersion(opt.GenesisForkVersion)
+	if err != nil {
+		return Network{}, err
+	}
+	return NewCustomNetwork(core.Network(opt.Network), opt.MinGenesisTime, forkVersion), nil
+}
+
+func parseForkVersion(s string) (phase0.Version, error) {
+	var version phase0.Version
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return version, errors.Wrap(err, "could not decode genesis fork version")
+	}
+	if len(b) != len(version) {
+		return version, errors.Errorf("invalid genesis fork version length %d", len(b))
+	}
+	copy(version[:], b)
+	return version, nil
+}
+
+// BeaconNetwork returns the network as known to the spec, it is only accurate for known networks
+// so slot and epoch computations should use Network itself.
+func (n Network) BeaconNetwork() spectypes.BeaconNetwork {
+	return spectypes.BeaconNetwork(n.Network)
+}
+
+// MarshalJSON encodes the network as its name, the same as spectypes.BeaconNetwork,
+// so that encoded runners don't depend on the network type.
+func (n Network) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(n.Network))
+}
+
+// IsCustom returns true if the network isn't known to core, in which case
+// core would exit when asked for its genesis.
+func (n Network) IsCustom() bool {
+	return core.NetworkFromString(string(n.Network)) == ""
+}
+
+// SignerNetwork returns the network to pass to the key manager signer. Custom networks fall back to mainnet,
+// which genesis is earlier than of any custom network so the signer far future protection never rejects
+// what IsValidFarFutureEpoch and IsValidFarFutureSlot allow.
+func (n Network) SignerNetwork() core.Network {
+	if n.IsCustom() {
+		return core.MainNetwork
+	}
+	return n.Network
+}
+
+// ForkVersion returns the genesis fork version of the network
+func (n Network) ForkVersion() [4]byte {
+	return n.GenesisForkVersion()
+}
+
+// GenesisForkVersion returns the genesis fork version of the network
+func (n Network) GenesisForkVersion() phase0.Version {
+	if n.genesisForkVersion != nil {
+		return *n.genesisForkVersion
+	}
+	return n.Network.GenesisForkVersion()
 }
 
 // GetSlotStartTime returns the start time for the given slot
@@ -33,6 +149,16 @@ func (n Network) MinGenesisTime() uint64 {
 	}
 }
 
+// SlotDurationSec returns slot duration
+func (n Network) SlotDurationSec() time.Duration {
+	return 12 * time.Second
+}
+
+// SlotsPerEpoch returns number of slots per one epoch
+func (n Network) SlotsPerEpoch() uint64 {
+	return 32
+}
+
 // EstimatedCurrentSlot returns the estimation of the current slot
 func (n Network) EstimatedCurrentSlot() phase0.Slot {
 	return n.EstimatedSlotAtTime(time.Now().Unix())
@@ -47,6 +173,11 @@ func (n Network) EstimatedSlotAtTime(time int64) phase0.Slot {
 	return phase0.Slot(uint64(time-genesis) / uint64(n.SlotDurationSec().Seconds()))
 }
 
+// EstimatedTimeAtSlot estimates the start time of the given slot
+func (n Network) EstimatedTimeAtSlot(slot phase0.Slot) int64 {
+	return int64(n.MinGenesisTime()) + int64(slot)*int64(n.SlotDurationSec().Seconds())
+}
+
 // EstimatedCurrentEpoch estimates the current epoch
 // https://github.com/ethereum/eth2.0-specs/blob/dev/specs/phase0/beacon-chain.md#compute_start_slot_at_epoch
 func (n Network) EstimatedCurrentEpoch() phase0.Epoch {
@@ -65,7 +196,28 @@ func (n Network) IsFirstSlotOfEpoch(slot phase0.Slot) bool {
 
 // GetEpochFirstSlot returns the beacon node first slot in epoch
 func (n Network) GetEpochFirstSlot(epoch phase0.Epoch) phase0.Slot {
-	return phase0.Slot(epoch * 32)
+	return n.FirstSlotAtEpoch(epoch)
+}
+
+// FirstSlotAtEpoch returns the first slot of the given epoch
+func (n Network) FirstSlotAtEpoch(epoch phase0.Epoch) phase0.Slot {
+	return phase0.Slot(uint64(epoch) * n.SlotsPerEpoch())
+}
+
+// EpochStartTime returns the start time of the given epoch
+func (n Network) EpochStartTime(epoch phase0.Epoch) time.Time {
+	return time.Unix(n.EstimatedTimeAtSlot(n.FirstSlotAtEpoch(epoch)), 0)
+}
+
+// IsValidFarFutureEpoch returns false if the given epoch is too far into the future to be signed,
+// it mirrors the key manager signer check with the genesis of this network.
+func (n Network) IsValidFarFutureEpoch(epoch phase0.Epoch) bool {
+	return epoch <= n.EstimatedEpochAtSlot(n.EstimatedSlotAtTime(time.Now().Unix()+keymanagersigner.FarFutureMaxValidEpoch))
+}
+
+// IsValidFarFutureSlot returns false if the given slot is too far into the future to be signed
+func (n Network) IsValidFarFutureSlot(slot phase0.Slot) bool {
+	return slot <= n.EstimatedSlotAtTime(time.Now().Unix()+keymanagersigner.FarFutureMaxValidEpoch)
 }
 
 // EstimatedSyncCommitteePeriodAtEpoch estimates the current sync committee period at the given Epoch
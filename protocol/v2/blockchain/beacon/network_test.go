@@ -0,0 +1,105 @@
+package beacon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/bloxapp/eth2-key-manager/core"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkFromOptions(t *testing.T) {
+	t.Run("mainnet", func(t *testing.T) {
+		n, err := NetworkFromOptions(Options{Network: "mainnet"})
+		require.NoError(t, err)
+		require.Equal(t, core.MainNetwork, n.Network)
+		require.Equal(t, spectypes.MainNetwork, n.BeaconNetwork())
+		require.Equal(t, phase0.Version{0, 0, 0, 0}, n.GenesisForkVersion())
+		require.Equal(t, core.MainNetwork.MinGenesisTime(), n.MinGenesisTime())
+	})
+
+	t.Run("prater", func(t *testing.T) {
+		n, err := NetworkFromOptions(Options{Network: "prater"})
+		require.NoError(t, err)
+		require.Equal(t, spectypes.PraterNetwork, n.BeaconNetwork())
+		require.Equal(t, phase0.Version{0x00, 0x00, 0x10, 0x20}, n.GenesisForkVersion())
+	})
+
+	t.Run("goerli", func(t *testing.T) {
+		n, err := NetworkFromOptions(Options{Network: "goerli"})
+		require.NoError(t, err)
+		require.Equal(t, core.PraterNetwork, n.Network)
+		require.False(t, n.IsCustom())
+		require.Equal(t, spectypes.PraterNetwork, n.BeaconNetwork())
+		require.Equal(t, phase0.Version{0x00, 0x00, 0x10, 0x20}, n.GenesisForkVersion())
+	})
+
+	t.Run("custom network", func(t *testing.T) {
+		n, err := NetworkFromOptions(Options{
+			Network:            "devnet",
+			MinGenesisTime:     1680000000,
+			GenesisForkVersion: "0x00000069",
+		})
+		require.NoError(t, err)
+		require.Equal(t, spectypes.BeaconNetwork("devnet"), n.BeaconNetwork())
+		require.Equal(t, phase0.Version{0x00, 0x00, 0x00, 0x69}, n.GenesisForkVersion())
+		require.Equal(t, uint64(1680000000), n.MinGenesisTime())
+		require.Equal(t, phase0.Slot(10), n.EstimatedSlotAtTime(1680000000+120))
+	})
+
+	t.Run("custom network without genesis", func(t *testing.T) {
+		_, err := NetworkFromOptions(Options{Network: "devnet", GenesisForkVersion: "0x00000069"})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid fork version", func(t *testing.T) {
+		_, err := NetworkFromOptions(Options{Network: "devnet", MinGenesisTime: 1, GenesisForkVersion: "0x0069"})
+		require.Error(t, err)
+	})
+}
+
+func TestCustomNetwork(t *testing.T) {
+	n, err := NetworkFromOptions(Options{
+		Network:            "devnet",
+		MinGenesisTime:     1680000000,
+		GenesisForkVersion: "0x00000069",
+	})
+	require.NoError(t, err)
+	require.True(t, n.IsCustom())
+	require.Equal(t, core.MainNetwork, n.SignerNetwork())
+
+	// runners use the network through BeaconNetwork, which must not fall back to the spec defaults
+	var network BeaconNetwork = n
+	require.Equal(t, [4]byte{0x00, 0x00, 0x00, 0x69}, network.ForkVersion())
+	require.Equal(t, phase0.Epoch(2), network.EstimatedEpochAtSlot(64))
+	require.Equal(t, phase0.Slot(96), network.FirstSlotAtEpoch(3))
+	require.Equal(t, phase0.Slot(100), network.EstimatedSlotAtTime(1680000000+1200))
+	require.Equal(t, int64(1680000000+1200), network.EstimatedTimeAtSlot(100))
+	require.Equal(t, time.Unix(1680000000+3*32*12, 0), network.EpochStartTime(3))
+
+	// the domain is computed with the devnet fork version
+	domain, err := spectypes.ComputeETHDomain(spectypes.DomainApplicationBuilder, network.ForkVersion(), phase0.Root{})
+	require.NoError(t, err)
+	expectedDomain, err := spectypes.ComputeETHDomain(spectypes.DomainApplicationBuilder, phase0.Version{0x00, 0x00, 0x00, 0x69}, phase0.Root{})
+	require.NoError(t, err)
+	require.Equal(t, expectedDomain, domain)
+	specDomain, err := spectypes.ComputeETHDomain(spectypes.DomainApplicationBuilder, n.BeaconNetwork().ForkVersion(), phase0.Root{})
+	require.NoError(t, err)
+	require.NotEqual(t, specDomain, domain)
+
+	// far future checks use the devnet genesis
+	currentEpoch := n.EstimatedCurrentEpoch()
+	require.True(t, n.IsValidFarFutureEpoch(currentEpoch+1))
+	require.False(t, n.IsValidFarFutureEpoch(currentEpoch+10))
+	require.False(t, n.IsValidFarFutureSlot(n.FirstSlotAtEpoch(currentEpoch+10)))
+
+	// encoded as the network name, same as spectypes.BeaconNetwork
+	encoded, err := json.Marshal(n)
+	require.NoError(t, err)
+	specEncoded, err := json.Marshal(spectypes.BeaconNetwork("devnet"))
+	require.NoError(t, err)
+	require.Equal(t, specEncoded, encoded)
+}
@@ -13,6 +13,16 @@ const (
 	SSVEventMsgType spectypes.MsgType = 200
 )
 
+const (
+	// BNRoleVoluntaryExit extends spec beacon role, 100 is taken by the unknown duty type of spec tests
+	BNRoleVoluntaryExit spectypes.BeaconRole = 101
+)
+
+const (
+	// VoluntaryExitPartialSig extends spec partial signature msg type
+	VoluntaryExitPartialSig spectypes.PartialSigMsgType = 100
+)
+
 // MsgTypeToString extension for spec msg type. convert spec msg type to string
 func MsgTypeToString(mt spectypes.MsgType) string {
 	switch mt {
@@ -31,6 +41,16 @@ func MsgTypeToString(mt spectypes.MsgType) string {
 	}
 }
 
+// BeaconRoleToString extension for spec beacon role. convert spec beacon role to string
+func BeaconRoleToString(role spectypes.BeaconRole) string {
+	switch role {
+	case BNRoleVoluntaryExit:
+		return "VOLUNTARY_EXIT"
+	default:
+		return role.String()
+	}
+}
+
 // BeaconRoleFromString returns BeaconRole from string
 func BeaconRoleFromString(s string) (spectypes.BeaconRole, error) {
 	switch s {
@@ -46,6 +66,8 @@ func BeaconRoleFromString(s string) (spectypes.BeaconRole, error) {
 		return spectypes.BNRoleSyncCommitteeContribution, nil
 	case "VALIDATOR_REGISTRATION":
 		return spectypes.BNRoleValidatorRegistration, nil
+	case "VOLUNTARY_EXIT":
+		return BNRoleVoluntaryExit, nil
 	default:
 		return 0, fmt.Errorf("unknown role: %s", s)
 	}
@@ -46,6 +46,12 @@ type InstanceStore interface {
 
 	// CleanAllInstances removes all historical and highest instances for the given identifier.
 	CleanAllInstances(logger *zap.Logger, msgID []byte) error
+
+	// GetPrunedHeight returns the lowest height of the given identifier that wasn't pruned.
+	GetPrunedHeight(identifier []byte) (specqbft.Height, error)
+
+	// PruneInstances removes historical instances of the given identifier below the given height.
+	PruneInstances(identifier []byte, below specqbft.Height) (int, error)
 }
 
 // QBFTStore is the store used by QBFT components
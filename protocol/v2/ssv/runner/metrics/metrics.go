@@ -8,6 +8,8 @@ import (
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/bloxapp/ssv/protocol/v2/message"
 )
 
 var (
@@ -96,7 +98,7 @@ type ConsensusMetrics struct {
 }
 
 func NewConsensusMetrics(role spectypes.BeaconRole) ConsensusMetrics {
-	values := []string{role.String()}
+	values := []string{message.BeaconRoleToString(role)}
 	return ConsensusMetrics{
 		preConsensus:            metricsPreConsensusDuration.WithLabelValues(values...),
 		consensus:               metricsConsensusDuration.WithLabelValues(values...),
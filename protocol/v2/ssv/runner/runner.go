@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"encoding/json"
 	"sync"
 
 	spec "github.com/attestantio/go-eth2-client/spec/phase0"
@@ -8,6 +9,7 @@ import (
 	specssv "github.com/bloxapp/ssv-spec/ssv"
 	"github.com/bloxapp/ssv-spec/types"
 	spectypes "github.com/bloxapp/ssv-spec/types"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	"github.com/bloxapp/ssv/protocol/v2/qbft/controller"
 	ssz "github.com/ferranbt/fastssz"
 	"github.com/pkg/errors"
@@ -50,7 +52,7 @@ type BaseRunner struct {
 	State          *State
 	Share          *spectypes.Share
 	QBFTController *controller.Controller
-	BeaconNetwork  spectypes.BeaconNetwork
+	BeaconNetwork  beaconprotocol.BeaconNetwork
 	BeaconRoleType spectypes.BeaconRole
 
 	// implementation vars
@@ -60,6 +62,21 @@ type BaseRunner struct {
 	highestDecidedSlot spec.Slot
 }
 
+// UnmarshalJSON decodes the beacon network into a spectypes.BeaconNetwork,
+// as it can't be decoded into the interface and is encoded as the network name.
+func (b *BaseRunner) UnmarshalJSON(data []byte) error {
+	type baseRunner BaseRunner
+	aux := &struct {
+		*baseRunner
+		BeaconNetwork spectypes.BeaconNetwork
+	}{baseRunner: (*baseRunner)(b)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	b.BeaconNetwork = aux.BeaconNetwork
+	return nil
+}
+
 // SetHighestDecidedSlot set highestDecidedSlot for base runner
 func (b *BaseRunner) SetHighestDecidedSlot(slot spec.Slot) {
 	b.highestDecidedSlot = slot
@@ -80,7 +97,7 @@ func NewBaseRunner(
 	state *State,
 	share *spectypes.Share,
 	controller *controller.Controller,
-	beaconNetwork spectypes.BeaconNetwork,
+	beaconNetwork beaconprotocol.BeaconNetwork,
 	beaconRoleType spectypes.BeaconRole,
 	highestDecidedSlot spec.Slot,
 ) *BaseRunner {
@@ -110,6 +127,11 @@ func (b *BaseRunner) canStartNewDuty() error {
 		return nil
 	}
 
+	// runners without a consensus phase have no controller
+	if b.QBFTController == nil {
+		return nil
+	}
+
 	return b.QBFTController.CanStartInstance()
 }
 
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	specqbft "github.com/bloxapp/ssv-spec/qbft"
 	specssv "github.com/bloxapp/ssv-spec/ssv"
 	spectypes "github.com/bloxapp/ssv-spec/types"
@@ -75,7 +76,13 @@ func RunMsgProcessing(t *testing.T, test *MsgProcessingSpecTest) {
 }
 
 func (test *MsgProcessingSpecTest) compareBroadcastedBeaconMsgs(t *testing.T) {
-	broadcastedRoots := test.Runner.GetBeaconNode().(*spectestingutils.TestingBeaconNode).BroadcastedRoots
+	var broadcastedRoots []phase0.Root
+	switch bn := test.Runner.GetBeaconNode().(type) {
+	case *ssvtesting.TestingBeaconNode:
+		broadcastedRoots = bn.BroadcastedRoots
+	default:
+		broadcastedRoots = bn.(*spectestingutils.TestingBeaconNode).BroadcastedRoots
+	}
 	require.Len(t, broadcastedRoots, len(test.BeaconBroadcastedRoots))
 	for _, r1 := range test.BeaconBroadcastedRoots {
 		found := false
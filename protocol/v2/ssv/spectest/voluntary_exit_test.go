@@ -0,0 +1,166 @@
+package spectest
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	spectestingutils "github.com/bloxapp/ssv-spec/types/testingutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bloxapp/ssv/logging"
+	ssvtesting "github.com/bloxapp/ssv/protocol/v2/ssv/testing"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+)
+
+// TestVoluntaryExit runs msg processing tests of the voluntary exit runner, in the format of the spec tests
+func TestVoluntaryExit(t *testing.T) {
+	origDomain := types.GetDefaultDomain()
+	types.SetDefaultDomain(spectestingutils.TestingSSVDomainType)
+	defer func() {
+		types.SetDefaultDomain(origDomain)
+	}()
+
+	logger := logging.TestLogger(t)
+	ks := spectestingutils.Testing4SharesSet()
+
+	signedExit := &phase0.SignedVoluntaryExit{Message: ssvtesting.TestingVoluntaryExit}
+	copy(signedExit.Signature[:], ks.ValidatorSK.SignByte(signingRoot(t, ssvtesting.TestingVoluntaryExit)).Serialize())
+	r, err := signedExit.HashTreeRoot()
+	require.NoError(t, err)
+	signedExitRoot := hex.EncodeToString(r[:])
+
+	tests := []*MsgProcessingSpecTest{
+		{
+			Name:   "happy flow",
+			Runner: ssvtesting.VoluntaryExitRunner(logger, ks),
+			Duty:   ssvtesting.TestingVoluntaryExitDuty,
+			Messages: []*spectypes.SSVMessage{
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1)),
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[2], 2)),
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[3], 3)),
+			},
+			PostDutyRunnerStateRoot: "b64b26302f85164f74b02012e9cb68442dd7db49671baf84d8cac3a05f20f58c",
+			OutputMessages: []*spectypes.SignedPartialSignatureMessage{
+				ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1),
+			},
+			BeaconBroadcastedRoots: []string{signedExitRoot},
+		},
+		{
+			Name:   "quorum not reached",
+			Runner: ssvtesting.VoluntaryExitRunner(logger, ks),
+			Duty:   ssvtesting.TestingVoluntaryExitDuty,
+			Messages: []*spectypes.SSVMessage{
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1)),
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[2], 2)),
+			},
+			PostDutyRunnerStateRoot: "0fe6b798e74a849a781dd4c3a4595390562f9cf641aff54e16a84b62f6ada38f",
+			OutputMessages: []*spectypes.SignedPartialSignatureMessage{
+				ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1),
+			},
+			BeaconBroadcastedRoots: []string{},
+		},
+		{
+			Name:   "message after finished",
+			Runner: ssvtesting.VoluntaryExitRunner(logger, ks),
+			Duty:   ssvtesting.TestingVoluntaryExitDuty,
+			Messages: []*spectypes.SSVMessage{
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1)),
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[2], 2)),
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[3], 3)),
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[4], 4)),
+			},
+			PostDutyRunnerStateRoot: "b64b26302f85164f74b02012e9cb68442dd7db49671baf84d8cac3a05f20f58c",
+			OutputMessages: []*spectypes.SignedPartialSignatureMessage{
+				ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1),
+			},
+			BeaconBroadcastedRoots: []string{signedExitRoot},
+			ExpectedError:          "failed processing voluntary exit message: invalid pre-consensus message: no running duty",
+		},
+		{
+			Name:   "wrong root",
+			Runner: ssvtesting.VoluntaryExitRunner(logger, ks),
+			Duty:   ssvtesting.TestingVoluntaryExitDuty,
+			Messages: []*spectypes.SSVMessage{
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitWrongRootMsg(ks.Shares[2], 2)),
+			},
+			PostDutyRunnerStateRoot: "4933de154b677f09ecf95352ad072e4817891aa8b9b619790a8818a2677a2258",
+			OutputMessages: []*spectypes.SignedPartialSignatureMessage{
+				ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1),
+			},
+			BeaconBroadcastedRoots: []string{},
+			ExpectedError:          "failed processing voluntary exit message: invalid pre-consensus message: wrong signing root",
+		},
+		{
+			Name:   "wrong slot",
+			Runner: ssvtesting.VoluntaryExitRunner(logger, ks),
+			Duty:   ssvtesting.TestingVoluntaryExitDuty,
+			Messages: []*spectypes.SSVMessage{
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitWrongSlotMsg(ks.Shares[2], 2)),
+			},
+			PostDutyRunnerStateRoot: "4933de154b677f09ecf95352ad072e4817891aa8b9b619790a8818a2677a2258",
+			OutputMessages: []*spectypes.SignedPartialSignatureMessage{
+				ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1),
+			},
+			BeaconBroadcastedRoots: []string{},
+			ExpectedError:          "failed processing voluntary exit message: invalid pre-consensus message: invalid partial sig slot",
+		},
+		{
+			Name:   "too many roots",
+			Runner: ssvtesting.VoluntaryExitRunner(logger, ks),
+			Duty:   ssvtesting.TestingVoluntaryExitDuty,
+			Messages: []*spectypes.SSVMessage{
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitTooManyRootsMsg(ks.Shares[2], 2)),
+			},
+			PostDutyRunnerStateRoot: "4933de154b677f09ecf95352ad072e4817891aa8b9b619790a8818a2677a2258",
+			OutputMessages: []*spectypes.SignedPartialSignatureMessage{
+				ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1),
+			},
+			BeaconBroadcastedRoots: []string{},
+			ExpectedError:          "failed processing voluntary exit message: invalid pre-consensus message: wrong expected roots count",
+		},
+		{
+			Name:   "no running duty",
+			Runner: ssvtesting.VoluntaryExitRunner(logger, ks),
+			Duty:   ssvtesting.TestingVoluntaryExitDuty,
+			Messages: []*spectypes.SSVMessage{
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1)),
+			},
+			PostDutyRunnerStateRoot: "7617941592f5e6ebbdf07b28c1e306c0076fcf834f03f0d0cdd3d79cfbdf4f7b",
+			OutputMessages:          []*spectypes.SignedPartialSignatureMessage{},
+			BeaconBroadcastedRoots:  []string{},
+			DontStartDuty:           true,
+			ExpectedError:           "failed processing voluntary exit message: invalid pre-consensus message: no running duty",
+		},
+		{
+			Name:   "post consensus message",
+			Runner: ssvtesting.VoluntaryExitRunner(logger, ks),
+			Duty:   ssvtesting.TestingVoluntaryExitDuty,
+			Messages: []*spectypes.SSVMessage{
+				ssvtesting.SSVMsgVoluntaryExit(nil, ssvtesting.PostConsensusAttestationMsg(ks.Shares[1], 1, 1)),
+			},
+			PostDutyRunnerStateRoot: "4933de154b677f09ecf95352ad072e4817891aa8b9b619790a8818a2677a2258",
+			OutputMessages: []*spectypes.SignedPartialSignatureMessage{
+				ssvtesting.PreConsensusVoluntaryExitMsg(ks.Shares[1], 1),
+			},
+			BeaconBroadcastedRoots: []string{},
+			ExpectedError:          "no post consensus phase for voluntary exit",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.TestName(), func(t *testing.T) {
+			RunMsgProcessing(t, test)
+		})
+	}
+}
+
+func signingRoot(t *testing.T, exit *phase0.VoluntaryExit) []byte {
+	domain, err := spectestingutils.NewTestingBeaconNode().DomainData(exit.Epoch, spectypes.DomainVoluntaryExit)
+	require.NoError(t, err)
+	root, err := spectypes.ComputeETHSigningRoot(exit, domain)
+	require.NoError(t, err)
+	return root[:]
+}
@@ -0,0 +1,24 @@
+package testing
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	spectestingutils "github.com/bloxapp/ssv-spec/types/testingutils"
+)
+
+// TestingBeaconNode extends the spec testing beacon node with voluntary exits, which are not a part of the spec
+type TestingBeaconNode struct {
+	*spectestingutils.TestingBeaconNode
+}
+
+func NewTestingBeaconNode() *TestingBeaconNode {
+	return &TestingBeaconNode{
+		TestingBeaconNode: spectestingutils.NewTestingBeaconNode(),
+	}
+}
+
+// SubmitVoluntaryExit records the root of the submitted exit
+func (bn *TestingBeaconNode) SubmitVoluntaryExit(voluntaryExit *phase0.SignedVoluntaryExit) error {
+	r, _ := voluntaryExit.HashTreeRoot()
+	bn.BroadcastedRoots = append(bn.BroadcastedRoots, r)
+	return nil
+}
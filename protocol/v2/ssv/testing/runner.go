@@ -8,6 +8,7 @@ import (
 	spectestingutils "github.com/bloxapp/ssv-spec/types/testingutils"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/protocol/v2/message"
 	"github.com/bloxapp/ssv/protocol/v2/qbft/testing"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/runner"
 )
@@ -54,6 +55,10 @@ var ValidatorRegistrationRunner = func(logger *zap.Logger, keySet *spectestingut
 	return ret
 }
 
+var VoluntaryExitRunner = func(logger *zap.Logger, keySet *spectestingutils.TestKeySet) runner.Runner {
+	return baseRunner(logger, message.BNRoleVoluntaryExit, nil, keySet)
+}
+
 var UnknownDutyTypeRunner = func(logger *zap.Logger, keySet *spectestingutils.TestKeySet) runner.Runner {
 	return baseRunner(logger, spectestingutils.UnknownDutyType, spectestingutils.UnknownDutyValueCheck(), keySet)
 }
@@ -144,6 +149,14 @@ var baseRunner = func(logger *zap.Logger, role spectypes.BeaconRole, valCheck sp
 			net,
 			km,
 		)
+	case message.BNRoleVoluntaryExit:
+		return runner.NewVoluntaryExitRunner(
+			spectypes.BeaconTestNetwork,
+			share,
+			NewTestingBeaconNode(),
+			net,
+			km,
+		)
 	case spectestingutils.UnknownDutyType:
 		ret := runner.NewAttesterRunnner(
 			spectypes.BeaconTestNetwork,
@@ -9,6 +9,8 @@ import (
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/bloxapp/ssv-spec/types/testingutils"
 	"github.com/herumi/bls-eth-go-binary/bls"
+
+	"github.com/bloxapp/ssv/protocol/v2/message"
 )
 
 var TestingSSVDomainType = spectypes.V3Testnet
@@ -37,6 +39,10 @@ var ValidatorRegistrationMsgID = func() []byte {
 	ret := spectypes.NewMsgID(TestingSSVDomainType, testingutils.TestingValidatorPubKey[:], spectypes.BNRoleValidatorRegistration)
 	return ret[:]
 }()
+var VoluntaryExitMsgID = func() []byte {
+	ret := spectypes.NewMsgID(TestingSSVDomainType, testingutils.TestingValidatorPubKey[:], message.BNRoleVoluntaryExit)
+	return ret[:]
+}()
 
 var TestAttesterConsensusData = &spectypes.ConsensusData{
 	Duty:    testingutils.TestingAttesterDuty,
@@ -116,6 +122,10 @@ var SSVMsgValidatorRegistration = func(qbftMsg *specqbft.SignedMessage, partialS
 	return ssvMsg(qbftMsg, partialSigMsg, spectypes.NewMsgID(TestingSSVDomainType, testingutils.TestingValidatorPubKey[:], spectypes.BNRoleValidatorRegistration))
 }
 
+var SSVMsgVoluntaryExit = func(qbftMsg *specqbft.SignedMessage, partialSigMsg *spectypes.SignedPartialSignatureMessage) *spectypes.SSVMessage {
+	return ssvMsg(qbftMsg, partialSigMsg, spectypes.NewMsgID(TestingSSVDomainType, testingutils.TestingValidatorPubKey[:], message.BNRoleVoluntaryExit))
+}
+
 var ssvMsg = func(qbftMsg *specqbft.SignedMessage, postMsg *spectypes.SignedPartialSignatureMessage, msgID spectypes.MessageID) *spectypes.SSVMessage {
 	var msgType spectypes.MsgType
 	var data []byte
@@ -887,3 +897,70 @@ func ensureRoot(root [32]byte) [32]byte {
 	copy(tmp[:], root[:])
 	return tmp
 }
+
+var TestingVoluntaryExitDuty = &spectypes.Duty{
+	Type:           message.BNRoleVoluntaryExit,
+	PubKey:         testingutils.TestingValidatorPubKey,
+	Slot:           testingutils.TestingDutySlot,
+	ValidatorIndex: testingutils.TestingValidatorIndex,
+}
+
+var TestingVoluntaryExit = &spec.VoluntaryExit{
+	Epoch:          testingutils.TestingDutyEpoch,
+	ValidatorIndex: testingutils.TestingValidatorIndex,
+}
+
+var TestingVoluntaryExitWrong = &spec.VoluntaryExit{
+	Epoch:          testingutils.TestingDutyEpoch,
+	ValidatorIndex: testingutils.TestingValidatorIndex + 1,
+}
+
+var PreConsensusVoluntaryExitMsg = func(msgSK *bls.SecretKey, msgID spectypes.OperatorID) *spectypes.SignedPartialSignatureMessage {
+	return voluntaryExitMsg(msgSK, msgID, TestingVoluntaryExit, testingutils.TestingDutySlot, 1)
+}
+
+var PreConsensusVoluntaryExitWrongRootMsg = func(msgSK *bls.SecretKey, msgID spectypes.OperatorID) *spectypes.SignedPartialSignatureMessage {
+	return voluntaryExitMsg(msgSK, msgID, TestingVoluntaryExitWrong, testingutils.TestingDutySlot, 1)
+}
+
+var PreConsensusVoluntaryExitWrongSlotMsg = func(msgSK *bls.SecretKey, msgID spectypes.OperatorID) *spectypes.SignedPartialSignatureMessage {
+	return voluntaryExitMsg(msgSK, msgID, TestingVoluntaryExit, testingutils.TestingDutySlot+1, 1)
+}
+
+var PreConsensusVoluntaryExitTooManyRootsMsg = func(msgSK *bls.SecretKey, msgID spectypes.OperatorID) *spectypes.SignedPartialSignatureMessage {
+	return voluntaryExitMsg(msgSK, msgID, TestingVoluntaryExit, testingutils.TestingDutySlot, 2)
+}
+
+var voluntaryExitMsg = func(
+	sk *bls.SecretKey,
+	id spectypes.OperatorID,
+	exit *spec.VoluntaryExit,
+	slot spec.Slot,
+	msgCnt int,
+) *spectypes.SignedPartialSignatureMessage {
+	signer := testingutils.NewTestingKeyManager()
+	beacon := testingutils.NewTestingBeaconNode()
+	d, _ := beacon.DomainData(exit.Epoch, spectypes.DomainVoluntaryExit)
+
+	signed, root, _ := signer.SignBeaconObject(exit, d, sk.GetPublicKey().Serialize(), spectypes.DomainVoluntaryExit)
+
+	msgs := spectypes.PartialSignatureMessages{
+		Type:     message.VoluntaryExitPartialSig,
+		Slot:     slot,
+		Messages: []*spectypes.PartialSignatureMessage{},
+	}
+	for i := 0; i < msgCnt; i++ {
+		msgs.Messages = append(msgs.Messages, &spectypes.PartialSignatureMessage{
+			PartialSignature: signed[:],
+			SigningRoot:      root,
+			Signer:           id,
+		})
+	}
+
+	sig, _ := signer.SignRoot(msgs, spectypes.PartialSignatureType, sk.GetPublicKey().Serialize())
+	return &spectypes.SignedPartialSignatureMessage{
+		Message:   msgs,
+		Signature: sig,
+		Signer:    id,
+	}
+}
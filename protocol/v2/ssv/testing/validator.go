@@ -7,6 +7,7 @@ import (
 	spectestingutils "github.com/bloxapp/ssv-spec/types/testingutils"
 	"go.uber.org/zap"
 
+	"github.com/bloxapp/ssv/protocol/v2/message"
 	"github.com/bloxapp/ssv/protocol/v2/qbft/testing"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/runner"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/validator"
@@ -33,6 +34,7 @@ var BaseValidator = func(logger *zap.Logger, keySet *spectestingutils.TestKeySet
 				spectypes.BNRoleSyncCommittee:             SyncCommitteeRunner(logger, keySet),
 				spectypes.BNRoleSyncCommitteeContribution: SyncCommitteeContributionRunner(logger, keySet),
 				spectypes.BNRoleValidatorRegistration:     ValidatorRegistrationRunner(logger, keySet),
+				message.BNRoleVoluntaryExit:               VoluntaryExitRunner(logger, keySet),
 			},
 		},
 	)
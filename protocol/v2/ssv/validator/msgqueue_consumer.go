@@ -190,6 +190,18 @@ func (v *Validator) logMsg(logger *zap.Logger, msg *queue.DecodedSSVMessage, log
 	logger.Debug(logMsg, append(baseFields, withFields...)...)
 }
 
+// QueueLengths returns the amount of pending messages in the queue of each duty role
+func (v *Validator) QueueLengths() map[spectypes.BeaconRole]int {
+	v.mtx.RLock() // read v.Queues
+	defer v.mtx.RUnlock()
+
+	lengths := make(map[spectypes.BeaconRole]int, len(v.Queues))
+	for role, q := range v.Queues {
+		lengths[role] = q.Q.Len()
+	}
+	return lengths
+}
+
 // GetLastHeight returns the last height for the given identifier
 func (v *Validator) GetLastHeight(identifier spectypes.MessageID) specqbft.Height {
 	r := v.DutyRunners.DutyRunnerForMsgID(identifier)
@@ -6,6 +6,7 @@ import (
 	spectypes "github.com/bloxapp/ssv-spec/types"
 
 	"github.com/bloxapp/ssv/ibft/storage"
+	beaconprotocol "github.com/bloxapp/ssv/protocol/v2/blockchain/beacon"
 	qbftctrl "github.com/bloxapp/ssv/protocol/v2/qbft/controller"
 	"github.com/bloxapp/ssv/protocol/v2/ssv/runner"
 	"github.com/bloxapp/ssv/protocol/v2/types"
@@ -18,6 +19,7 @@ const (
 // Options represents options that should be passed to a new instance of Validator.
 type Options struct {
 	Network           specqbft.Network
+	BeaconNetwork     beaconprotocol.BeaconNetwork
 	Beacon            specssv.BeaconNode
 	Storage           *storage.QBFTStores
 	SSVShare          *types.SSVShare
@@ -29,6 +31,7 @@ type Options struct {
 	BuilderProposals  bool
 	QueueSize         int
 	GasLimit          uint64
+	VoluntaryExits    bool
 }
 
 func (o *Options) defaults() {
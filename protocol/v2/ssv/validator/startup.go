@@ -52,7 +52,10 @@ func (v *Validator) Start(logger *zap.Logger) error {
 				return err
 			}
 			go v.StartQueueConsumer(logger, identifier, v.ProcessMessage)
-			go v.sync(logger, identifier)
+			// runners without a controller have no decided messages to sync
+			if r.GetBaseRunner().QBFTController != nil {
+				go v.sync(logger, identifier)
+			}
 		}
 	}
 	return nil
@@ -13,8 +13,8 @@ import (
 	protocolp2p "github.com/bloxapp/ssv/protocol/v2/p2p"
 )
 
-// HistoryHandler handler for decided history protocol
-// TODO: add msg validation and report scores
+// HistoryHandler handler for decided history protocol,
+// invalid requests are answered with StatusBadRequest and reported.
 func HistoryHandler(logger *zap.Logger, storeMap *storage.QBFTStores, reporting protocolp2p.ValidationReporting, maxBatchSize int) protocolp2p.RequestHandler {
 	return func(msg *spectypes.SSVMessage) (*spectypes.SSVMessage, error) {
 		logger := logger.With(zap.String("msg_id", fmt.Sprintf("%x", msg.MsgID)))
@@ -28,16 +28,29 @@ func HistoryHandler(logger *zap.Logger, storeMap *storage.QBFTStores, reporting
 			// not this protocol
 			// TODO: remove after v0
 			return nil, nil
+		} else if err := validateHistoryRequest(msg, sm); err != nil {
+			logger.Debug("❌ invalid history request", zap.Error(err))
+			reporting.ReportValidation(logger, msg, protocolp2p.ValidationRejectMedium)
+			sm.Status = message.StatusBadRequest
+		} else if store := storeMap.Get(msg.MsgID.GetRoleType()); store == nil {
+			logger.Debug("❌ unknown role", zap.String("role", msg.MsgID.GetRoleType().String()))
+			reporting.ReportValidation(logger, msg, protocolp2p.ValidationRejectLow)
+			sm.Status = message.StatusBadRequest
+		} else if err := validateHistoryHeights(store, msg.MsgID, sm.Params.Height[0]); err != nil {
+			if errors.Is(err, ErrHeightsTooHigh) {
+				logger.Debug("❌ invalid history request", zap.Error(err))
+				// low severity, as this node might be far behind
+				reporting.ReportValidation(logger, msg, protocolp2p.ValidationRejectLow)
+				sm.Status = message.StatusBadRequest
+			} else {
+				sm.UpdateResults(err)
+			}
 		} else {
-			items := int(sm.Params.Height[1] - sm.Params.Height[0])
-			if items > maxBatchSize {
+			// comparing heights rather than their difference as int, which might overflow
+			if sm.Params.Height[1]-sm.Params.Height[0] > specqbft.Height(maxBatchSize) {
 				sm.Params.Height[1] = sm.Params.Height[0] + specqbft.Height(maxBatchSize)
 			}
 			msgID := msg.GetID()
-			store := storeMap.Get(msgID.GetRoleType())
-			if store == nil {
-				return nil, errors.New(fmt.Sprintf("not storage found for type %s", msgID.GetRoleType().String()))
-			}
 			instances, err := store.GetInstancesInRange(msgID[:], sm.Params.Height[0], sm.Params.Height[1])
 			results := make([]*specqbft.SignedMessage, 0, len(instances))
 			for _, instance := range instances {
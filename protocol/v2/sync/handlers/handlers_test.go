@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"math"
+	"testing"
+
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/bloxapp/ssv/ibft/storage"
+	"github.com/bloxapp/ssv/logging"
+	"github.com/bloxapp/ssv/protocol/v2/message"
+	protocolp2p "github.com/bloxapp/ssv/protocol/v2/p2p"
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+	"github.com/bloxapp/ssv/protocol/v2/types"
+	ssvstorage "github.com/bloxapp/ssv/storage"
+	"github.com/bloxapp/ssv/storage/basedb"
+)
+
+type mockReporting struct {
+	results []protocolp2p.MsgValidationResult
+}
+
+func (r *mockReporting) ReportValidation(logger *zap.Logger, message *spectypes.SSVMessage, res protocolp2p.MsgValidationResult) {
+	r.results = append(r.results, res)
+}
+
+func TestHistoryHandler(t *testing.T) {
+	logger := logging.TestLogger(t)
+	stores := newTestStores(t, logger, 10)
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleAttester)
+
+	tests := []struct {
+		name           string
+		msgID          spectypes.MessageID
+		params         *message.SyncParams
+		expectedStatus message.StatusCode
+		expectedReport []protocolp2p.MsgValidationResult
+		expectedCount  int
+	}{
+		{
+			name:           "valid range",
+			msgID:          msgID,
+			params:         &message.SyncParams{Identifier: msgID, Height: []specqbft.Height{2, 5}},
+			expectedStatus: message.StatusSuccess,
+			expectedCount:  4,
+		},
+		{
+			name:           "capped range",
+			msgID:          msgID,
+			params:         &message.SyncParams{Identifier: msgID, Height: []specqbft.Height{0, 100}},
+			expectedStatus: message.StatusSuccess,
+			expectedCount:  4,
+		},
+		{
+			name:           "range too wide",
+			msgID:          msgID,
+			params:         &message.SyncParams{Identifier: msgID, Height: []specqbft.Height{0, math.MaxUint64}},
+			expectedStatus: message.StatusBadRequest,
+			expectedReport: []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectMedium},
+		},
+		{
+			name:           "ahead of highest",
+			msgID:          msgID,
+			params:         &message.SyncParams{Identifier: msgID, Height: []specqbft.Height{500, 510}},
+			expectedStatus: message.StatusNotFound,
+		},
+		{
+			name:           "far above highest",
+			msgID:          msgID,
+			params:         &message.SyncParams{Identifier: msgID, Height: []specqbft.Height{5000, 5010}},
+			expectedStatus: message.StatusBadRequest,
+			expectedReport: []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectLow},
+		},
+		{
+			name:  "far above unknown identifier",
+			msgID: spectypes.NewMsgID(types.GetDefaultDomain(), []byte("other"), spectypes.BNRoleAttester),
+			params: &message.SyncParams{
+				Identifier: spectypes.NewMsgID(types.GetDefaultDomain(), []byte("other"), spectypes.BNRoleAttester),
+				Height:     []specqbft.Height{5000, 5010},
+			},
+			expectedStatus: message.StatusBadRequest,
+			expectedReport: []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectLow},
+		},
+		{
+			name:           "from > to",
+			msgID:          msgID,
+			params:         &message.SyncParams{Identifier: msgID, Height: []specqbft.Height{5, 2}},
+			expectedStatus: message.StatusBadRequest,
+			expectedReport: []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectMedium},
+		},
+		{
+			name:           "single height",
+			msgID:          msgID,
+			params:         &message.SyncParams{Identifier: msgID, Height: []specqbft.Height{5}},
+			expectedStatus: message.StatusBadRequest,
+			expectedReport: []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectMedium},
+		},
+		{
+			name:           "missing params",
+			msgID:          msgID,
+			expectedStatus: message.StatusBadRequest,
+			expectedReport: []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectMedium},
+		},
+		{
+			name:  "identifier mismatch",
+			msgID: msgID,
+			params: &message.SyncParams{
+				Identifier: spectypes.NewMsgID(types.GetDefaultDomain(), []byte("other"), spectypes.BNRoleAttester),
+				Height:     []specqbft.Height{2, 5},
+			},
+			expectedStatus: message.StatusBadRequest,
+			expectedReport: []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectMedium},
+		},
+		{
+			name:  "unknown role",
+			msgID: spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BeaconRole(100)),
+			params: &message.SyncParams{
+				Identifier: spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BeaconRole(100)),
+				Height:     []specqbft.Height{2, 5},
+			},
+			expectedStatus: message.StatusBadRequest,
+			expectedReport: []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectLow},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reporting := &mockReporting{}
+			handler := HistoryHandler(logger, stores, reporting, 3)
+			res, err := handler(newSyncRequest(t, test.msgID, message.DecidedHistoryType, test.params))
+			require.NoError(t, err)
+			sm := &message.SyncMessage{}
+			require.NoError(t, sm.Decode(res.Data))
+			require.Equal(t, test.expectedStatus, sm.Status)
+			require.Len(t, sm.Data, test.expectedCount)
+			require.Equal(t, test.expectedReport, reporting.results)
+		})
+	}
+}
+
+func TestLastDecidedHandler(t *testing.T) {
+	logger := logging.TestLogger(t)
+	stores := newTestStores(t, logger, 10)
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleAttester)
+
+	t.Run("valid request", func(t *testing.T) {
+		reporting := &mockReporting{}
+		handler := LastDecidedHandler(logger, stores, reporting)
+		res, err := handler(newSyncRequest(t, msgID, message.LastDecidedType, &message.SyncParams{Identifier: msgID}))
+		require.NoError(t, err)
+		sm := &message.SyncMessage{}
+		require.NoError(t, sm.Decode(res.Data))
+		require.Equal(t, message.StatusSuccess, sm.Status)
+		require.Len(t, sm.Data, 1)
+		require.Equal(t, specqbft.Height(10), sm.Data[0].Message.Height)
+		require.Empty(t, reporting.results)
+	})
+
+	t.Run("missing params", func(t *testing.T) {
+		reporting := &mockReporting{}
+		handler := LastDecidedHandler(logger, stores, reporting)
+		res, err := handler(newSyncRequest(t, msgID, message.LastDecidedType, nil))
+		require.NoError(t, err)
+		sm := &message.SyncMessage{}
+		require.NoError(t, sm.Decode(res.Data))
+		require.Equal(t, message.StatusBadRequest, sm.Status)
+		require.Equal(t, []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectMedium}, reporting.results)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		reporting := &mockReporting{}
+		handler := LastDecidedHandler(logger, stores, reporting)
+		unknownID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BeaconRole(100))
+		res, err := handler(newSyncRequest(t, unknownID, message.LastDecidedType, &message.SyncParams{Identifier: unknownID}))
+		require.NoError(t, err)
+		sm := &message.SyncMessage{}
+		require.NoError(t, sm.Decode(res.Data))
+		require.Equal(t, message.StatusBadRequest, sm.Status)
+		require.Equal(t, []protocolp2p.MsgValidationResult{protocolp2p.ValidationRejectLow}, reporting.results)
+	})
+}
+
+func newSyncRequest(t *testing.T, msgID spectypes.MessageID, protocol message.SyncMsgType, params *message.SyncParams) *spectypes.SSVMessage {
+	data, err := (&message.SyncMessage{
+		Protocol: protocol,
+		Params:   params,
+	}).Encode()
+	require.NoError(t, err)
+	return &spectypes.SSVMessage{
+		MsgType: message.SSVSyncMsgType,
+		MsgID:   msgID,
+		Data:    data,
+	}
+}
+
+// newTestStores creates attester stores with decided instances of heights [0, highest]
+func newTestStores(t *testing.T, logger *zap.Logger, highest specqbft.Height) *storage.QBFTStores {
+	db, err := ssvstorage.GetStorageFactory(logger, basedb.Options{Type: "badger-memory"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close(logger)
+	})
+
+	stores := storage.NewStoresFromRoles(db, spectypes.BNRoleAttester)
+	store := stores.Get(spectypes.BNRoleAttester)
+	msgID := spectypes.NewMsgID(types.GetDefaultDomain(), []byte("pk"), spectypes.BNRoleAttester)
+	for h := specqbft.Height(0); h <= highest; h++ {
+		instance := &qbftstorage.StoredInstance{
+			State: &specqbft.State{
+				ID:     msgID[:],
+				Height: h,
+			},
+			DecidedMessage: &specqbft.SignedMessage{
+				Signature: []byte("sig"),
+				Signers:   []spectypes.OperatorID{1},
+				Message: specqbft.Message{
+					MsgType:    specqbft.CommitMsgType,
+					Height:     h,
+					Identifier: msgID[:],
+				},
+			},
+		}
+		require.NoError(t, store.SaveInstance(instance))
+		if h == highest {
+			require.NoError(t, store.SaveHighestInstance(instance))
+		}
+	}
+	return stores
+}
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"fmt"
-
 	spectypes "github.com/bloxapp/ssv-spec/types"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -13,8 +11,8 @@ import (
 	protocolp2p "github.com/bloxapp/ssv/protocol/v2/p2p"
 )
 
-// LastDecidedHandler handler for last-decided protocol
-// TODO: add msg validation and report scores
+// LastDecidedHandler handler for last-decided protocol,
+// invalid requests are answered with StatusBadRequest and reported.
 func LastDecidedHandler(plogger *zap.Logger, storeMap *storage.QBFTStores, reporting protocolp2p.ValidationReporting) protocolp2p.RequestHandler {
 	return func(msg *spectypes.SSVMessage) (*spectypes.SSVMessage, error) {
 		logger := plogger.With(fields.PubKey(msg.MsgID.GetPubKey()))
@@ -28,12 +26,16 @@ func LastDecidedHandler(plogger *zap.Logger, storeMap *storage.QBFTStores, repor
 			// not this protocol
 			// TODO: remove after v0
 			return nil, nil
+		} else if err := validateLastDecidedRequest(msg, sm); err != nil {
+			logger.Debug("❌ invalid last decided request", zap.Error(err))
+			reporting.ReportValidation(logger, msg, protocolp2p.ValidationRejectMedium)
+			sm.Status = message.StatusBadRequest
+		} else if store := storeMap.Get(msg.MsgID.GetRoleType()); store == nil {
+			logger.Debug("❌ unknown role", zap.String("role", msg.MsgID.GetRoleType().String()))
+			reporting.ReportValidation(logger, msg, protocolp2p.ValidationRejectLow)
+			sm.Status = message.StatusBadRequest
 		} else {
 			msgID := msg.GetID()
-			store := storeMap.Get(msgID.GetRoleType())
-			if store == nil {
-				return nil, errors.New(fmt.Sprintf("not storage found for type %s", msgID.GetRoleType().String()))
-			}
 			instance, err := store.GetHighestInstance(msgID[:])
 			if err != nil {
 				logger.Debug("❗ failed to get highest instance", zap.Error(err))
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	specqbft "github.com/bloxapp/ssv-spec/qbft"
+	spectypes "github.com/bloxapp/ssv-spec/types"
+	"github.com/pkg/errors"
+
+	"github.com/bloxapp/ssv/protocol/v2/message"
+	qbftstorage "github.com/bloxapp/ssv/protocol/v2/qbft/storage"
+)
+
+const (
+	// maxHistoryRange is the widest range of heights that a history request may ask for,
+	// honest peers split their requests into batches of a few dozen heights
+	maxHistoryRange specqbft.Height = 1000
+	// maxHistoryHeightsAhead is how far above the highest stored height a history request may start,
+	// as this node might be behind the peers that the requester synced the highest decided from
+	maxHistoryHeightsAhead specqbft.Height = 1000
+)
+
+var (
+	// ErrMissingParams means that the request has no params
+	ErrMissingParams = errors.New("missing params")
+	// ErrIdentifierMismatch means that the identifier in the params doesn't match the message
+	ErrIdentifierMismatch = errors.New("identifier mismatch")
+	// ErrInvalidHeights means that the requested heights are not a valid range
+	ErrInvalidHeights = errors.New("invalid heights")
+	// ErrHeightsTooHigh means that the requested heights are far above the highest stored height
+	ErrHeightsTooHigh = errors.New("heights too high")
+)
+
+// validateLastDecidedRequest validates the params of a last decided request
+func validateLastDecidedRequest(msg *spectypes.SSVMessage, sm *message.SyncMessage) error {
+	if sm.Params == nil {
+		return ErrMissingParams
+	}
+	if sm.Params.Identifier != msg.MsgID {
+		return ErrIdentifierMismatch
+	}
+	return nil
+}
+
+// validateHistoryRequest validates the params of a decided history request,
+// the requested heights must be a range of [from, to] where from <= to, of up to maxHistoryRange heights
+func validateHistoryRequest(msg *spectypes.SSVMessage, sm *message.SyncMessage) error {
+	if err := validateLastDecidedRequest(msg, sm); err != nil {
+		return err
+	}
+	if len(sm.Params.Height) != 2 {
+		return ErrInvalidHeights
+	}
+	from, to := sm.Params.Height[0], sm.Params.Height[1]
+	if from > to {
+		return ErrInvalidHeights
+	}
+	// comparing heights rather than their difference as int, which might overflow
+	if to-from > maxHistoryRange {
+		return ErrInvalidHeights
+	}
+	return nil
+}
+
+// validateHistoryHeights checks that the requested heights don't start far above the highest height in the store
+func validateHistoryHeights(store qbftstorage.QBFTStore, msgID spectypes.MessageID, from specqbft.Height) error {
+	highest, err := store.GetHighestInstance(msgID[:])
+	if err != nil {
+		return errors.Wrap(err, "could not get highest instance")
+	}
+	var highestHeight specqbft.Height
+	if highest != nil && highest.State != nil {
+		highestHeight = highest.State.Height
+	}
+	if from > highestHeight+maxHistoryHeightsAhead {
+		return ErrHeightsTooHigh
+	}
+	return nil
+}
@@ -16,7 +16,10 @@ type Options struct {
 	Ctx        context.Context
 }
 
-// Txn interface for badger transaction like functions
+// Txn interface for badger transaction like functions.
+// The key of each operation is appended to the given prefix, and the transaction keeps the result until it's committed.
+// Therefore a prefix that is reused across operations must have no spare capacity (e.g. allocated with an exact capacity),
+// otherwise the keys of the transaction would share memory and override each other.
 type Txn interface {
 	Set(prefix []byte, key []byte, value []byte) error
 	Get(prefix []byte, key []byte) (Obj, bool, error)
@@ -33,6 +36,8 @@ type IDb interface {
 	Delete(prefix []byte, key []byte) error
 	DeleteByPrefix(prefix []byte) (int, error)
 	GetAll(logger *zap.Logger, prefix []byte, handler func(int, Obj) error) error
+	// GetRange iterates the items of the given prefix which keys are within [from, to], in lexicographic order.
+	GetRange(prefix []byte, from []byte, to []byte, handler func(Obj) error) error
 	CountByCollection(prefix []byte) (int64, error)
 	RemoveAllByCollection(prefix []byte) error
 	Update(fn func(Txn) error) error
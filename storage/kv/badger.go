@@ -166,20 +166,29 @@ func (b *BadgerDb) Delete(prefix []byte, key []byte) error {
 	})
 }
 
-// DeleteByPrefix all items with this prefix
+// DeleteByPrefix all items with this prefix,
+// the items are deleted in a write batch which is split to multiple transactions if needed
 func (b *BadgerDb) DeleteByPrefix(prefix []byte) (int, error) {
-	count := 0
-	err := b.db.Update(func(txn *badger.Txn) error {
-		rawKeys := b.listRawKeys(prefix, txn)
-		for _, k := range rawKeys {
-			if err := txn.Delete(k); err != nil {
-				return err
-			}
-			count++
-		}
+	var rawKeys [][]byte
+	err := b.db.View(func(txn *badger.Txn) error {
+		rawKeys = listRawKeys(prefix, txn)
 		return nil
 	})
-	return count, err
+	if err != nil {
+		return 0, err
+	}
+
+	wb := b.db.NewWriteBatch()
+	for _, k := range rawKeys {
+		if err := wb.Delete(k); err != nil {
+			wb.Cancel()
+			return 0, err
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return 0, err
+	}
+	return len(rawKeys), nil
 }
 
 // GetAll returns all the items of a given collection
@@ -188,7 +197,7 @@ func (b *BadgerDb) GetAll(logger *zap.Logger, prefix []byte, handler func(int, b
 	// instead, the keys are first fetched using an iterator, and afterwards the values are fetched one by one
 	// to avoid issues
 	err := b.db.View(func(txn *badger.Txn) error {
-		rawKeys := b.listRawKeys(prefix, txn)
+		rawKeys := listRawKeys(prefix, txn)
 		for i, k := range rawKeys {
 			trimmedResKey := bytes.TrimPrefix(k, prefix)
 			item, err := txn.Get(k)
@@ -214,6 +223,38 @@ func (b *BadgerDb) GetAll(logger *zap.Logger, prefix []byte, handler func(int, b
 	return err
 }
 
+// GetRange iterates the items of the given prefix which keys are within [from, to], in lexicographic order.
+// the keys that are passed to the handler are trimmed from the prefix.
+func (b *BadgerDb) GetRange(prefix []byte, from []byte, to []byte, handler func(basedb.Obj) error) error {
+	return b.db.View(func(txn *badger.Txn) error {
+		opt := badger.DefaultIteratorOptions
+		opt.Prefix = prefix
+		it := txn.NewIterator(opt)
+		defer it.Close()
+
+		start := make([]byte, 0, len(prefix)+len(from))
+		start = append(append(start, prefix...), from...)
+		for it.Seek(start); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			key := item.KeyCopy(nil)[len(prefix):]
+			if bytes.Compare(key, to) > 0 {
+				return nil
+			}
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return errors.Wrap(err, "failed to copy value")
+			}
+			if err := handler(basedb.Obj{
+				Key:   key,
+				Value: val,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // CountByCollection return the object count for all keys under specified prefix(bucket)
 func (b *BadgerDb) CountByCollection(prefix []byte) (int64, error) {
 	var res int64
@@ -277,7 +318,7 @@ func (b *BadgerDb) periodicallyReport(logger *zap.Logger, interval time.Duration
 	}
 }
 
-func (b *BadgerDb) listRawKeys(prefix []byte, txn *badger.Txn) [][]byte {
+func listRawKeys(prefix []byte, txn *badger.Txn) [][]byte {
 	var keys [][]byte
 
 	opt := badger.DefaultIteratorOptions
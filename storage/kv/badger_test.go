@@ -86,6 +86,29 @@ func TestBadgerEndToEnd(t *testing.T) {
 	require.Equal(t, 1, deleted)
 }
 
+func TestBadgerDb_DeleteByPrefixBatches(t *testing.T) {
+	logger := logging.TestLogger(t)
+	db, err := New(logger, basedb.Options{Type: "badger-memory"})
+	require.NoError(t, err)
+	defer db.Close(logger)
+
+	// more deletes than fit in a single transaction
+	n := int(db.(*BadgerDb).db.MaxBatchCount()) + 1
+	prefix := []byte("prefix")
+	require.NoError(t, db.SetMany(prefix, n, func(i int) (basedb.Obj, error) {
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, uint64(i))
+		return basedb.Obj{Key: key, Value: []byte{1}}, nil
+	}))
+
+	deleted, err := db.DeleteByPrefix(prefix)
+	require.NoError(t, err)
+	require.Equal(t, n, deleted)
+	count, err := db.CountByCollection(prefix)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
 func TestBadgerDb_GetAll(t *testing.T) {
 	logger := logging.TestLogger(t)
 	options := basedb.Options{
@@ -173,6 +196,49 @@ func TestBadgerDb_SetMany(t *testing.T) {
 	}
 }
 
+func TestBadgerDb_GetRange(t *testing.T) {
+	logger := logging.TestLogger(t)
+	options := basedb.Options{
+		Type: "badger-memory",
+		Path: "",
+	}
+	db, err := New(logger, options)
+	require.NoError(t, err)
+	defer db.Close(logger)
+
+	prefix := []byte("prefix")
+	beKey := func(n uint64) []byte {
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, n)
+		return b
+	}
+	for i := uint64(0); i < 300; i++ {
+		require.NoError(t, db.Set(prefix, beKey(i), uInt64ToByteSlice(i)))
+	}
+	// items of other prefixes are not iterated
+	require.NoError(t, db.Set([]byte("prefix2"), beKey(10), []byte("other")))
+
+	var results []uint64
+	err = db.GetRange(prefix, beKey(10), beKey(260), func(obj basedb.Obj) error {
+		require.Equal(t, obj.Key, beKey(binary.LittleEndian.Uint64(obj.Value)))
+		results = append(results, binary.BigEndian.Uint64(obj.Key))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 251)
+	for i, n := range results {
+		require.Equal(t, uint64(i+10), n)
+	}
+
+	results = nil
+	err = db.GetRange(prefix, beKey(500), beKey(600), func(obj basedb.Obj) error {
+		results = append(results, binary.BigEndian.Uint64(obj.Key))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 0)
+}
+
 func uInt64ToByteSlice(n uint64) []byte {
 	b := make([]byte, 8)
 	binary.LittleEndian.PutUint64(b, n)
@@ -0,0 +1,19 @@
+package format
+
+import "strings"
+
+// Addresses returns the given node addresses in order, without empty entries and duplicates.
+// It is used for options with a single address and a list of failover addresses.
+func Addresses(addrs ...string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, addr := range addrs {
+		addr = strings.TrimSpace(addr)
+		if len(addr) == 0 || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		unique = append(unique, addr)
+	}
+	return unique
+}
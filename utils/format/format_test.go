@@ -63,3 +63,9 @@ func TestIdentifierFormat(t *testing.T) {
 	identifier := IdentifierFormat([]byte("111"), "ATTESTER")
 	require.Equal(t, "313131_ATTESTER", identifier)
 }
+
+func TestAddresses(t *testing.T) {
+	require.Equal(t, []string{"a", "b", "c"}, Addresses("a", " b", "", "a", "c", "b "))
+	require.Equal(t, []string{"b"}, Addresses("", "b"))
+	require.Empty(t, Addresses("", " "))
+}
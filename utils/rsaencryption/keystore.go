@@ -0,0 +1,113 @@
+package rsaencryption
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bloxapp/eth2-key-manager/encryptor/keystorev4"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Keystore is a password protected operator private key, in the format of EIP-2335 keystores
+// (scrypt key derivation and aes-128-ctr encryption of the PEM encoded private key)
+type Keystore struct {
+	Crypto map[string]interface{} `json:"crypto"`
+	// PubKey is the base64 encoded public key of the operator
+	PubKey  string `json:"pubKey"`
+	UUID    string `json:"uuid"`
+	Version uint   `json:"version"`
+}
+
+// EncryptKeystore encrypts the given PEM encoded private key with the password
+func EncryptKeystore(skPem []byte, password string) (*Keystore, error) {
+	if len(password) == 0 {
+		return nil, errors.New("empty password")
+	}
+	sk, err := ConvertPemToPrivateKey(string(skPem))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid private key")
+	}
+	pk, err := ExtractPublicKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	encryptor := keystorev4.New()
+	crypto, err := encryptor.Encrypt(skPem, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt private key")
+	}
+	return &Keystore{
+		Crypto:  crypto,
+		PubKey:  pk,
+		UUID:    uuid.New().String(),
+		Version: encryptor.Version(),
+	}, nil
+}
+
+// DecryptKeystore decrypts the private key of the given keystore
+func DecryptKeystore(ks *Keystore, password string) (*rsa.PrivateKey, error) {
+	if ks.Crypto == nil {
+		return nil, errors.New("keystore has no crypto")
+	}
+	skPem, err := keystorev4.New().Decrypt(ks.Crypto, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt private key")
+	}
+	sk, err := ConvertPemToPrivateKey(string(skPem))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid private key")
+	}
+	return sk, nil
+}
+
+// WriteKeystore writes the given keystore to a file that is readable only by the current user
+func WriteKeystore(path string, ks *Keystore) error {
+	raw, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal keystore")
+	}
+	return os.WriteFile(filepath.Clean(path), raw, 0600)
+}
+
+// ReadKeystore reads a keystore file
+func ReadKeystore(path string) (*Keystore, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read keystore file")
+	}
+	ks := &Keystore{}
+	if err := json.Unmarshal(raw, ks); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal keystore")
+	}
+	return ks, nil
+}
+
+// ReadPasswordFile reads a keystore password from the given file, surrounding whitespaces are ignored
+func ReadPasswordFile(path string) (string, error) {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", errors.Wrap(err, "could not read password file")
+	}
+	password := strings.TrimSpace(string(raw))
+	if len(password) == 0 {
+		return "", errors.New("password file is empty")
+	}
+	return password, nil
+}
+
+// LoadKeystore reads and decrypts the private key of the given keystore file with the password of the password file
+func LoadKeystore(keystoreFile, passwordFile string) (*rsa.PrivateKey, error) {
+	password, err := ReadPasswordFile(passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	ks, err := ReadKeystore(keystoreFile)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptKeystore(ks, password)
+}
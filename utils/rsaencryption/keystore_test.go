@@ -0,0 +1,45 @@
+package rsaencryption
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	testingspace "github.com/bloxapp/ssv/utils/rsaencryption/testingspace"
+)
+
+func TestKeystore(t *testing.T) {
+	sk, err := ConvertPemToPrivateKey(testingspace.SkPem)
+	require.NoError(t, err)
+	pk, err := ExtractPublicKey(sk)
+	require.NoError(t, err)
+
+	ks, err := EncryptKeystore([]byte(testingspace.SkPem), "password")
+	require.NoError(t, err)
+	require.Equal(t, pk, ks.PubKey)
+
+	dir := t.TempDir()
+	keystoreFile := filepath.Join(dir, "keystore.json")
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, WriteKeystore(keystoreFile, ks))
+	require.NoError(t, os.WriteFile(passwordFile, []byte("password\n"), 0600))
+
+	decrypted, err := LoadKeystore(keystoreFile, passwordFile)
+	require.NoError(t, err)
+	require.True(t, sk.Equal(decrypted))
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := DecryptKeystore(ks, "wrong")
+		require.Error(t, err)
+	})
+
+	t.Run("empty password", func(t *testing.T) {
+		_, err := EncryptKeystore([]byte(testingspace.SkPem), "")
+		require.EqualError(t, err, "empty password")
+		require.NoError(t, os.WriteFile(passwordFile, []byte(" \n"), 0600))
+		_, err = LoadKeystore(keystoreFile, passwordFile)
+		require.EqualError(t, err, "password file is empty")
+	})
+}